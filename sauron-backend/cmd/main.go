@@ -7,7 +7,9 @@ import (
 	"os"
 	"sauron-backend/docs"
 	"sauron-backend/internal/api"
+	"sauron-backend/internal/api/handlers"
 	"sauron-backend/internal/db"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
@@ -41,6 +43,19 @@ func main() {
 	cleanFlag := flag.Bool("clean", false, "Clean orphaned records from the database")
 	statsFlag := flag.Bool("stats", false, "Display database statistics")
 	helpFlag := flag.Bool("help", false, "Display help information")
+	importRulesFlag := flag.String("import-rules", "", "Import a YAML compatibility ruleset file as a new ruleset version")
+	exportRulesFlag := flag.String("export-rules", "", "Export the compatibility ruleset as YAML to a file, or - for stdout")
+	rulesVersionFlag := flag.Int("rules-version", 0, "With --export-rules, export this imported ruleset version instead of the current rules")
+	dryRunFlag := flag.Bool("dry-run", false, "With --import-rules, only report which rules and builds the import would change")
+	rebuildIndexFlag := flag.Bool("rebuild-compatibility", false, "Rebuild the precomputed part compatibility index")
+	exportGraphFlag := flag.String("export-graph", "", "Export the compatibility graph to a file, or - for stdout")
+	graphFormatFlag := flag.String("graph-format", "", "With --export-graph, dot or graphml (default from the file extension, else dot)")
+	graphModelFlag := flag.Int("graph-model", 0, "With --export-graph, only this firearm model ID, its categories and the parts that fit it")
+	graphCategoriesFlag := flag.Bool("graph-categories-only", false, "With --export-graph, leave out individual parts")
+	complianceReportFlag := flag.String("compliance-report", "", "Check every prebuilt firearm against every jurisdiction's laws and write the matrix to a file, or - for stdout")
+	reportFormatFlag := flag.String("report-format", "", "With --compliance-report, csv or json (default from the file extension, else csv)")
+	reportJurisdictionsFlag := flag.String("report-jurisdictions", "", "With --compliance-report, comma-separated jurisdiction codes to check instead of all, e.g. US-CA,US-NY")
+	reportAsOfFlag := flag.String("report-as-of", "", "With --compliance-report, check the laws as of this date (YYYY-MM-DD, default today)")
 
 	// Parse command line flags
 	flag.Parse()
@@ -59,6 +74,15 @@ func main() {
 		fmt.Println("  main --reset --seed     # Reset database and seed with fresh data")
 		fmt.Println("  main --stats            # Display database statistics")
 		fmt.Println("  main --clean            # Clean orphaned records")
+		fmt.Println("  main --seed --import-rules rules.yaml           # Seed, then import a compatibility ruleset")
+		fmt.Println("  main --import-rules rules.yaml --dry-run        # Report builds a ruleset would invalidate")
+		fmt.Println("  main --export-rules -                           # Print the current ruleset as YAML")
+		fmt.Println("  main --export-rules v3.yaml --rules-version 3   # Export ruleset version 3")
+		fmt.Println("  main --rebuild-compatibility                    # Recompute compatible parts for the whole catalog")
+		fmt.Println("  main --export-graph graph.dot --graph-model 1   # Export the AR-15 compatibility graph as Graphviz DOT")
+		fmt.Println("  main --export-graph taxonomy.graphml --graph-categories-only  # Export models and categories as GraphML")
+		fmt.Println("  main --compliance-report compliance.csv         # Check every prebuilt firearm in every jurisdiction")
+		fmt.Println("  main --compliance-report - --report-format json --report-jurisdictions US-CA,US-NY  # CA and NY as JSON")
 		return
 	}
 
@@ -67,8 +91,11 @@ func main() {
 		log.Println("Warning: No .env file found")
 	}
 
+	// Commands that read or write a file rather than start the server
+	fileCommand := *importRulesFlag != "" || *exportRulesFlag != "" || *exportGraphFlag != "" || *complianceReportFlag != ""
+
 	// Special handling for stats command - connect to DB but don't auto-seed
-	if *statsFlag && !(*seedFlag || *wipeFlag || *resetFlag || *cleanFlag) {
+	if *statsFlag && !(*seedFlag || *wipeFlag || *resetFlag || *cleanFlag || *rebuildIndexFlag || fileCommand) {
 		// Just connect to DB without seeding
 		db.ConnectDB()
 
@@ -78,7 +105,7 @@ func main() {
 		for table, count := range stats {
 			fmt.Printf("%-20s: %d records\n", table, count)
 		}
-		fmt.Println("---------------------------\n")
+		fmt.Print("---------------------------\n\n")
 
 		// Exit early
 		log.Println("Stats command executed successfully")
@@ -92,7 +119,7 @@ func main() {
 
 		fmt.Println("\n⚠️  WARNING: You are about to COMPLETELY RESET the database schema.")
 		fmt.Println("⚠️  This will DROP ALL TABLES and DELETE ALL DATA!")
-		fmt.Println("⚠️  Press Ctrl+C now to cancel, or wait 5 seconds to continue...\n")
+		fmt.Print("⚠️  Press Ctrl+C now to cancel, or wait 5 seconds to continue...\n\n")
 
 		// Wait 5 seconds to allow user to cancel
 		for i := 5; i > 0; i-- {
@@ -112,6 +139,7 @@ func main() {
 
 	// Handle database commands
 	handledCommand := false
+	seeded := false
 
 	// Handle reset flag - mark as handled since we already executed it
 	if *resetFlag {
@@ -122,6 +150,7 @@ func main() {
 		if *seedFlag {
 			log.Println("Seeding database after reset...")
 			db.SeedDatabase()
+			seeded = true
 			// Mark seed as handled so we don't do it twice
 			*seedFlag = false
 		}
@@ -140,6 +169,128 @@ func main() {
 	if *seedFlag {
 		log.Println("Seeding database as requested...")
 		db.SeedDatabase()
+		seeded = true
+		handledCommand = true
+	}
+
+	// Rebuild the compatibility index when asked, and after seeding since the seed writes
+	// parts and rules directly rather than through the API that keeps the index current
+	if *rebuildIndexFlag || seeded {
+		log.Println("Rebuilding part compatibility index...")
+		count, err := handlers.RebuildPartCompatibilityIndex()
+		if err != nil {
+			log.Fatalf("Error rebuilding part compatibility index: %v", err)
+		}
+		log.Printf("Part compatibility index rebuilt with %d edges", count)
+		if *rebuildIndexFlag {
+			handledCommand = true
+		}
+	}
+
+	// Handle ruleset import (after seeding, so a fresh database can be seeded and given a ruleset in one run)
+	if *importRulesFlag != "" {
+		content, err := os.ReadFile(*importRulesFlag)
+		if err != nil {
+			log.Fatalf("Error reading ruleset: %v", err)
+		}
+		report, err := handlers.ImportRuleSet(content, *importRulesFlag, *dryRunFlag)
+		if err != nil {
+			log.Fatalf("Error importing ruleset: %v", err)
+		}
+		printRuleSetReport(report)
+
+		// The API rebuilds the index in the background; this process may exit first
+		if !*dryRunFlag {
+			count, err := handlers.RebuildPartCompatibilityIndex()
+			if err != nil {
+				log.Fatalf("Error rebuilding part compatibility index: %v", err)
+			}
+			log.Printf("Part compatibility index rebuilt with %d edges", count)
+		}
+		handledCommand = true
+	}
+
+	// Handle ruleset export
+	if *exportRulesFlag != "" {
+		content, err := handlers.ExportRuleSet(*rulesVersionFlag)
+		if err != nil {
+			log.Fatalf("Error exporting ruleset: %v", err)
+		}
+		if *exportRulesFlag == "-" {
+			os.Stdout.Write(content)
+		} else if err := os.WriteFile(*exportRulesFlag, content, 0644); err != nil {
+			log.Fatalf("Error writing ruleset: %v", err)
+		} else {
+			log.Printf("Exported ruleset to %s", *exportRulesFlag)
+		}
+		handledCommand = true
+	}
+
+	// Handle compatibility graph export
+	if *exportGraphFlag != "" {
+		format := *graphFormatFlag
+		if format == "" {
+			format = handlers.GraphFormatDOT
+			if strings.HasSuffix(strings.ToLower(*exportGraphFlag), ".graphml") {
+				format = handlers.GraphFormatGraphML
+			}
+		}
+		content, err := handlers.ExportCompatibilityGraph(handlers.GraphExportOptions{
+			Format:         format,
+			FirearmModelID: *graphModelFlag,
+			IncludeParts:   !*graphCategoriesFlag,
+		})
+		if err != nil {
+			log.Fatalf("Error exporting compatibility graph: %v", err)
+		}
+		if *exportGraphFlag == "-" {
+			os.Stdout.Write(content)
+		} else if err := os.WriteFile(*exportGraphFlag, content, 0644); err != nil {
+			log.Fatalf("Error writing compatibility graph: %v", err)
+		} else {
+			log.Printf("Exported compatibility graph to %s", *exportGraphFlag)
+		}
+		handledCommand = true
+	}
+
+	// Handle compliance report
+	if *complianceReportFlag != "" {
+		format := *reportFormatFlag
+		if format == "" {
+			format = handlers.ComplianceReportFormatCSV
+			if strings.HasSuffix(strings.ToLower(*complianceReportFlag), ".json") {
+				format = handlers.ComplianceReportFormatJSON
+			}
+		}
+		asOf := time.Now().UTC()
+		if *reportAsOfFlag != "" {
+			date, err := time.Parse("2006-01-02", *reportAsOfFlag)
+			if err != nil {
+				log.Fatalf("Error parsing --report-as-of: %v", err)
+			}
+			asOf = date
+		}
+		var jurisdictions []string
+		for _, code := range strings.Split(*reportJurisdictionsFlag, ",") {
+			if code = strings.TrimSpace(code); code != "" {
+				jurisdictions = append(jurisdictions, code)
+			}
+		}
+		content, err := handlers.ExportComplianceReport(handlers.ComplianceReportOptions{
+			Format:        format,
+			Jurisdictions: jurisdictions,
+			AsOf:          asOf,
+		})
+		if err != nil {
+			log.Fatalf("Error building compliance report: %v", err)
+		}
+		if *complianceReportFlag == "-" {
+			os.Stdout.Write(content)
+		} else if err := os.WriteFile(*complianceReportFlag, content, 0644); err != nil {
+			log.Fatalf("Error writing compliance report: %v", err)
+		} else {
+			log.Printf("Wrote compliance report to %s", *complianceReportFlag)
+		}
 		handledCommand = true
 	}
 
@@ -157,12 +308,12 @@ func main() {
 		for table, count := range stats {
 			fmt.Printf("%-20s: %d records\n", table, count)
 		}
-		fmt.Println("---------------------------\n")
+		fmt.Print("---------------------------\n\n")
 		handledCommand = true
 	}
 
 	// If we handled a command and there's no need to start the server, exit
-	if handledCommand && (*seedFlag || *wipeFlag || *resetFlag || *cleanFlag || *statsFlag) {
+	if handledCommand && (*seedFlag || *wipeFlag || *resetFlag || *cleanFlag || *statsFlag || *rebuildIndexFlag || fileCommand) {
 		log.Println("Command(s) executed successfully")
 		return
 	}
@@ -170,6 +321,11 @@ func main() {
 	// Continue with starting the server
 	log.Println("Starting the server...")
 
+	// Build the compatibility index if the database was just auto-seeded or predates it
+	if err := handlers.EnsurePartCompatibilityIndex(); err != nil {
+		log.Printf("Warning: Failed to build part compatibility index: %v", err)
+	}
+
 	// Programmatically set swagger info
 	docs.SwaggerInfo.Title = "Sauron Backend API"
 	docs.SwaggerInfo.Description = "A comprehensive API for firearms database management including parts, models, categories, product listings, and compatibility."
@@ -197,3 +353,24 @@ func main() {
 		log.Fatal("Failed to start server:", err)
 	}
 }
+
+// printRuleSetReport prints what a ruleset import changed, or would change on a dry run
+func printRuleSetReport(report *handlers.RuleSetImportReport) {
+	if report.DryRun {
+		fmt.Printf("\n--- Ruleset Dry Run: %s (version %d, latest %d) ---\n", report.Name, report.Version, report.PreviousVersion)
+	} else {
+		fmt.Printf("\n--- Imported Ruleset: %s (version %d, previous %d) ---\n", report.Name, report.Version, report.PreviousVersion)
+	}
+	fmt.Printf("Rules:   %d (checksum %s)\n", report.RuleCount, report.Checksum)
+	fmt.Printf("Added:   %v\n", report.AddedRules)
+	fmt.Printf("Removed: %v\n", report.RemovedRules)
+	fmt.Printf("Changed: %v\n", report.ChangedRules)
+	fmt.Printf("Builds newly invalidated: %d of %d\n", len(report.InvalidatedBuilds), report.BuildsChecked)
+	for _, build := range report.InvalidatedBuilds {
+		fmt.Printf("  #%d %s\n", build.BuildID, build.BuildName)
+		for _, failure := range build.Failures {
+			fmt.Printf("    - %s: %s\n", failure.RuleName, failure.Message)
+		}
+	}
+	fmt.Print("---------------------------\n\n")
+}
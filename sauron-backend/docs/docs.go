@@ -24,59 +24,48 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/firearm-models": {
+        "/admin/compatibility-graph": {
             "get": {
-                "description": "Get a list of all firearm models in the database",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Firearm Models"
-                ],
-                "summary": "Get all firearm models",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.FirearmModel"
-                            }
-                        }
-                    }
-                }
-            },
-            "post": {
-                "description": "Add a new firearm model to the database",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Export firearm models, part categories and parts as a Graphviz DOT or GraphML graph for auditing the taxonomy. Edges are the categories each model uses, the category hierarchy, category membership, declared fitments and compatible parts, with compatible pairs also rolled up per pair of categories. Category nodes carry part, model and compatible category counts and flag categories no firearm model uses.",
                 "produces": [
-                    "application/json"
+                    "text/vnd.graphviz",
+                    "application/graphml+xml"
                 ],
                 "tags": [
-                    "Firearm Models"
+                    "Admin",
+                    "Compatibility"
                 ],
-                "summary": "Create a new firearm model",
+                "summary": "Export the compatibility graph",
                 "parameters": [
                     {
-                        "description": "Firearm Model Info",
-                        "name": "model",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.FirearmModel"
-                        }
+                        "enum": [
+                            "dot",
+                            "graphml"
+                        ],
+                        "type": "string",
+                        "default": "dot",
+                        "description": "Output format",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only this firearm model, its part categories and the parts that fit it",
+                        "name": "firearm_model_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include individual parts and their edges (default true)",
+                        "name": "include_parts",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "Graph document",
                         "schema": {
-                            "$ref": "#/definitions/models.FirearmModel"
+                            "type": "string"
                         }
                     },
                     "400": {
@@ -87,38 +76,6 @@ const docTemplate = `{
                                 "type": "string"
                             }
                         }
-                    }
-                }
-            }
-        },
-        "/firearm-models/{id}": {
-            "get": {
-                "description": "Get details of a specific firearm model",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Firearm Models"
-                ],
-                "summary": "Get a firearm model by ID",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Firearm Model ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/models.FirearmModel"
-                        }
                     },
                     "404": {
                         "description": "Not Found",
@@ -128,44 +85,61 @@ const docTemplate = `{
                                 "type": "string"
                             }
                         }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
                     }
                 }
-            },
-            "put": {
-                "description": "Update details of a specific firearm model",
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/admin/compliance-report": {
+            "get": {
+                "description": "Check every prebuilt firearm against the laws of every jurisdiction, or the given ones, and export the matrix of pass, fail or undetermined with the violated or undecidable rules and their citations. Each firearm also lists the jurisdictions it is unavailable in and data issues found in its components and specifications, such as lengths that are missing, unreadable or disagree with its parts.",
                 "produces": [
+                    "text/csv",
                     "application/json"
                 ],
                 "tags": [
-                    "Firearm Models"
+                    "Admin",
+                    "Laws"
                 ],
-                "summary": "Update a firearm model",
+                "summary": "Export the prebuilt firearm compliance report",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Firearm Model ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "enum": [
+                            "csv",
+                            "json"
+                        ],
+                        "type": "string",
+                        "default": "csv",
+                        "description": "Output format",
+                        "name": "format",
+                        "in": "query"
                     },
                     {
-                        "description": "Updated Firearm Model Info",
-                        "name": "model",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.FirearmModel"
-                        }
+                        "type": "string",
+                        "description": "Comma-separated jurisdiction codes or IDs, e.g. US-CA,US-NY; all when omitted",
+                        "name": "jurisdictions",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Date to check the laws as of, YYYY-MM-DD or RFC 3339; defaults to now",
+                        "name": "as_of",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.FirearmModel"
+                            "$ref": "#/definitions/handlers.ComplianceReport"
                         }
                     },
                     "400": {
@@ -185,11 +159,22 @@ const docTemplate = `{
                                 "type": "string"
                             }
                         }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
                     }
                 }
-            },
-            "delete": {
-                "description": "Delete a specific firearm model",
+            }
+        },
+        "/builds": {
+            "get": {
+                "description": "Get a list of all saved builds with their selected parts",
                 "consumes": [
                     "application/json"
                 ],
@@ -197,37 +182,31 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Firearm Models"
+                    "Builds"
                 ],
-                "summary": "Delete a firearm model",
+                "summary": "Get all builds",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Firearm Model ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Filter by firearm model ID",
+                        "name": "firearm_model_id",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
-                    },
-                    "404": {
-                        "description": "Not Found",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Build"
                             }
                         }
                     }
                 }
-            }
-        },
-        "/firearm-models/{id}/categories": {
-            "get": {
-                "description": "Retrieves all part categories associated with a specific firearm model",
+            },
+            "post": {
+                "description": "Save a firearm model together with the part selected for each slot. The selection is validated before saving.",
                 "consumes": [
                     "application/json"
                 ],
@@ -235,37 +214,35 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Firearm Models",
-                    "Part Categories"
+                    "Builds"
                 ],
-                "summary": "Get part categories for a firearm model",
+                "summary": "Create a new build",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Firearm Model ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Build Info",
+                        "name": "build",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.BuildInput"
+                        }
                     },
                     {
                         "type": "boolean",
-                        "description": "Filter by required status (true=required, false=optional, omit=both)",
-                        "name": "required",
+                        "description": "Save even if required slots are empty or accessories do not fit on the rails",
+                        "name": "allow_incomplete",
                         "in": "query"
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Array of part categories with required status",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "type": "object"
-                            }
+                            "$ref": "#/definitions/models.Build"
                         }
                     },
                     "400": {
-                        "description": "Invalid firearm model ID",
+                        "description": "Bad Request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -273,17 +250,14 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "404": {
-                        "description": "Firearm model not found",
+                    "422": {
+                        "description": "Unprocessable Entity",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/handlers.BuildValidationResult"
                         }
                     },
                     "500": {
-                        "description": "Server error",
+                        "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -294,9 +268,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/firearm-models/{id}/categories-hierarchy": {
-            "get": {
-                "description": "Retrieves all part categories associated with a specific firearm model in a hierarchical structure",
+        "/builds/complete": {
+            "post": {
+                "description": "Fill the remaining required slots of a firearm model (and optionally its optional slots) with in-stock parts declared to fit the model that pass the compatibility rules against the locked parts and each other, so the total landed cost stays within the budget. The completed selection is validated like POST /builds/validate and is only feasible when it passes. The lowest_cost strategy picks the cheapest part for each slot; closest_to_budget upgrades parts to spend as much of the budget as possible. Given a destination state, only listings that can ship there are used.",
                 "consumes": [
                     "application/json"
                 ],
@@ -304,31 +278,29 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Firearm Models",
-                    "Part Categories"
+                    "Builds"
                 ],
-                "summary": "Get hierarchical categories for a firearm model",
+                "summary": "Complete a build within a budget",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Firearm Model ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Model, locked parts and budget",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.BuildCompletionInput"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Hierarchical array of part categories with required status",
+                        "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/handlers.CategoryWithRequiredStatusHierarchy"
-                            }
+                            "$ref": "#/definitions/handlers.BuildCompletionResult"
                         }
                     },
                     "400": {
-                        "description": "Invalid firearm model ID",
+                        "description": "Bad Request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -337,7 +309,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Firearm model not found",
+                        "description": "Not Found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -345,8 +317,14 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.BuildValidationResult"
+                        }
+                    },
                     "500": {
-                        "description": "Server error",
+                        "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -357,9 +335,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/firearm-models/{id}/categories/{category_id}": {
-            "put": {
-                "description": "Updates the relationship between a firearm model and a part category",
+        "/builds/cost": {
+            "post": {
+                "description": "Price an unsaved slot selection using the cheapest in-stock listing for each part, including shipping, optionally only from listings that can ship to a destination state",
                 "consumes": [
                     "application/json"
                 ],
@@ -367,56 +345,29 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Firearm Models",
-                    "Part Categories"
+                    "Builds"
                 ],
-                "summary": "Update a firearm model-category relationship",
+                "summary": "Price a slot selection",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Firearm Model ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Part Category ID",
-                        "name": "category_id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Updated relationship parameters",
-                        "name": "relationship",
+                        "description": "Slot selection to price",
+                        "name": "selection",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "type": "object"
+                            "$ref": "#/definitions/handlers.BuildCostInput"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Success message",
+                        "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/handlers.BuildCostResult"
                         }
                     },
                     "400": {
-                        "description": "Invalid input",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    },
-                    "404": {
-                        "description": "Relationship not found",
+                        "description": "Bad Request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -425,7 +376,7 @@ const docTemplate = `{
                         }
                     },
                     "500": {
-                        "description": "Server error",
+                        "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -434,9 +385,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
-                "description": "Associates a part category with a firearm model",
+            }
+        },
+        "/builds/diff": {
+            "get": {
+                "description": "List per-slot additions, removals and swaps between the current state of two builds, with price and weight deltas",
                 "consumes": [
                     "application/json"
                 ],
@@ -444,47 +397,34 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Firearm Models",
-                    "Part Categories"
+                    "Builds"
                 ],
-                "summary": "Add a category to a firearm model",
+                "summary": "Diff two builds",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Firearm Model ID",
-                        "name": "id",
-                        "in": "path",
+                        "description": "Build ID to diff from",
+                        "name": "from",
+                        "in": "query",
                         "required": true
                     },
                     {
                         "type": "integer",
-                        "description": "Part Category ID",
-                        "name": "category_id",
-                        "in": "path",
+                        "description": "Build ID to diff to",
+                        "name": "to",
+                        "in": "query",
                         "required": true
-                    },
-                    {
-                        "description": "Relationship parameters",
-                        "name": "relationship",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "type": "object"
-                        }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Success message",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/handlers.BuildDiff"
                         }
                     },
                     "400": {
-                        "description": "Invalid input",
+                        "description": "Bad Request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -493,16 +433,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Firearm model or part category not found",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    },
-                    "409": {
-                        "description": "Relationship already exists",
+                        "description": "Not Found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -511,7 +442,7 @@ const docTemplate = `{
                         }
                     },
                     "500": {
-                        "description": "Server error",
+                        "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -520,9 +451,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Removes the association between a part category and a firearm model",
+            }
+        },
+        "/builds/magazines": {
+            "post": {
+                "description": "Get the magazine variants whose family the firearm model takes and that feed the caliber an unsaved slot selection is chambered in",
                 "consumes": [
                     "application/json"
                 ],
@@ -530,38 +463,30 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Firearm Models",
-                    "Part Categories"
+                    "Builds",
+                    "Magazines"
                 ],
-                "summary": "Remove a category from a firearm model",
+                "summary": "Find the magazines that fit a slot selection",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Firearm Model ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Part Category ID",
-                        "name": "category_id",
-                        "in": "path",
-                        "required": true
+                        "description": "Slot selection",
+                        "name": "selection",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.BuildMagazinesInput"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Success message",
+                        "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/handlers.BuildMagazinesResult"
                         }
                     },
                     "400": {
-                        "description": "Invalid input",
+                        "description": "Bad Request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -570,7 +495,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Relationship not found",
+                        "description": "Not Found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -579,7 +504,7 @@ const docTemplate = `{
                         }
                     },
                     "500": {
-                        "description": "Server error",
+                        "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -590,9 +515,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/listings": {
-            "get": {
-                "description": "Get a list of all product listings in the database",
+        "/builds/nfa": {
+            "post": {
+                "description": "Derive an unsaved slot selection's federal classification from its barrel length, stock and the NFA tags of its parts, with a warning for each NFA-regulated configuration or item",
                 "consumes": [
                     "application/json"
                 ],
@@ -600,23 +525,61 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Product Listings"
+                    "Builds",
+                    "Laws"
+                ],
+                "summary": "Classify a slot selection under the NFA",
+                "parameters": [
+                    {
+                        "description": "Slot selection",
+                        "name": "selection",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.BuildNFAInput"
+                        }
+                    }
                 ],
-                "summary": "Get all product listings",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.ProductListing"
+                            "$ref": "#/definitions/handlers.NFAClassification"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
                             }
                         }
                     }
                 }
-            },
+            }
+        },
+        "/builds/rail-space": {
             "post": {
-                "description": "Add a new product listing to the database",
+                "description": "Allocate the rail space consumed by an unsaved slot selection's accessories to the rail sections its parts provide, reporting each section's usage and any accessory that does not fit",
                 "consumes": [
                     "application/json"
                 ],
@@ -624,25 +587,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Product Listings"
+                    "Builds"
                 ],
-                "summary": "Create a new product listing",
+                "summary": "Check rail space of a slot selection",
                 "parameters": [
                     {
-                        "description": "Product Listing Info",
-                        "name": "listing",
+                        "description": "Slot selection to check",
+                        "name": "selection",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.ProductListing"
+                            "$ref": "#/definitions/handlers.BuildRailSpaceInput"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.ProductListing"
+                            "$ref": "#/definitions/handlers.BuildRailSpaceResult"
                         }
                     },
                     "400": {
@@ -653,13 +616,22 @@ const docTemplate = `{
                                 "type": "string"
                             }
                         }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
                     }
                 }
             }
         },
-        "/listings/part/{partId}": {
-            "get": {
-                "description": "Get all product listings for a specific part",
+        "/builds/validate": {
+            "post": {
+                "description": "Check a slot selection against the firearm model's part categories and report missing required slots, category mismatches, parts declared to fit other models, magazines the build cannot take and accessories that do not fit on the build's rails. NFA-regulated configurations, such as a short-barreled rifle, are reported as warnings that do not make the build invalid, as are accessories that may not fit when the rail allocation search was cut short.",
                 "consumes": [
                     "application/json"
                 ],
@@ -667,34 +639,60 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Product Listings"
+                    "Builds"
                 ],
-                "summary": "Get listings by part ID",
+                "summary": "Validate a build",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Part ID",
-                        "name": "partId",
-                        "in": "path",
-                        "required": true
+                        "description": "Slot selection to validate",
+                        "name": "selection",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.BuildValidationInput"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.ProductListing"
+                            "$ref": "#/definitions/handlers.BuildValidationResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
                             }
                         }
                     }
                 }
             }
         },
-        "/listings/prebuilt/{prebuiltId}": {
-            "get": {
-                "description": "Get all product listings for a specific prebuilt firearm",
+        "/builds/weight": {
+            "post": {
+                "description": "Sum the weight of an unsaved slot selection, grouped by top-level part category, flagging parts with no weight recorded",
                 "consumes": [
                     "application/json"
                 ],
@@ -702,34 +700,51 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Product Listings"
+                    "Builds"
                 ],
-                "summary": "Get listings by prebuilt ID",
+                "summary": "Weigh a slot selection",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Prebuilt Firearm ID",
-                        "name": "prebuiltId",
-                        "in": "path",
-                        "required": true
+                        "description": "Slot selection to weigh",
+                        "name": "selection",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.BuildWeightInput"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.ProductListing"
+                            "$ref": "#/definitions/handlers.BuildWeightResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
                             }
                         }
                     }
                 }
             }
         },
-        "/listings/seller/{sellerId}": {
+        "/builds/{id}": {
             "get": {
-                "description": "Get all product listings for a specific seller",
+                "description": "Get a saved build with its selected parts",
                 "consumes": [
                     "application/json"
                 ],
@@ -737,14 +752,14 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Product Listings"
+                    "Builds"
                 ],
-                "summary": "Get listings by seller",
+                "summary": "Get a build by ID",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Seller ID",
-                        "name": "sellerId",
+                        "description": "Build ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
@@ -753,18 +768,22 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.ProductListing"
+                            "$ref": "#/definitions/models.Build"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
                             }
                         }
                     }
                 }
-            }
-        },
-        "/listings/{id}": {
-            "get": {
-                "description": "Get details of a specific product listing",
+            },
+            "put": {
+                "description": "Replace the details and slot selections of a saved build, recording a new revision",
                 "consumes": [
                     "application/json"
                 ],
@@ -772,71 +791,38 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Product Listings"
+                    "Builds"
                 ],
-                "summary": "Get a product listing by ID",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Product Listing ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/models.ProductListing"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    }
-                }
-            },
-            "put": {
-                "description": "Update details of a specific product listing",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Product Listings"
-                ],
-                "summary": "Update a product listing",
+                "summary": "Update a build",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Product Listing ID",
+                        "description": "Build ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Updated Product Listing Info",
-                        "name": "listing",
+                        "description": "Updated Build Info",
+                        "name": "build",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.ProductListing"
+                            "$ref": "#/definitions/handlers.BuildInput"
                         }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Save even if required slots are empty or accessories do not fit on the rails",
+                        "name": "allow_incomplete",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.ProductListing"
+                            "$ref": "#/definitions/models.Build"
                         }
                     },
                     "400": {
@@ -856,11 +842,26 @@ const docTemplate = `{
                                 "type": "string"
                             }
                         }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.BuildValidationResult"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
                     }
                 }
             },
             "delete": {
-                "description": "Delete a specific product listing",
+                "description": "Delete a saved build with its slot selections and revision history",
                 "consumes": [
                     "application/json"
                 ],
@@ -868,13 +869,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Product Listings"
+                    "Builds"
                 ],
-                "summary": "Delete a product listing",
+                "summary": "Delete a build",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Product Listing ID",
+                        "description": "Build ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -896,9 +897,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/listings/{id}/availability": {
-            "patch": {
-                "description": "Update the availability and optionally the price of a specific listing",
+        "/builds/{id}/cart": {
+            "get": {
+                "description": "Choose the sellers for a saved build's parts that minimize total landed cost, grouped by seller, optionally only from listings that can ship to a destination state",
                 "consumes": [
                     "application/json"
                 ],
@@ -906,32 +907,30 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Product Listings"
+                    "Builds",
+                    "Cart"
                 ],
-                "summary": "Update listing availability",
+                "summary": "Get optimized cart for a build",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Product Listing ID",
+                        "description": "Build ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Availability Update Info",
-                        "name": "availability",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "type": "object"
-                        }
+                        "type": "string",
+                        "description": "Destination state, e.g. CA or US-CA",
+                        "name": "ship_to",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.ProductListing"
+                            "$ref": "#/definitions/handlers.CartResult"
                         }
                     },
                     "400": {
@@ -951,43 +950,9 @@ const docTemplate = `{
                                 "type": "string"
                             }
                         }
-                    }
-                }
-            }
-        },
-        "/part-categories": {
-            "get": {
-                "description": "Retrieves all part categories with optional parent-child relationships",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Part Categories"
-                ],
-                "summary": "Get all part categories",
-                "parameters": [
-                    {
-                        "type": "boolean",
-                        "description": "Whether to include child categories recursively",
-                        "name": "recursive",
-                        "in": "query"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.PartCategory"
-                            }
-                        }
                     },
                     "500": {
-                        "description": "Error message",
+                        "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -996,9 +961,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
-                "description": "Creates a new part category",
+            }
+        },
+        "/builds/{id}/compatibility": {
+            "get": {
+                "description": "Check every pair of parts in a saved build against declared fitments, the attribute compatibility rules and firearm model linkage",
                 "consumes": [
                     "application/json"
                 ],
@@ -1006,29 +973,34 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Part Categories"
+                    "Builds",
+                    "Compatibility"
                 ],
-                "summary": "Create a new part category",
+                "summary": "Check compatibility of a build",
                 "parameters": [
                     {
-                        "description": "Part Category object",
-                        "name": "category",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.PartCategory"
-                        }
+                        "type": "integer",
+                        "description": "Build ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include every pair, not only incompatible pairs and pairs a rule applied to",
+                        "name": "all",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.PartCategory"
+                            "$ref": "#/definitions/handlers.BuildCompatibilityResult"
                         }
                     },
-                    "400": {
-                        "description": "Invalid input",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1037,7 +1009,7 @@ const docTemplate = `{
                         }
                     },
                     "500": {
-                        "description": "Server error",
+                        "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1048,9 +1020,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/part-categories/firearm/{id}": {
+        "/builds/{id}/cost": {
             "get": {
-                "description": "Retrieves all part categories associated with a specific firearm model",
+                "description": "Price a saved build using the cheapest in-stock listing for each part, including shipping. Given a destination state, listings that cannot ship there are skipped and listings that must ship to an FFL are flagged.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1058,37 +1030,33 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Firearm Models",
-                    "Part Categories"
+                    "Builds"
                 ],
-                "summary": "Get part categories for a firearm model",
+                "summary": "Get build cost",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Firearm Model ID",
+                        "description": "Build ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "type": "boolean",
-                        "description": "Filter by required status (true=required, false=optional, omit=both)",
-                        "name": "required",
+                        "type": "string",
+                        "description": "Destination state, e.g. CA or US-CA",
+                        "name": "ship_to",
                         "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Array of part categories with required status",
+                        "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "type": "object"
-                            }
+                            "$ref": "#/definitions/handlers.BuildCostResult"
                         }
                     },
                     "400": {
-                        "description": "Invalid firearm model ID",
+                        "description": "Bad Request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1097,7 +1065,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Firearm model not found",
+                        "description": "Not Found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1106,7 +1074,7 @@ const docTemplate = `{
                         }
                     },
                     "500": {
-                        "description": "Server error",
+                        "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1117,9 +1085,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/part-categories/{id}": {
+        "/builds/{id}/diff": {
             "get": {
-                "description": "Retrieves a single part category by ID",
+                "description": "List per-slot additions, removals and swaps between two revisions of a build, with price and weight deltas. Defaults to the previous and latest revisions.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1127,27 +1095,39 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Part Categories"
+                    "Builds"
                 ],
-                "summary": "Get part category by ID",
+                "summary": "Diff two revisions of a build",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Part Category ID",
+                        "description": "Build ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Revision to diff from (defaults to the one before 'to')",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Revision to diff to (defaults to the latest)",
+                        "name": "to",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.PartCategory"
+                            "$ref": "#/definitions/handlers.BuildDiff"
                         }
                     },
                     "400": {
-                        "description": "Invalid ID format",
+                        "description": "Bad Request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1156,7 +1136,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Category not found",
+                        "description": "Not Found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1165,7 +1145,7 @@ const docTemplate = `{
                         }
                     },
                     "500": {
-                        "description": "Server error",
+                        "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1174,46 +1154,56 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
-                "description": "Updates an existing part category",
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/builds/{id}/export": {
+            "get": {
+                "description": "Export a build as a bill of materials with category path, part, manufacturer, SKU, chosen seller, price and affiliate link for each slot. Given a destination state, only listings that can ship there are chosen and those that must ship to an FFL are flagged.",
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/csv",
+                    "text/markdown"
                 ],
                 "tags": [
-                    "Part Categories"
+                    "Builds"
                 ],
-                "summary": "Update a part category",
+                "summary": "Export a build's bill of materials",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Part Category ID",
+                        "description": "Build ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Updated Part Category object",
-                        "name": "category",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.PartCategory"
-                        }
+                        "enum": [
+                            "json",
+                            "csv",
+                            "markdown"
+                        ],
+                        "type": "string",
+                        "default": "json",
+                        "description": "Export format",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Destination state, e.g. CA or US-CA",
+                        "name": "ship_to",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.PartCategory"
+                            "$ref": "#/definitions/handlers.BOM"
                         }
                     },
                     "400": {
-                        "description": "Invalid input",
+                        "description": "Bad Request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1222,7 +1212,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Category not found",
+                        "description": "Not Found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1231,7 +1221,7 @@ const docTemplate = `{
                         }
                     },
                     "500": {
-                        "description": "Server error",
+                        "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1240,9 +1230,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Deletes a part category",
+            }
+        },
+        "/builds/{id}/magazines": {
+            "get": {
+                "description": "Get the magazine variants whose family a saved build's firearm model takes and that feed the caliber the build is chambered in",
                 "consumes": [
                     "application/json"
                 ],
@@ -1250,13 +1242,14 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Part Categories"
+                    "Builds",
+                    "Magazines"
                 ],
-                "summary": "Delete a part category",
+                "summary": "Get the magazines that fit a build",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Part Category ID",
+                        "description": "Build ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -1264,25 +1257,13 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Success message",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    },
-                    "400": {
-                        "description": "Invalid ID format",
+                        "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/handlers.BuildMagazinesResult"
                         }
                     },
                     "404": {
-                        "description": "Category not found",
+                        "description": "Not Found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1291,7 +1272,7 @@ const docTemplate = `{
                         }
                     },
                     "500": {
-                        "description": "Server error",
+                        "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1302,9 +1283,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/part-hierarchy": {
+        "/builds/{id}/nfa": {
             "get": {
-                "description": "Get a hierarchical view of part categories",
+                "description": "Derive a saved build's federal classification (rifle, short-barreled rifle, pistol, any other weapon, shotgun, short-barreled shotgun or machine gun) from its barrel length, stock and the NFA tags of its parts, with a warning for each NFA-regulated configuration or item",
                 "consumes": [
                     "application/json"
                 ],
@@ -1312,73 +1293,37 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Parts"
+                    "Builds",
+                    "Laws"
                 ],
-                "summary": "Get part hierarchy",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/handlers.PartItem"
-                            }
-                        }
-                    }
-                }
-            }
-        },
-        "/parts": {
-            "get": {
-                "description": "Get all parts with optional filtering by category, subcategory, or category_id",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Parts"
-                ],
-                "summary": "Get all parts",
+                "summary": "Classify a build under the NFA",
                 "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Filter by category name",
-                        "name": "category",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Filter by subcategory name",
-                        "name": "subcategory",
-                        "in": "query"
-                    },
                     {
                         "type": "integer",
-                        "description": "Filter by part category ID (new schema)",
-                        "name": "category_id",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "Filter by prebuilt status",
-                        "name": "is_prebuilt",
-                        "in": "query"
+                        "description": "Build ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Part"
+                            "$ref": "#/definitions/handlers.NFAClassification"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
                             }
                         }
                     },
                     "500": {
-                        "description": "Server error",
+                        "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1387,9 +1332,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
-                "description": "Create a new part in the database",
+            }
+        },
+        "/builds/{id}/rail-space": {
+            "get": {
+                "description": "Allocate the rail space consumed by a saved build's accessories to the rail sections its parts provide, reporting each section's usage and any accessory that does not fit",
                 "consumes": [
                     "application/json"
                 ],
@@ -1397,29 +1344,27 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Parts"
+                    "Builds"
                 ],
-                "summary": "Create a part",
+                "summary": "Get build rail space",
                 "parameters": [
                     {
-                        "description": "Part object to create",
-                        "name": "part",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.Part"
-                        }
+                        "type": "integer",
+                        "description": "Build ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Part"
+                            "$ref": "#/definitions/handlers.BuildRailSpaceResult"
                         }
                     },
-                    "400": {
-                        "description": "Invalid input",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1428,7 +1373,7 @@ const docTemplate = `{
                         }
                     },
                     "500": {
-                        "description": "Server error",
+                        "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1439,9 +1384,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/parts/category/{category}": {
+        "/builds/{id}/revisions": {
             "get": {
-                "description": "Get all parts in a specific category",
+                "description": "Get the append-only revision history of a build, oldest first",
                 "consumes": [
                     "application/json"
                 ],
@@ -1449,14 +1394,14 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Parts"
+                    "Builds"
                 ],
-                "summary": "Get parts by category",
+                "summary": "Get build revisions",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Category name",
-                        "name": "category",
+                        "type": "integer",
+                        "description": "Build ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
@@ -1467,12 +1412,12 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/models.Part"
+                                "$ref": "#/definitions/models.BuildRevision"
                             }
                         }
                     },
-                    "500": {
-                        "description": "Server error",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1483,9 +1428,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/parts/compatible-models": {
+        "/builds/{id}/revisions/{revision}": {
             "get": {
-                "description": "Get a list of unique firearm models that parts are compatible with",
+                "description": "Get a single revision of a build",
                 "consumes": [
                     "application/json"
                 ],
@@ -1493,15 +1438,37 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Parts"
+                    "Builds"
+                ],
+                "summary": "Get a build revision",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Build ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Revision number",
+                        "name": "revision",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get compatible firearm models",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
+                            "$ref": "#/definitions/models.BuildRevision"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
                                 "type": "string"
                             }
                         }
@@ -1509,9 +1476,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/parts/{id}": {
+        "/builds/{id}/weight": {
             "get": {
-                "description": "Get a specific part by its ID",
+                "description": "Sum the weight of a saved build's parts, grouped by top-level part category, flagging parts with no weight recorded",
                 "consumes": [
                     "application/json"
                 ],
@@ -1519,13 +1486,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Parts"
+                    "Builds"
                 ],
-                "summary": "Get part by ID",
+                "summary": "Get build weight",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Part ID",
+                        "description": "Build ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -1535,11 +1502,11 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Part"
+                            "$ref": "#/definitions/handlers.BuildWeightResult"
                         }
                     },
-                    "400": {
-                        "description": "Invalid ID format",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1547,17 +1514,51 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "404": {
-                        "description": "Part not found",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
                                 "type": "string"
                             }
                         }
+                    }
+                }
+            }
+        },
+        "/calibers": {
+            "get": {
+                "description": "Get a list of all calibers, optionally only those matching a name or alias",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Calibers"
+                ],
+                "summary": "Get all calibers",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Case-insensitive caliber name or alias, e.g. 5.56 NATO",
+                        "name": "q",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Caliber"
+                            }
+                        }
                     },
                     "500": {
-                        "description": "Server error",
+                        "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1567,8 +1568,8 @@ const docTemplate = `{
                     }
                 }
             },
-            "put": {
-                "description": "Update an existing part",
+            "post": {
+                "description": "Add a new caliber",
                 "consumes": [
                     "application/json"
                 ],
@@ -1576,36 +1577,29 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Parts"
+                    "Calibers"
                 ],
-                "summary": "Update a part",
+                "summary": "Create a caliber",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Part ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Updated part object",
-                        "name": "part",
+                        "description": "Caliber Info",
+                        "name": "caliber",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.Part"
+                            "$ref": "#/definitions/handlers.CaliberInput"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/models.Part"
+                            "$ref": "#/definitions/models.Caliber"
                         }
                     },
                     "400": {
-                        "description": "Invalid input",
+                        "description": "Bad Request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1613,8 +1607,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "404": {
-                        "description": "Part not found",
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1623,7 +1617,7 @@ const docTemplate = `{
                         }
                     },
                     "500": {
-                        "description": "Server error",
+                        "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1632,9 +1626,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Delete a specific part",
+            }
+        },
+        "/calibers/{id}": {
+            "get": {
+                "description": "Get a caliber with its parent cartridge and the calibers its chamber can fire",
                 "consumes": [
                     "application/json"
                 ],
@@ -1642,13 +1638,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Parts"
+                    "Calibers"
                 ],
-                "summary": "Delete a part",
+                "summary": "Get a caliber by ID",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Part ID",
+                        "description": "Caliber ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -1656,34 +1652,13 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Success message",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    },
-                    "400": {
-                        "description": "Invalid ID format",
+                        "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/models.Caliber"
                         }
                     },
                     "404": {
-                        "description": "Part not found",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    },
-                    "500": {
-                        "description": "Server error",
+                        "description": "Not Found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1692,11 +1667,9 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/parts/{id}/compatible": {
-            "get": {
-                "description": "Get all parts compatible with a specific part",
+            },
+            "put": {
+                "description": "Update a caliber, replacing its aliases and the calibers its chamber can fire",
                 "consumes": [
                     "application/json"
                 ],
@@ -1704,31 +1677,36 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Parts",
-                    "Compatibility"
+                    "Calibers"
                 ],
-                "summary": "Get compatible parts",
+                "summary": "Update a caliber",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Part ID",
+                        "description": "Caliber ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Updated Caliber Info",
+                        "name": "caliber",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CaliberInput"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Part"
-                            }
+                            "$ref": "#/definitions/models.Caliber"
                         }
                     },
                     "400": {
-                        "description": "Invalid ID format",
+                        "description": "Bad Request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1737,7 +1715,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Part not found",
+                        "description": "Not Found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1745,75 +1723,17 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "500": {
-                        "description": "Server error",
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
                                 "type": "string"
                             }
                         }
-                    }
-                }
-            }
-        },
-        "/prebuilt-firearms": {
-            "get": {
-                "description": "Get a list of all prebuilt firearms in the database",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Prebuilt Firearms"
-                ],
-                "summary": "Get all prebuilt firearms",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.PrebuiltFirearm"
-                            }
-                        }
-                    }
-                }
-            },
-            "post": {
-                "description": "Add a new prebuilt firearm to the database",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Prebuilt Firearms"
-                ],
-                "summary": "Create a new prebuilt firearm",
-                "parameters": [
-                    {
-                        "description": "Prebuilt Firearm Info",
-                        "name": "firearm",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.PrebuiltFirearm"
-                        }
-                    }
-                ],
-                "responses": {
-                    "201": {
-                        "description": "Created",
-                        "schema": {
-                            "$ref": "#/definitions/models.PrebuiltFirearm"
-                        }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1822,46 +1742,9 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/prebuilt-firearms/model/{modelId}": {
-            "get": {
-                "description": "Get all prebuilt firearms for a specific firearm model",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Prebuilt Firearms"
-                ],
-                "summary": "Get prebuilt firearms by model",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Firearm Model ID",
-                        "name": "modelId",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.PrebuiltFirearm"
-                            }
-                        }
-                    }
-                }
-            }
-        },
-        "/prebuilt-firearms/{id}": {
-            "get": {
-                "description": "Get details of a specific prebuilt firearm",
+            },
+            "delete": {
+                "description": "Delete a caliber that no firearm model, prebuilt firearm, part or magazine variant references",
                 "consumes": [
                     "application/json"
                 ],
@@ -1869,24 +1752,21 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Prebuilt Firearms"
+                    "Calibers"
                 ],
-                "summary": "Get a prebuilt firearm by ID",
+                "summary": "Delete a caliber",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Prebuilt Firearm ID",
+                        "description": "Caliber ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/models.PrebuiltFirearm"
-                        }
+                    "204": {
+                        "description": "No Content"
                     },
                     "404": {
                         "description": "Not Found",
@@ -1896,11 +1776,22 @@ const docTemplate = `{
                                 "type": "string"
                             }
                         }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
                     }
                 }
-            },
-            "put": {
-                "description": "Update details of a specific prebuilt firearm",
+            }
+        },
+        "/cart/optimize": {
+            "post": {
+                "description": "Choose the sellers for a set of parts that minimize total landed cost. Shipping is charged once per seller, so consolidating parts at one seller can beat buying each part at its cheapest listing. Given a destination state, listings that cannot ship there are skipped and items that must ship to an FFL are flagged.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1908,24 +1799,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Prebuilt Firearms"
+                    "Cart"
                 ],
-                "summary": "Update a prebuilt firearm",
+                "summary": "Optimize a cart",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Prebuilt Firearm ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Updated Prebuilt Firearm Info",
-                        "name": "firearm",
+                        "description": "Parts to buy",
+                        "name": "cart",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.PrebuiltFirearm"
+                            "$ref": "#/definitions/handlers.CartOptimizeInput"
                         }
                     }
                 ],
@@ -1933,7 +1817,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.PrebuiltFirearm"
+                            "$ref": "#/definitions/handlers.CartResult"
                         }
                     },
                     "400": {
@@ -1945,8 +1829,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1955,9 +1839,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Delete a specific prebuilt firearm",
+            }
+        },
+        "/compatibility-rules": {
+            "get": {
+                "description": "Get all attribute-based compatibility rules, optionally filtered to those involving a part category",
                 "consumes": [
                     "application/json"
                 ],
@@ -1965,61 +1851,31 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Prebuilt Firearms"
+                    "Compatibility"
                 ],
-                "summary": "Delete a prebuilt firearm",
+                "summary": "Get compatibility rules",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Prebuilt Firearm ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "204": {
-                        "description": "No Content"
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
+                        "description": "Only rules whose source or target is this part category",
+                        "name": "category_id",
+                        "in": "query"
                     }
-                }
-            }
-        },
-        "/sellers": {
-            "get": {
-                "description": "Get a list of all sellers in the database",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Sellers"
                 ],
-                "summary": "Get all sellers",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/models.Seller"
+                                "$ref": "#/definitions/models.CompatibilityRule"
                             }
                         }
                     }
                 }
             },
             "post": {
-                "description": "Add a new seller to the database",
+                "description": "Create a rule comparing an attribute of parts in one category with an attribute of parts in another. Exceptions are managed through rulesets.",
                 "consumes": [
                     "application/json"
                 ],
@@ -2027,17 +1883,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Sellers"
+                    "Compatibility"
                 ],
-                "summary": "Create a new seller",
+                "summary": "Create a compatibility rule",
                 "parameters": [
                     {
-                        "description": "Seller Info",
-                        "name": "seller",
+                        "description": "Rule to create",
+                        "name": "rule",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.Seller"
+                            "$ref": "#/definitions/models.CompatibilityRule"
                         }
                     }
                 ],
@@ -2045,7 +1901,7 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/models.Seller"
+                            "$ref": "#/definitions/models.CompatibilityRule"
                         }
                     },
                     "400": {
@@ -2056,13 +1912,22 @@ const docTemplate = `{
                                 "type": "string"
                             }
                         }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
                     }
                 }
             }
         },
-        "/sellers/{id}": {
+        "/compatibility-rules/{id}": {
             "get": {
-                "description": "Get details of a specific seller",
+                "description": "Get a specific compatibility rule",
                 "consumes": [
                     "application/json"
                 ],
@@ -2070,13 +1935,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Sellers"
+                    "Compatibility"
                 ],
-                "summary": "Get a seller by ID",
+                "summary": "Get compatibility rule by ID",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Seller ID",
+                        "description": "Rule ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -2086,7 +1951,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Seller"
+                            "$ref": "#/definitions/models.CompatibilityRule"
                         }
                     },
                     "404": {
@@ -2101,7 +1966,7 @@ const docTemplate = `{
                 }
             },
             "put": {
-                "description": "Update details of a specific seller",
+                "description": "Update an existing compatibility rule",
                 "consumes": [
                     "application/json"
                 ],
@@ -2109,24 +1974,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Sellers"
+                    "Compatibility"
                 ],
-                "summary": "Update a seller",
+                "summary": "Update a compatibility rule",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Seller ID",
+                        "description": "Rule ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Updated Seller Info",
-                        "name": "seller",
+                        "description": "Updated rule",
+                        "name": "rule",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.Seller"
+                            "$ref": "#/definitions/models.CompatibilityRule"
                         }
                     }
                 ],
@@ -2134,7 +1999,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Seller"
+                            "$ref": "#/definitions/models.CompatibilityRule"
                         }
                     },
                     "400": {
@@ -2154,11 +2019,20 @@ const docTemplate = `{
                                 "type": "string"
                             }
                         }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
                     }
                 }
             },
             "delete": {
-                "description": "Delete a specific seller",
+                "description": "Delete a specific compatibility rule",
                 "consumes": [
                     "application/json"
                 ],
@@ -2166,13 +2040,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Sellers"
+                    "Compatibility"
                 ],
-                "summary": "Delete a seller",
+                "summary": "Delete a compatibility rule",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Seller ID",
+                        "description": "Rule ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -2194,9 +2068,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/sellers/{id}/affiliate-status": {
-            "patch": {
-                "description": "Update the affiliate status of a specific seller",
+        "/compatibility/check": {
+            "get": {
+                "description": "Return a verdict for part_b from part_a's point of view with the reasons behind it: the firearm model linkage between their categories, the overlap of their declared fitments and every attribute rule that applied",
                 "consumes": [
                     "application/json"
                 ],
@@ -2204,32 +2078,30 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Sellers"
+                    "Compatibility"
                 ],
-                "summary": "Update seller affiliate status",
+                "summary": "Explain compatibility of two parts",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Seller ID",
-                        "name": "id",
-                        "in": "path",
+                        "description": "Part ID to check from",
+                        "name": "part_a",
+                        "in": "query",
                         "required": true
                     },
                     {
-                        "description": "Affiliate Status Update Info",
-                        "name": "status",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "type": "object"
-                        }
+                        "type": "integer",
+                        "description": "Part ID to check against",
+                        "name": "part_b",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Seller"
+                            "$ref": "#/definitions/handlers.CompatibilityVerdict"
                         }
                     },
                     "400": {
@@ -2249,37 +2121,20 @@ const docTemplate = `{
                                 "type": "string"
                             }
                         }
-                    }
-                }
-            }
-        },
-        "/user-suggestions": {
-            "get": {
-                "description": "Get a list of all user suggestions in the database",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "User Suggestions"
-                ],
-                "summary": "Get all user suggestions",
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.UserSuggestion"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
                             }
                         }
                     }
                 }
             },
             "post": {
-                "description": "Add a new user suggestion to the database",
+                "description": "Check every pair in a set of parts against declared fitments, the attribute compatibility rules and firearm model linkage",
                 "consumes": [
                     "application/json"
                 ],
@@ -2287,25 +2142,31 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "User Suggestions"
+                    "Compatibility"
                 ],
-                "summary": "Create a new user suggestion",
+                "summary": "Check compatibility of a set of parts",
                 "parameters": [
                     {
-                        "description": "User Suggestion Info",
-                        "name": "suggestion",
+                        "description": "Parts to check",
+                        "name": "parts",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.UserSuggestion"
+                            "$ref": "#/definitions/handlers.BuildCompatibilityInput"
                         }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include every pair, not only incompatible pairs and pairs a rule applied to",
+                        "name": "all",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.UserSuggestion"
+                            "$ref": "#/definitions/handlers.BuildCompatibilityResult"
                         }
                     },
                     "400": {
@@ -2316,13 +2177,31 @@ const docTemplate = `{
                                 "type": "string"
                             }
                         }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
                     }
                 }
             }
         },
-        "/user-suggestions/status/{status}": {
+        "/firearm-models": {
             "get": {
-                "description": "Get all user suggestions with a specific status",
+                "description": "Get a list of all firearm models in the database, optionally only those chambered in a caliber",
                 "consumes": [
                     "application/json"
                 ],
@@ -2330,21 +2209,15 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "User Suggestions"
+                    "Firearm Models"
                 ],
-                "summary": "Get user suggestions by status",
+                "summary": "Get all firearm models",
                 "parameters": [
                     {
-                        "enum": [
-                            "pending",
-                            "approved",
-                            "rejected"
-                        ],
                         "type": "string",
-                        "description": "Suggestion Status",
-                        "name": "status",
-                        "in": "path",
-                        "required": true
+                        "description": "Filter by caliber ID, name or alias",
+                        "name": "caliber",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -2353,16 +2226,66 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/models.UserSuggestion"
+                                "$ref": "#/definitions/models.FirearmModel"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Add a new firearm model to the database",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Firearm Models"
+                ],
+                "summary": "Create a new firearm model",
+                "parameters": [
+                    {
+                        "description": "Firearm Model Info",
+                        "name": "model",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.FirearmModel"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.FirearmModel"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
                             }
                         }
                     }
                 }
             }
         },
-        "/user-suggestions/{id}": {
+        "/firearm-models/{id}": {
             "get": {
-                "description": "Get details of a specific user suggestion",
+                "description": "Get details of a specific firearm model",
                 "consumes": [
                     "application/json"
                 ],
@@ -2370,13 +2293,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "User Suggestions"
+                    "Firearm Models"
                 ],
-                "summary": "Get a user suggestion by ID",
+                "summary": "Get a firearm model by ID",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "User Suggestion ID",
+                        "description": "Firearm Model ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -2386,7 +2309,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.UserSuggestion"
+                            "$ref": "#/definitions/models.FirearmModel"
                         }
                     },
                     "404": {
@@ -2401,7 +2324,7 @@ const docTemplate = `{
                 }
             },
             "put": {
-                "description": "Update details of a specific user suggestion",
+                "description": "Update details of a specific firearm model",
                 "consumes": [
                     "application/json"
                 ],
@@ -2409,24 +2332,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "User Suggestions"
+                    "Firearm Models"
                 ],
-                "summary": "Update a user suggestion",
+                "summary": "Update a firearm model",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "User Suggestion ID",
+                        "description": "Firearm Model ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Updated User Suggestion Info",
-                        "name": "suggestion",
+                        "description": "Updated Firearm Model Info",
+                        "name": "model",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.UserSuggestion"
+                            "$ref": "#/definitions/models.FirearmModel"
                         }
                     }
                 ],
@@ -2434,7 +2357,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.UserSuggestion"
+                            "$ref": "#/definitions/models.FirearmModel"
                         }
                     },
                     "400": {
@@ -2458,7 +2381,7 @@ const docTemplate = `{
                 }
             },
             "delete": {
-                "description": "Delete a specific user suggestion",
+                "description": "Delete a specific firearm model. Law rules limited to the model must be deleted or changed first, so their history records who did it.",
                 "consumes": [
                     "application/json"
                 ],
@@ -2466,13 +2389,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "User Suggestions"
+                    "Firearm Models"
                 ],
-                "summary": "Delete a user suggestion",
+                "summary": "Delete a firearm model",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "User Suggestion ID",
+                        "description": "Firearm Model ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -2490,13 +2413,31 @@ const docTemplate = `{
                                 "type": "string"
                             }
                         }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
                     }
                 }
             }
         },
-        "/user-suggestions/{id}/status": {
-            "patch": {
-                "description": "Update the status of a specific user suggestion",
+        "/firearm-models/{id}/categories": {
+            "get": {
+                "description": "Retrieves all part categories associated with a specific firearm model",
                 "consumes": [
                     "application/json"
                 ],
@@ -2504,36 +2445,37 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "User Suggestions"
+                    "Firearm Models",
+                    "Part Categories"
                 ],
-                "summary": "Update user suggestion status",
+                "summary": "Get part categories for a firearm model",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "User Suggestion ID",
+                        "description": "Firearm Model ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Status Update Info",
-                        "name": "status",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "type": "object"
-                        }
+                        "type": "boolean",
+                        "description": "Filter by required status (true=required, false=optional, omit=both)",
+                        "name": "required",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Array of part categories with required status",
                         "schema": {
-                            "$ref": "#/definitions/models.UserSuggestion"
+                            "type": "array",
+                            "items": {
+                                "type": "object"
+                            }
                         }
                     },
                     "400": {
-                        "description": "Bad Request",
+                        "description": "Invalid firearm model ID",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -2542,7 +2484,16 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Not Found",
+                        "description": "Firearm model not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -2552,152 +2503,8700 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "handlers.CategoryWithRequiredStatusHierarchy": {
-            "type": "object",
-            "properties": {
-                "child_categories": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/handlers.CategoryWithRequiredStatusHierarchy"
+        },
+        "/firearm-models/{id}/categories-hierarchy": {
+            "get": {
+                "description": "Retrieves all part categories associated with a specific firearm model in a hierarchical structure",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Firearm Models",
+                    "Part Categories"
+                ],
+                "summary": "Get hierarchical categories for a firearm model",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Firearm Model ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
-                },
-                "created_at": {
-                    "description": "Creation timestamp",
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Hierarchical array of part categories with required status",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handlers.CategoryWithRequiredStatusHierarchy"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid firearm model ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Firearm model not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/firearm-models/{id}/categories/{category_id}": {
+            "put": {
+                "description": "Updates the relationship between a firearm model and a part category",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Firearm Models",
+                    "Part Categories"
+                ],
+                "summary": "Update a firearm model-category relationship",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Firearm Model ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Part Category ID",
+                        "name": "category_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated relationship parameters",
+                        "name": "relationship",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success message",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Relationship not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Associates a part category with a firearm model",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Firearm Models",
+                    "Part Categories"
+                ],
+                "summary": "Add a category to a firearm model",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Firearm Model ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Part Category ID",
+                        "name": "category_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Relationship parameters",
+                        "name": "relationship",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Success message",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Firearm model or part category not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Relationship already exists",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Removes the association between a part category and a firearm model",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Firearm Models",
+                    "Part Categories"
+                ],
+                "summary": "Remove a category from a firearm model",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Firearm Model ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Part Category ID",
+                        "name": "category_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success message",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Relationship not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/firearm-models/{id}/parts": {
+            "get": {
+                "description": "Retrieves the parts explicitly declared to fit a firearm model, with fitment notes",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Firearm Models",
+                    "Parts"
+                ],
+                "summary": "Get parts that fit a firearm model",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Firearm Model ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only parts in this part category or its subcategories",
+                        "name": "category_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Filter by whether fitting requires modification",
+                        "name": "requires_modification",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.PartFirearmModel"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid firearm model ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Firearm model not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/jurisdictions": {
+            "get": {
+                "description": "Get the federal and state jurisdictions whose firearm laws can be checked, optionally only the children of one",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Laws"
+                ],
+                "summary": "Get all jurisdictions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only jurisdictions under this parent code or ID",
+                        "name": "parent",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Jurisdiction"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Add a new jurisdiction, normally a state under the federal jurisdiction",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Laws"
+                ],
+                "summary": "Create a jurisdiction",
+                "parameters": [
+                    {
+                        "description": "Jurisdiction to create",
+                        "name": "jurisdiction",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Jurisdiction"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Jurisdiction"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/jurisdictions/{id}": {
+            "get": {
+                "description": "Get a specific jurisdiction by code, e.g. US-CA, or ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Laws"
+                ],
+                "summary": "Get a jurisdiction",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Jurisdiction code or ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Jurisdiction"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update an existing jurisdiction",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Laws"
+                ],
+                "summary": "Update a jurisdiction",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Jurisdiction code or ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated jurisdiction",
+                        "name": "jurisdiction",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Jurisdiction"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Jurisdiction"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a jurisdiction that has no law rules and no child jurisdictions",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Laws"
+                ],
+                "summary": "Delete a jurisdiction",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Jurisdiction code or ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/law-rule-changes": {
+            "get": {
+                "description": "Get changes to law rules across jurisdictions, newest first, optionally filtered by jurisdiction, author or date",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Laws"
+                ],
+                "summary": "Get the law rule audit trail",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only changes to rules of this jurisdiction code or ID",
+                        "name": "jurisdiction",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only changes made by this person or system",
+                        "name": "changed_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only changes made on or after this date, YYYY-MM-DD or RFC 3339",
+                        "name": "since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of changes to return (default 100, max 1000)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.LawRuleChange"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/law-rules": {
+            "get": {
+                "description": "Get the firearm law rules of every jurisdiction, or of one jurisdiction without those it inherits, optionally only those in effect on a date",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Laws"
+                ],
+                "summary": "Get all law rules",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only rules of this jurisdiction code or ID",
+                        "name": "jurisdiction",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only rules in effect on this date, YYYY-MM-DD or RFC 3339",
+                        "name": "as_of",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.LawRule"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Add a firearm law rule to a jurisdiction at version 1 and record who added it in the rule's history",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Laws"
+                ],
+                "summary": "Create a law rule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Who is making the change; free text, not authenticated",
+                        "name": "X-User",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Why the change is made",
+                        "name": "X-Change-Reason",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Law rule to create",
+                        "name": "rule",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.LawRule"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.LawRule"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/law-rules/{id}": {
+            "get": {
+                "description": "Get a specific law rule with its jurisdiction",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Laws"
+                ],
+                "summary": "Get a law rule by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Law Rule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.LawRule"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update an existing law rule, increasing its version and recording what changed in the rule's history along with the author given in X-User. X-User is not authenticated, so the history records who the caller says they are. Fields left out of the body keep their values; a version in the body must match the current one, so edits based on a stale copy are refused. To schedule a change in the law, set effective_until on this rule and add the new rule with a matching effective_from instead.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Laws"
+                ],
+                "summary": "Update a law rule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Who is making the change; free text, not authenticated",
+                        "name": "X-User",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Why the change is made",
+                        "name": "X-Change-Reason",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Law Rule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated law rule",
+                        "name": "rule",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.LawRule"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.LawRule"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a law rule that was entered in error, keeping its history. Laws that are repealed should be given an effective_until date instead, so checks as of earlier dates still apply them.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Laws"
+                ],
+                "summary": "Delete a law rule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Who is making the change; free text, not authenticated",
+                        "name": "X-User",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Why the change is made",
+                        "name": "X-Change-Reason",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Law Rule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/law-rules/{id}/history": {
+            "get": {
+                "description": "Get every recorded change to a law rule, oldest first, including for rules that have since been deleted",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Laws"
+                ],
+                "summary": "Get the history of a law rule",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Law Rule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.LawRuleChange"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/laws/check": {
+            "get": {
+                "description": "Evaluate a saved build or a prebuilt firearm against the law rules of a jurisdiction and its parents, e.g. federal law for a state, in effect on a date, and list each violated rule with its citation. Rules that cannot be decided from the recorded data are listed as undetermined, and rules taking effect later that the firearm would violate are listed as upcoming.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Laws"
+                ],
+                "summary": "Check a build or prebuilt firearm against a jurisdiction's laws",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Jurisdiction code or ID, e.g. US-CA",
+                        "name": "jurisdiction",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Build to check",
+                        "name": "build_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Prebuilt firearm to check",
+                        "name": "prebuilt_firearm_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Date to check the laws as of, YYYY-MM-DD or RFC 3339; defaults to now",
+                        "name": "as_of",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.LawCheckResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Evaluate an unsaved slot selection against the law rules of a jurisdiction and its parents in effect on a date and list each violated rule with its citation, and the upcoming rules it would violate",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Laws"
+                ],
+                "summary": "Check a slot selection against a jurisdiction's laws",
+                "parameters": [
+                    {
+                        "description": "Jurisdiction and slot selection",
+                        "name": "selection",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.LawCheckInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.LawCheckResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/listings": {
+            "get": {
+                "description": "Get a list of all product listings in the database, optionally only those that can ship to a state, with their shipping status there",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Product Listings"
+                ],
+                "summary": "Get all product listings",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Leave out listings that cannot ship to this state and flag those that must ship to an FFL, e.g. CA or US-CA",
+                        "name": "ship_to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handlers.ProductListingWithShipping"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Add a new product listing to the database",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Product Listings"
+                ],
+                "summary": "Create a new product listing",
+                "parameters": [
+                    {
+                        "description": "Product Listing Info",
+                        "name": "listing",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ProductListing"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProductListing"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/listings/part/{partId}": {
+            "get": {
+                "description": "Get all product listings for a specific part, optionally only those that can ship to a state, with their shipping status there",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Product Listings"
+                ],
+                "summary": "Get listings by part ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Part ID",
+                        "name": "partId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Leave out listings that cannot ship to this state and flag those that must ship to an FFL, e.g. CA or US-CA",
+                        "name": "ship_to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handlers.ProductListingWithShipping"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/listings/prebuilt/{prebuiltId}": {
+            "get": {
+                "description": "Get all product listings for a specific prebuilt firearm, optionally only those that can ship to a state, with their shipping status there",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Product Listings"
+                ],
+                "summary": "Get listings by prebuilt ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Prebuilt Firearm ID",
+                        "name": "prebuiltId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Leave out listings that cannot ship to this state and flag those that must ship to an FFL, e.g. CA or US-CA",
+                        "name": "ship_to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handlers.ProductListingWithShipping"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/listings/seller/{sellerId}": {
+            "get": {
+                "description": "Get all product listings for a specific seller, optionally only those that can ship to a state, with their shipping status there",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Product Listings"
+                ],
+                "summary": "Get listings by seller",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Seller ID",
+                        "name": "sellerId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Leave out listings that cannot ship to this state and flag those that must ship to an FFL, e.g. CA or US-CA",
+                        "name": "ship_to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handlers.ProductListingWithShipping"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/listings/{id}": {
+            "get": {
+                "description": "Get details of a specific product listing",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Product Listings"
+                ],
+                "summary": "Get a product listing by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Product Listing ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProductListing"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update details of a specific product listing",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Product Listings"
+                ],
+                "summary": "Update a product listing",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Product Listing ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated Product Listing Info",
+                        "name": "listing",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ProductListing"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProductListing"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a specific product listing",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Product Listings"
+                ],
+                "summary": "Delete a product listing",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Product Listing ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/listings/{id}/availability": {
+            "patch": {
+                "description": "Update the availability and optionally the price of a specific listing",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Product Listings"
+                ],
+                "summary": "Update listing availability",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Product Listing ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Availability Update Info",
+                        "name": "availability",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProductListing"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/listings/{id}/shipping": {
+            "get": {
+                "description": "Apply the shipping restrictions of a listing and its seller to a destination state and report whether the listing can ship there and whether it must go through an FFL",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Product Listings",
+                    "Shipping Restrictions"
+                ],
+                "summary": "Check whether a listing ships to a state",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Product Listing ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Destination state code, e.g. CA or US-CA",
+                        "name": "ship_to",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ListingShippingStatus"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/magazine-compatibility": {
+            "get": {
+                "description": "Get one row per magazine family, firearm model and caliber with the capacities available, optionally for a single firearm model or caliber",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Magazines"
+                ],
+                "summary": "Get the magazine compatibility matrix",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Only rows for this firearm model",
+                        "name": "firearm_model_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only rows for this caliber ID, name or alias",
+                        "name": "caliber",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handlers.MagazineMatrixRow"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/magazine-families": {
+            "get": {
+                "description": "Get every magazine family with the firearm models that take it, the calibers it feeds and its capacity variants",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Magazines"
+                ],
+                "summary": "Get all magazine families",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.MagazineFamily"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Add a magazine family with the firearm models that take it and the calibers it feeds",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Magazines"
+                ],
+                "summary": "Create a magazine family",
+                "parameters": [
+                    {
+                        "description": "Magazine family",
+                        "name": "family",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.MagazineFamilyInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.MagazineFamily"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/magazine-families/{id}": {
+            "get": {
+                "description": "Get a magazine family with its firearm models, calibers and capacity variants",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Magazines"
+                ],
+                "summary": "Get a magazine family by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Magazine Family ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.MagazineFamily"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update a magazine family, replacing its firearm models and calibers",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Magazines"
+                ],
+                "summary": "Update a magazine family",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Magazine Family ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated magazine family",
+                        "name": "family",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.MagazineFamilyInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.MagazineFamily"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a magazine family together with its capacity variants",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Magazines"
+                ],
+                "summary": "Delete a magazine family",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Magazine Family ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/magazine-families/{id}/variants": {
+            "get": {
+                "description": "Get the capacity variants of a magazine family, smallest first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Magazines"
+                ],
+                "summary": "Get a magazine family's variants",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Magazine Family ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.MagazineVariant"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Add a capacity variant to a magazine family, optionally for one of its calibers and tied to a catalog part",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Magazines"
+                ],
+                "summary": "Add a magazine variant",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Magazine Family ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Magazine variant",
+                        "name": "variant",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.MagazineVariantInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.MagazineVariant"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/magazine-families/{id}/variants/{variant_id}": {
+            "put": {
+                "description": "Update a capacity variant of a magazine family",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Magazines"
+                ],
+                "summary": "Update a magazine variant",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Magazine Family ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Magazine Variant ID",
+                        "name": "variant_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated magazine variant",
+                        "name": "variant",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.MagazineVariantInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.MagazineVariant"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a capacity variant of a magazine family",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Magazines"
+                ],
+                "summary": "Remove a magazine variant",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Magazine Family ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Magazine Variant ID",
+                        "name": "variant_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/mounting-interfaces": {
+            "get": {
+                "description": "Get the accessory mounting standards rail sections can use, e.g. Picatinny, M-LOK and KeyMod",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Mounting Interfaces"
+                ],
+                "summary": "Get all mounting interfaces",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.MountingInterface"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Add a new accessory mounting standard",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Mounting Interfaces"
+                ],
+                "summary": "Create a mounting interface",
+                "parameters": [
+                    {
+                        "description": "Mounting interface to create",
+                        "name": "interface",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.MountingInterface"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.MountingInterface"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/mounting-interfaces/{id}": {
+            "get": {
+                "description": "Get a specific mounting interface",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Mounting Interfaces"
+                ],
+                "summary": "Get a mounting interface by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Mounting Interface ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.MountingInterface"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update an existing mounting interface",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Mounting Interfaces"
+                ],
+                "summary": "Update a mounting interface",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Mounting Interface ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated mounting interface",
+                        "name": "interface",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.MountingInterface"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.MountingInterface"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a mounting interface that no part mount uses",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Mounting Interfaces"
+                ],
+                "summary": "Delete a mounting interface",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Mounting Interface ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/part-categories": {
+            "get": {
+                "description": "Retrieves all part categories with optional parent-child relationships",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Part Categories"
+                ],
+                "summary": "Get all part categories",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Whether to include child categories recursively",
+                        "name": "recursive",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.PartCategory"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Error message",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Creates a new part category",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Part Categories"
+                ],
+                "summary": "Create a new part category",
+                "parameters": [
+                    {
+                        "description": "Part Category object",
+                        "name": "category",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PartCategory"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.PartCategory"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/part-categories/firearm/{id}": {
+            "get": {
+                "description": "Retrieves all part categories associated with a specific firearm model",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Firearm Models",
+                    "Part Categories"
+                ],
+                "summary": "Get part categories for a firearm model",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Firearm Model ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Filter by required status (true=required, false=optional, omit=both)",
+                        "name": "required",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Array of part categories with required status",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "object"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid firearm model ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Firearm model not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/part-categories/{id}": {
+            "get": {
+                "description": "Retrieves a single part category by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Part Categories"
+                ],
+                "summary": "Get part category by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Part Category ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.PartCategory"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID format",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Category not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Updates an existing part category",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Part Categories"
+                ],
+                "summary": "Update a part category",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Part Category ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated Part Category object",
+                        "name": "category",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PartCategory"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.PartCategory"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Category not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Deletes a part category",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Part Categories"
+                ],
+                "summary": "Delete a part category",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Part Category ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success message",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID format",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Category not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/part-categories/{id}/attribute-schema": {
+            "get": {
+                "description": "Get the attribute schema parts in a category must satisfy, including definitions inherited from parent categories",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Part Categories"
+                ],
+                "summary": "Get a part category's attribute schema",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Part Category ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handlers.EffectiveAttribute"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID format",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Category not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/part-hierarchy": {
+            "get": {
+                "description": "Get a hierarchical view of part categories",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Parts"
+                ],
+                "summary": "Get part hierarchy",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handlers.PartItem"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/parts": {
+            "get": {
+                "description": "Get all parts with optional filtering by category, subcategory, category_id or caliber",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Parts"
+                ],
+                "summary": "Get all parts",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter by category name",
+                        "name": "category",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by subcategory name",
+                        "name": "subcategory",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter by part category ID (new schema)",
+                        "name": "category_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Filter by prebuilt status",
+                        "name": "is_prebuilt",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by caliber ID, name or alias",
+                        "name": "caliber",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "With caliber, also include parts for calibers that caliber's chamber can fire",
+                        "name": "include_fired",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Part"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Unknown caliber",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a new part in the database. Its attributes must satisfy the attribute schema of its part category.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Parts"
+                ],
+                "summary": "Create a part",
+                "parameters": [
+                    {
+                        "description": "Part object to create",
+                        "name": "part",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Part"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Part"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Attributes do not satisfy the category schema",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/parts/category/{category}": {
+            "get": {
+                "description": "Get all parts in a specific category",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Parts"
+                ],
+                "summary": "Get parts by category",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Category name",
+                        "name": "category",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Part"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/parts/compatible-models": {
+            "get": {
+                "description": "Get a list of unique firearm models that parts are compatible with",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Parts"
+                ],
+                "summary": "Get compatible firearm models",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/parts/{id}": {
+            "get": {
+                "description": "Get a specific part by its ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Parts"
+                ],
+                "summary": "Get part by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Part ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Part"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID format",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Part not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update an existing part. Its attributes must satisfy the attribute schema of its part category.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Parts"
+                ],
+                "summary": "Update a part",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Part ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated part object",
+                        "name": "part",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Part"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Part"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Part not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Attributes do not satisfy the category schema",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a specific part",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Parts"
+                ],
+                "summary": "Delete a part",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Part ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success message",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID format",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Part not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/parts/{id}/compatible": {
+            "get": {
+                "description": "Get a page of the parts compatible with a specific part: parts in categories used by the same firearm models, excluding any declared to fit only other firearm models or that fail an attribute compatibility rule. Results come from the precomputed compatibility index, ordered by part ID; while a full rebuild of the index is pending they are evaluated at query time instead.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Parts",
+                    "Compatibility"
+                ],
+                "summary": "Get compatible parts",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Part ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Also exclude parts when a rule cannot be evaluated because an attribute is missing",
+                        "name": "strict",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only compatible parts in this part category or its subcategories",
+                        "name": "category_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of parts to return (default 100, max 500)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of parts to skip",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Part"
+                            }
+                        },
+                        "headers": {
+                            "X-Total-Count": {
+                                "type": "integer",
+                                "description": "Number of compatible parts across all pages"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID format or paging parameters",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Part not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/parts/{id}/firearms": {
+            "get": {
+                "description": "Get the firearm models whose magazine well takes a magazine part's family, and the prebuilt firearms of those models chambered in a caliber the magazine feeds or with no caliber recorded",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Parts",
+                    "Magazines"
+                ],
+                "summary": "Get the guns that take a magazine",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Part ID of the magazine",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.MagazineFirearmsResult"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/parts/{id}/fits": {
+            "get": {
+                "description": "Retrieves the firearm models a part is explicitly declared to fit, with fitment notes",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Parts",
+                    "Firearm Models"
+                ],
+                "summary": "Get firearm models a part fits",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Part ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.PartFirearmModel"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid part ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Part not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/parts/{id}/fits/{model_id}": {
+            "put": {
+                "description": "Creates or updates the fitment of a part to a firearm model",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Parts",
+                    "Firearm Models"
+                ],
+                "summary": "Declare that a part fits a firearm model",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Part ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Firearm Model ID",
+                        "name": "model_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fitment details",
+                        "name": "fitment",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.PartFitmentInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.PartFirearmModel"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Part or firearm model not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Deletes the declaration that a part fits a firearm model",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Parts",
+                    "Firearm Models"
+                ],
+                "summary": "Remove a part's fitment to a firearm model",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Part ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Firearm Model ID",
+                        "name": "model_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Fitment not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/parts/{id}/mounts": {
+            "get": {
+                "description": "Get the rail sections a part provides and the rail space it consumes",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Parts",
+                    "Mounting Interfaces"
+                ],
+                "summary": "Get a part's rail mounts",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Part ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.PartMount"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Record a rail section the part provides or rail space it consumes. A part may do both, e.g. an M-LOK to Picatinny rail section.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Parts",
+                    "Mounting Interfaces"
+                ],
+                "summary": "Add a rail mount to a part",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Part ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Rail mount",
+                        "name": "mount",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.PartMountInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.PartMount"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/parts/{id}/mounts/{mount_id}": {
+            "put": {
+                "description": "Update a rail section or rail space recorded on a part",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Parts",
+                    "Mounting Interfaces"
+                ],
+                "summary": "Update a part's rail mount",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Part ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Mount ID",
+                        "name": "mount_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated rail mount",
+                        "name": "mount",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.PartMountInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.PartMount"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a rail section or rail space recorded on a part",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Parts",
+                    "Mounting Interfaces"
+                ],
+                "summary": "Remove a part's rail mount",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Part ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Mount ID",
+                        "name": "mount_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/prebuilt-firearms": {
+            "get": {
+                "description": "Get a list of all prebuilt firearms in the database, optionally only those chambered in a caliber",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Prebuilt Firearms"
+                ],
+                "summary": "Get all prebuilt firearms",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter by caliber ID, name or alias",
+                        "name": "caliber",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.PrebuiltFirearm"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Add a new prebuilt firearm to the database",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Prebuilt Firearms"
+                ],
+                "summary": "Create a new prebuilt firearm",
+                "parameters": [
+                    {
+                        "description": "Prebuilt Firearm Info",
+                        "name": "firearm",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PrebuiltFirearm"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.PrebuiltFirearm"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/prebuilt-firearms/model/{modelId}": {
+            "get": {
+                "description": "Get all prebuilt firearms for a specific firearm model",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Prebuilt Firearms"
+                ],
+                "summary": "Get prebuilt firearms by model",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Firearm Model ID",
+                        "name": "modelId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.PrebuiltFirearm"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/prebuilt-firearms/{id}": {
+            "get": {
+                "description": "Get details of a specific prebuilt firearm",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Prebuilt Firearms"
+                ],
+                "summary": "Get a prebuilt firearm by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Prebuilt Firearm ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.PrebuiltFirearm"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update details of a specific prebuilt firearm",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Prebuilt Firearms"
+                ],
+                "summary": "Update a prebuilt firearm",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Prebuilt Firearm ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated Prebuilt Firearm Info",
+                        "name": "firearm",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PrebuiltFirearm"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.PrebuiltFirearm"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a specific prebuilt firearm",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Prebuilt Firearms"
+                ],
+                "summary": "Delete a prebuilt firearm",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Prebuilt Firearm ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/prebuilt-firearms/{id}/build-vs-buy": {
+            "get": {
+                "description": "Price every part referenced in a prebuilt firearm's components tree, pricing an assembly through its sub-parts when they reference parts, and compare the parts build total with the prebuilt's price and cheapest listing. Given a destination state, listings of parts and of the prebuilt that cannot ship there are skipped and listings that must ship to an FFL are flagged.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Prebuilt Firearms",
+                    "Builds"
+                ],
+                "summary": "Compare building vs buying a prebuilt",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Prebuilt Firearm ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Destination state, e.g. CA or US-CA",
+                        "name": "ship_to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.BuildVsBuyResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/prebuilt-firearms/{id}/fork": {
+            "post": {
+                "description": "Create an editable build pre-filled from a prebuilt firearm's components tree. Component names are matched to the firearm model's part category slots; entries that cannot be placed are reported.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Prebuilt Firearms",
+                    "Builds"
+                ],
+                "summary": "Fork a prebuilt firearm into a build",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Prebuilt Firearm ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Build details",
+                        "name": "fork",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ForkPrebuiltInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ForkPrebuiltResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/prebuilt-firearms/{id}/nfa": {
+            "get": {
+                "description": "Derive a prebuilt firearm's federal classification from the parts its components reference and its specifications",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Prebuilt Firearms",
+                    "Laws"
+                ],
+                "summary": "Classify a prebuilt firearm under the NFA",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Prebuilt Firearm ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.NFAClassification"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/rule-sets": {
+            "get": {
+                "description": "Get every imported compatibility ruleset version, newest first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Compatibility"
+                ],
+                "summary": "Get ruleset versions",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.RuleSet"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/rule-sets/export": {
+            "get": {
+                "description": "Export the current compatibility rules as a YAML ruleset, or a previously imported version exactly as it was imported",
+                "produces": [
+                    "application/x-yaml"
+                ],
+                "tags": [
+                    "Compatibility"
+                ],
+                "summary": "Export a ruleset as YAML",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Ruleset version to export instead of the current rules",
+                        "name": "version",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "YAML ruleset",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/rule-sets/import": {
+            "post": {
+                "description": "Validate a YAML ruleset and report the rules it adds, removes and changes and the saved builds it would newly invalidate. Unless dry_run is set, all compatibility rules are then atomically replaced by the ruleset as a new version.",
+                "consumes": [
+                    "application/x-yaml"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Compatibility"
+                ],
+                "summary": "Import a YAML ruleset",
+                "parameters": [
+                    {
+                        "description": "YAML ruleset",
+                        "name": "ruleset",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only report the effect of the import",
+                        "name": "dry_run",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Dry run report",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.RuleSetImportReport"
+                        }
+                    },
+                    "201": {
+                        "description": "Imported",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.RuleSetImportReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ruleset",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Version is not newer than the latest ruleset",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/sellers": {
+            "get": {
+                "description": "Get a list of all sellers in the database",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Sellers"
+                ],
+                "summary": "Get all sellers",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Seller"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Add a new seller to the database",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Sellers"
+                ],
+                "summary": "Create a new seller",
+                "parameters": [
+                    {
+                        "description": "Seller Info",
+                        "name": "seller",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Seller"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Seller"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/sellers/{id}": {
+            "get": {
+                "description": "Get details of a specific seller",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Sellers"
+                ],
+                "summary": "Get a seller by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Seller ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Seller"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update details of a specific seller",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Sellers"
+                ],
+                "summary": "Update a seller",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Seller ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated Seller Info",
+                        "name": "seller",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Seller"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Seller"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a specific seller",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Sellers"
+                ],
+                "summary": "Delete a seller",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Seller ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/sellers/{id}/affiliate-status": {
+            "patch": {
+                "description": "Update the affiliate status of a specific seller",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Sellers"
+                ],
+                "summary": "Update seller affiliate status",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Seller ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Affiliate Status Update Info",
+                        "name": "status",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Seller"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/shipping-restrictions": {
+            "get": {
+                "description": "Get the destination restrictions sellers and listings declare, optionally filtered by seller, listing or state. The seller filter covers only seller-wide restrictions.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Shipping Restrictions"
+                ],
+                "summary": "Get all shipping restrictions",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Only seller-wide restrictions of this seller",
+                        "name": "seller_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only restrictions of this listing",
+                        "name": "listing_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only restrictions covering this state, including those covering every state",
+                        "name": "state",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ShippingRestriction"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Declare a state a seller, or one of its listings, does not ship to or only ships to through an FFL, optionally limited to magazines, ammunition or firearms",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Shipping Restrictions"
+                ],
+                "summary": "Create a shipping restriction",
+                "parameters": [
+                    {
+                        "description": "Shipping restriction to create",
+                        "name": "restriction",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ShippingRestriction"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.ShippingRestriction"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/shipping-restrictions/{id}": {
+            "get": {
+                "description": "Get a specific shipping restriction",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Shipping Restrictions"
+                ],
+                "summary": "Get a shipping restriction",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Shipping Restriction ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ShippingRestriction"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Replace an existing shipping restriction; fields left out of the body are cleared",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Shipping Restrictions"
+                ],
+                "summary": "Update a shipping restriction",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Shipping Restriction ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated shipping restriction",
+                        "name": "restriction",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ShippingRestriction"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ShippingRestriction"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a specific shipping restriction",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Shipping Restrictions"
+                ],
+                "summary": "Delete a shipping restriction",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Shipping Restriction ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/user-suggestions": {
+            "get": {
+                "description": "Get a list of all user suggestions in the database",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User Suggestions"
+                ],
+                "summary": "Get all user suggestions",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.UserSuggestion"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Add a new user suggestion to the database",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User Suggestions"
+                ],
+                "summary": "Create a new user suggestion",
+                "parameters": [
+                    {
+                        "description": "User Suggestion Info",
+                        "name": "suggestion",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UserSuggestion"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.UserSuggestion"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/user-suggestions/status/{status}": {
+            "get": {
+                "description": "Get all user suggestions with a specific status",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User Suggestions"
+                ],
+                "summary": "Get user suggestions by status",
+                "parameters": [
+                    {
+                        "enum": [
+                            "pending",
+                            "approved",
+                            "rejected"
+                        ],
+                        "type": "string",
+                        "description": "Suggestion Status",
+                        "name": "status",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.UserSuggestion"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/user-suggestions/{id}": {
+            "get": {
+                "description": "Get details of a specific user suggestion",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User Suggestions"
+                ],
+                "summary": "Get a user suggestion by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User Suggestion ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.UserSuggestion"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update details of a specific user suggestion",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User Suggestions"
+                ],
+                "summary": "Update a user suggestion",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User Suggestion ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated User Suggestion Info",
+                        "name": "suggestion",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UserSuggestion"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.UserSuggestion"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a specific user suggestion",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User Suggestions"
+                ],
+                "summary": "Delete a user suggestion",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User Suggestion ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/user-suggestions/{id}/status": {
+            "patch": {
+                "description": "Update the status of a specific user suggestion",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User Suggestions"
+                ],
+                "summary": "Update user suggestion status",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User Suggestion ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Status Update Info",
+                        "name": "status",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.UserSuggestion"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "handlers.BOM": {
+            "type": "object",
+            "properties": {
+                "build_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "build_name": {
+                    "type": "string",
+                    "example": "Lightweight 16in Carbine"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "USD"
+                },
+                "firearm_model": {
+                    "type": "string",
+                    "example": "AR-15"
+                },
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.BOMLine"
+                    }
+                },
+                "ship_to": {
+                    "description": "Destination state the listings were limited to, if any",
+                    "type": "string",
+                    "example": "CA"
+                },
+                "total": {
+                    "description": "Sum of listing prices, excluding shipping and parts without a listing",
+                    "type": "number",
+                    "example": 1149.5
+                }
+            }
+        },
+        "handlers.BOMLine": {
+            "type": "object",
+            "properties": {
+                "category_path": {
+                    "description": "Category path from the top-level category down, e.g. \"Upper Receiver \u003e Barrel\"",
+                    "type": "string",
+                    "example": "Upper Receiver \u003e Barrel"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "USD"
+                },
+                "ffl_required": {
+                    "description": "Whether the listing only ships to a licensed dealer (FFL) in the destination state",
+                    "type": "boolean",
+                    "example": false
+                },
+                "link": {
+                    "description": "Affiliate link for the listing, or the plain listing URL for non-affiliate sellers",
+                    "type": "string",
+                    "example": "https://www.brownells.com/?aff=gunguru_BRN-BBL-16"
+                },
+                "manufacturer": {
+                    "type": "string",
+                    "example": "Daniel Defense"
+                },
+                "part_id": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "part_name": {
+                    "type": "string",
+                    "example": "16\" 5.56 NATO Barrel (AR-15)"
+                },
+                "price": {
+                    "type": "number",
+                    "example": 189.99
+                },
+                "seller_name": {
+                    "type": "string",
+                    "example": "Brownells"
+                },
+                "shipping_notes": {
+                    "description": "Reasons of the shipping restrictions that apply to the part's listings",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "Magazines ship only to California FFLs"
+                    ]
+                },
+                "shipping_restricted": {
+                    "description": "Whether the part has in-stock listings but none ship to the destination",
+                    "type": "boolean",
+                    "example": false
+                },
+                "sku": {
+                    "description": "Chosen listing: the cheapest in-stock listing after shipping, if any",
+                    "type": "string",
+                    "example": "BRN-BBL-16"
+                }
+            }
+        },
+        "handlers.BuildCompatibilityInput": {
+            "type": "object",
+            "required": [
+                "part_ids"
+            ],
+            "properties": {
+                "part_ids": {
+                    "type": "array",
+                    "minItems": 2,
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        5,
+                        12,
+                        31
+                    ]
+                }
+            }
+        },
+        "handlers.BuildCompatibilityResult": {
+            "type": "object",
+            "properties": {
+                "build_id": {
+                    "description": "Build that was checked, omitted for unsaved part sets",
+                    "type": "integer",
+                    "example": 1
+                },
+                "compatible": {
+                    "description": "Whether no pair of parts is incompatible",
+                    "type": "boolean",
+                    "example": false
+                },
+                "incompatible_pairs": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "pairs": {
+                    "description": "Pairs that are incompatible or had a rule or declared fitments apply; every pair with ?all=true",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.CompatibilityVerdict"
+                    }
+                },
+                "unverified_pairs": {
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "handlers.BuildCompletionInput": {
+            "type": "object",
+            "required": [
+                "budget",
+                "firearm_model_id"
+            ],
+            "properties": {
+                "budget": {
+                    "description": "Maximum total landed cost, including the locked parts",
+                    "type": "number",
+                    "example": 1200
+                },
+                "firearm_model_id": {
+                    "description": "Firearm model to complete a build for",
+                    "type": "integer",
+                    "example": 1
+                },
+                "include_optional": {
+                    "description": "Also fill optional slots of the model when the budget allows",
+                    "type": "boolean",
+                    "example": false
+                },
+                "locked": {
+                    "description": "Parts the user has already chosen, keyed by part category (slot) ID",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "ship_to": {
+                    "description": "Destination state; listings that cannot ship there are not used, e.g. CA or US-CA",
+                    "type": "string",
+                    "example": "CA"
+                },
+                "strategy": {
+                    "description": "Optimization goal, defaults to lowest_cost",
+                    "type": "string",
+                    "enum": [
+                        "lowest_cost",
+                        "closest_to_budget"
+                    ],
+                    "example": "lowest_cost"
+                }
+            }
+        },
+        "handlers.BuildCompletionLine": {
+            "type": "object",
+            "properties": {
+                "category_name": {
+                    "type": "string",
+                    "example": "Barrel"
+                },
+                "ffl_required": {
+                    "description": "Whether the listing only ships to a licensed dealer (FFL) in the destination state, and why",
+                    "type": "boolean",
+                    "example": false
+                },
+                "listing_id": {
+                    "description": "Cheapest in-stock listing for the part and its landed price, if any",
+                    "type": "integer",
+                    "example": 42
+                },
+                "locked": {
+                    "description": "Whether the part was supplied by the caller rather than chosen by the solver",
+                    "type": "boolean",
+                    "example": false
+                },
+                "part_category_id": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "part_id": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "part_name": {
+                    "type": "string",
+                    "example": "16\" 5.56 NATO Barrel (AR-15)"
+                },
+                "price": {
+                    "type": "number",
+                    "example": 197.98
+                },
+                "seller_name": {
+                    "type": "string",
+                    "example": "Brownells"
+                },
+                "shipping_notes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "Magazines ship only to California FFLs"
+                    ]
+                }
+            }
+        },
+        "handlers.BuildCompletionResult": {
+            "type": "object",
+            "properties": {
+                "budget": {
+                    "type": "number",
+                    "example": 1200
+                },
+                "errors": {
+                    "description": "Problems the completed selection still fails validation with, which make it infeasible",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.BuildValidationError"
+                    }
+                },
+                "feasible": {
+                    "description": "Whether the required slots could be filled without exceeding the budget",
+                    "type": "boolean",
+                    "example": true
+                },
+                "firearm_model_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.BuildCompletionLine"
+                    }
+                },
+                "remaining": {
+                    "type": "number",
+                    "example": 50.5
+                },
+                "restricted_part_ids": {
+                    "description": "Locked parts whose in-stock listings cannot ship to the destination",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "ship_to": {
+                    "description": "Destination state the listings were limited to, if any",
+                    "type": "string",
+                    "example": "CA"
+                },
+                "slots": {
+                    "description": "Complete selection keyed by slot ID, ready to be saved with POST /builds",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "strategy": {
+                    "type": "string",
+                    "example": "lowest_cost"
+                },
+                "total": {
+                    "description": "Total landed cost of the selection and the budget left over",
+                    "type": "number",
+                    "example": 1149.5
+                },
+                "unavailable_part_ids": {
+                    "description": "Locked parts with no in-stock listing, which are excluded from the total",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "unfillable_slots": {
+                    "description": "Required slots with no in-stock compatible part",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "handlers.BuildCostInput": {
+            "type": "object",
+            "required": [
+                "slots"
+            ],
+            "properties": {
+                "ship_to": {
+                    "description": "Destination state; listings that cannot ship there are not used, e.g. CA or US-CA",
+                    "type": "string",
+                    "example": "CA"
+                },
+                "slots": {
+                    "description": "Selected parts keyed by part category (slot) ID",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "handlers.BuildCostLine": {
+            "type": "object",
+            "properties": {
+                "ffl_required": {
+                    "description": "Whether the listing only ships to a licensed dealer (FFL) in the destination state",
+                    "type": "boolean",
+                    "example": false
+                },
+                "in_stock": {
+                    "description": "Whether an in-stock listing was found for the part",
+                    "type": "boolean",
+                    "example": true
+                },
+                "line_total": {
+                    "type": "number",
+                    "example": 197.98
+                },
+                "listing_id": {
+                    "description": "Cheapest in-stock listing for the part, if any",
+                    "type": "integer",
+                    "example": 42
+                },
+                "part_category_id": {
+                    "description": "Slot (part category) the part fills",
+                    "type": "integer",
+                    "example": 10
+                },
+                "part_id": {
+                    "description": "Part selected for the slot",
+                    "type": "integer",
+                    "example": 5
+                },
+                "part_name": {
+                    "type": "string",
+                    "example": "16\" 5.56 NATO Barrel (AR-15)"
+                },
+                "price": {
+                    "description": "Listing price, shipping charged and their sum",
+                    "type": "number",
+                    "example": 189.99
+                },
+                "seller_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "seller_name": {
+                    "type": "string",
+                    "example": "Brownells"
+                },
+                "shipping_cost": {
+                    "type": "number",
+                    "example": 7.99
+                },
+                "shipping_notes": {
+                    "description": "Reasons of the shipping restrictions that apply to the part's listings",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "Magazines ship only to California FFLs"
+                    ]
+                },
+                "shipping_restricted": {
+                    "description": "Whether the part has in-stock listings but none ship to the destination",
+                    "type": "boolean",
+                    "example": false
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://www.brownells.com/product/16-5.56-nato-barrel-ar-15"
+                }
+            }
+        },
+        "handlers.BuildCostResult": {
+            "type": "object",
+            "properties": {
+                "build_id": {
+                    "description": "Build that was priced, omitted for unsaved selections",
+                    "type": "integer",
+                    "example": 1
+                },
+                "complete": {
+                    "description": "Whether every part in the build could be priced",
+                    "type": "boolean",
+                    "example": true
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "USD"
+                },
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.BuildCostLine"
+                    }
+                },
+                "parts_total": {
+                    "description": "Totals across all parts with an in-stock listing",
+                    "type": "number",
+                    "example": 1149.5
+                },
+                "restricted_part_ids": {
+                    "description": "Unavailable parts whose in-stock listings cannot ship to the destination",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "ship_to": {
+                    "description": "Destination state the listings were limited to, if any",
+                    "type": "string",
+                    "example": "CA"
+                },
+                "shipping_total": {
+                    "type": "number",
+                    "example": 31.96
+                },
+                "total": {
+                    "type": "number",
+                    "example": 1181.46
+                },
+                "unavailable_part_ids": {
+                    "description": "Parts with no in-stock listing, which are excluded from the totals",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "handlers.BuildDiff": {
+            "type": "object",
+            "properties": {
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.BuildSlotChange"
+                    }
+                },
+                "from": {
+                    "$ref": "#/definitions/handlers.BuildDiffSide"
+                },
+                "from_firearm_model_id": {
+                    "description": "Firearm model change, if the two sides are for different models",
+                    "type": "integer",
+                    "example": 1
+                },
+                "to": {
+                    "$ref": "#/definitions/handlers.BuildDiffSide"
+                },
+                "to_firearm_model_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "total_price_delta": {
+                    "type": "number",
+                    "example": -24.5
+                },
+                "total_weight_delta": {
+                    "type": "number",
+                    "example": -0.18
+                }
+            }
+        },
+        "handlers.BuildDiffSide": {
+            "type": "object",
+            "properties": {
+                "build_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Lightweight 16in Carbine"
+                },
+                "revision": {
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "handlers.BuildInput": {
+            "type": "object",
+            "required": [
+                "firearm_model_id",
+                "name"
+            ],
+            "properties": {
+                "description": {
+                    "description": "Description of the build",
+                    "type": "string",
+                    "example": "A lightweight AR-15 build for general purpose use."
+                },
+                "firearm_model_id": {
+                    "description": "Firearm model the build is based on",
+                    "type": "integer",
+                    "example": 1
+                },
+                "name": {
+                    "description": "Name of the build",
+                    "type": "string",
+                    "example": "Lightweight 16in Carbine"
+                },
+                "slots": {
+                    "description": "Selected parts keyed by part category (slot) ID",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "handlers.BuildMagazinesInput": {
+            "type": "object",
+            "required": [
+                "firearm_model_id"
+            ],
+            "properties": {
+                "firearm_model_id": {
+                    "description": "Firearm model the selection is for",
+                    "type": "integer",
+                    "example": 1
+                },
+                "slots": {
+                    "description": "Selected parts keyed by part category (slot) ID",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "handlers.BuildMagazinesResult": {
+            "type": "object",
+            "properties": {
+                "build_id": {
+                    "description": "Build that was checked, omitted for unsaved selections",
+                    "type": "integer",
+                    "example": 1
+                },
+                "caliber_ids": {
+                    "description": "Calibers the build is chambered in, from its parts or else its firearm model",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        2
+                    ]
+                },
+                "firearm_model_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "magazines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.MagazineFit"
+                    }
+                }
+            }
+        },
+        "handlers.BuildNFAInput": {
+            "type": "object",
+            "required": [
+                "firearm_model_id"
+            ],
+            "properties": {
+                "firearm_model_id": {
+                    "description": "Firearm model the selection is for",
+                    "type": "integer",
+                    "example": 1
+                },
+                "slots": {
+                    "description": "Selected parts keyed by part category (slot) ID",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "handlers.BuildRailSpaceInput": {
+            "type": "object",
+            "required": [
+                "slots"
+            ],
+            "properties": {
+                "slots": {
+                    "description": "Selected parts keyed by part category (slot) ID",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "handlers.BuildRailSpaceResult": {
+            "type": "object",
+            "properties": {
+                "build_id": {
+                    "description": "Build that was checked, omitted for unsaved selections",
+                    "type": "integer",
+                    "example": 1
+                },
+                "fits": {
+                    "description": "Whether every accessory mount was allocated to a rail section",
+                    "type": "boolean",
+                    "example": false
+                },
+                "search_truncated": {
+                    "description": "Whether the allocation search hit its step limit before placing everything, in which\ncase the unplaced accessories might still fit in an arrangement it did not try",
+                    "type": "boolean",
+                    "example": false
+                },
+                "sections": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.RailSectionUsage"
+                    }
+                },
+                "unplaced": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.RailMountShortfall"
+                    }
+                }
+            }
+        },
+        "handlers.BuildSlotChange": {
+            "type": "object",
+            "properties": {
+                "category_name": {
+                    "type": "string",
+                    "example": "Barrel"
+                },
+                "change": {
+                    "type": "string",
+                    "enum": [
+                        "added",
+                        "removed",
+                        "swapped"
+                    ],
+                    "example": "swapped"
+                },
+                "from_part_id": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "from_part_name": {
+                    "type": "string",
+                    "example": "16\" 5.56 NATO Barrel (AR-15)"
+                },
+                "part_category_id": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "price_delta": {
+                    "description": "Change in cheapest in-stock landed price and in weight (pounds)",
+                    "type": "number",
+                    "example": -24.5
+                },
+                "price_incomplete": {
+                    "description": "Whether a part on either side had no in-stock listing, making the price delta partial",
+                    "type": "boolean",
+                    "example": false
+                },
+                "to_part_id": {
+                    "type": "integer",
+                    "example": 7
+                },
+                "to_part_name": {
+                    "type": "string",
+                    "example": "14.5\" 5.56 NATO Barrel (AR-15)"
+                },
+                "weight_delta": {
+                    "type": "number",
+                    "example": -0.18
+                }
+            }
+        },
+        "handlers.BuildValidationError": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "Machine-readable error code",
+                    "type": "string",
+                    "example": "missing_required_slot"
+                },
+                "expected_part_category_id": {
+                    "description": "Slot the part belongs in, for parts picked for the wrong slot",
+                    "type": "integer",
+                    "example": 13
+                },
+                "message": {
+                    "description": "Human-readable description of the error",
+                    "type": "string",
+                    "example": "Required slot Barrel has no part selected"
+                },
+                "part_category_id": {
+                    "description": "Slot (part category) the error refers to",
+                    "type": "integer",
+                    "example": 10
+                },
+                "part_id": {
+                    "description": "Part the error refers to, if any",
+                    "type": "integer",
+                    "example": 5
+                }
+            }
+        },
+        "handlers.BuildValidationInput": {
+            "type": "object",
+            "required": [
+                "firearm_model_id"
+            ],
+            "properties": {
+                "firearm_model_id": {
+                    "description": "Firearm model the selection is for",
+                    "type": "integer",
+                    "example": 1
+                },
+                "slots": {
+                    "description": "Selected parts keyed by part category (slot) ID",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "handlers.BuildValidationResult": {
+            "type": "object",
+            "properties": {
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.BuildValidationError"
+                    }
+                },
+                "valid": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "warnings": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.BuildValidationWarning"
+                    }
+                }
+            }
+        },
+        "handlers.BuildValidationWarning": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "Machine-readable warning code: an NFA classification or item, or insufficient_rail_space\nwhen an accessory may not fit but the rail allocation search was cut short",
+                    "type": "string",
+                    "example": "short_barreled_rifle"
+                },
+                "message": {
+                    "description": "Human-readable description of the warning",
+                    "type": "string",
+                    "example": "This configuration is a short-barreled rifle (SBR): the 10.5 in barrel is under 16 in and B5 SOPMOD Stock (AR-15) is a shoulder stock. It must be registered on an approved ATF Form 1 or Form 4 before it is assembled."
+                },
+                "part_category_id": {
+                    "description": "Slot and part the warning refers to, if a single part causes it",
+                    "type": "integer",
+                    "example": 23
+                },
+                "part_id": {
+                    "type": "integer",
+                    "example": 42
+                }
+            }
+        },
+        "handlers.BuildVsBuyComponent": {
+            "type": "object",
+            "properties": {
+                "ffl_required": {
+                    "description": "Whether the listing only ships to a licensed dealer (FFL) in the destination state",
+                    "type": "boolean",
+                    "example": false
+                },
+                "in_stock": {
+                    "description": "Whether an in-stock listing was found for the part",
+                    "type": "boolean",
+                    "example": true
+                },
+                "line_total": {
+                    "type": "number",
+                    "example": 157.98
+                },
+                "listing_id": {
+                    "description": "Cheapest in-stock listing for the part, if any",
+                    "type": "integer",
+                    "example": 42
+                },
+                "part_id": {
+                    "description": "Part referenced by the component, if any",
+                    "type": "integer",
+                    "example": 5
+                },
+                "part_name": {
+                    "type": "string",
+                    "example": "Complete BCG (AR-15)"
+                },
+                "path": {
+                    "description": "Position of the component in the prebuilt's parts tree",
+                    "type": "string",
+                    "example": "Upper Assembly \u003e Bolt Carrier Group"
+                },
+                "price": {
+                    "type": "number",
+                    "example": 149.99
+                },
+                "seller_name": {
+                    "type": "string",
+                    "example": "Brownells"
+                },
+                "shipping_cost": {
+                    "type": "number",
+                    "example": 7.99
+                },
+                "shipping_notes": {
+                    "description": "Reasons of the shipping restrictions that apply to the part's listings",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "Magazines ship only to California FFLs"
+                    ]
+                },
+                "shipping_restricted": {
+                    "description": "Whether the part has in-stock listings but none ship to the destination",
+                    "type": "boolean",
+                    "example": false
+                }
+            }
+        },
+        "handlers.BuildVsBuyResult": {
+            "type": "object",
+            "properties": {
+                "build_total": {
+                    "type": "number",
+                    "example": 1181.46
+                },
+                "buy_total": {
+                    "description": "Lowest of the catalog price and the cheapest listing total",
+                    "type": "number",
+                    "example": 1269.98
+                },
+                "cheaper": {
+                    "description": "Cheaper option, omitted when some components could not be priced",
+                    "type": "string",
+                    "enum": [
+                        "build",
+                        "buy"
+                    ],
+                    "example": "build"
+                },
+                "complete": {
+                    "description": "Whether every component could be priced, making the comparison meaningful",
+                    "type": "boolean",
+                    "example": true
+                },
+                "components": {
+                    "description": "Every component of the prebuilt priced at its cheapest in-stock listing",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.BuildVsBuyComponent"
+                    }
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "USD"
+                },
+                "difference": {
+                    "description": "Optimized build total minus buy total; negative means building is cheaper",
+                    "type": "number",
+                    "example": -104.51
+                },
+                "optimized_build_total": {
+                    "description": "Build total when parts are consolidated across sellers to save on shipping",
+                    "type": "number",
+                    "example": 1165.47
+                },
+                "parts_total": {
+                    "type": "number",
+                    "example": 1149.5
+                },
+                "prebuilt_cheapest_offer": {
+                    "$ref": "#/definitions/handlers.PrebuiltOffer"
+                },
+                "prebuilt_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "prebuilt_name": {
+                    "type": "string",
+                    "example": "Standard AR-15 Rifle"
+                },
+                "prebuilt_price": {
+                    "description": "Catalog price of the prebuilt and its cheapest in-stock listing, if any",
+                    "type": "number",
+                    "example": 1299.99
+                },
+                "prebuilt_shipping_restricted": {
+                    "description": "Whether the prebuilt has in-stock listings but none ship to the destination",
+                    "type": "boolean",
+                    "example": false
+                },
+                "restricted_part_ids": {
+                    "description": "Parts whose in-stock listings cannot ship to the destination",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "ship_to": {
+                    "description": "Destination state the listings were limited to, if any",
+                    "type": "string",
+                    "example": "CA"
+                },
+                "shipping_total": {
+                    "type": "number",
+                    "example": 31.96
+                },
+                "unpriced_components": {
+                    "description": "Components that could not be priced: no part ID or no in-stock listing",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "handlers.BuildWeightAssembly": {
+            "type": "object",
+            "properties": {
+                "category_name": {
+                    "type": "string",
+                    "example": "Upper Receiver"
+                },
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.BuildWeightLine"
+                    }
+                },
+                "missing_weight_count": {
+                    "description": "Parts in the assembly with no weight recorded",
+                    "type": "integer",
+                    "example": 0
+                },
+                "part_category_id": {
+                    "description": "Top-level part category, e.g. Upper Receiver",
+                    "type": "integer",
+                    "example": 2
+                },
+                "weight": {
+                    "type": "number",
+                    "example": 3.42
+                }
+            }
+        },
+        "handlers.BuildWeightInput": {
+            "type": "object",
+            "required": [
+                "slots"
+            ],
+            "properties": {
+                "slots": {
+                    "description": "Selected parts keyed by part category (slot) ID",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "handlers.BuildWeightLine": {
+            "type": "object",
+            "properties": {
+                "dimensions": {
+                    "description": "Dimensions as recorded on the part, if any",
+                    "type": "string",
+                    "example": "16 x 1 x 1 in"
+                },
+                "missing_weight": {
+                    "description": "Whether the part has no weight recorded, so the totals understate the build",
+                    "type": "boolean",
+                    "example": false
+                },
+                "part_category_id": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "part_id": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "part_name": {
+                    "type": "string",
+                    "example": "16\" 5.56 NATO Barrel (AR-15)"
+                },
+                "weight": {
+                    "description": "Weight in pounds, zero when missing",
+                    "type": "number",
+                    "example": 1.65
+                }
+            }
+        },
+        "handlers.BuildWeightResult": {
+            "type": "object",
+            "properties": {
+                "assemblies": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.BuildWeightAssembly"
+                    }
+                },
+                "build_id": {
+                    "description": "Build that was weighed, omitted for unsaved selections",
+                    "type": "integer",
+                    "example": 1
+                },
+                "complete": {
+                    "description": "Whether every part had a weight recorded",
+                    "type": "boolean",
+                    "example": true
+                },
+                "missing_weight_part_ids": {
+                    "description": "Parts with no weight recorded, which are excluded from the totals",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "total_weight": {
+                    "type": "number",
+                    "example": 6.48
+                },
+                "unit": {
+                    "description": "Weights are in pounds",
+                    "type": "string",
+                    "example": "lb"
+                }
+            }
+        },
+        "handlers.CaliberInput": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "aliases": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "5.56 NATO",
+                        "5.56mm"
+                    ]
+                },
+                "bullet_diameter": {
+                    "type": "number",
+                    "example": 0.224
+                },
+                "case_length": {
+                    "type": "number",
+                    "example": 44.7
+                },
+                "fires_caliber_ids": {
+                    "description": "Calibers that can be safely fired in a chamber for this caliber",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        2
+                    ]
+                },
+                "name": {
+                    "type": "string",
+                    "example": "5.56x45mm NATO"
+                },
+                "overall_length": {
+                    "type": "number",
+                    "example": 57.4
+                },
+                "parent_caliber_id": {
+                    "description": "Cartridge this caliber was derived from",
+                    "type": "integer",
+                    "example": 2
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "rifle",
+                        "pistol",
+                        "rimfire",
+                        "shotgun"
+                    ],
+                    "example": "rifle"
+                }
+            }
+        },
+        "handlers.CartItem": {
+            "type": "object",
+            "properties": {
+                "ffl_required": {
+                    "description": "Whether the listing only ships to a licensed dealer (FFL) in the destination state, and why",
+                    "type": "boolean",
+                    "example": false
+                },
+                "listing_id": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "part_id": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "part_name": {
+                    "type": "string",
+                    "example": "Complete BCG (AR-15)"
+                },
+                "price": {
+                    "type": "number",
+                    "example": 149.99
+                },
+                "shipping_notes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "Receivers ship to an FFL only"
+                    ]
+                },
+                "sku": {
+                    "type": "string",
+                    "example": "BRO-1234"
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://www.brownells.com/product/complete-bcg-ar-15"
+                }
+            }
+        },
+        "handlers.CartOptimizeInput": {
+            "type": "object",
+            "required": [
+                "part_ids"
+            ],
+            "properties": {
+                "part_ids": {
+                    "description": "Parts to buy",
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        1,
+                        2,
+                        3
+                    ]
+                },
+                "ship_to": {
+                    "description": "Destination state; listings that cannot ship there are not used, e.g. CA or US-CA",
+                    "type": "string",
+                    "example": "CA"
+                }
+            }
+        },
+        "handlers.CartResult": {
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "type": "string",
+                    "example": "USD"
+                },
+                "naive_total": {
+                    "description": "Total when every part is bought from its cheapest listing and shipped separately",
+                    "type": "number",
+                    "example": 1203.41
+                },
+                "optimal": {
+                    "description": "False when the search limit was reached before the best cart was proven optimal",
+                    "type": "boolean",
+                    "example": true
+                },
+                "parts_total": {
+                    "type": "number",
+                    "example": 1149.5
+                },
+                "restricted_part_ids": {
+                    "description": "Unavailable parts whose in-stock listings cannot ship to the destination",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "savings": {
+                    "type": "number",
+                    "example": 27.93
+                },
+                "sellers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.SellerCart"
+                    }
+                },
+                "ship_to": {
+                    "description": "Destination state the listings were limited to, if any",
+                    "type": "string",
+                    "example": "CA"
+                },
+                "shipping_total": {
+                    "type": "number",
+                    "example": 25.98
+                },
+                "total": {
+                    "type": "number",
+                    "example": 1175.48
+                },
+                "unavailable_part_ids": {
+                    "description": "Parts with no in-stock listing, which are left out of the cart",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "handlers.CategoryWithRequiredStatusHierarchy": {
+            "type": "object",
+            "properties": {
+                "attribute_schema": {
+                    "description": "Attributes parts in this category and its subcategories must satisfy, as a list of AttributeDefinition",
+                    "type": "array",
+                    "items": {
+                        "type": "object"
+                    }
+                },
+                "child_categories": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.CategoryWithRequiredStatusHierarchy"
+                    }
+                },
+                "created_at": {
+                    "description": "Creation timestamp",
+                    "type": "string"
+                },
+                "description": {
+                    "description": "Description of the category",
+                    "type": "string",
+                    "example": "Core upper receiver components"
+                },
+                "id": {
+                    "description": "Unique identifier for the category",
+                    "type": "integer",
+                    "example": 1
+                },
+                "is_required": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "name": {
+                    "description": "Name of the category",
+                    "type": "string",
+                    "example": "Upper Assembly"
+                },
+                "nfa_tag": {
+                    "description": "NFA-relevant role of parts in this category and its subcategories, if any",
+                    "type": "string",
+                    "enum": [
+                        "suppressor",
+                        "short_barrel",
+                        "shoulder_stock",
+                        "pistol_brace",
+                        "vertical_foregrip",
+                        "full_auto"
+                    ],
+                    "example": "suppressor"
+                },
+                "parent_category": {
+                    "description": "Parent category (self-referential relationship)",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.PartCategory"
+                        }
+                    ]
+                },
+                "parent_category_id": {
+                    "description": "Parent category ID for hierarchical relationships (null for top-level categories)",
+                    "type": "integer",
+                    "example": 0
+                },
+                "shipping_class": {
+                    "description": "Shipping class of parts in this category and its subcategories that sellers may restrict, if any",
+                    "type": "string",
+                    "enum": [
+                        "magazine",
+                        "ammunition",
+                        "firearm"
+                    ],
+                    "example": "magazine"
+                },
+                "updated_at": {
+                    "description": "Last update timestamp",
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.CompatibilityPart": {
+            "type": "object",
+            "properties": {
+                "category_name": {
+                    "type": "string",
+                    "example": "Barrel"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "name": {
+                    "type": "string",
+                    "example": "16\" 5.56 NATO Barrel (AR-15)"
+                },
+                "part_category_id": {
+                    "type": "integer",
+                    "example": 10
+                }
+            }
+        },
+        "handlers.CompatibilityVerdict": {
+            "type": "object",
+            "properties": {
+                "compatible": {
+                    "description": "Whether part B would be listed by GET /parts/{part_a}/compatible; for sets of\nparts, whether neither declared fitments nor an attribute rule failed",
+                    "type": "boolean",
+                    "example": false
+                },
+                "fitment": {
+                    "description": "Overlap of the firearm models the parts are declared to fit, present when both declare fitments",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/handlers.FitmentCheck"
+                        }
+                    ]
+                },
+                "model_linkage": {
+                    "description": "Firearm model linkage between the two parts' categories",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/handlers.ModelLinkage"
+                        }
+                    ]
+                },
+                "part_a": {
+                    "$ref": "#/definitions/handlers.CompatibilityPart"
+                },
+                "part_b": {
+                    "$ref": "#/definitions/handlers.CompatibilityPart"
+                },
+                "rules": {
+                    "description": "Every attribute rule that applied to the pair and its outcome",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.RuleCheck"
+                    }
+                },
+                "verdict": {
+                    "type": "string",
+                    "enum": [
+                        "compatible",
+                        "incompatible",
+                        "unverified"
+                    ],
+                    "example": "incompatible"
+                }
+            }
+        },
+        "handlers.ComplianceCell": {
+            "type": "object",
+            "properties": {
+                "jurisdiction": {
+                    "type": "string",
+                    "example": "US-CA"
+                },
+                "reasons": {
+                    "description": "Violated rules for a fail, undecidable rules for undetermined, and rules taking\neffect later that the firearm would violate",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "Large-capacity magazine ban (Cal. Penal Code § 32310): PMAG 30-Round (AR-15) holds 30 rounds",
+                        " more than the limit of 10"
+                    ]
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "pass",
+                        "fail",
+                        "undetermined",
+                        "error"
+                    ],
+                    "example": "fail"
+                }
+            }
+        },
+        "handlers.ComplianceReport": {
+            "type": "object",
+            "properties": {
+                "as_of": {
+                    "type": "string",
+                    "example": "2025-01-01T00:00:00Z"
+                },
+                "firearms": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.PrebuiltCompliance"
+                    }
+                },
+                "generated_at": {
+                    "type": "string",
+                    "example": "2025-01-01T12:00:00Z"
+                },
+                "jurisdictions": {
+                    "description": "Codes of the jurisdictions checked, in the order of each firearm's results",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "US",
+                        "US-CA",
+                        "US-NY"
+                    ]
+                }
+            }
+        },
+        "handlers.EffectiveAttribute": {
+            "type": "object",
+            "properties": {
+                "declared_by_category_id": {
+                    "description": "Category the definition was declared on, which may be an ancestor",
+                    "type": "integer",
+                    "example": 10
+                },
+                "description": {
+                    "description": "What the attribute describes",
+                    "type": "string",
+                    "example": "Outer diameter of the barrel where the gas block mounts"
+                },
+                "enum": {
+                    "description": "Allowed values for string attributes; empty allows any value",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "name": {
+                    "description": "Attribute key in Part.Attributes",
+                    "type": "string",
+                    "example": "gas_block_journal_diameter"
+                },
+                "required": {
+                    "description": "Whether every part in the category must set the attribute",
+                    "type": "boolean",
+                    "example": true
+                },
+                "type": {
+                    "description": "Value type: string, number, integer or boolean",
+                    "type": "string",
+                    "enum": [
+                        "string",
+                        "number",
+                        "integer",
+                        "boolean"
+                    ],
+                    "example": "number"
+                },
+                "unit": {
+                    "description": "Unit the value is expressed in, if any",
+                    "type": "string",
+                    "example": "in"
+                }
+            }
+        },
+        "handlers.FitmentCheck": {
+            "type": "object",
+            "properties": {
+                "firearm_model_ids": {
+                    "description": "Firearm models both parts are declared to fit",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "firearm_model_names": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "message": {
+                    "type": "string",
+                    "example": "Glock 19 Slide is declared to fit Glock 19, but Glock 17 Frame is declared to fit Glock 17"
+                },
+                "result": {
+                    "type": "string",
+                    "enum": [
+                        "pass",
+                        "fail"
+                    ],
+                    "example": "fail"
+                }
+            }
+        },
+        "handlers.ForkPrebuiltInput": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "description": "Description of the new build, defaults to the prebuilt's description",
+                    "type": "string",
+                    "example": "Forked from the Standard AR-15 Rifle"
+                },
+                "name": {
+                    "description": "Name of the new build, defaults to the prebuilt's name",
+                    "type": "string",
+                    "example": "My Standard AR-15"
+                }
+            }
+        },
+        "handlers.ForkPrebuiltResult": {
+            "type": "object",
+            "properties": {
+                "build": {
+                    "$ref": "#/definitions/models.Build"
+                },
+                "unresolved": {
+                    "description": "Components of the prebuilt that were not carried over into the build",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.ForkUnresolvedEntry"
+                    }
+                },
+                "validation": {
+                    "description": "Validation of the new build, listing any required slots still to be filled",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/handlers.BuildValidationResult"
+                        }
+                    ]
+                }
+            }
+        },
+        "handlers.ForkUnresolvedEntry": {
+            "type": "object",
+            "properties": {
+                "part_id": {
+                    "description": "Part referenced by the component, if any",
+                    "type": "integer",
+                    "example": 12
+                },
+                "path": {
+                    "description": "Position of the component in the prebuilt's parts tree",
+                    "type": "string",
+                    "example": "Lower Assembly \u003e Lower Parts Kit"
+                },
+                "reason": {
+                    "description": "Why the component could not be placed",
+                    "type": "string",
+                    "example": "No part category named Lower Parts Kit"
+                }
+            }
+        },
+        "handlers.InvalidatedBuild": {
+            "type": "object",
+            "properties": {
+                "build_id": {
+                    "type": "integer",
+                    "example": 7
+                },
+                "build_name": {
+                    "type": "string",
+                    "example": "Lightweight 16in Carbine"
+                },
+                "failures": {
+                    "description": "Rule checks that fail under the ruleset",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.RuleCheck"
+                    }
+                }
+            }
+        },
+        "handlers.LawCheckInput": {
+            "type": "object",
+            "required": [
+                "firearm_model_id",
+                "jurisdiction"
+            ],
+            "properties": {
+                "as_of": {
+                    "description": "Date to check the laws as of, YYYY-MM-DD or RFC 3339; defaults to now",
+                    "type": "string",
+                    "example": "2025-01-01"
+                },
+                "firearm_model_id": {
+                    "description": "Firearm model the selection is for",
+                    "type": "integer",
+                    "example": 1
+                },
+                "jurisdiction": {
+                    "description": "Jurisdiction code or ID",
+                    "type": "string",
+                    "example": "US-CA"
+                },
+                "slots": {
+                    "description": "Selected parts keyed by part category (slot) ID",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "handlers.LawCheckResult": {
+            "type": "object",
+            "properties": {
+                "as_of": {
+                    "description": "Date the laws were checked as of",
+                    "type": "string",
+                    "example": "2025-01-01T00:00:00Z"
+                },
+                "build_id": {
+                    "description": "Firearm that was checked; both are omitted for unsaved selections",
+                    "type": "integer",
+                    "example": 1
+                },
+                "compliant": {
+                    "description": "Whether no applicable rule in effect is violated; undetermined rules do not count against it",
+                    "type": "boolean",
+                    "example": false
+                },
+                "firearm_class": {
+                    "description": "Firearm class the rules were applied for: rifle, pistol or shotgun as the firearm's NFA\nclassification has it, firearm for one that is none of them, or its model's category\nwhen the classification is undetermined; empty when that is not known either",
+                    "type": "string",
+                    "example": "rifle"
+                },
+                "jurisdiction": {
+                    "type": "string",
+                    "example": "US-CA"
+                },
+                "jurisdiction_name": {
+                    "type": "string",
+                    "example": "California"
+                },
+                "prebuilt_firearm_id": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "rules_checked": {
+                    "description": "Number of applicable rules in effect that were evaluated",
+                    "type": "integer",
+                    "example": 9
+                },
+                "undetermined": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.LawRuleResult"
+                    }
+                },
+                "upcoming": {
+                    "description": "Rules that take effect after as_of and that the firearm would violate, soonest first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.LawRuleResult"
+                    }
+                },
+                "violations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.LawRuleResult"
+                    }
+                }
+            }
+        },
+        "handlers.LawRuleResult": {
+            "type": "object",
+            "properties": {
+                "citation": {
+                    "type": "string",
+                    "example": "Cal. Penal Code § 32310"
+                },
+                "citation_url": {
+                    "type": "string",
+                    "example": "https://leginfo.legislature.ca.gov/faces/codes_displaySection.xhtml?lawCode=PEN\u0026sectionNum=32310"
+                },
+                "description": {
+                    "description": "Explanation of the rule",
+                    "type": "string",
+                    "example": "Magazines holding more than 10 rounds may not be manufactured, imported, sold or possessed"
+                },
+                "effective_from": {
+                    "description": "Dates the rule is in effect, if limited",
+                    "type": "string",
+                    "example": "2017-07-01T00:00:00Z"
+                },
+                "effective_until": {
+                    "type": "string"
+                },
+                "jurisdiction": {
+                    "type": "string",
+                    "example": "US-CA"
+                },
+                "message": {
+                    "description": "What was found, e.g. the offending part",
+                    "type": "string",
+                    "example": "PMAG 30-Round (AR-15) holds 30 rounds, more than the limit of 10"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Large-capacity magazine ban"
+                },
+                "part_id": {
+                    "description": "Part that violates the rule, if a single part does",
+                    "type": "integer",
+                    "example": 42
+                },
+                "rule_id": {
+                    "type": "integer",
+                    "example": 4
+                },
+                "source": {
+                    "description": "Bill or regulation behind the rule and the version of the rule that was applied",
+                    "type": "string",
+                    "example": "SB 1446 (2016)"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "satisfied",
+                        "violated",
+                        "undetermined"
+                    ],
+                    "example": "violated"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "max_magazine_capacity"
+                },
+                "version": {
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "handlers.ListingShippingStatus": {
+            "type": "object",
+            "properties": {
+                "can_ship": {
+                    "description": "Whether the seller ships the item to the state at all",
+                    "type": "boolean",
+                    "example": false
+                },
+                "ffl_required": {
+                    "description": "Whether the item only ships to a licensed dealer (FFL) in the state",
+                    "type": "boolean",
+                    "example": false
+                },
+                "listing_id": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "reasons": {
+                    "description": "Reasons of the restrictions that apply",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "We do not ship magazines to California"
+                    ]
+                },
+                "ship_to": {
+                    "type": "string",
+                    "example": "CA"
+                },
+                "shipping_class": {
+                    "description": "Shipping class of the listed item, if it has one",
+                    "type": "string",
+                    "example": "magazine"
+                }
+            }
+        },
+        "handlers.MagazineFamilyInput": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "caliber_ids": {
+                    "description": "Calibers the family feeds",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        1,
+                        2
+                    ]
+                },
+                "description": {
+                    "type": "string",
+                    "example": "NATO STANAG 4179 pattern AR-15/M16 box magazine"
+                },
+                "firearm_model_ids": {
+                    "description": "Firearm models whose magazine well takes the family",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        1
+                    ]
+                },
+                "name": {
+                    "type": "string",
+                    "example": "STANAG"
+                }
+            }
+        },
+        "handlers.MagazineFirearmsResult": {
+            "type": "object",
+            "properties": {
+                "caliber_ids": {
+                    "description": "Calibers the magazine feeds",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        1,
+                        2
+                    ]
+                },
+                "firearm_models": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.FirearmModel"
+                    }
+                },
+                "magazine": {
+                    "description": "Magazine variant the part is sold as",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/handlers.MagazineFit"
+                        }
+                    ]
+                },
+                "part_id": {
+                    "type": "integer",
+                    "example": 45
+                },
+                "part_name": {
+                    "type": "string",
+                    "example": "PMAG 30-Round (AR-15)"
+                },
+                "prebuilt_firearms": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PrebuiltFirearm"
+                    }
+                }
+            }
+        },
+        "handlers.MagazineFit": {
+            "type": "object",
+            "properties": {
+                "caliber": {
+                    "type": "string",
+                    "example": "5.56x45mm NATO"
+                },
+                "caliber_id": {
+                    "description": "Caliber the variant is made for, empty when it feeds every caliber of its family",
+                    "type": "integer",
+                    "example": 2
+                },
+                "capacity": {
+                    "type": "integer",
+                    "example": 30
+                },
+                "magazine_family": {
+                    "type": "string",
+                    "example": "STANAG"
+                },
+                "magazine_family_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "part_id": {
+                    "description": "Catalog part sold as the variant, if any",
+                    "type": "integer",
+                    "example": 45
+                },
+                "part_name": {
+                    "type": "string",
+                    "example": "PMAG 30-Round (AR-15)"
+                },
+                "variant_id": {
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "handlers.MagazineMatrixRow": {
+            "type": "object",
+            "properties": {
+                "caliber": {
+                    "type": "string",
+                    "example": "5.56x45mm NATO"
+                },
+                "caliber_id": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "capacities": {
+                    "description": "Capacities available for the caliber, smallest first",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        10,
+                        20,
+                        30,
+                        40
+                    ]
+                },
+                "firearm_model": {
+                    "type": "string",
+                    "example": "AR-15"
+                },
+                "firearm_model_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "magazine_family": {
+                    "type": "string",
+                    "example": "STANAG"
+                },
+                "magazine_family_id": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "handlers.MagazineVariantInput": {
+            "type": "object",
+            "required": [
+                "capacity"
+            ],
+            "properties": {
+                "caliber_id": {
+                    "description": "Caliber the variant is made for, one of its family's calibers; omit when it feeds them all",
+                    "type": "integer",
+                    "example": 2
+                },
+                "capacity": {
+                    "type": "integer",
+                    "example": 30
+                },
+                "notes": {
+                    "type": "string",
+                    "example": "Gen M3 with window"
+                },
+                "part_id": {
+                    "description": "Catalog part sold as the variant",
+                    "type": "integer",
+                    "example": 45
+                }
+            }
+        },
+        "handlers.ModelLinkage": {
+            "type": "object",
+            "properties": {
+                "firearm_model_ids": {
+                    "description": "Firearm models that use part A's category and part B's category",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "firearm_model_names": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "message": {
+                    "type": "string",
+                    "example": "Both categories are used by AR-15"
+                },
+                "result": {
+                    "type": "string",
+                    "enum": [
+                        "pass",
+                        "fail"
+                    ],
+                    "example": "pass"
+                }
+            }
+        },
+        "handlers.NFAClassification": {
+            "type": "object",
+            "properties": {
+                "barrel_length": {
+                    "description": "Shortest barrel and overall length in inches, if recorded",
+                    "type": "number",
+                    "example": 10.5
+                },
+                "build_id": {
+                    "description": "Firearm that was classified; both are omitted for unsaved selections",
+                    "type": "integer",
+                    "example": 1
+                },
+                "classification": {
+                    "type": "string",
+                    "enum": [
+                        "rifle",
+                        "short_barreled_rifle",
+                        "pistol",
+                        "any_other_weapon",
+                        "shotgun",
+                        "short_barreled_shotgun",
+                        "machine_gun",
+                        "firearm",
+                        "undetermined"
+                    ],
+                    "example": "short_barreled_rifle"
+                },
+                "firearm_model_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "nfa_regulated": {
+                    "description": "Whether the classification or a part makes the firearm NFA-regulated",
+                    "type": "boolean",
+                    "example": true
+                },
+                "overall_length": {
+                    "type": "number",
+                    "example": 27.5
+                },
+                "prebuilt_firearm_id": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "stock": {
+                    "description": "How the firearm is meant to be held: shoulder_stock, pistol_brace or none",
+                    "type": "string",
+                    "enum": [
+                        "shoulder_stock",
+                        "pistol_brace",
+                        "none"
+                    ],
+                    "example": "shoulder_stock"
+                },
+                "warnings": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.NFAWarning"
+                    }
+                }
+            }
+        },
+        "handlers.NFAWarning": {
+            "type": "object",
+            "properties": {
+                "citation": {
+                    "type": "string",
+                    "example": "26 U.S.C. § 5845(a)(3), (c)"
+                },
+                "code": {
+                    "description": "Classification or NFA item the warning is about",
+                    "type": "string",
+                    "example": "short_barreled_rifle"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "This configuration is a short-barreled rifle (SBR): the 10.5 in barrel is under 16 in and B5 SOPMOD Stock (AR-15) is a shoulder stock. It must be registered on an approved ATF Form 1 or Form 4 before it is assembled."
+                },
+                "part_id": {
+                    "description": "Part that makes the configuration regulated, if a single part does",
+                    "type": "integer",
+                    "example": 42
+                }
+            }
+        },
+        "handlers.PartFitmentInput": {
+            "type": "object",
+            "properties": {
+                "notes": {
+                    "description": "Installation notes, e.g. generation or variant caveats",
+                    "type": "string",
+                    "example": "Gen 3 and later only"
+                },
+                "requires_modification": {
+                    "description": "Whether the part needs gunsmithing or fitting to work with the model",
+                    "type": "boolean",
+                    "example": false
+                }
+            }
+        },
+        "handlers.PartItem": {
+            "type": "object",
+            "properties": {
+                "children": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.PartItem"
+                    }
+                },
+                "id": {
+                    "type": "string",
+                    "example": "upper-receiver"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Upper Receiver"
+                }
+            }
+        },
+        "handlers.PartMountInput": {
+            "type": "object",
+            "required": [
+                "mounting_interface_id",
+                "position",
+                "role",
+                "slots"
+            ],
+            "properties": {
+                "mounting_interface_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "notes": {
+                    "type": "string",
+                    "example": "Co-witnesses with iron sights"
+                },
+                "position": {
+                    "description": "Where the section is, or where the accessory must go; any is only valid for consumes",
+                    "type": "string",
+                    "enum": [
+                        "top",
+                        "bottom",
+                        "left",
+                        "right",
+                        "any"
+                    ],
+                    "example": "top"
+                },
+                "role": {
+                    "description": "provides for rail sections on the part, consumes for rail space the part occupies",
+                    "type": "string",
+                    "enum": [
+                        "provides",
+                        "consumes"
+                    ],
+                    "example": "consumes"
+                },
+                "slots": {
+                    "description": "Slots the section offers, or the accessory occupies",
+                    "type": "integer",
+                    "example": 4
+                }
+            }
+        },
+        "handlers.PrebuiltCompliance": {
+            "type": "object",
+            "properties": {
+                "data_issues": {
+                    "description": "Problems with the firearm's components or specifications found while checking it",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "magazine capacity is unknown: no magazine part records it and specifications have no magazine_capacity"
+                    ]
+                },
+                "firearm_model_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Colt LE6920"
+                },
+                "prebuilt_firearm_id": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.ComplianceCell"
+                    }
+                },
+                "unavailable_in": {
+                    "description": "Jurisdictions whose laws the firearm violates, e.g. for \"not available in\" badges",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "US-CA",
+                        "US-NY"
+                    ]
+                }
+            }
+        },
+        "handlers.PrebuiltOffer": {
+            "type": "object",
+            "properties": {
+                "ffl_required": {
+                    "description": "Whether the listing only ships to a licensed dealer (FFL) in the destination state, and why",
+                    "type": "boolean",
+                    "example": true
+                },
+                "listing_id": {
+                    "type": "integer",
+                    "example": 7
+                },
+                "price": {
+                    "type": "number",
+                    "example": 1249.99
+                },
+                "seller_id": {
+                    "type": "integer",
+                    "example": 4
+                },
+                "seller_name": {
+                    "type": "string",
+                    "example": "Palmetto State Armory"
+                },
+                "shipping_cost": {
+                    "type": "number",
+                    "example": 19.99
+                },
+                "shipping_notes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "Firearms ship to an FFL only"
+                    ]
+                },
+                "total": {
+                    "type": "number",
+                    "example": 1269.98
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://palmettostatearmory.com/product/standard-ar-15-rifle"
+                }
+            }
+        },
+        "handlers.ProductListingWithShipping": {
+            "type": "object",
+            "properties": {
+                "additional_info": {
+                    "description": "Additional seller-specific information\n@Description JSON object containing additional product details",
+                    "type": "string",
+                    "example": "{\"condition\":\"new\",\"warranty\":\"lifetime\",\"made_in_usa\":true}"
+                },
+                "availability": {
+                    "description": "Current availability status (in_stock, out_of_stock, backorder)",
+                    "type": "string",
+                    "example": "in_stock"
+                },
+                "created_at": {
+                    "description": "Creation timestamp",
+                    "type": "string"
+                },
+                "currency": {
+                    "description": "Currency of the price",
+                    "type": "string",
+                    "example": "USD"
+                },
+                "id": {
+                    "description": "Unique identifier for the product listing",
+                    "type": "integer",
+                    "example": 1
+                },
+                "last_checked": {
+                    "description": "Last time the listing was checked/updated",
+                    "type": "string"
+                },
+                "part": {
+                    "description": "Related part information if this is a part listing",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Part"
+                        }
+                    ]
+                },
+                "part_id": {
+                    "description": "Optional: ID of the part if this listing is for a part",
+                    "type": "integer",
+                    "example": 1
+                },
+                "prebuilt_firearm": {
+                    "description": "Related prebuilt firearm information if this is a prebuilt listing",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.PrebuiltFirearm"
+                        }
+                    ]
+                },
+                "prebuilt_id": {
+                    "description": "Optional: ID of the prebuilt firearm if this listing is for a prebuilt",
+                    "type": "integer",
+                    "example": 1
+                },
+                "price": {
+                    "description": "Current price of the product",
+                    "type": "number",
+                    "example": 129.99
+                },
+                "seller": {
+                    "description": "Related seller information",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Seller"
+                        }
+                    ]
+                },
+                "seller_id": {
+                    "description": "ID of the seller offering this product",
+                    "type": "integer",
+                    "example": 1
+                },
+                "shipping": {
+                    "description": "Shipping status for the ship_to state, omitted when none was given",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/handlers.ListingShippingStatus"
+                        }
+                    ]
+                },
+                "shipping_info": {
+                    "description": "Shipping information\n@Description JSON object containing shipping details",
+                    "type": "string",
+                    "example": "{\"free_shipping\":true,\"shipping_cost\":0,\"handling_time\":\"1-3 business days\"}"
+                },
+                "sku": {
+                    "description": "Seller's SKU for the product",
+                    "type": "string",
+                    "example": "BRN-BCG-01"
+                },
+                "updated_at": {
+                    "description": "Last update timestamp",
+                    "type": "string"
+                },
+                "url": {
+                    "description": "URL to the product on the seller's website",
+                    "type": "string",
+                    "example": "https://www.brownells.com/products/bcg-standard"
+                }
+            }
+        },
+        "handlers.RailAccessory": {
+            "type": "object",
+            "properties": {
+                "part_id": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "part_name": {
+                    "type": "string",
+                    "example": "Red Dot Mount (AR-15)"
+                },
+                "slots": {
+                    "type": "integer",
+                    "example": 5
+                }
+            }
+        },
+        "handlers.RailMountShortfall": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "Not enough free Picatinny slots on top: needs 5, the most free on one section is 3"
+                },
+                "mounting_interface": {
+                    "type": "string",
+                    "example": "Picatinny"
+                },
+                "part_category_id": {
+                    "description": "Slot (part category) the accessory was selected for",
+                    "type": "integer",
+                    "example": 27
+                },
+                "part_id": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "part_name": {
+                    "type": "string",
+                    "example": "Red Dot Mount (AR-15)"
+                },
+                "position": {
+                    "type": "string",
+                    "example": "top"
+                },
+                "slots": {
+                    "type": "integer",
+                    "example": 5
+                }
+            }
+        },
+        "handlers.RailSectionUsage": {
+            "type": "object",
+            "properties": {
+                "accessories": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.RailAccessory"
+                    }
+                },
+                "free_length": {
+                    "description": "Free rail length in inches, when the interface's slot pitch is recorded",
+                    "type": "number",
+                    "example": 10.64
+                },
+                "free_slots": {
+                    "type": "integer",
+                    "example": 27
+                },
+                "mount_id": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "mounting_interface": {
+                    "type": "string",
+                    "example": "Picatinny"
+                },
+                "mounting_interface_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "part_id": {
+                    "type": "integer",
+                    "example": 20
+                },
+                "part_name": {
+                    "type": "string",
+                    "example": "M-LOK Handguard (AR-15)"
+                },
+                "position": {
+                    "type": "string",
+                    "example": "top"
+                },
+                "slots": {
+                    "type": "integer",
+                    "example": 36
+                },
+                "used_slots": {
+                    "type": "integer",
+                    "example": 9
+                }
+            }
+        },
+        "handlers.RuleCheck": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "gas_block_bore_diameter 0.625 must equal gas_block_journal_diameter 0.75"
+                },
+                "result": {
+                    "description": "pass, fail, or unknown when either part lacks the attribute",
+                    "type": "string",
+                    "enum": [
+                        "pass",
+                        "fail",
+                        "unknown"
+                    ],
+                    "example": "fail"
+                },
+                "rule_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "rule_name": {
+                    "type": "string",
+                    "example": "Gas block fits barrel journal"
+                },
+                "source_attribute": {
+                    "type": "string",
+                    "example": "gas_block_bore_diameter"
+                },
+                "source_part_id": {
+                    "description": "Part and attribute read as the rule's source and target",
+                    "type": "integer",
+                    "example": 31
+                },
+                "target_attribute": {
+                    "type": "string",
+                    "example": "gas_block_journal_diameter"
+                },
+                "target_part_id": {
+                    "type": "integer",
+                    "example": 7
+                }
+            }
+        },
+        "handlers.RuleSetImportReport": {
+            "type": "object",
+            "properties": {
+                "added_rules": {
+                    "description": "Rules, by name, the ruleset adds, drops or redefines compared to the current rules",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "builds_checked": {
+                    "description": "Saved builds that pass the current rules but fail the ruleset",
+                    "type": "integer",
+                    "example": 12
+                },
+                "changed_rules": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "checksum": {
+                    "type": "string",
+                    "example": "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+                },
+                "dry_run": {
+                    "description": "Whether the ruleset was only checked and not applied",
+                    "type": "boolean",
+                    "example": true
+                },
+                "invalidated_builds": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.InvalidatedBuild"
+                    }
+                },
+                "name": {
+                    "type": "string",
+                    "example": "AR-15 core rules"
+                },
+                "previous_version": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "removed_rules": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "rule_count": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "version": {
+                    "description": "Version the ruleset was, or would be, imported as",
+                    "type": "integer",
+                    "example": 4
+                }
+            }
+        },
+        "handlers.SellerCart": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.CartItem"
+                    }
+                },
+                "seller_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "seller_name": {
+                    "type": "string",
+                    "example": "Brownells"
+                },
+                "shipping": {
+                    "type": "number",
+                    "example": 12.99
+                },
+                "subtotal": {
+                    "description": "Sum of item prices, the single shipping charge for the order, and their total",
+                    "type": "number",
+                    "example": 489.97
+                },
+                "total": {
+                    "type": "number",
+                    "example": 502.96
+                }
+            }
+        },
+        "models.Build": {
+            "description": "A firearm model together with the part chosen for each of its part category slots",
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "description": "Creation timestamp",
+                    "type": "string"
+                },
+                "description": {
+                    "description": "Description of the build",
+                    "type": "string",
+                    "example": "A lightweight AR-15 build for general purpose use."
+                },
+                "firearm_model_id": {
+                    "description": "Reference to the base firearm model",
+                    "type": "integer",
+                    "example": 1
+                },
+                "id": {
+                    "description": "Unique identifier for the build",
+                    "type": "integer",
+                    "example": 1
+                },
+                "name": {
+                    "description": "Name of the build",
+                    "type": "string",
+                    "example": "Lightweight 16in Carbine"
+                },
+                "parts": {
+                    "description": "Parts selected for each slot of the build",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.BuildPart"
+                    }
+                },
+                "updated_at": {
+                    "description": "Last update timestamp",
+                    "type": "string"
+                }
+            }
+        },
+        "models.BuildPart": {
+            "description": "A slot in a build, identified by a part category of the build's firearm model, and the part chosen for it",
+            "type": "object",
+            "properties": {
+                "build_id": {
+                    "description": "Reference to the build this slot belongs to",
+                    "type": "integer",
+                    "example": 1
+                },
+                "created_at": {
+                    "description": "Creation timestamp",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "Unique identifier for the build part",
+                    "type": "integer",
+                    "example": 1
+                },
+                "part": {
+                    "$ref": "#/definitions/models.Part"
+                },
+                "part_category_id": {
+                    "description": "Part category that identifies the slot",
+                    "type": "integer",
+                    "example": 10
+                },
+                "part_id": {
+                    "description": "Reference to the part chosen for the slot",
+                    "type": "integer",
+                    "example": 5
+                }
+            }
+        },
+        "models.BuildRevision": {
+            "description": "Append-only history entry recording a build's details and slot selections at a point in time",
+            "type": "object",
+            "properties": {
+                "build_id": {
+                    "description": "Reference to the build this revision belongs to",
+                    "type": "integer",
+                    "example": 1
+                },
+                "created_at": {
+                    "description": "Creation timestamp",
+                    "type": "string"
+                },
+                "description": {
+                    "description": "Description of the build at this revision",
+                    "type": "string",
+                    "example": "A lightweight AR-15 build for general purpose use."
+                },
+                "firearm_model_id": {
+                    "description": "Firearm model of the build at this revision",
+                    "type": "integer",
+                    "example": 1
+                },
+                "id": {
+                    "description": "Unique identifier for the revision",
+                    "type": "integer",
+                    "example": 1
+                },
+                "name": {
+                    "description": "Name of the build at this revision",
+                    "type": "string",
+                    "example": "Lightweight 16in Carbine"
+                },
+                "revision": {
+                    "description": "Sequential revision number within the build, starting at 1",
+                    "type": "integer",
+                    "example": 3
+                },
+                "slots": {
+                    "description": "Selected parts keyed by part category (slot) ID",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    },
+                    "example": {
+                        "10": 5,
+                        "13": 2
+                    }
+                }
+            }
+        },
+        "models.Caliber": {
+            "description": "Cartridge information including alternate names, case dimensions and which cartridges a chamber can safely fire",
+            "type": "object",
+            "properties": {
+                "aliases": {
+                    "description": "Alternate names the caliber is sold under",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "[\"5.56 NATO\"",
+                        "\"5.56mm\"]"
+                    ]
+                },
+                "bullet_diameter": {
+                    "description": "Bullet diameter in inches",
+                    "type": "number",
+                    "example": 0.224
+                },
+                "case_length": {
+                    "description": "Case length in millimetres",
+                    "type": "number",
+                    "example": 44.7
+                },
+                "created_at": {
+                    "description": "Creation timestamp",
+                    "type": "string"
+                },
+                "fires_calibers": {
+                    "description": "Other calibers that can be safely fired in a chamber for this caliber",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Caliber"
+                    }
+                },
+                "id": {
+                    "description": "Unique identifier for the caliber",
+                    "type": "integer",
+                    "example": 1
+                },
+                "name": {
+                    "description": "Canonical name of the caliber",
+                    "type": "string",
+                    "example": "5.56x45mm NATO"
+                },
+                "overall_length": {
+                    "description": "Overall cartridge length in millimetres",
+                    "type": "number",
+                    "example": 57.4
+                },
+                "parent_caliber": {
+                    "$ref": "#/definitions/models.Caliber"
+                },
+                "parent_caliber_id": {
+                    "description": "Cartridge this caliber was derived from, e.g. .223 Remington for .300 AAC Blackout",
+                    "type": "integer",
+                    "example": 2
+                },
+                "type": {
+                    "description": "Broad type of cartridge",
+                    "type": "string",
+                    "enum": [
+                        "rifle",
+                        "pistol",
+                        "rimfire",
+                        "shotgun"
+                    ],
+                    "example": "rifle"
+                },
+                "updated_at": {
+                    "description": "Last update timestamp",
+                    "type": "string"
+                }
+            }
+        },
+        "models.CompatibilityRule": {
+            "description": "Rule comparing an attribute of parts in one category with an attribute of parts in another, e.g. gas block bore must equal barrel journal diameter",
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "description": "Creation timestamp",
                     "type": "string"
                 },
                 "description": {
-                    "description": "Description of the category",
+                    "description": "Explanation shown when the rule fails",
                     "type": "string",
-                    "example": "Core upper receiver components"
+                    "example": "Gas block inner diameter must equal the barrel's gas block journal diameter"
+                },
+                "exceptions": {
+                    "description": "Part pairings the rule does not apply to",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CompatibilityRuleException"
+                    }
                 },
                 "id": {
-                    "description": "Unique identifier for the category",
+                    "description": "Unique identifier for the rule",
                     "type": "integer",
                     "example": 1
                 },
-                "is_required": {
-                    "type": "boolean",
-                    "example": true
+                "name": {
+                    "description": "Short name of the rule",
+                    "type": "string",
+                    "example": "Gas block fits barrel journal"
+                },
+                "operator": {
+                    "description": "Comparison applied as source \u003coperator\u003e target (eq, ne, lt, lte, gt, gte)",
+                    "type": "string",
+                    "example": "eq"
+                },
+                "rule_set_id": {
+                    "description": "Ruleset version that imported the rule, unset for rules managed through the API",
+                    "type": "integer",
+                    "example": 3
+                },
+                "source_attribute": {
+                    "description": "Attribute read from the source part",
+                    "type": "string",
+                    "example": "gas_block_bore_diameter"
+                },
+                "source_category_id": {
+                    "description": "Category (including its subcategories) whose parts supply the left-hand attribute",
+                    "type": "integer",
+                    "example": 11
+                },
+                "target_attribute": {
+                    "description": "Attribute read from the target part",
+                    "type": "string",
+                    "example": "gas_block_journal_diameter"
+                },
+                "target_category_id": {
+                    "description": "Category (including its subcategories) whose parts supply the right-hand attribute",
+                    "type": "integer",
+                    "example": 10
+                },
+                "tolerance": {
+                    "description": "Allowed difference when comparing numeric attributes for equality",
+                    "type": "number",
+                    "example": 0.001
+                },
+                "updated_at": {
+                    "description": "Last update timestamp",
+                    "type": "string"
+                }
+            }
+        },
+        "models.CompatibilityRuleException": {
+            "description": "Known-good pairing that passes a rule regardless of attributes; an unset part matches any part",
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "description": "Creation timestamp",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "Unique identifier for the exception",
+                    "type": "integer",
+                    "example": 1
+                },
+                "reason": {
+                    "description": "Why the pairing is known to work",
+                    "type": "string",
+                    "example": "Ships with a 0.750 adapter sleeve"
+                },
+                "rule_id": {
+                    "description": "Rule the exception belongs to",
+                    "type": "integer",
+                    "example": 1
+                },
+                "source_part_id": {
+                    "description": "Source part exempted, or any source part when unset",
+                    "type": "integer",
+                    "example": 31
+                },
+                "target_part_id": {
+                    "description": "Target part exempted, or any target part when unset",
+                    "type": "integer",
+                    "example": 7
+                }
+            }
+        },
+        "models.FirearmModel": {
+            "description": "Firearm model information including hierarchical parts structure",
+            "type": "object",
+            "properties": {
+                "action_type": {
+                    "description": "How the firearm cycles, which assault weapon laws depend on; unknown when empty",
+                    "type": "string",
+                    "enum": [
+                        "semi_auto",
+                        "bolt",
+                        "pump",
+                        "lever",
+                        "single_shot"
+                    ],
+                    "example": "semi_auto"
+                },
+                "caliber": {
+                    "$ref": "#/definitions/models.Caliber"
+                },
+                "caliber_id": {
+                    "description": "Caliber the firearm model is chambered in by default",
+                    "type": "integer",
+                    "example": 1
+                },
+                "category": {
+                    "description": "Category of the firearm",
+                    "type": "string",
+                    "example": "Rifle"
+                },
+                "created_at": {
+                    "description": "Creation timestamp",
+                    "type": "string"
+                },
+                "description": {
+                    "description": "Description of the firearm model",
+                    "type": "string",
+                    "example": "A high-quality semi-automatic modular rifle platform."
+                },
+                "id": {
+                    "description": "Unique identifier for the firearm model",
+                    "type": "integer",
+                    "example": 1
+                },
+                "images": {
+                    "description": "Image URLs for the firearm model",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "[\"https://example.com/images/firearms/AR-15.jpg\"]"
+                    ]
+                },
+                "magazine_type": {
+                    "description": "How the firearm is fed, which assault weapon laws depend on; unknown when empty",
+                    "type": "string",
+                    "enum": [
+                        "detachable",
+                        "fixed",
+                        "tube"
+                    ],
+                    "example": "detachable"
+                },
+                "manufacturer_id": {
+                    "description": "Reference to the manufacturer",
+                    "type": "integer",
+                    "example": 1
                 },
                 "name": {
-                    "description": "Name of the category",
+                    "description": "Name of the firearm model",
                     "type": "string",
-                    "example": "Upper Assembly"
+                    "example": "AR-15"
                 },
-                "parent_category": {
-                    "description": "Parent category (self-referential relationship)",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/models.PartCategory"
-                        }
+                "part_categories": {
+                    "description": "Related part categories through FirearmModelPartCategory",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PartCategory"
+                    }
+                },
+                "price_range": {
+                    "description": "Price range for the firearm model",
+                    "type": "string",
+                    "example": "$765 - $1569"
+                },
+                "specifications": {
+                    "description": "Specifications of the firearm",
+                    "type": "string",
+                    "example": "{\"weight\": \"6.5 lbs\"}"
+                },
+                "subcategory": {
+                    "description": "Subcategory of the firearm",
+                    "type": "string",
+                    "example": "Assault"
+                },
+                "updated_at": {
+                    "description": "Last update timestamp",
+                    "type": "string"
+                },
+                "variant": {
+                    "description": "Variant of the firearm model",
+                    "type": "string",
+                    "example": ""
+                }
+            }
+        },
+        "models.Jurisdiction": {
+            "description": "Federal or state jurisdiction; the laws of a jurisdiction include those of its parent",
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "Short code, e.g. US for federal law or US-CA for California",
+                    "type": "string",
+                    "example": "US-CA"
+                },
+                "created_at": {
+                    "description": "Creation timestamp",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "Unique identifier for the jurisdiction",
+                    "type": "integer",
+                    "example": 2
+                },
+                "level": {
+                    "description": "Level of government",
+                    "type": "string",
+                    "enum": [
+                        "federal",
+                        "state"
+                    ],
+                    "example": "state"
+                },
+                "name": {
+                    "description": "Name of the jurisdiction",
+                    "type": "string",
+                    "example": "California"
+                },
+                "parent_jurisdiction": {
+                    "$ref": "#/definitions/models.Jurisdiction"
+                },
+                "parent_jurisdiction_id": {
+                    "description": "Jurisdiction whose laws also apply, e.g. federal law for a state",
+                    "type": "integer",
+                    "example": 1
+                },
+                "updated_at": {
+                    "description": "Last update timestamp",
+                    "type": "string"
+                }
+            }
+        },
+        "models.LawRule": {
+            "description": "Legal limit or prohibition of a jurisdiction, optionally limited to a firearm class or model, with its citation and the dates it is in effect",
+            "type": "object",
+            "properties": {
+                "citation": {
+                    "description": "Statute or regulation the rule comes from",
+                    "type": "string",
+                    "example": "Cal. Penal Code § 32310"
+                },
+                "citation_url": {
+                    "description": "Link to the text of the statute, if any",
+                    "type": "string",
+                    "example": "https://leginfo.legislature.ca.gov/faces/codes_displaySection.xhtml?lawCode=PEN\u0026sectionNum=32310"
+                },
+                "created_at": {
+                    "description": "Creation timestamp",
+                    "type": "string"
+                },
+                "description": {
+                    "description": "Explanation shown when the rule is violated",
+                    "type": "string",
+                    "example": "Magazines holding more than 10 rounds may not be manufactured, imported, sold or possessed"
+                },
+                "effective_from": {
+                    "description": "Date the rule takes effect; it has always applied when unset",
+                    "type": "string",
+                    "example": "2017-07-01T00:00:00Z"
+                },
+                "effective_until": {
+                    "description": "Date the rule stops applying, exclusive; it applies indefinitely when unset",
+                    "type": "string",
+                    "example": "2030-01-01T00:00:00Z"
+                },
+                "feature": {
+                    "description": "Feature a banned feature rule prohibits",
+                    "type": "string",
+                    "example": "flash_hider"
+                },
+                "features": {
+                    "description": "Features an assault weapon feature rule prohibits, any one of which is enough",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "[\"pistol_grip\"",
+                        "\"adjustable_stock\"",
+                        "\"flash_hider\"]"
+                    ]
+                },
+                "firearm_class": {
+                    "description": "Firearm class the rule is limited to, or every class when empty",
+                    "type": "string",
+                    "enum": [
+                        "rifle",
+                        "pistol",
+                        "shotgun"
+                    ],
+                    "example": "rifle"
+                },
+                "firearm_model": {
+                    "$ref": "#/definitions/models.FirearmModel"
+                },
+                "firearm_model_id": {
+                    "description": "Firearm model the rule is limited to, or every model when unset",
+                    "type": "integer",
+                    "example": 1
+                },
+                "id": {
+                    "description": "Unique identifier for the law rule",
+                    "type": "integer",
+                    "example": 1
+                },
+                "jurisdiction": {
+                    "$ref": "#/definitions/models.Jurisdiction"
+                },
+                "jurisdiction_id": {
+                    "description": "Jurisdiction that enacted the rule",
+                    "type": "integer",
+                    "example": 2
+                },
+                "name": {
+                    "description": "Short name of the rule",
+                    "type": "string",
+                    "example": "Large-capacity magazine ban"
+                },
+                "source": {
+                    "description": "Bill, session law or regulation that enacted or last amended the rule",
+                    "type": "string",
+                    "example": "SB 1446 (2016)"
+                },
+                "type": {
+                    "description": "Kind of rule",
+                    "type": "string",
+                    "enum": [
+                        "min_barrel_length",
+                        "min_overall_length",
+                        "max_magazine_capacity",
+                        "banned_feature",
+                        "suppressor_prohibited",
+                        "assault_weapon_features"
+                    ],
+                    "example": "max_magazine_capacity"
+                },
+                "updated_at": {
+                    "description": "Last update timestamp",
+                    "type": "string"
+                },
+                "value": {
+                    "description": "Length in inches or capacity in rounds for limit rules",
+                    "type": "number",
+                    "example": 10
+                },
+                "version": {
+                    "description": "Revision number, starting at 1 and increased by every change",
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "models.LawRuleChange": {
+            "description": "Append-only audit entry recording who created, updated or deleted a law rule, when, and the rule before and after",
+            "type": "object",
+            "properties": {
+                "action": {
+                    "description": "Kind of change",
+                    "type": "string",
+                    "enum": [
+                        "created",
+                        "updated",
+                        "deleted"
+                    ],
+                    "example": "updated"
+                },
+                "after": {
+                    "description": "Rule after the change, empty for deletions",
+                    "type": "object"
+                },
+                "before": {
+                    "description": "Rule before the change, empty for creations",
+                    "type": "object"
+                },
+                "changed_by": {
+                    "description": "Person or system that made the change",
+                    "type": "string",
+                    "example": "jdoe"
+                },
+                "changed_fields": {
+                    "description": "Names of the fields that changed",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "value",
+                        "effective_from"
                     ]
                 },
-                "parent_category_id": {
-                    "description": "Parent category ID for hierarchical relationships (null for top-level categories)",
+                "created_at": {
+                    "description": "Time of the change",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "Unique identifier for the change",
+                    "type": "integer",
+                    "example": 1
+                },
+                "jurisdiction_id": {
+                    "description": "Jurisdiction of the rule at the time of the change",
+                    "type": "integer",
+                    "example": 2
+                },
+                "law_rule_id": {
+                    "description": "Law rule that was changed; kept after the rule is deleted",
                     "type": "integer",
-                    "example": 0
+                    "example": 4
                 },
-                "updated_at": {
-                    "description": "Last update timestamp",
-                    "type": "string"
+                "reason": {
+                    "description": "Why the change was made, e.g. the bill that prompted it",
+                    "type": "string",
+                    "example": "AB 1234 raised the limit"
+                },
+                "version": {
+                    "description": "Version of the rule the change produced, or the last version for deletions",
+                    "type": "integer",
+                    "example": 2
                 }
             }
         },
-        "handlers.PartItem": {
+        "models.MagazineFamily": {
+            "description": "Magazine pattern with the firearm models that take it and the calibers it feeds",
             "type": "object",
             "properties": {
-                "children": {
+                "calibers": {
+                    "description": "Calibers the family feeds",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/handlers.PartItem"
+                        "$ref": "#/definitions/models.Caliber"
                     }
                 },
-                "id": {
+                "created_at": {
+                    "description": "Creation timestamp",
+                    "type": "string"
+                },
+                "description": {
+                    "description": "Description of the magazine family",
                     "type": "string",
-                    "example": "upper-receiver"
+                    "example": "NATO STANAG 4179 pattern AR-15/M16 box magazine"
+                },
+                "firearm_models": {
+                    "description": "Firearm models whose magazine well takes the family",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.FirearmModel"
+                    }
+                },
+                "id": {
+                    "description": "Unique identifier for the magazine family",
+                    "type": "integer",
+                    "example": 1
                 },
                 "name": {
+                    "description": "Name of the magazine family",
                     "type": "string",
-                    "example": "Upper Receiver"
+                    "example": "STANAG"
+                },
+                "updated_at": {
+                    "description": "Last update timestamp",
+                    "type": "string"
+                },
+                "variants": {
+                    "description": "Capacity variants of the family",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.MagazineVariant"
+                    }
                 }
             }
         },
-        "models.FirearmModel": {
-            "description": "Firearm model information including hierarchical parts structure",
+        "models.MagazineVariant": {
+            "description": "Magazine of a family with a given capacity, optionally for a single caliber of the family",
             "type": "object",
             "properties": {
-                "category": {
-                    "description": "Category of the firearm",
-                    "type": "string",
-                    "example": "Rifle"
+                "caliber": {
+                    "$ref": "#/definitions/models.Caliber"
+                },
+                "caliber_id": {
+                    "description": "Caliber the variant is made for; when empty it feeds every caliber of its family",
+                    "type": "integer",
+                    "example": 2
+                },
+                "capacity": {
+                    "description": "Rounds the magazine holds",
+                    "type": "integer",
+                    "example": 30
                 },
                 "created_at": {
                     "description": "Creation timestamp",
                     "type": "string"
                 },
-                "description": {
-                    "description": "Description of the firearm model",
-                    "type": "string",
-                    "example": "A high-quality semi-automatic modular rifle platform."
-                },
                 "id": {
-                    "description": "Unique identifier for the firearm model",
+                    "description": "Unique identifier for the magazine variant",
                     "type": "integer",
                     "example": 1
                 },
-                "images": {
-                    "description": "Image URLs for the firearm model",
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    },
-                    "example": [
-                        "[\"https://example.com/images/firearms/AR-15.jpg\"]"
-                    ]
+                "magazine_family": {
+                    "$ref": "#/definitions/models.MagazineFamily"
                 },
-                "manufacturer_id": {
-                    "description": "Reference to the manufacturer",
+                "magazine_family_id": {
+                    "description": "Magazine family the variant belongs to",
                     "type": "integer",
                     "example": 1
                 },
-                "name": {
-                    "description": "Name of the firearm model",
+                "notes": {
+                    "description": "Variant notes, e.g. generation or follower colour",
                     "type": "string",
-                    "example": "AR-15"
+                    "example": "Gen M3 with window"
                 },
-                "part_categories": {
-                    "description": "Related part categories through FirearmModelPartCategory",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.PartCategory"
-                    }
+                "part": {
+                    "$ref": "#/definitions/models.Part"
                 },
-                "price_range": {
-                    "description": "Price range for the firearm model",
-                    "type": "string",
-                    "example": "$765 - $1569"
+                "part_id": {
+                    "description": "Catalog part sold as this variant, if any; a part belongs to at most one variant",
+                    "type": "integer",
+                    "example": 45
                 },
-                "specifications": {
-                    "description": "Specifications of the firearm",
+                "updated_at": {
+                    "description": "Last update timestamp",
+                    "type": "string"
+                }
+            }
+        },
+        "models.MountingInterface": {
+            "description": "Accessory mounting standard whose rail sections are measured in slots",
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "description": "Creation timestamp",
+                    "type": "string"
+                },
+                "description": {
+                    "description": "Description of the mounting interface",
                     "type": "string",
-                    "example": "{\"weight\": \"6.5 lbs\", \"caliber\": \"5.56x45mm NATO\"}"
+                    "example": "MIL-STD-1913 rail with cross slots on 0.394 in centers"
                 },
-                "subcategory": {
-                    "description": "Subcategory of the firearm",
+                "id": {
+                    "description": "Unique identifier for the mounting interface",
+                    "type": "integer",
+                    "example": 1
+                },
+                "name": {
+                    "description": "Name of the mounting interface",
                     "type": "string",
-                    "example": "Assault"
+                    "example": "Picatinny"
+                },
+                "slot_pitch": {
+                    "description": "Distance between slot centres in inches, used to express slot counts as rail length; 0 when not recorded",
+                    "type": "number",
+                    "example": 0.394
                 },
                 "updated_at": {
                     "description": "Last update timestamp",
                     "type": "string"
-                },
-                "variant": {
-                    "description": "Variant of the firearm model",
-                    "type": "string",
-                    "example": ""
                 }
             }
         },
@@ -2705,6 +11204,25 @@ const docTemplate = `{
             "description": "Detailed information about a firearm part including compatibility and specifications",
             "type": "object",
             "properties": {
+                "attributes": {
+                    "description": "Typed interface attributes evaluated by compatibility rules, e.g. thread pitch or gas system\nlength; the caliber comes from caliber_id and cannot be set here",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    },
+                    "example": {
+                        "gas_system_length": "carbine",
+                        "thread_pitch": "1/2x28"
+                    }
+                },
+                "caliber": {
+                    "$ref": "#/definitions/models.Caliber"
+                },
+                "caliber_id": {
+                    "description": "Caliber the part is made for, for caliber-sensitive parts such as barrels, bolts, magazines and ammunition",
+                    "type": "integer",
+                    "example": 1
+                },
                 "created_at": {
                     "description": "Creation timestamp",
                     "type": "string"
@@ -2749,6 +11267,19 @@ const docTemplate = `{
                     "type": "string",
                     "example": "PMAG 30 AR/M4 GEN M3"
                 },
+                "nfa_tag": {
+                    "description": "NFA-relevant role of the part, overriding the one its category implies",
+                    "type": "string",
+                    "enum": [
+                        "suppressor",
+                        "short_barrel",
+                        "shoulder_stock",
+                        "pistol_brace",
+                        "vertical_foregrip",
+                        "full_auto"
+                    ],
+                    "example": "pistol_brace"
+                },
                 "part_category": {
                     "$ref": "#/definitions/models.PartCategory"
                 },
@@ -2772,6 +11303,13 @@ const docTemplate = `{
             "description": "Hierarchical structure of part categories",
             "type": "object",
             "properties": {
+                "attribute_schema": {
+                    "description": "Attributes parts in this category and its subcategories must satisfy, as a list of AttributeDefinition",
+                    "type": "array",
+                    "items": {
+                        "type": "object"
+                    }
+                },
                 "child_categories": {
                     "description": "Child categories (reverse relationship)",
                     "type": "array",
@@ -2798,6 +11336,19 @@ const docTemplate = `{
                     "type": "string",
                     "example": "Upper Assembly"
                 },
+                "nfa_tag": {
+                    "description": "NFA-relevant role of parts in this category and its subcategories, if any",
+                    "type": "string",
+                    "enum": [
+                        "suppressor",
+                        "short_barrel",
+                        "shoulder_stock",
+                        "pistol_brace",
+                        "vertical_foregrip",
+                        "full_auto"
+                    ],
+                    "example": "suppressor"
+                },
                 "parent_category": {
                     "description": "Parent category (self-referential relationship)",
                     "allOf": [
@@ -2811,6 +11362,127 @@ const docTemplate = `{
                     "type": "integer",
                     "example": 0
                 },
+                "shipping_class": {
+                    "description": "Shipping class of parts in this category and its subcategories that sellers may restrict, if any",
+                    "type": "string",
+                    "enum": [
+                        "magazine",
+                        "ammunition",
+                        "firearm"
+                    ],
+                    "example": "magazine"
+                },
+                "updated_at": {
+                    "description": "Last update timestamp",
+                    "type": "string"
+                }
+            }
+        },
+        "models.PartFirearmModel": {
+            "description": "Explicit fitment of a part to a firearm model, with notes and whether fitting requires modification",
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "description": "Creation timestamp",
+                    "type": "string"
+                },
+                "firearm_model": {
+                    "$ref": "#/definitions/models.FirearmModel"
+                },
+                "firearm_model_id": {
+                    "description": "Firearm model the part fits",
+                    "type": "integer",
+                    "example": 1
+                },
+                "id": {
+                    "description": "Unique identifier for the fitment",
+                    "type": "integer",
+                    "example": 1
+                },
+                "notes": {
+                    "description": "Installation notes, e.g. generation or variant caveats",
+                    "type": "string",
+                    "example": "Gen 3 and later only"
+                },
+                "part": {
+                    "$ref": "#/definitions/models.Part"
+                },
+                "part_id": {
+                    "description": "Part that fits the firearm model",
+                    "type": "integer",
+                    "example": 12
+                },
+                "requires_modification": {
+                    "description": "Whether the part needs gunsmithing or fitting to work with the model",
+                    "type": "boolean",
+                    "example": false
+                },
+                "updated_at": {
+                    "description": "Last update timestamp",
+                    "type": "string"
+                }
+            }
+        },
+        "models.PartMount": {
+            "description": "Rail section a part provides, or rail space an accessory consumes, on a mounting interface",
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "description": "Creation timestamp",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "Unique identifier for the mount",
+                    "type": "integer",
+                    "example": 1
+                },
+                "mounting_interface": {
+                    "$ref": "#/definitions/models.MountingInterface"
+                },
+                "mounting_interface_id": {
+                    "description": "Mounting interface of the rail section",
+                    "type": "integer",
+                    "example": 1
+                },
+                "notes": {
+                    "description": "Mounting notes, e.g. sight height or clamp type",
+                    "type": "string",
+                    "example": "Continuous top rail, flush with the upper receiver"
+                },
+                "part": {
+                    "$ref": "#/definitions/models.Part"
+                },
+                "part_id": {
+                    "description": "Part providing or consuming rail space",
+                    "type": "integer",
+                    "example": 20
+                },
+                "position": {
+                    "description": "Where the section is, or for consumed mounts where the accessory must go",
+                    "type": "string",
+                    "enum": [
+                        "top",
+                        "bottom",
+                        "left",
+                        "right",
+                        "any"
+                    ],
+                    "example": "top"
+                },
+                "role": {
+                    "description": "Whether the part provides the rail section or consumes space on one",
+                    "type": "string",
+                    "enum": [
+                        "provides",
+                        "consumes"
+                    ],
+                    "example": "provides"
+                },
+                "slots": {
+                    "description": "Slots the section offers, or the accessory occupies",
+                    "type": "integer",
+                    "example": 12
+                },
                 "updated_at": {
                     "description": "Last update timestamp",
                     "type": "string"
@@ -2826,6 +11498,14 @@ const docTemplate = `{
                     "type": "string",
                     "example": "in_stock"
                 },
+                "caliber": {
+                    "$ref": "#/definitions/models.Caliber"
+                },
+                "caliber_id": {
+                    "description": "Caliber the prebuilt firearm is chambered in",
+                    "type": "integer",
+                    "example": 1
+                },
                 "compatible_parts": {
                     "description": "Compatible accessories specifically for this prebuilt configuration",
                     "type": "string",
@@ -2878,7 +11558,7 @@ const docTemplate = `{
                 "specifications": {
                     "description": "Specifications specific to this prebuilt configuration",
                     "type": "string",
-                    "example": "{\"weight\": \"6.5 lbs\", \"caliber\": \"5.56x45mm NATO\"}"
+                    "example": "{\"weight\": \"6.5 lbs\"}"
                 },
                 "updated_at": {
                     "description": "Last update timestamp",
@@ -2983,6 +11663,56 @@ const docTemplate = `{
                 }
             }
         },
+        "models.RuleSet": {
+            "description": "A versioned compatibility ruleset imported from YAML; importing swaps it in for all existing rules",
+            "type": "object",
+            "properties": {
+                "active": {
+                    "description": "Whether this is the most recently imported ruleset",
+                    "type": "boolean",
+                    "example": true
+                },
+                "checksum": {
+                    "description": "SHA-256 of the imported YAML",
+                    "type": "string",
+                    "example": "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+                },
+                "created_at": {
+                    "description": "Import timestamp",
+                    "type": "string"
+                },
+                "description": {
+                    "description": "Description of the ruleset",
+                    "type": "string",
+                    "example": "Interface constraints between AR-15 upper assembly parts"
+                },
+                "id": {
+                    "description": "Unique identifier for the ruleset",
+                    "type": "integer",
+                    "example": 1
+                },
+                "name": {
+                    "description": "Name of the ruleset",
+                    "type": "string",
+                    "example": "AR-15 core rules"
+                },
+                "rule_count": {
+                    "description": "Number of rules in the ruleset",
+                    "type": "integer",
+                    "example": 3
+                },
+                "source": {
+                    "description": "File or client the ruleset was imported from",
+                    "type": "string",
+                    "example": "rulesets/ar15.yaml"
+                },
+                "version": {
+                    "description": "Version number, increasing with every import",
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
         "models.Seller": {
             "description": "Information about sellers who offer parts and prebuilt firearms",
             "type": "object",
@@ -3037,6 +11767,70 @@ const docTemplate = `{
                 }
             }
         },
+        "models.ShippingRestriction": {
+            "description": "Destination state a seller or a single listing will not ship to, or only ships to through an FFL, optionally limited to magazines, ammunition or firearms",
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "description": "Creation timestamp",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "Unique identifier for the shipping restriction",
+                    "type": "integer",
+                    "example": 1
+                },
+                "product_listing": {
+                    "$ref": "#/definitions/models.ProductListing"
+                },
+                "product_listing_id": {
+                    "description": "Single listing the restriction covers",
+                    "type": "integer",
+                    "example": 12
+                },
+                "reason": {
+                    "description": "Explanation shown to buyers",
+                    "type": "string",
+                    "example": "We do not ship magazines to California"
+                },
+                "seller": {
+                    "$ref": "#/definitions/models.Seller"
+                },
+                "seller_id": {
+                    "description": "Seller whose listings the restriction covers; exactly one of seller_id and product_listing_id is set",
+                    "type": "integer",
+                    "example": 1
+                },
+                "shipping_class": {
+                    "description": "Shipping class of the items covered, or every item when empty",
+                    "type": "string",
+                    "enum": [
+                        "magazine",
+                        "ammunition",
+                        "firearm"
+                    ],
+                    "example": "magazine"
+                },
+                "state": {
+                    "description": "Two-letter code of the destination state, or every state when empty",
+                    "type": "string",
+                    "example": "CA"
+                },
+                "type": {
+                    "description": "Kind of restriction",
+                    "type": "string",
+                    "enum": [
+                        "no_ship",
+                        "ffl_required"
+                    ],
+                    "example": "no_ship"
+                },
+                "updated_at": {
+                    "description": "Last update timestamp",
+                    "type": "string"
+                }
+            }
+        },
         "models.UserSuggestion": {
             "description": "User suggestions for new models, parts, or configurations",
             "type": "object",
@@ -0,0 +1,257 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"sauron-backend/internal/models"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/datatypes"
+)
+
+// validAttributeTypes lists the value types an attribute definition may declare
+var validAttributeTypes = map[string]bool{
+	models.AttributeTypeString:  true,
+	models.AttributeTypeNumber:  true,
+	models.AttributeTypeInteger: true,
+	models.AttributeTypeBoolean: true,
+}
+
+// EffectiveAttribute is an attribute definition together with the category that declares it
+type EffectiveAttribute struct {
+	models.AttributeDefinition
+
+	// Category the definition was declared on, which may be an ancestor
+	DeclaredByCategoryID int `json:"declared_by_category_id" example:"10"`
+}
+
+// AttributeError describes a part attribute that does not satisfy its category's schema
+type AttributeError struct {
+	Attribute string `json:"attribute" example:"caliber"`
+	Message   string `json:"message" example:"Attribute caliber is required"`
+}
+
+// @Summary Get a part category's attribute schema
+// @Description Get the attribute schema parts in a category must satisfy, including definitions inherited from parent categories
+// @Tags Part Categories
+// @Accept json
+// @Produce json
+// @Param id path int true "Part Category ID"
+// @Success 200 {array} EffectiveAttribute
+// @Failure 400 {object} map[string]string "Invalid ID format"
+// @Failure 404 {object} map[string]string "Category not found"
+// @Failure 500 {object} map[string]string "Server error"
+// @Router /part-categories/{id}/attribute-schema [get]
+func GetPartCategoryAttributeSchema(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category ID"})
+		return
+	}
+
+	tree, err := loadCategoryTree()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load part categories"})
+		return
+	}
+	if _, ok := tree[id]; !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Part category not found"})
+		return
+	}
+
+	schema, err := tree.attributeSchema(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, schema)
+}
+
+// parseAttributeSchema decodes a category's attribute schema JSON
+func parseAttributeSchema(raw datatypes.JSON) ([]models.AttributeDefinition, error) {
+	var definitions []models.AttributeDefinition
+	if len(raw) == 0 || string(raw) == "null" {
+		return definitions, nil
+	}
+	if err := json.Unmarshal(raw, &definitions); err != nil {
+		return nil, fmt.Errorf("attribute_schema must be a list of attribute definitions: %w", err)
+	}
+	return definitions, nil
+}
+
+// validateAttributeSchema checks that a schema's definitions are well formed
+func validateAttributeSchema(raw datatypes.JSON) error {
+	definitions, err := parseAttributeSchema(raw)
+	if err != nil {
+		return err
+	}
+
+	seen := make(map[string]bool, len(definitions))
+	for _, definition := range definitions {
+		if definition.Name == "" {
+			return fmt.Errorf("attribute_schema entries must have a name")
+		}
+		if seen[definition.Name] {
+			return fmt.Errorf("attribute %s is declared more than once", definition.Name)
+		}
+		seen[definition.Name] = true
+
+		if !validAttributeTypes[definition.Type] {
+			return fmt.Errorf("attribute %s has invalid type %q, expected string, number, integer or boolean", definition.Name, definition.Type)
+		}
+		if len(definition.Enum) > 0 && definition.Type != models.AttributeTypeString {
+			return fmt.Errorf("attribute %s declares enum values but is not a string", definition.Name)
+		}
+	}
+	return nil
+}
+
+// attributeSchema merges the schemas of a category and its ancestors. Definitions on a
+// nearer category override same-named ones inherited from further up the chain, except
+// that an inherited requirement cannot be relaxed.
+func (t categoryTree) attributeSchema(categoryID int) ([]EffectiveAttribute, error) {
+	chain := append([]int{categoryID}, t.ancestors(categoryID)...)
+
+	schema := []EffectiveAttribute{}
+	index := make(map[string]int)
+	for i := len(chain) - 1; i >= 0; i-- {
+		definitions, err := parseAttributeSchema(t[chain[i]].AttributeSchema)
+		if err != nil {
+			return nil, fmt.Errorf("part category %d has an invalid attribute schema: %w", chain[i], err)
+		}
+		for _, definition := range definitions {
+			attribute := EffectiveAttribute{AttributeDefinition: definition, DeclaredByCategoryID: chain[i]}
+			if existing, ok := index[definition.Name]; ok {
+				attribute.Required = attribute.Required || schema[existing].Required
+				schema[existing] = attribute
+				continue
+			}
+			index[definition.Name] = len(schema)
+			schema = append(schema, attribute)
+		}
+	}
+	return schema, nil
+}
+
+// validatePartAttributes checks a part's attributes against the schema of its category.
+// Attributes the schema does not declare are allowed so rules can use ad-hoc keys. The
+// caliber attribute is derived from caliber_id, so a required caliber means caliber_id must
+// be set and the attribute itself may not be.
+func validatePartAttributes(tree categoryTree, part models.Part) ([]AttributeError, error) {
+	errs := []AttributeError{}
+
+	var attributes map[string]interface{}
+	if len(part.Attributes) > 0 && string(part.Attributes) != "null" {
+		if err := json.Unmarshal(part.Attributes, &attributes); err != nil {
+			return append(errs, AttributeError{Message: "attributes must be a JSON object"}), nil
+		}
+	}
+	if _, ok := attributes[models.PartAttributeCaliber]; ok {
+		errs = append(errs, AttributeError{
+			Attribute: models.PartAttributeCaliber,
+			Message:   "Attribute caliber is derived from caliber_id and cannot be set directly",
+		})
+	}
+	if part.PartCategoryID == nil {
+		return errs, nil
+	}
+
+	schema, err := tree.attributeSchema(*part.PartCategoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, attribute := range schema {
+		if attribute.Name == models.PartAttributeCaliber {
+			if attribute.Required && part.CaliberID == nil {
+				errs = append(errs, AttributeError{
+					Attribute: attribute.Name,
+					Message:   "Attribute caliber is required: set caliber_id",
+				})
+			}
+			continue
+		}
+
+		value, ok := attributes[attribute.Name]
+		if !ok || value == nil {
+			if attribute.Required {
+				errs = append(errs, AttributeError{
+					Attribute: attribute.Name,
+					Message:   fmt.Sprintf("Attribute %s is required", attribute.Name),
+				})
+			}
+			continue
+		}
+
+		if message := checkAttributeValue(attribute.AttributeDefinition, value); message != "" {
+			errs = append(errs, AttributeError{Attribute: attribute.Name, Message: message})
+		}
+	}
+	return errs, nil
+}
+
+// checkAttributeValue returns why a value does not fit its definition, or "" if it does
+func checkAttributeValue(definition models.AttributeDefinition, value interface{}) string {
+	switch definition.Type {
+	case models.AttributeTypeString:
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Sprintf("Attribute %s must be a string", definition.Name)
+		}
+		if len(definition.Enum) == 0 {
+			return ""
+		}
+		for _, allowed := range definition.Enum {
+			if s == allowed {
+				return ""
+			}
+		}
+		return fmt.Sprintf("Attribute %s must be one of %v", definition.Name, definition.Enum)
+	case models.AttributeTypeNumber:
+		if _, ok := value.(float64); !ok {
+			return fmt.Sprintf("Attribute %s must be a number", definition.Name)
+		}
+	case models.AttributeTypeInteger:
+		if n, ok := value.(float64); !ok || n != math.Trunc(n) {
+			return fmt.Sprintf("Attribute %s must be an integer", definition.Name)
+		}
+	case models.AttributeTypeBoolean:
+		if _, ok := value.(bool); !ok {
+			return fmt.Sprintf("Attribute %s must be true or false", definition.Name)
+		}
+	}
+	return ""
+}
+
+// checkPartAttributes validates a part against its category's schema, writing an error
+// response and returning false when it does not satisfy it
+func checkPartAttributes(c *gin.Context, part models.Part) bool {
+	tree, err := loadCategoryTree()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load part categories"})
+		return false
+	}
+	if part.PartCategoryID != nil {
+		if _, ok := tree[*part.PartCategoryID]; !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Part category not found"})
+			return false
+		}
+	}
+
+	errs, err := validatePartAttributes(tree, part)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return false
+	}
+	if len(errs) > 0 {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":            "Part attributes do not satisfy the category's attribute schema",
+			"attribute_errors": errs,
+		})
+		return false
+	}
+	return true
+}
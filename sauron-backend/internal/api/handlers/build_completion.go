@@ -0,0 +1,561 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"sauron-backend/internal/db"
+	"sauron-backend/internal/models"
+	"sort"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Build completion strategies
+const (
+	CompletionStrategyLowestCost      = "lowest_cost"
+	CompletionStrategyClosestToBudget = "closest_to_budget"
+)
+
+// BuildCompletionInput is the request body for completing a build within a budget
+type BuildCompletionInput struct {
+	// Firearm model to complete a build for
+	FirearmModelID int `json:"firearm_model_id" binding:"required" example:"1"`
+
+	// Parts the user has already chosen, keyed by part category (slot) ID
+	Locked map[int]int `json:"locked"`
+
+	// Maximum total landed cost, including the locked parts
+	Budget float64 `json:"budget" binding:"required,gt=0" example:"1200"`
+
+	// Also fill optional slots of the model when the budget allows
+	IncludeOptional bool `json:"include_optional" example:"false"`
+
+	// Optimization goal, defaults to lowest_cost
+	Strategy string `json:"strategy" example:"lowest_cost" enums:"lowest_cost,closest_to_budget"`
+
+	// Destination state; listings that cannot ship there are not used, e.g. CA or US-CA
+	ShipTo string `json:"ship_to" example:"CA"`
+}
+
+// BuildCompletionLine is one slot of a completed build
+type BuildCompletionLine struct {
+	PartCategoryID int    `json:"part_category_id" example:"10"`
+	CategoryName   string `json:"category_name" example:"Barrel"`
+	PartID         int    `json:"part_id" example:"5"`
+	PartName       string `json:"part_name" example:"16\" 5.56 NATO Barrel (AR-15)"`
+
+	// Whether the part was supplied by the caller rather than chosen by the solver
+	Locked bool `json:"locked" example:"false"`
+
+	// Cheapest in-stock listing for the part and its landed price, if any
+	ListingID  int     `json:"listing_id,omitempty" example:"42"`
+	SellerName string  `json:"seller_name,omitempty" example:"Brownells"`
+	Price      float64 `json:"price" example:"197.98"`
+
+	// Whether the listing only ships to a licensed dealer (FFL) in the destination state, and why
+	FFLRequired   bool     `json:"ffl_required,omitempty" example:"false"`
+	ShippingNotes []string `json:"shipping_notes,omitempty" example:"Magazines ship only to California FFLs"`
+}
+
+// BuildCompletionResult is a full slot selection for a firearm model chosen within a budget
+type BuildCompletionResult struct {
+	FirearmModelID int     `json:"firearm_model_id" example:"1"`
+	Strategy       string  `json:"strategy" example:"lowest_cost"`
+	Budget         float64 `json:"budget" example:"1200"`
+
+	// Destination state the listings were limited to, if any
+	ShipTo string `json:"ship_to,omitempty" example:"CA"`
+
+	// Whether the required slots could be filled without exceeding the budget
+	Feasible bool `json:"feasible" example:"true"`
+
+	// Complete selection keyed by slot ID, ready to be saved with POST /builds
+	Slots map[int]int           `json:"slots"`
+	Lines []BuildCompletionLine `json:"lines"`
+
+	// Total landed cost of the selection and the budget left over
+	Total     float64 `json:"total" example:"1149.50"`
+	Remaining float64 `json:"remaining" example:"50.50"`
+
+	// Required slots with no in-stock compatible part
+	UnfillableSlots []int `json:"unfillable_slots"`
+
+	// Locked parts with no in-stock listing, which are excluded from the total
+	UnavailablePartIDs []int `json:"unavailable_part_ids"`
+
+	// Locked parts whose in-stock listings cannot ship to the destination
+	RestrictedPartIDs []int `json:"restricted_part_ids"`
+
+	// Problems the completed selection still fails validation with, which make it infeasible
+	Errors []BuildValidationError `json:"errors"`
+}
+
+// completionCandidate is a part that can fill a slot, priced at its cheapest listing
+type completionCandidate struct {
+	part    models.Part
+	listing models.ProductListing
+	price   float64
+}
+
+// completionSlot is an open slot and its candidates ordered from cheapest to most expensive
+type completionSlot struct {
+	id         int
+	required   bool
+	candidates []completionCandidate
+
+	// Index of the chosen candidate, or -1 for a slot left empty
+	choice int
+}
+
+func (s *completionSlot) price() float64 {
+	if s.choice < 0 {
+		return 0
+	}
+	return s.candidates[s.choice].price
+}
+
+// @Summary     Complete a build within a budget
+// @Description Fill the remaining required slots of a firearm model (and optionally its optional slots) with in-stock parts declared to fit the model that pass the compatibility rules against the locked parts and each other, so the total landed cost stays within the budget. The completed selection is validated like POST /builds/validate and is only feasible when it passes. The lowest_cost strategy picks the cheapest part for each slot; closest_to_budget upgrades parts to spend as much of the budget as possible. Given a destination state, only listings that can ship there are used.
+// @Tags        Builds
+// @Accept      json
+// @Produce     json
+// @Param       request body BuildCompletionInput true "Model, locked parts and budget"
+// @Success     200 {object} BuildCompletionResult
+// @Failure     400 {object} map[string]string
+// @Failure     404 {object} map[string]string
+// @Failure     422 {object} BuildValidationResult
+// @Failure     500 {object} map[string]string
+// @Router      /builds/complete [post]
+func CompleteBuild(c *gin.Context) {
+	var input BuildCompletionInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if input.Strategy == "" {
+		input.Strategy = CompletionStrategyLowestCost
+	}
+	if input.Strategy != CompletionStrategyLowestCost && input.Strategy != CompletionStrategyClosestToBudget {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid strategy, expected lowest_cost or closest_to_budget"})
+		return
+	}
+	if input.Locked == nil {
+		input.Locked = map[int]int{}
+	}
+	state, ok := shipToParam(c, input.ShipTo)
+	if !ok {
+		return
+	}
+	input.ShipTo = state
+
+	validation, err := validateBuildSelection(input.FirearmModelID, input.Locked)
+	if errors.Is(err, errFirearmModelNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Firearm model not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to validate locked parts"})
+		return
+	}
+	if blocking := validation.blockingErrors(true); len(blocking) > 0 {
+		c.JSON(http.StatusUnprocessableEntity, BuildValidationResult{Valid: false, Errors: blocking})
+		return
+	}
+
+	result, err := completeBuild(input, validation)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to complete build"})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// completeBuild picks a part for every open slot. The locked selection must already be
+// valid; its validation result supplies the required slots that are still missing.
+func completeBuild(input BuildCompletionInput, validation *BuildValidationResult) (*BuildCompletionResult, error) {
+	tree, err := loadCategoryTree()
+	if err != nil {
+		return nil, err
+	}
+
+	var relations []models.FirearmModelPartCategory
+	if err := db.DB.Where("firearm_model_id = ?", input.FirearmModelID).Order("part_category_id").Find(&relations).Error; err != nil {
+		return nil, err
+	}
+
+	// Required slots still missing; filling a parent slot covers its children
+	missing := make(map[int]bool)
+	for _, e := range validation.Errors {
+		if e.Code == BuildErrorMissingRequiredSlot {
+			missing[e.PartCategoryID] = true
+		}
+	}
+	var openSlots []*completionSlot
+	occupied := make([]int, 0, len(input.Locked))
+	for slotID := range input.Locked {
+		occupied = append(occupied, slotID)
+	}
+	for _, relation := range relations {
+		if missing[relation.PartCategoryID] && !hasAncestorIn(tree, relation.PartCategoryID, missing) {
+			openSlots = append(openSlots, &completionSlot{id: relation.PartCategoryID, required: true, choice: -1})
+			occupied = append(occupied, relation.PartCategoryID)
+		}
+	}
+
+	// Optional slots that do not overlap anything already selected or being filled
+	if input.IncludeOptional {
+		optional := make(map[int]bool)
+		for _, relation := range relations {
+			if relation.IsRequired {
+				continue
+			}
+			overlaps := false
+			for _, slotID := range occupied {
+				if tree.isWithin(slotID, relation.PartCategoryID) || tree.isWithin(relation.PartCategoryID, slotID) {
+					overlaps = true
+					break
+				}
+			}
+			if !overlaps {
+				optional[relation.PartCategoryID] = true
+			}
+		}
+		for _, relation := range relations {
+			if optional[relation.PartCategoryID] && !hasAncestorIn(tree, relation.PartCategoryID, optional) {
+				openSlots = append(openSlots, &completionSlot{id: relation.PartCategoryID, choice: -1})
+			}
+		}
+	}
+
+	lockedParts := make(map[int]bool, len(input.Locked))
+	lockedPartIDs := make([]int, 0, len(input.Locked))
+	for _, partID := range input.Locked {
+		lockedParts[partID] = true
+		lockedPartIDs = append(lockedPartIDs, partID)
+	}
+
+	// Gather the parts in the categories of the open slots
+	var slotCategoryIDs []int
+	for categoryID := range tree {
+		for _, slot := range openSlots {
+			if tree.isWithin(categoryID, slot.id) {
+				slotCategoryIDs = append(slotCategoryIDs, categoryID)
+				break
+			}
+		}
+	}
+	var parts []models.Part
+	if len(slotCategoryIDs) > 0 {
+		if err := db.DB.Where("part_category_id IN ?", slotCategoryIDs).Find(&parts).Error; err != nil {
+			return nil, err
+		}
+	}
+	candidatePartIDs := make([]int, 0, len(parts))
+	for _, part := range parts {
+		if !lockedParts[part.ID] {
+			candidatePartIDs = append(candidatePartIDs, part.ID)
+		}
+	}
+
+	lockedPartByID := make(map[int]models.Part, len(lockedPartIDs))
+	if len(lockedPartIDs) > 0 {
+		var lockedPartList []models.Part
+		if err := db.DB.Where("id IN ?", lockedPartIDs).Find(&lockedPartList).Error; err != nil {
+			return nil, err
+		}
+		for _, part := range lockedPartList {
+			lockedPartByID[part.ID] = part
+		}
+	}
+
+	shippable, err := loadShippableListingsByPart(append(candidatePartIDs, lockedPartIDs...), input.ShipTo)
+	if err != nil {
+		return nil, err
+	}
+	listingsByPart := shippable.byPart
+
+	engine, err := loadCompatibilityEngine()
+	if err != nil {
+		return nil, err
+	}
+	magazines, err := loadMagazineCatalog()
+	if err != nil {
+		return nil, err
+	}
+	chamberCaliberIDs, err := selectionChamberCalibers(input.FirearmModelID, input.Locked, magazines)
+	if err != nil {
+		return nil, err
+	}
+	fitsByPart, err := loadDeclaredFitments(append(candidatePartIDs, lockedPartIDs...))
+	if err != nil {
+		return nil, err
+	}
+	solver := &completionSolver{slots: openSlots, engine: engine, fitsByPart: fitsByPart, verdicts: make(map[[2]int]bool)}
+
+	// Candidates must be in stock, be declared to fit the model if they declare any fitments,
+	// fit the model's magazine well and go with every locked part
+	for _, slot := range openSlots {
+		for _, part := range parts {
+			listings := listingsByPart[part.ID]
+			if lockedParts[part.ID] || len(listings) == 0 || !tree.isWithin(*part.PartCategoryID, slot.id) {
+				continue
+			}
+			if fits := fitsByPart[part.ID]; fits != nil && !fits[input.FirearmModelID] {
+				continue
+			}
+			if entry, isMagazine := magazines.variantByPart[part.ID]; isMagazine && !magazines.fits(entry, input.FirearmModelID, chamberCaliberIDs) {
+				continue
+			}
+			if !solver.fitsAll(part, lockedPartByID) {
+				continue
+			}
+			slot.candidates = append(slot.candidates, completionCandidate{
+				part:    part,
+				listing: listings[0],
+				price:   roundCents(landedPrice(listings[0])),
+			})
+		}
+		sort.SliceStable(slot.candidates, func(i, j int) bool {
+			if slot.candidates[i].price != slot.candidates[j].price {
+				return slot.candidates[i].price < slot.candidates[j].price
+			}
+			return slot.candidates[i].part.ID < slot.candidates[j].part.ID
+		})
+	}
+
+	result := &BuildCompletionResult{
+		FirearmModelID:     input.FirearmModelID,
+		Strategy:           input.Strategy,
+		Budget:             roundCents(input.Budget),
+		ShipTo:             input.ShipTo,
+		Slots:              map[int]int{},
+		Lines:              []BuildCompletionLine{},
+		UnfillableSlots:    []int{},
+		UnavailablePartIDs: []int{},
+		RestrictedPartIDs:  []int{},
+		Errors:             []BuildValidationError{},
+	}
+
+	// Locked parts are part of the spend regardless of strategy
+	lockedTotal := 0.0
+	for _, partID := range uniqueInts(lockedPartIDs) {
+		if listings := listingsByPart[partID]; len(listings) > 0 {
+			lockedTotal += roundCents(landedPrice(listings[0]))
+		} else {
+			result.UnavailablePartIDs = append(result.UnavailablePartIDs, partID)
+			if _, restricted := shippable.restricted[partID]; restricted {
+				result.RestrictedPartIDs = append(result.RestrictedPartIDs, partID)
+			}
+		}
+	}
+
+	// Start from the cheapest part in every required slot that goes with the parts picked so far
+	total := lockedTotal
+	for _, slot := range openSlots {
+		if !slot.required {
+			continue
+		}
+		if i := solver.cheapestFitting(slot); i >= 0 {
+			slot.choice = i
+			total += slot.price()
+			continue
+		}
+		result.UnfillableSlots = append(result.UnfillableSlots, slot.id)
+	}
+
+	if total <= input.Budget {
+		switch input.Strategy {
+		case CompletionStrategyLowestCost:
+			total = solver.addCheapestOptional(total, input.Budget)
+		case CompletionStrategyClosestToBudget:
+			total = solver.spendTowardsBudget(total, input.Budget)
+		}
+	}
+
+	result.Total = roundCents(total)
+	result.Remaining = roundCents(input.Budget - total)
+
+	result.Lines = completionLines(input.Locked, lockedPartByID, openSlots, listingsByPart, tree)
+	for i, line := range result.Lines {
+		result.Slots[line.PartCategoryID] = line.PartID
+		if status := shippable.status[line.ListingID]; status.FFLRequired {
+			result.Lines[i].FFLRequired = true
+			result.Lines[i].ShippingNotes = status.Reasons
+		}
+	}
+
+	// The picks only guarantee the pairwise rules; check the whole selection like a saved build
+	final, err := validateBuildSelection(input.FirearmModelID, result.Slots)
+	if err != nil {
+		return nil, err
+	}
+	if blocking := final.blockingErrors(false); len(blocking) > 0 {
+		result.Errors = blocking
+	}
+
+	result.Feasible = len(result.UnfillableSlots) == 0 && len(result.Errors) == 0 && total <= input.Budget
+	return result, nil
+}
+
+// completionSolver picks candidates that share a declared fitment with and pass the
+// compatibility rules against the locked parts and the parts picked for the other open slots
+type completionSolver struct {
+	slots      []*completionSlot
+	engine     *compatibilityEngine
+	fitsByPart map[int]map[int]bool
+
+	// Pair verdicts already evaluated, keyed by the lower part ID first
+	verdicts map[[2]int]bool
+}
+
+// compatible reports whether the pair can share a firearm model and no rule fails for it,
+// caching the verdict
+func (s *completionSolver) compatible(a, b models.Part) bool {
+	key := [2]int{a.ID, b.ID}
+	if b.ID < a.ID {
+		key = [2]int{b.ID, a.ID}
+	}
+	if verdict, ok := s.verdicts[key]; ok {
+		return verdict
+	}
+	verdict := sharesDeclaredFitment(s.fitsByPart, a.ID, b.ID)
+	if verdict {
+		verdict, _ = s.engine.check(a, b)
+	}
+	s.verdicts[key] = verdict
+	return verdict
+}
+
+// fitsAll reports whether a part is compatible with every part in the set
+func (s *completionSolver) fitsAll(part models.Part, others map[int]models.Part) bool {
+	for _, other := range others {
+		if !s.compatible(part, other) {
+			return false
+		}
+	}
+	return true
+}
+
+// fitsPicks reports whether a part is compatible with the parts picked for every other slot
+func (s *completionSolver) fitsPicks(slot *completionSlot, part models.Part) bool {
+	for _, other := range s.slots {
+		if other == slot || other.choice < 0 {
+			continue
+		}
+		if !s.compatible(part, other.candidates[other.choice].part) {
+			return false
+		}
+	}
+	return true
+}
+
+// cheapestFitting returns the index of the cheapest candidate of a slot that goes with the
+// current picks, or -1 when there is none
+func (s *completionSolver) cheapestFitting(slot *completionSlot) int {
+	for i, candidate := range slot.candidates {
+		if s.fitsPicks(slot, candidate.part) {
+			return i
+		}
+	}
+	return -1
+}
+
+// addCheapestOptional fills optional slots with their cheapest part that goes with the current
+// picks, cheapest slots first, for as long as the budget allows
+func (s *completionSolver) addCheapestOptional(total, budget float64) float64 {
+	var optional []*completionSlot
+	for _, slot := range s.slots {
+		if !slot.required && len(slot.candidates) > 0 {
+			optional = append(optional, slot)
+		}
+	}
+	sort.SliceStable(optional, func(i, j int) bool {
+		return optional[i].candidates[0].price < optional[j].candidates[0].price
+	})
+
+	for _, slot := range optional {
+		i := s.cheapestFitting(slot)
+		if i >= 0 && total+slot.candidates[i].price <= budget {
+			slot.choice = i
+			total += slot.price()
+		}
+	}
+	return total
+}
+
+// spendTowardsBudget repeatedly applies the single part change that raises the total
+// the most without exceeding the budget or breaking compatibility with the other picks.
+// Optional slots may go from empty to filled. This is a greedy approximation of the
+// underlying multiple-choice knapsack.
+func (s *completionSolver) spendTowardsBudget(total, budget float64) float64 {
+	for {
+		var bestSlot *completionSlot
+		bestChoice, bestDelta := 0, 0.0
+
+		for _, slot := range s.slots {
+			current := slot.price()
+			for i := slot.choice + 1; i < len(slot.candidates); i++ {
+				delta := slot.candidates[i].price - current
+				if delta > bestDelta && total+delta <= budget && s.fitsPicks(slot, slot.candidates[i].part) {
+					bestSlot, bestChoice, bestDelta = slot, i, delta
+				}
+			}
+		}
+
+		if bestSlot == nil {
+			return total
+		}
+		bestSlot.choice = bestChoice
+		total += bestDelta
+	}
+}
+
+// completionLines lists the locked parts and the solver's picks ordered by slot
+func completionLines(locked map[int]int, lockedPartByID map[int]models.Part, slots []*completionSlot, listingsByPart map[int][]models.ProductListing, tree categoryTree) []BuildCompletionLine {
+	lines := []BuildCompletionLine{}
+	for slotID, partID := range locked {
+		line := BuildCompletionLine{
+			PartCategoryID: slotID,
+			CategoryName:   tree[slotID].Name,
+			PartID:         partID,
+			PartName:       lockedPartByID[partID].Name,
+			Locked:         true,
+		}
+		if listings := listingsByPart[partID]; len(listings) > 0 {
+			line.ListingID = listings[0].ID
+			line.SellerName = listings[0].Seller.Name
+			line.Price = roundCents(landedPrice(listings[0]))
+		}
+		lines = append(lines, line)
+	}
+
+	for _, slot := range slots {
+		if slot.choice < 0 {
+			continue
+		}
+		candidate := slot.candidates[slot.choice]
+		lines = append(lines, BuildCompletionLine{
+			PartCategoryID: slot.id,
+			CategoryName:   tree[slot.id].Name,
+			PartID:         candidate.part.ID,
+			PartName:       candidate.part.Name,
+			ListingID:      candidate.listing.ID,
+			SellerName:     candidate.listing.Seller.Name,
+			Price:          candidate.price,
+		})
+	}
+
+	sort.Slice(lines, func(i, j int) bool { return lines[i].PartCategoryID < lines[j].PartCategoryID })
+	return lines
+}
+
+// hasAncestorIn reports whether any parent of a category is in the set
+func hasAncestorIn(tree categoryTree, categoryID int, set map[int]bool) bool {
+	for _, ancestorID := range tree.ancestors(categoryID) {
+		if set[ancestorID] {
+			return true
+		}
+	}
+	return false
+}
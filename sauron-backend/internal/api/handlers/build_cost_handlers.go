@@ -0,0 +1,224 @@
+package handlers
+
+import (
+	"net/http"
+	"sauron-backend/internal/db"
+	"sauron-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BuildCostInput is the request body for pricing an unsaved slot selection
+type BuildCostInput struct {
+	// Selected parts keyed by part category (slot) ID
+	Slots map[int]int `json:"slots" binding:"required"`
+
+	// Destination state; listings that cannot ship there are not used, e.g. CA or US-CA
+	ShipTo string `json:"ship_to" example:"CA"`
+}
+
+// BuildCostLine is the price breakdown for a single slot of a build
+type BuildCostLine struct {
+	// Slot (part category) the part fills
+	PartCategoryID int `json:"part_category_id" example:"10"`
+
+	// Part selected for the slot
+	PartID   int    `json:"part_id" example:"5"`
+	PartName string `json:"part_name" example:"16\" 5.56 NATO Barrel (AR-15)"`
+
+	// Whether an in-stock listing was found for the part
+	InStock bool `json:"in_stock" example:"true"`
+
+	// Cheapest in-stock listing for the part, if any
+	ListingID  int    `json:"listing_id,omitempty" example:"42"`
+	SellerID   int    `json:"seller_id,omitempty" example:"1"`
+	SellerName string `json:"seller_name,omitempty" example:"Brownells"`
+	URL        string `json:"url,omitempty" example:"https://www.brownells.com/product/16-5.56-nato-barrel-ar-15"`
+
+	// Listing price, shipping charged and their sum
+	Price        float64 `json:"price" example:"189.99"`
+	ShippingCost float64 `json:"shipping_cost" example:"7.99"`
+	LineTotal    float64 `json:"line_total" example:"197.98"`
+
+	// Whether the part has in-stock listings but none ship to the destination
+	ShippingRestricted bool `json:"shipping_restricted,omitempty" example:"false"`
+
+	// Whether the listing only ships to a licensed dealer (FFL) in the destination state
+	FFLRequired bool `json:"ffl_required,omitempty" example:"false"`
+
+	// Reasons of the shipping restrictions that apply to the part's listings
+	ShippingNotes []string `json:"shipping_notes,omitempty" example:"Magazines ship only to California FFLs"`
+}
+
+// BuildCostResult is the total price of a build with a per-slot breakdown
+type BuildCostResult struct {
+	// Build that was priced, omitted for unsaved selections
+	BuildID int `json:"build_id,omitempty" example:"1"`
+
+	// Destination state the listings were limited to, if any
+	ShipTo string `json:"ship_to,omitempty" example:"CA"`
+
+	Currency string          `json:"currency" example:"USD"`
+	Lines    []BuildCostLine `json:"lines"`
+
+	// Totals across all parts with an in-stock listing
+	PartsTotal    float64 `json:"parts_total" example:"1149.50"`
+	ShippingTotal float64 `json:"shipping_total" example:"31.96"`
+	Total         float64 `json:"total" example:"1181.46"`
+
+	// Parts with no in-stock listing, which are excluded from the totals
+	UnavailablePartIDs []int `json:"unavailable_part_ids"`
+
+	// Unavailable parts whose in-stock listings cannot ship to the destination
+	RestrictedPartIDs []int `json:"restricted_part_ids"`
+
+	// Whether every part in the build could be priced
+	Complete bool `json:"complete" example:"true"`
+}
+
+// @Summary     Get build cost
+// @Description Price a saved build using the cheapest in-stock listing for each part, including shipping. Given a destination state, listings that cannot ship there are skipped and listings that must ship to an FFL are flagged.
+// @Tags        Builds
+// @Accept      json
+// @Produce     json
+// @Param       id      path  int    true  "Build ID"
+// @Param       ship_to query string false "Destination state, e.g. CA or US-CA"
+// @Success     200 {object} BuildCostResult
+// @Failure     400 {object} map[string]string
+// @Failure     404 {object} map[string]string
+// @Failure     500 {object} map[string]string
+// @Router      /builds/{id}/cost [get]
+func GetBuildCost(c *gin.Context) {
+	state, ok := shipToParam(c, c.Query("ship_to"))
+	if !ok {
+		return
+	}
+	build, err := loadBuild(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Build not found"})
+		return
+	}
+
+	result, err := computeBuildCost(buildSlots(build), state)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to price build"})
+		return
+	}
+	result.BuildID = build.ID
+
+	c.JSON(http.StatusOK, result)
+}
+
+// @Summary     Price a slot selection
+// @Description Price an unsaved slot selection using the cheapest in-stock listing for each part, including shipping, optionally only from listings that can ship to a destination state
+// @Tags        Builds
+// @Accept      json
+// @Produce     json
+// @Param       selection body BuildCostInput true "Slot selection to price"
+// @Success     200 {object} BuildCostResult
+// @Failure     400 {object} map[string]string
+// @Failure     500 {object} map[string]string
+// @Router      /builds/cost [post]
+func PriceBuildSelection(c *gin.Context) {
+	var input BuildCostInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	state, ok := shipToParam(c, input.ShipTo)
+	if !ok {
+		return
+	}
+
+	result, err := computeBuildCost(input.Slots, state)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to price build"})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// computeBuildCost prices each selected part at its cheapest in-stock listing, where
+// cheapest means the lowest price after shipping is added. Only listings that can ship to
+// the state are used when one is given.
+func computeBuildCost(slots map[int]int, state string) (*BuildCostResult, error) {
+	partIDs := make([]int, 0, len(slots))
+	for _, partID := range slots {
+		partIDs = append(partIDs, partID)
+	}
+
+	partByID := make(map[int]models.Part)
+	if len(partIDs) > 0 {
+		var parts []models.Part
+		if err := db.DB.Where("id IN ?", partIDs).Find(&parts).Error; err != nil {
+			return nil, err
+		}
+		for _, part := range parts {
+			partByID[part.ID] = part
+		}
+	}
+
+	shippable, err := loadShippableListingsByPart(partIDs, state)
+	if err != nil {
+		return nil, err
+	}
+	listingsByPart := shippable.byPart
+
+	result := &BuildCostResult{
+		ShipTo:             state,
+		Currency:           "USD",
+		Lines:              []BuildCostLine{},
+		UnavailablePartIDs: []int{},
+		RestrictedPartIDs:  shippable.restrictedPartIDs(),
+		Complete:           true,
+	}
+
+	for _, slotID := range sortedSlotIDs(slots) {
+		partID := slots[slotID]
+		line := BuildCostLine{
+			PartCategoryID: slotID,
+			PartID:         partID,
+			PartName:       partByID[partID].Name,
+		}
+
+		listings := listingsByPart[partID]
+		if len(listings) == 0 {
+			result.Complete = false
+			result.UnavailablePartIDs = append(result.UnavailablePartIDs, partID)
+			if reasons, restricted := shippable.restricted[partID]; restricted {
+				line.ShippingRestricted = true
+				line.ShippingNotes = reasons
+			}
+			result.Lines = append(result.Lines, line)
+			continue
+		}
+
+		cheapest := listings[0]
+		line.InStock = true
+		line.ListingID = cheapest.ID
+		line.SellerID = cheapest.SellerID
+		line.SellerName = cheapest.Seller.Name
+		line.URL = cheapest.URL
+		line.Price = roundCents(cheapest.Price)
+		line.ShippingCost = roundCents(listingShipping(cheapest))
+		line.LineTotal = roundCents(line.Price + line.ShippingCost)
+		if status := shippable.status[cheapest.ID]; status.FFLRequired {
+			line.FFLRequired = true
+			line.ShippingNotes = status.Reasons
+		}
+		if cheapest.Currency != "" {
+			result.Currency = cheapest.Currency
+		}
+
+		result.PartsTotal += line.Price
+		result.ShippingTotal += line.ShippingCost
+		result.Lines = append(result.Lines, line)
+	}
+
+	result.PartsTotal = roundCents(result.PartsTotal)
+	result.ShippingTotal = roundCents(result.ShippingTotal)
+	result.Total = roundCents(result.PartsTotal + result.ShippingTotal)
+	return result, nil
+}
@@ -0,0 +1,299 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"sauron-backend/internal/db"
+	"sauron-backend/internal/models"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Export formats supported by the BOM endpoint
+const (
+	BOMFormatJSON     = "json"
+	BOMFormatCSV      = "csv"
+	BOMFormatMarkdown = "markdown"
+)
+
+// BOMLine is one row of a build's bill of materials
+type BOMLine struct {
+	// Category path from the top-level category down, e.g. "Upper Receiver > Barrel"
+	CategoryPath string `json:"category_path" example:"Upper Receiver > Barrel"`
+
+	PartID       int    `json:"part_id" example:"5"`
+	PartName     string `json:"part_name" example:"16\" 5.56 NATO Barrel (AR-15)"`
+	Manufacturer string `json:"manufacturer" example:"Daniel Defense"`
+
+	// Chosen listing: the cheapest in-stock listing after shipping, if any
+	SKU        string  `json:"sku" example:"BRN-BBL-16"`
+	SellerName string  `json:"seller_name" example:"Brownells"`
+	Price      float64 `json:"price" example:"189.99"`
+	Currency   string  `json:"currency" example:"USD"`
+
+	// Affiliate link for the listing, or the plain listing URL for non-affiliate sellers
+	Link string `json:"link" example:"https://www.brownells.com/?aff=gunguru_BRN-BBL-16"`
+
+	// Whether the part has in-stock listings but none ship to the destination
+	ShippingRestricted bool `json:"shipping_restricted,omitempty" example:"false"`
+
+	// Whether the listing only ships to a licensed dealer (FFL) in the destination state
+	FFLRequired bool `json:"ffl_required,omitempty" example:"false"`
+
+	// Reasons of the shipping restrictions that apply to the part's listings
+	ShippingNotes []string `json:"shipping_notes,omitempty" example:"Magazines ship only to California FFLs"`
+}
+
+// BOM is a build's bill of materials
+type BOM struct {
+	BuildID      int       `json:"build_id" example:"1"`
+	BuildName    string    `json:"build_name" example:"Lightweight 16in Carbine"`
+	FirearmModel string    `json:"firearm_model" example:"AR-15"`
+	Lines        []BOMLine `json:"lines"`
+
+	// Destination state the listings were limited to, if any
+	ShipTo string `json:"ship_to,omitempty" example:"CA"`
+
+	// Sum of listing prices, excluding shipping and parts without a listing
+	Total    float64 `json:"total" example:"1149.50"`
+	Currency string  `json:"currency" example:"USD"`
+}
+
+// @Summary     Export a build's bill of materials
+// @Description Export a build as a bill of materials with category path, part, manufacturer, SKU, chosen seller, price and affiliate link for each slot. Given a destination state, only listings that can ship there are chosen and those that must ship to an FFL are flagged.
+// @Tags        Builds
+// @Produce     json
+// @Produce     text/csv
+// @Produce     text/markdown
+// @Param       id path int true "Build ID"
+// @Param       format query string false "Export format" Enums(json, csv, markdown) default(json)
+// @Param       ship_to query string false "Destination state, e.g. CA or US-CA"
+// @Success     200 {object} BOM
+// @Failure     400 {object} map[string]string
+// @Failure     404 {object} map[string]string
+// @Failure     500 {object} map[string]string
+// @Router      /builds/{id}/export [get]
+func ExportBuildBOM(c *gin.Context) {
+	format := strings.ToLower(c.DefaultQuery("format", BOMFormatJSON))
+	if format == "md" {
+		format = BOMFormatMarkdown
+	}
+	if format != BOMFormatJSON && format != BOMFormatCSV && format != BOMFormatMarkdown {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid format, expected json, csv or markdown"})
+		return
+	}
+	state, ok := shipToParam(c, c.Query("ship_to"))
+	if !ok {
+		return
+	}
+
+	build, err := loadBuild(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Build not found"})
+		return
+	}
+
+	bom, err := buildBOM(build, state)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build bill of materials"})
+		return
+	}
+
+	filename := fmt.Sprintf("build-%d-bom", build.ID)
+	switch format {
+	case BOMFormatCSV:
+		data, err := bom.csv()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to write CSV"})
+			return
+		}
+		c.Header("Content-Disposition", "attachment; filename="+filename+".csv")
+		c.Data(http.StatusOK, "text/csv; charset=utf-8", data)
+	case BOMFormatMarkdown:
+		c.Header("Content-Disposition", "inline; filename="+filename+".md")
+		c.Data(http.StatusOK, "text/markdown; charset=utf-8", bom.markdown())
+	default:
+		c.JSON(http.StatusOK, bom)
+	}
+}
+
+// buildBOM resolves every slot of a build to its part, manufacturer and cheapest listing,
+// choosing only from listings that can ship to the state when one is given
+func buildBOM(build *models.Build, state string) (*BOM, error) {
+	slots := buildSlots(build)
+	partIDs := make([]int, 0, len(slots))
+	for _, partID := range slots {
+		partIDs = append(partIDs, partID)
+	}
+	partIDs = uniqueInts(partIDs)
+
+	partByID := make(map[int]models.Part)
+	if len(partIDs) > 0 {
+		var parts []models.Part
+		if err := db.DB.Preload("Manufacturer").Where("id IN ?", partIDs).Find(&parts).Error; err != nil {
+			return nil, err
+		}
+		for _, part := range parts {
+			partByID[part.ID] = part
+		}
+	}
+
+	shippable, err := loadShippableListingsByPart(partIDs, state)
+	if err != nil {
+		return nil, err
+	}
+
+	tree, err := loadCategoryTree()
+	if err != nil {
+		return nil, err
+	}
+
+	bom := &BOM{
+		BuildID:   build.ID,
+		BuildName: build.Name,
+		Lines:     []BOMLine{},
+		ShipTo:    state,
+		Currency:  "USD",
+	}
+	var firearmModel models.FirearmModel
+	if err := db.DB.Select("id", "name").First(&firearmModel, build.FirearmModelID).Error; err == nil {
+		bom.FirearmModel = firearmModel.Name
+	}
+
+	for _, slotID := range sortedSlotIDs(slots) {
+		part := partByID[slots[slotID]]
+
+		// The part's own category is the most specific; fall back to the slot it fills
+		categoryID := slotID
+		if part.PartCategoryID != nil {
+			categoryID = *part.PartCategoryID
+		}
+
+		line := BOMLine{
+			CategoryPath: tree.path(categoryID),
+			PartID:       slots[slotID],
+			PartName:     part.Name,
+			Manufacturer: part.Manufacturer.Name,
+		}
+
+		if reasons, restricted := shippable.restricted[line.PartID]; restricted {
+			line.ShippingRestricted = true
+			line.ShippingNotes = reasons
+		}
+		if listings := shippable.byPart[line.PartID]; len(listings) > 0 {
+			listing := listings[0]
+			line.SKU = listing.SKU
+			line.SellerName = listing.Seller.Name
+			line.Price = roundCents(listing.Price)
+			line.Currency = listing.Currency
+			line.Link = listingLink(listing)
+			if status := shippable.status[listing.ID]; status.FFLRequired {
+				line.FFLRequired = true
+				line.ShippingNotes = status.Reasons
+			}
+			if listing.Currency != "" {
+				bom.Currency = listing.Currency
+			}
+			bom.Total += line.Price
+		}
+
+		bom.Lines = append(bom.Lines, line)
+	}
+
+	bom.Total = roundCents(bom.Total)
+	return bom, nil
+}
+
+// listingLink builds the affiliate link for a listing from its seller's template,
+// falling back to the listing URL when the seller is not an affiliate
+func listingLink(listing models.ProductListing) string {
+	seller := listing.Seller
+	if !seller.IsAffiliate || seller.AffiliateLinkTemplate == "" {
+		return listing.URL
+	}
+
+	productID := listing.SKU
+	if productID == "" && listing.PartID != nil {
+		productID = strconv.Itoa(*listing.PartID)
+	}
+	return strings.ReplaceAll(seller.AffiliateLinkTemplate, "{product_id}", productID)
+}
+
+// bomHeader is the column order shared by the CSV and Markdown exports
+var bomHeader = []string{"Category", "Part", "Manufacturer", "SKU", "Seller", "Price", "Shipping restrictions", "Link"}
+
+func (l BOMLine) fields() []string {
+	price := ""
+	if l.SellerName != "" {
+		price = strconv.FormatFloat(l.Price, 'f', 2, 64)
+	}
+	return []string{l.CategoryPath, l.PartName, l.Manufacturer, l.SKU, l.SellerName, price, l.shippingNote(), l.Link}
+}
+
+// shippingNote summarizes the shipping restrictions of a line for the CSV and Markdown exports
+func (l BOMLine) shippingNote() string {
+	note := ""
+	switch {
+	case l.ShippingRestricted:
+		note = "Does not ship to destination"
+	case l.FFLRequired:
+		note = "FFL required"
+	default:
+		return ""
+	}
+	if len(l.ShippingNotes) > 0 {
+		note += ": " + strings.Join(l.ShippingNotes, "; ")
+	}
+	return note
+}
+
+// csv renders the BOM as a CSV document with a header row
+func (b *BOM) csv() ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(bomHeader); err != nil {
+		return nil, err
+	}
+	for _, line := range b.Lines {
+		if err := w.Write(line.fields()); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+// markdown renders the BOM as a Markdown heading and table, suitable for forum posts
+func (b *BOM) markdown() []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "## %s\n\n", escapeMarkdownCell(b.BuildName))
+	if b.FirearmModel != "" {
+		fmt.Fprintf(&buf, "Firearm model: %s\n\n", escapeMarkdownCell(b.FirearmModel))
+	}
+
+	buf.WriteString("| " + strings.Join(bomHeader, " | ") + " |\n")
+	buf.WriteString("|" + strings.Repeat(" --- |", len(bomHeader)) + "\n")
+	for _, line := range b.Lines {
+		cells := line.fields()
+		for i, cell := range cells {
+			cells[i] = escapeMarkdownCell(cell)
+		}
+		if line.Link != "" {
+			cells[len(cells)-1] = "[link](" + line.Link + ")"
+		}
+		buf.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+	}
+
+	fmt.Fprintf(&buf, "\n**Total:** %.2f %s\n", b.Total, b.Currency)
+	return buf.Bytes()
+}
+
+// escapeMarkdownCell keeps pipes and line breaks from breaking a table row
+func escapeMarkdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", "\\|")
+	return strings.ReplaceAll(s, "\n", " ")
+}
@@ -0,0 +1,271 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"sauron-backend/internal/db"
+	"sauron-backend/internal/models"
+	"sort"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// BuildInput is the request body used to create or update a build
+type BuildInput struct {
+	// Name of the build
+	Name string `json:"name" binding:"required" example:"Lightweight 16in Carbine"`
+
+	// Description of the build
+	Description string `json:"description" example:"A lightweight AR-15 build for general purpose use."`
+
+	// Firearm model the build is based on
+	FirearmModelID int `json:"firearm_model_id" binding:"required" example:"1"`
+
+	// Selected parts keyed by part category (slot) ID
+	Slots map[int]int `json:"slots"`
+}
+
+// @Summary     Get all builds
+// @Description Get a list of all saved builds with their selected parts
+// @Tags        Builds
+// @Accept      json
+// @Produce     json
+// @Param       firearm_model_id query int false "Filter by firearm model ID"
+// @Success     200 {array}  models.Build
+// @Router      /builds [get]
+func GetBuilds(c *gin.Context) {
+	var builds []models.Build
+
+	query := db.DB.Preload("Parts.Part")
+	if modelID := c.Query("firearm_model_id"); modelID != "" {
+		query = query.Where("firearm_model_id = ?", modelID)
+	}
+
+	query.Find(&builds)
+	c.JSON(http.StatusOK, builds)
+}
+
+// @Summary     Create a new build
+// @Description Save a firearm model together with the part selected for each slot. The selection is validated before saving.
+// @Tags        Builds
+// @Accept      json
+// @Produce     json
+// @Param       build body BuildInput true "Build Info"
+// @Param       allow_incomplete query bool false "Save even if required slots are empty or accessories do not fit on the rails"
+// @Success     201 {object} models.Build
+// @Failure     400 {object} map[string]string
+// @Failure     422 {object} BuildValidationResult
+// @Failure     500 {object} map[string]string
+// @Router      /builds [post]
+func CreateBuild(c *gin.Context) {
+	var input BuildInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if !checkBuildInput(c, input) {
+		return
+	}
+
+	build := models.Build{
+		Name:           input.Name,
+		Description:    input.Description,
+		FirearmModelID: input.FirearmModelID,
+	}
+
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&build).Error; err != nil {
+			return err
+		}
+		if err := saveBuildParts(tx, build.ID, input.Slots); err != nil {
+			return err
+		}
+		return recordBuildRevision(tx, build, input.Slots)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create build"})
+		return
+	}
+
+	created, err := loadBuild(build.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load build"})
+		return
+	}
+	c.JSON(http.StatusCreated, created)
+}
+
+// @Summary     Get a build by ID
+// @Description Get a saved build with its selected parts
+// @Tags        Builds
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "Build ID"
+// @Success     200 {object} models.Build
+// @Failure     404 {object} map[string]string
+// @Router      /builds/{id} [get]
+func GetBuildByID(c *gin.Context) {
+	build, err := loadBuild(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Build not found"})
+		return
+	}
+	c.JSON(http.StatusOK, build)
+}
+
+// @Summary     Update a build
+// @Description Replace the details and slot selections of a saved build, recording a new revision
+// @Tags        Builds
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "Build ID"
+// @Param       build body BuildInput true "Updated Build Info"
+// @Param       allow_incomplete query bool false "Save even if required slots are empty or accessories do not fit on the rails"
+// @Success     200 {object} models.Build
+// @Failure     400 {object} map[string]string
+// @Failure     404 {object} map[string]string
+// @Failure     422 {object} BuildValidationResult
+// @Failure     500 {object} map[string]string
+// @Router      /builds/{id} [put]
+func UpdateBuild(c *gin.Context) {
+	id := c.Param("id")
+	var build models.Build
+	if err := db.DB.First(&build, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Build not found"})
+		return
+	}
+
+	var input BuildInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if !checkBuildInput(c, input) {
+		return
+	}
+
+	build.Name = input.Name
+	build.Description = input.Description
+	build.FirearmModelID = input.FirearmModelID
+
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := lockBuild(tx, build.ID); err != nil {
+			return err
+		}
+		if err := ensureInitialRevision(tx, build.ID); err != nil {
+			return err
+		}
+		if err := tx.Omit("Parts").Save(&build).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("build_id = ?", build.ID).Delete(&models.BuildPart{}).Error; err != nil {
+			return err
+		}
+		if err := saveBuildParts(tx, build.ID, input.Slots); err != nil {
+			return err
+		}
+		return recordBuildRevision(tx, build, input.Slots)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update build"})
+		return
+	}
+
+	updated, err := loadBuild(build.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load build"})
+		return
+	}
+	c.JSON(http.StatusOK, updated)
+}
+
+// @Summary     Delete a build
+// @Description Delete a saved build with its slot selections and revision history
+// @Tags        Builds
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "Build ID"
+// @Success     204 "No Content"
+// @Failure     404 {object} map[string]string
+// @Router      /builds/{id} [delete]
+func DeleteBuild(c *gin.Context) {
+	id := c.Param("id")
+	var build models.Build
+	if err := db.DB.First(&build, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Build not found"})
+		return
+	}
+
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("build_id = ?", build.ID).Delete(&models.BuildPart{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("build_id = ?", build.ID).Delete(&models.BuildRevision{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&build).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete build"})
+		return
+	}
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// loadBuild fetches a build with its slot selections and their parts
+func loadBuild(id interface{}) (*models.Build, error) {
+	var build models.Build
+	if err := db.DB.Preload("Parts.Part").First(&build, id).Error; err != nil {
+		return nil, err
+	}
+	return &build, nil
+}
+
+// checkBuildInput validates the slot selection of a build before it is saved and writes
+// the error response when the selection cannot be stored
+func checkBuildInput(c *gin.Context, input BuildInput) bool {
+	result, err := validateBuildSelection(input.FirearmModelID, input.Slots)
+	if errors.Is(err, errFirearmModelNotFound) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Firearm model not found"})
+		return false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to validate build"})
+		return false
+	}
+
+	allowIncomplete := c.Query("allow_incomplete") == "true"
+	if blocking := result.blockingErrors(allowIncomplete); len(blocking) > 0 {
+		c.JSON(http.StatusUnprocessableEntity, BuildValidationResult{Valid: false, Errors: blocking})
+		return false
+	}
+	return true
+}
+
+// saveBuildParts inserts one BuildPart row per slot selection
+func saveBuildParts(tx *gorm.DB, buildID int, slots map[int]int) error {
+	for _, categoryID := range sortedSlotIDs(slots) {
+		buildPart := models.BuildPart{
+			BuildID:        buildID,
+			PartCategoryID: categoryID,
+			PartID:         slots[categoryID],
+		}
+		if err := tx.Omit("Part", "PartCategory").Create(&buildPart).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sortedSlotIDs returns the slot category IDs of a selection in ascending order
+func sortedSlotIDs(slots map[int]int) []int {
+	ids := make([]int, 0, len(slots))
+	for categoryID := range slots {
+		ids = append(ids, categoryID)
+	}
+	sort.Ints(ids)
+	return ids
+}
@@ -0,0 +1,348 @@
+package handlers
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"sauron-backend/internal/db"
+	"sauron-backend/internal/models"
+	"sort"
+
+	"github.com/gin-gonic/gin"
+)
+
+// railSearchLimit bounds the allocation search; past it the best allocation found so far is used
+const railSearchLimit = 200000
+
+// BuildRailSpaceInput is the request body for checking the rail space of an unsaved slot selection
+type BuildRailSpaceInput struct {
+	// Selected parts keyed by part category (slot) ID
+	Slots map[int]int `json:"slots" binding:"required"`
+}
+
+// RailAccessory is an accessory allocated to a rail section
+type RailAccessory struct {
+	PartID   int    `json:"part_id" example:"42"`
+	PartName string `json:"part_name" example:"Red Dot Mount (AR-15)"`
+	Slots    int    `json:"slots" example:"5"`
+}
+
+// RailSectionUsage is a rail section of a selected part and the accessories allocated to it
+type RailSectionUsage struct {
+	MountID  int    `json:"mount_id" example:"3"`
+	PartID   int    `json:"part_id" example:"20"`
+	PartName string `json:"part_name" example:"M-LOK Handguard (AR-15)"`
+
+	MountingInterfaceID int    `json:"mounting_interface_id" example:"1"`
+	MountingInterface   string `json:"mounting_interface" example:"Picatinny"`
+	Position            string `json:"position" example:"top"`
+
+	Slots     int `json:"slots" example:"36"`
+	UsedSlots int `json:"used_slots" example:"9"`
+	FreeSlots int `json:"free_slots" example:"27"`
+
+	// Free rail length in inches, when the interface's slot pitch is recorded
+	FreeLength float64 `json:"free_length,omitempty" example:"10.64"`
+
+	Accessories []RailAccessory `json:"accessories"`
+}
+
+// RailMountShortfall is an accessory mount that found no room on the build's rails
+type RailMountShortfall struct {
+	// Slot (part category) the accessory was selected for
+	PartCategoryID int    `json:"part_category_id" example:"27"`
+	PartID         int    `json:"part_id" example:"42"`
+	PartName       string `json:"part_name" example:"Red Dot Mount (AR-15)"`
+
+	MountingInterface string `json:"mounting_interface" example:"Picatinny"`
+	Position          string `json:"position" example:"top"`
+	Slots             int    `json:"slots" example:"5"`
+
+	Message string `json:"message" example:"Not enough free Picatinny slots on top: needs 5, the most free on one section is 3"`
+}
+
+// BuildRailSpaceResult reports whether the accessories of a build fit on its rail sections
+type BuildRailSpaceResult struct {
+	// Build that was checked, omitted for unsaved selections
+	BuildID int `json:"build_id,omitempty" example:"1"`
+
+	// Whether every accessory mount was allocated to a rail section
+	Fits bool `json:"fits" example:"false"`
+
+	Sections []RailSectionUsage   `json:"sections"`
+	Unplaced []RailMountShortfall `json:"unplaced"`
+
+	// Whether the allocation search hit its step limit before placing everything, in which
+	// case the unplaced accessories might still fit in an arrangement it did not try
+	SearchTruncated bool `json:"search_truncated,omitempty" example:"false"`
+}
+
+// @Summary     Get build rail space
+// @Description Allocate the rail space consumed by a saved build's accessories to the rail sections its parts provide, reporting each section's usage and any accessory that does not fit
+// @Tags        Builds
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "Build ID"
+// @Success     200 {object} BuildRailSpaceResult
+// @Failure     404 {object} map[string]string
+// @Failure     500 {object} map[string]string
+// @Router      /builds/{id}/rail-space [get]
+func GetBuildRailSpace(c *gin.Context) {
+	build, err := loadBuild(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Build not found"})
+		return
+	}
+
+	result, err := computeRailSpace(buildSlots(build))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to allocate rail space"})
+		return
+	}
+	result.BuildID = build.ID
+
+	c.JSON(http.StatusOK, result)
+}
+
+// @Summary     Check rail space of a slot selection
+// @Description Allocate the rail space consumed by an unsaved slot selection's accessories to the rail sections its parts provide, reporting each section's usage and any accessory that does not fit
+// @Tags        Builds
+// @Accept      json
+// @Produce     json
+// @Param       selection body BuildRailSpaceInput true "Slot selection to check"
+// @Success     200 {object} BuildRailSpaceResult
+// @Failure     400 {object} map[string]string
+// @Failure     500 {object} map[string]string
+// @Router      /builds/rail-space [post]
+func CheckBuildSelectionRailSpace(c *gin.Context) {
+	var input BuildRailSpaceInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := computeRailSpace(input.Slots)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to allocate rail space"})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// railSection is a provided rail section with its remaining capacity during allocation
+type railSection struct {
+	mount models.PartMount
+	part  models.Part
+	free  int
+}
+
+// railRequest is an accessory's consumed mount waiting to be allocated
+type railRequest struct {
+	mount  models.PartMount
+	part   models.Part
+	slotID int
+}
+
+// fitsOn reports whether a request can go on a section, ignoring free space
+func (r railRequest) fitsOn(section *railSection) bool {
+	return section.part.ID != r.part.ID &&
+		section.mount.MountingInterfaceID == r.mount.MountingInterfaceID &&
+		(r.mount.Position == models.MountPositionAny || r.mount.Position == section.mount.Position)
+}
+
+// computeRailSpace allocates the rail space the selected parts consume to the rail sections
+// they provide. Each consumed mount must fit whole on one section of the same interface and
+// position. Sections on adapters, parts that both consume and provide rail, only count while
+// the adapter itself is placed.
+func computeRailSpace(slots map[int]int) (*BuildRailSpaceResult, error) {
+	partIDs := make([]int, 0, len(slots))
+	slotByPart := make(map[int]int, len(slots))
+	for _, slotID := range sortedSlotIDs(slots) {
+		partIDs = append(partIDs, slots[slotID])
+		slotByPart[slots[slotID]] = slotID
+	}
+
+	result := &BuildRailSpaceResult{Fits: true, Sections: []RailSectionUsage{}, Unplaced: []RailMountShortfall{}}
+	if len(partIDs) == 0 {
+		return result, nil
+	}
+
+	var mounts []models.PartMount
+	if err := db.DB.Preload("MountingInterface").Preload("Part").Where("part_id IN ?", partIDs).Order("id").Find(&mounts).Error; err != nil {
+		return nil, err
+	}
+
+	var sections []*railSection
+	var requests []railRequest
+	for _, mount := range mounts {
+		part := *mount.Part
+		if mount.Role == models.MountRoleProvides {
+			sections = append(sections, &railSection{mount: mount, part: part})
+		} else {
+			requests = append(requests, railRequest{mount: mount, part: part, slotID: slotByPart[part.ID]})
+		}
+	}
+
+	// Most constrained mounts first: fixed positions, then the largest
+	sort.SliceStable(requests, func(i, j int) bool {
+		anyI := requests[i].mount.Position == models.MountPositionAny
+		anyJ := requests[j].mount.Position == models.MountPositionAny
+		if anyI != anyJ {
+			return !anyI
+		}
+		return requests[i].mount.Slots > requests[j].mount.Slots
+	})
+
+	// Drop sections of adapters that could not be placed until the allocation is stable
+	var placement []int
+	for {
+		var complete bool
+		placement, complete = allocateRailSpace(sections, requests)
+		result.SearchTruncated = result.SearchTruncated || !complete
+		unplacedParts := make(map[int]bool)
+		for i, sectionIndex := range placement {
+			if sectionIndex < 0 {
+				unplacedParts[requests[i].part.ID] = true
+			}
+		}
+		kept := sections[:0:0]
+		for _, section := range sections {
+			if !unplacedParts[section.part.ID] {
+				kept = append(kept, section)
+			}
+		}
+		if len(kept) == len(sections) {
+			break
+		}
+		sections = kept
+	}
+
+	usage := make([]RailSectionUsage, len(sections))
+	for i, section := range sections {
+		usage[i] = RailSectionUsage{
+			MountID:             section.mount.ID,
+			PartID:              section.part.ID,
+			PartName:            section.part.Name,
+			MountingInterfaceID: section.mount.MountingInterfaceID,
+			MountingInterface:   section.mount.MountingInterface.Name,
+			Position:            section.mount.Position,
+			Slots:               section.mount.Slots,
+			FreeSlots:           section.mount.Slots,
+			Accessories:         []RailAccessory{},
+		}
+	}
+	for i, request := range requests {
+		sectionIndex := placement[i]
+		if sectionIndex >= 0 {
+			section := &usage[sectionIndex]
+			section.UsedSlots += request.mount.Slots
+			section.FreeSlots -= request.mount.Slots
+			section.Accessories = append(section.Accessories, RailAccessory{
+				PartID:   request.part.ID,
+				PartName: request.part.Name,
+				Slots:    request.mount.Slots,
+			})
+			continue
+		}
+
+		result.Fits = false
+		result.Unplaced = append(result.Unplaced, RailMountShortfall{
+			PartCategoryID:    request.slotID,
+			PartID:            request.part.ID,
+			PartName:          request.part.Name,
+			MountingInterface: request.mount.MountingInterface.Name,
+			Position:          request.mount.Position,
+			Slots:             request.mount.Slots,
+			Message:           railShortfallMessage(request, sections, usage),
+		})
+	}
+	for i := range usage {
+		if pitch := sections[i].mount.MountingInterface.SlotPitch; pitch > 0 {
+			usage[i].FreeLength = math.Round(float64(usage[i].FreeSlots)*pitch*100) / 100
+		}
+	}
+
+	result.Sections = usage
+	return result, nil
+}
+
+// allocateRailSpace assigns each request to a section index, or -1 when it cannot be placed,
+// maximising the number of requests placed. The search is exhaustive up to railSearchLimit
+// steps, which covers any realistic build; the second result is false when it stopped there
+// without placing every request, so the allocation may not be the best one.
+func allocateRailSpace(sections []*railSection, requests []railRequest) ([]int, bool) {
+	for _, section := range sections {
+		section.free = section.mount.Slots
+	}
+
+	current := make([]int, len(requests))
+	best := make([]int, len(requests))
+	for i := range best {
+		best[i] = -1
+	}
+	bestPlaced, steps := -1, 0
+
+	var search func(i, placed int)
+	search = func(i, placed int) {
+		steps++
+		if placed+len(requests)-i <= bestPlaced || steps > railSearchLimit {
+			return
+		}
+		if i == len(requests) {
+			bestPlaced = placed
+			copy(best, current)
+			return
+		}
+
+		request := requests[i]
+		tried := make(map[[3]interface{}]bool)
+		for sectionIndex, section := range sections {
+			if section.free < request.mount.Slots || !request.fitsOn(section) {
+				continue
+			}
+			// Sections that look the same to this request lead to the same outcome
+			key := [3]interface{}{section.mount.MountingInterfaceID, section.mount.Position, section.free}
+			if tried[key] {
+				continue
+			}
+			tried[key] = true
+
+			section.free -= request.mount.Slots
+			current[i] = sectionIndex
+			search(i+1, placed+1)
+			section.free += request.mount.Slots
+			if bestPlaced == len(requests) {
+				return
+			}
+		}
+
+		current[i] = -1
+		search(i+1, placed)
+	}
+	search(0, 0)
+	return best, steps <= railSearchLimit || bestPlaced == len(requests)
+}
+
+// railShortfallMessage explains why a request could not be placed
+func railShortfallMessage(request railRequest, sections []*railSection, usage []RailSectionUsage) string {
+	interfaceName := request.mount.MountingInterface.Name
+	where := ""
+	if request.mount.Position != models.MountPositionAny {
+		where = " on " + request.mount.Position
+	}
+
+	mostFree, matching := 0, false
+	for i, section := range sections {
+		if request.fitsOn(section) {
+			matching = true
+			if usage[i].FreeSlots > mostFree {
+				mostFree = usage[i].FreeSlots
+			}
+		}
+	}
+	if !matching {
+		return fmt.Sprintf("No %s rail section%s for %s", interfaceName, where, request.part.Name)
+	}
+	return fmt.Sprintf("Not enough free %s slots%s: needs %d, the most free on one section is %d", interfaceName, where, request.mount.Slots, mostFree)
+}
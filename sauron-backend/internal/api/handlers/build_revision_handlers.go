@@ -0,0 +1,396 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sauron-backend/internal/db"
+	"sauron-backend/internal/models"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Slot change types reported by build diffs
+const (
+	SlotChangeAdded   = "added"
+	SlotChangeRemoved = "removed"
+	SlotChangeSwapped = "swapped"
+)
+
+// BuildDiffSide identifies one side of a diff: a build at a given revision
+type BuildDiffSide struct {
+	BuildID  int    `json:"build_id" example:"1"`
+	Revision int    `json:"revision,omitempty" example:"2"`
+	Name     string `json:"name" example:"Lightweight 16in Carbine"`
+}
+
+// BuildSlotChange describes what changed in one slot between two builds or revisions
+type BuildSlotChange struct {
+	PartCategoryID int    `json:"part_category_id" example:"10"`
+	CategoryName   string `json:"category_name" example:"Barrel"`
+	Change         string `json:"change" example:"swapped" enums:"added,removed,swapped"`
+
+	FromPartID   int    `json:"from_part_id,omitempty" example:"5"`
+	FromPartName string `json:"from_part_name,omitempty" example:"16\" 5.56 NATO Barrel (AR-15)"`
+	ToPartID     int    `json:"to_part_id,omitempty" example:"7"`
+	ToPartName   string `json:"to_part_name,omitempty" example:"14.5\" 5.56 NATO Barrel (AR-15)"`
+
+	// Change in cheapest in-stock landed price and in weight (pounds)
+	PriceDelta  float64 `json:"price_delta" example:"-24.50"`
+	WeightDelta float64 `json:"weight_delta" example:"-0.18"`
+
+	// Whether a part on either side had no in-stock listing, making the price delta partial
+	PriceIncomplete bool `json:"price_incomplete,omitempty" example:"false"`
+}
+
+// BuildDiff lists the slot changes between two builds or two revisions of a build
+type BuildDiff struct {
+	From    BuildDiffSide     `json:"from"`
+	To      BuildDiffSide     `json:"to"`
+	Changes []BuildSlotChange `json:"changes"`
+
+	// Firearm model change, if the two sides are for different models
+	FromFirearmModelID int `json:"from_firearm_model_id" example:"1"`
+	ToFirearmModelID   int `json:"to_firearm_model_id" example:"1"`
+
+	TotalPriceDelta  float64 `json:"total_price_delta" example:"-24.50"`
+	TotalWeightDelta float64 `json:"total_weight_delta" example:"-0.18"`
+}
+
+// @Summary     Get build revisions
+// @Description Get the append-only revision history of a build, oldest first
+// @Tags        Builds
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "Build ID"
+// @Success     200 {array} models.BuildRevision
+// @Failure     404 {object} map[string]string
+// @Router      /builds/{id}/revisions [get]
+func GetBuildRevisions(c *gin.Context) {
+	var build models.Build
+	if err := db.DB.First(&build, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Build not found"})
+		return
+	}
+
+	var revisions []models.BuildRevision
+	db.DB.Where("build_id = ?", build.ID).Order("revision").Find(&revisions)
+	c.JSON(http.StatusOK, revisions)
+}
+
+// @Summary     Get a build revision
+// @Description Get a single revision of a build
+// @Tags        Builds
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "Build ID"
+// @Param       revision path int true "Revision number"
+// @Success     200 {object} models.BuildRevision
+// @Failure     404 {object} map[string]string
+// @Router      /builds/{id}/revisions/{revision} [get]
+func GetBuildRevision(c *gin.Context) {
+	var revision models.BuildRevision
+	err := db.DB.Where("build_id = ? AND revision = ?", c.Param("id"), c.Param("revision")).First(&revision).Error
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Revision not found"})
+		return
+	}
+	c.JSON(http.StatusOK, revision)
+}
+
+// @Summary     Diff two revisions of a build
+// @Description List per-slot additions, removals and swaps between two revisions of a build, with price and weight deltas. Defaults to the previous and latest revisions.
+// @Tags        Builds
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "Build ID"
+// @Param       from query int false "Revision to diff from (defaults to the one before 'to')"
+// @Param       to query int false "Revision to diff to (defaults to the latest)"
+// @Success     200 {object} BuildDiff
+// @Failure     400 {object} map[string]string
+// @Failure     404 {object} map[string]string
+// @Failure     500 {object} map[string]string
+// @Router      /builds/{id}/diff [get]
+func DiffBuildRevisions(c *gin.Context) {
+	var build models.Build
+	if err := db.DB.First(&build, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Build not found"})
+		return
+	}
+
+	var latest models.BuildRevision
+	if err := db.DB.Where("build_id = ?", build.ID).Order("revision DESC").First(&latest).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Build has no revisions"})
+		return
+	}
+
+	to := latest.Revision
+	if toParam := c.Query("to"); toParam != "" {
+		parsed, err := strconv.Atoi(toParam)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid 'to' revision"})
+			return
+		}
+		to = parsed
+	}
+	from := to - 1
+	if fromParam := c.Query("from"); fromParam != "" {
+		parsed, err := strconv.Atoi(fromParam)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid 'from' revision"})
+			return
+		}
+		from = parsed
+	}
+
+	var fromRevision, toRevision models.BuildRevision
+	if err := db.DB.Where("build_id = ? AND revision = ?", build.ID, from).First(&fromRevision).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("Revision %d not found", from)})
+		return
+	}
+	if err := db.DB.Where("build_id = ? AND revision = ?", build.ID, to).First(&toRevision).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("Revision %d not found", to)})
+		return
+	}
+
+	fromSlots, err := revisionSlots(fromRevision)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read revision slots"})
+		return
+	}
+	toSlots, err := revisionSlots(toRevision)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read revision slots"})
+		return
+	}
+
+	diff, err := diffSlots(fromSlots, toSlots)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to diff revisions"})
+		return
+	}
+	diff.From = BuildDiffSide{BuildID: build.ID, Revision: fromRevision.Revision, Name: fromRevision.Name}
+	diff.To = BuildDiffSide{BuildID: build.ID, Revision: toRevision.Revision, Name: toRevision.Name}
+	diff.FromFirearmModelID = fromRevision.FirearmModelID
+	diff.ToFirearmModelID = toRevision.FirearmModelID
+
+	c.JSON(http.StatusOK, diff)
+}
+
+// @Summary     Diff two builds
+// @Description List per-slot additions, removals and swaps between the current state of two builds, with price and weight deltas
+// @Tags        Builds
+// @Accept      json
+// @Produce     json
+// @Param       from query int true "Build ID to diff from"
+// @Param       to query int true "Build ID to diff to"
+// @Success     200 {object} BuildDiff
+// @Failure     400 {object} map[string]string
+// @Failure     404 {object} map[string]string
+// @Failure     500 {object} map[string]string
+// @Router      /builds/diff [get]
+func DiffBuilds(c *gin.Context) {
+	fromID, err := strconv.Atoi(c.Query("from"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid 'from' build ID"})
+		return
+	}
+	toID, err := strconv.Atoi(c.Query("to"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid 'to' build ID"})
+		return
+	}
+
+	fromBuild, err := loadBuild(fromID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("Build %d not found", fromID)})
+		return
+	}
+	toBuild, err := loadBuild(toID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("Build %d not found", toID)})
+		return
+	}
+
+	diff, err := diffSlots(buildSlots(fromBuild), buildSlots(toBuild))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to diff builds"})
+		return
+	}
+	diff.From = BuildDiffSide{BuildID: fromBuild.ID, Name: fromBuild.Name}
+	diff.To = BuildDiffSide{BuildID: toBuild.ID, Name: toBuild.Name}
+	diff.FromFirearmModelID = fromBuild.FirearmModelID
+	diff.ToFirearmModelID = toBuild.FirearmModelID
+
+	c.JSON(http.StatusOK, diff)
+}
+
+// buildSlots returns a build's selections keyed by slot ID
+func buildSlots(build *models.Build) map[int]int {
+	slots := make(map[int]int, len(build.Parts))
+	for _, buildPart := range build.Parts {
+		slots[buildPart.PartCategoryID] = buildPart.PartID
+	}
+	return slots
+}
+
+// revisionSlots decodes the slot selections stored on a revision
+func revisionSlots(revision models.BuildRevision) (map[int]int, error) {
+	slots := make(map[int]int)
+	if len(revision.Slots) == 0 {
+		return slots, nil
+	}
+	if err := json.Unmarshal(revision.Slots, &slots); err != nil {
+		return nil, err
+	}
+	return slots, nil
+}
+
+// lockBuild locks a build's row until the transaction ends, so concurrent saves of the same
+// build number their revisions one after the other
+func lockBuild(tx *gorm.DB, buildID int) error {
+	var build models.Build
+	return tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&build, buildID).Error
+}
+
+// recordBuildRevision appends a snapshot of a build and its slot selections to its history
+func recordBuildRevision(tx *gorm.DB, build models.Build, slots map[int]int) error {
+	if err := lockBuild(tx, build.ID); err != nil {
+		return err
+	}
+
+	var latest int
+	if err := tx.Model(&models.BuildRevision{}).
+		Where("build_id = ?", build.ID).
+		Select("COALESCE(MAX(revision), 0)").
+		Scan(&latest).Error; err != nil {
+		return err
+	}
+
+	slotsJSON, err := json.Marshal(slots)
+	if err != nil {
+		return err
+	}
+
+	revision := models.BuildRevision{
+		BuildID:        build.ID,
+		Revision:       latest + 1,
+		Name:           build.Name,
+		Description:    build.Description,
+		FirearmModelID: build.FirearmModelID,
+		Slots:          datatypes.JSON(slotsJSON),
+	}
+	return tx.Omit("Build").Create(&revision).Error
+}
+
+// ensureInitialRevision snapshots a build that predates revision tracking so its
+// state before the next save is not lost
+func ensureInitialRevision(tx *gorm.DB, buildID int) error {
+	var count int64
+	if err := tx.Model(&models.BuildRevision{}).Where("build_id = ?", buildID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+
+	var build models.Build
+	if err := tx.Preload("Parts").First(&build, buildID).Error; err != nil {
+		return err
+	}
+	return recordBuildRevision(tx, build, buildSlots(&build))
+}
+
+// diffSlots compares two slot selections and prices each change at the parts'
+// cheapest in-stock landed price
+func diffSlots(from, to map[int]int) (*BuildDiff, error) {
+	slotIDs := make(map[int]int)
+	var partIDs []int
+	for slotID, partID := range from {
+		slotIDs[slotID] = slotID
+		partIDs = append(partIDs, partID)
+	}
+	for slotID, partID := range to {
+		slotIDs[slotID] = slotID
+		partIDs = append(partIDs, partID)
+	}
+	partIDs = uniqueInts(partIDs)
+
+	tree, err := loadCategoryTree()
+	if err != nil {
+		return nil, err
+	}
+
+	partByID := make(map[int]models.Part)
+	if len(partIDs) > 0 {
+		var parts []models.Part
+		if err := db.DB.Where("id IN ?", partIDs).Find(&parts).Error; err != nil {
+			return nil, err
+		}
+		for _, part := range parts {
+			partByID[part.ID] = part
+		}
+	}
+
+	listingsByPart, err := loadInStockListingsByPart(partIDs)
+	if err != nil {
+		return nil, err
+	}
+	priceOf := func(partID int) (float64, bool) {
+		listings := listingsByPart[partID]
+		if len(listings) == 0 {
+			return 0, false
+		}
+		return landedPrice(listings[0]), true
+	}
+
+	diff := &BuildDiff{Changes: []BuildSlotChange{}}
+	for _, slotID := range sortedSlotIDs(slotIDs) {
+		fromPart, inFrom := from[slotID]
+		toPart, inTo := to[slotID]
+		if inFrom && inTo && fromPart == toPart {
+			continue
+		}
+
+		change := BuildSlotChange{PartCategoryID: slotID, CategoryName: tree[slotID].Name}
+		switch {
+		case inFrom && inTo:
+			change.Change = SlotChangeSwapped
+		case inTo:
+			change.Change = SlotChangeAdded
+		default:
+			change.Change = SlotChangeRemoved
+		}
+
+		if inFrom {
+			change.FromPartID = fromPart
+			change.FromPartName = partByID[fromPart].Name
+			price, ok := priceOf(fromPart)
+			change.PriceDelta -= price
+			change.WeightDelta -= partByID[fromPart].Weight
+			change.PriceIncomplete = change.PriceIncomplete || !ok
+		}
+		if inTo {
+			change.ToPartID = toPart
+			change.ToPartName = partByID[toPart].Name
+			price, ok := priceOf(toPart)
+			change.PriceDelta += price
+			change.WeightDelta += partByID[toPart].Weight
+			change.PriceIncomplete = change.PriceIncomplete || !ok
+		}
+
+		change.PriceDelta = roundCents(change.PriceDelta)
+		change.WeightDelta = roundWeight(change.WeightDelta)
+		diff.TotalPriceDelta += change.PriceDelta
+		diff.TotalWeightDelta += change.WeightDelta
+		diff.Changes = append(diff.Changes, change)
+	}
+
+	diff.TotalPriceDelta = roundCents(diff.TotalPriceDelta)
+	diff.TotalWeightDelta = roundWeight(diff.TotalWeightDelta)
+	return diff, nil
+}
@@ -0,0 +1,353 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sauron-backend/internal/db"
+	"sauron-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Build validation error codes
+const (
+	BuildErrorMissingRequiredSlot    = "missing_required_slot"
+	BuildErrorUnknownSlot            = "unknown_slot"
+	BuildErrorPartNotFound           = "part_not_found"
+	BuildErrorPartUncategorized      = "part_uncategorized"
+	BuildErrorPartCategoryNotInModel = "part_category_not_in_model"
+	BuildErrorPartInWrongSlot        = "part_in_wrong_slot"
+	BuildErrorPartDoesNotFitModel    = "part_does_not_fit_model"
+	BuildErrorInsufficientRailSpace  = "insufficient_rail_space"
+	BuildErrorMagazineDoesNotFit     = "magazine_does_not_fit"
+)
+
+var errFirearmModelNotFound = errors.New("firearm model not found")
+
+// BuildValidationInput is the request body for validating a slot selection
+type BuildValidationInput struct {
+	// Firearm model the selection is for
+	FirearmModelID int `json:"firearm_model_id" binding:"required" example:"1"`
+
+	// Selected parts keyed by part category (slot) ID
+	Slots map[int]int `json:"slots"`
+}
+
+// BuildValidationError describes a single problem with a slot selection
+type BuildValidationError struct {
+	// Machine-readable error code
+	Code string `json:"code" example:"missing_required_slot"`
+
+	// Slot (part category) the error refers to
+	PartCategoryID int `json:"part_category_id" example:"10"`
+
+	// Part the error refers to, if any
+	PartID int `json:"part_id,omitempty" example:"5"`
+
+	// Slot the part belongs in, for parts picked for the wrong slot
+	ExpectedPartCategoryID int `json:"expected_part_category_id,omitempty" example:"13"`
+
+	// Human-readable description of the error
+	Message string `json:"message" example:"Required slot Barrel has no part selected"`
+}
+
+// BuildValidationWarning flags a legal concern with a slot selection that does not make it invalid
+type BuildValidationWarning struct {
+	// Machine-readable warning code: an NFA classification or item, or insufficient_rail_space
+	// when an accessory may not fit but the rail allocation search was cut short
+	Code string `json:"code" example:"short_barreled_rifle"`
+
+	// Slot and part the warning refers to, if a single part causes it
+	PartCategoryID int `json:"part_category_id,omitempty" example:"23"`
+	PartID         int `json:"part_id,omitempty" example:"42"`
+
+	// Human-readable description of the warning
+	Message string `json:"message" example:"This configuration is a short-barreled rifle (SBR): the 10.5 in barrel is under 16 in and B5 SOPMOD Stock (AR-15) is a shoulder stock. It must be registered on an approved ATF Form 1 or Form 4 before it is assembled."`
+}
+
+// BuildValidationResult is the outcome of validating a slot selection
+type BuildValidationResult struct {
+	Valid    bool                     `json:"valid" example:"false"`
+	Errors   []BuildValidationError   `json:"errors"`
+	Warnings []BuildValidationWarning `json:"warnings"`
+}
+
+// @Summary     Validate a build
+// @Description Check a slot selection against the firearm model's part categories and report missing required slots, category mismatches, parts declared to fit other models, magazines the build cannot take and accessories that do not fit on the build's rails. NFA-regulated configurations, such as a short-barreled rifle, are reported as warnings that do not make the build invalid, as are accessories that may not fit when the rail allocation search was cut short.
+// @Tags        Builds
+// @Accept      json
+// @Produce     json
+// @Param       selection body BuildValidationInput true "Slot selection to validate"
+// @Success     200 {object} BuildValidationResult
+// @Failure     400 {object} map[string]string
+// @Failure     404 {object} map[string]string
+// @Failure     500 {object} map[string]string
+// @Router      /builds/validate [post]
+func ValidateBuild(c *gin.Context) {
+	var input BuildValidationInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := validateBuildSelection(input.FirearmModelID, input.Slots)
+	if errors.Is(err, errFirearmModelNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Firearm model not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to validate build"})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// validateBuildSelection checks each selected part against the slots the firearm model
+// defines and reports required slots that are left empty. A required slot counts as
+// filled when it, one of its parent slots, or one of its child slots has a part.
+func validateBuildSelection(modelID int, slots map[int]int) (*BuildValidationResult, error) {
+	var model models.FirearmModel
+	if err := db.DB.First(&model, modelID).Error; err != nil {
+		return nil, errFirearmModelNotFound
+	}
+
+	var relations []models.FirearmModelPartCategory
+	if err := db.DB.Where("firearm_model_id = ?", modelID).Order("part_category_id").Find(&relations).Error; err != nil {
+		return nil, err
+	}
+
+	tree, err := loadCategoryTree()
+	if err != nil {
+		return nil, err
+	}
+
+	partIDs := make([]int, 0, len(slots))
+	for _, partID := range slots {
+		partIDs = append(partIDs, partID)
+	}
+	partByID := make(map[int]models.Part)
+	if len(partIDs) > 0 {
+		var parts []models.Part
+		if err := db.DB.Where("id IN ?", partIDs).Find(&parts).Error; err != nil {
+			return nil, err
+		}
+		for _, part := range parts {
+			partByID[part.ID] = part
+		}
+	}
+	fitsByPart, err := loadDeclaredFitments(partIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	slotRequired := make(map[int]bool, len(relations))
+	for _, relation := range relations {
+		slotRequired[relation.PartCategoryID] = relation.IsRequired
+	}
+
+	result := &BuildValidationResult{Valid: true, Errors: []BuildValidationError{}, Warnings: []BuildValidationWarning{}}
+	addError := func(e BuildValidationError) {
+		result.Valid = false
+		result.Errors = append(result.Errors, e)
+	}
+
+	// Check every selected part against the slot it was picked for
+	for _, slotID := range sortedSlotIDs(slots) {
+		partID := slots[slotID]
+		slotName := tree[slotID].Name
+
+		if _, ok := slotRequired[slotID]; !ok {
+			addError(BuildValidationError{
+				Code:           BuildErrorUnknownSlot,
+				PartCategoryID: slotID,
+				PartID:         partID,
+				Message:        fmt.Sprintf("Part category %d is not a slot of firearm model %s", slotID, model.Name),
+			})
+			continue
+		}
+
+		part, ok := partByID[partID]
+		if !ok {
+			addError(BuildValidationError{
+				Code:           BuildErrorPartNotFound,
+				PartCategoryID: slotID,
+				PartID:         partID,
+				Message:        fmt.Sprintf("Part %d selected for slot %s does not exist", partID, slotName),
+			})
+			continue
+		}
+
+		if part.PartCategoryID == nil {
+			addError(BuildValidationError{
+				Code:           BuildErrorPartUncategorized,
+				PartCategoryID: slotID,
+				PartID:         partID,
+				Message:        fmt.Sprintf("Part %s has no part category and cannot be placed in slot %s", part.Name, slotName),
+			})
+			continue
+		}
+
+		if tree.isWithin(*part.PartCategoryID, slotID) {
+			// Parts declared to fit specific models must list this one
+			if fits := fitsByPart[partID]; fits != nil && !fits[modelID] {
+				addError(BuildValidationError{
+					Code:           BuildErrorPartDoesNotFitModel,
+					PartCategoryID: slotID,
+					PartID:         partID,
+					Message:        fmt.Sprintf("Part %s is not declared to fit firearm model %s", part.Name, model.Name),
+				})
+			}
+			continue
+		}
+
+		// Find the slot the part actually belongs in, if the model has one
+		expectedSlot := 0
+		for _, candidate := range append([]int{*part.PartCategoryID}, tree.ancestors(*part.PartCategoryID)...) {
+			if _, ok := slotRequired[candidate]; ok {
+				expectedSlot = candidate
+				break
+			}
+		}
+
+		if expectedSlot == 0 {
+			addError(BuildValidationError{
+				Code:           BuildErrorPartCategoryNotInModel,
+				PartCategoryID: slotID,
+				PartID:         partID,
+				Message:        fmt.Sprintf("Part %s is in category %s, which is not used by firearm model %s", part.Name, tree[*part.PartCategoryID].Name, model.Name),
+			})
+			continue
+		}
+
+		addError(BuildValidationError{
+			Code:                   BuildErrorPartInWrongSlot,
+			PartCategoryID:         slotID,
+			PartID:                 partID,
+			ExpectedPartCategoryID: expectedSlot,
+			Message:                fmt.Sprintf("Part %s belongs in slot %s, not %s", part.Name, tree[expectedSlot].Name, slotName),
+		})
+	}
+
+	// A slot is covered when it or one of its parent slots has a part selected
+	covered := func(slotID int) bool {
+		if _, ok := slots[slotID]; ok {
+			return true
+		}
+		for _, ancestorID := range tree.ancestors(slotID) {
+			if _, ok := slots[ancestorID]; ok {
+				return true
+			}
+		}
+		return false
+	}
+
+	// Report required slots with nothing selected in or under them
+	for _, relation := range relations {
+		if !relation.IsRequired || covered(relation.PartCategoryID) {
+			continue
+		}
+
+		childSelected := false
+		for slotID := range slots {
+			if slotID != relation.PartCategoryID && tree.isWithin(slotID, relation.PartCategoryID) {
+				childSelected = true
+				break
+			}
+		}
+		if childSelected {
+			continue
+		}
+
+		addError(BuildValidationError{
+			Code:           BuildErrorMissingRequiredSlot,
+			PartCategoryID: relation.PartCategoryID,
+			Message:        fmt.Sprintf("Required slot %s has no part selected", tree[relation.PartCategoryID].Name),
+		})
+	}
+
+	// Report magazines whose family the model does not take or that do not feed its caliber
+	magazines, err := loadMagazineCatalog()
+	if err != nil {
+		return nil, err
+	}
+	chamberCaliberIDs, err := selectionChamberCalibers(modelID, slots, magazines)
+	if err != nil {
+		return nil, err
+	}
+	for _, slotID := range sortedSlotIDs(slots) {
+		entry, ok := magazines.variantByPart[slots[slotID]]
+		if !ok || magazines.fits(entry, modelID, chamberCaliberIDs) {
+			continue
+		}
+		reason := fmt.Sprintf("firearm model %s does not take %s magazines", model.Name, entry.family.Name)
+		if magazines.modelsByFamily[entry.family.ID][modelID] {
+			reason = "it does not feed the caliber the build is chambered in"
+		}
+		addError(BuildValidationError{
+			Code:           BuildErrorMagazineDoesNotFit,
+			PartCategoryID: slotID,
+			PartID:         slots[slotID],
+			Message:        fmt.Sprintf("Magazine %s does not fit: %s", partByID[slots[slotID]].Name, reason),
+		})
+	}
+
+	// Report accessories the selected rails have no room for. When the allocation search was
+	// cut short they might fit in another arrangement, so they are only warned about.
+	railSpace, err := computeRailSpace(slots)
+	if err != nil {
+		return nil, err
+	}
+	for _, shortfall := range railSpace.Unplaced {
+		if railSpace.SearchTruncated {
+			result.Warnings = append(result.Warnings, BuildValidationWarning{
+				Code:           BuildErrorInsufficientRailSpace,
+				PartCategoryID: shortfall.PartCategoryID,
+				PartID:         shortfall.PartID,
+				Message:        fmt.Sprintf("%s may not fit: %s. Not every arrangement of the accessories was tried.", shortfall.PartName, shortfall.Message),
+			})
+			continue
+		}
+		addError(BuildValidationError{
+			Code:           BuildErrorInsufficientRailSpace,
+			PartCategoryID: shortfall.PartCategoryID,
+			PartID:         shortfall.PartID,
+			Message:        fmt.Sprintf("%s does not fit: %s", shortfall.PartName, shortfall.Message),
+		})
+	}
+
+	// Warn about NFA-regulated configurations and items
+	profile, err := profileSelection(modelID, slots)
+	if err != nil {
+		return nil, err
+	}
+	slotByPart := make(map[int]int, len(slots))
+	for slotID, partID := range slots {
+		slotByPart[partID] = slotID
+	}
+	for _, warning := range classifyNFA(profile).Warnings {
+		result.Warnings = append(result.Warnings, BuildValidationWarning{
+			Code:           warning.Code,
+			PartCategoryID: slotByPart[warning.PartID],
+			PartID:         warning.PartID,
+			Message:        warning.Message,
+		})
+	}
+
+	return result, nil
+}
+
+// blockingErrors returns the errors that should prevent a build from being saved.
+// Missing required slots and accessories without rail space are tolerated when
+// allowIncomplete is set so work-in-progress builds can be stored.
+func (r *BuildValidationResult) blockingErrors(allowIncomplete bool) []BuildValidationError {
+	var blocking []BuildValidationError
+	for _, e := range r.Errors {
+		if allowIncomplete && (e.Code == BuildErrorMissingRequiredSlot || e.Code == BuildErrorInsufficientRailSpace) {
+			continue
+		}
+		blocking = append(blocking, e)
+	}
+	return blocking
+}
@@ -0,0 +1,316 @@
+package handlers
+
+import (
+	"net/http"
+	"sauron-backend/internal/db"
+	"sauron-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BuildVsBuyComponent is the price of one component of a prebuilt when bought as a part
+type BuildVsBuyComponent struct {
+	// Position of the component in the prebuilt's parts tree
+	Path string `json:"path" example:"Upper Assembly > Bolt Carrier Group"`
+
+	// Part referenced by the component, if any
+	PartID   int    `json:"part_id,omitempty" example:"5"`
+	PartName string `json:"part_name,omitempty" example:"Complete BCG (AR-15)"`
+
+	// Whether an in-stock listing was found for the part
+	InStock bool `json:"in_stock" example:"true"`
+
+	// Cheapest in-stock listing for the part, if any
+	ListingID    int     `json:"listing_id,omitempty" example:"42"`
+	SellerName   string  `json:"seller_name,omitempty" example:"Brownells"`
+	Price        float64 `json:"price" example:"149.99"`
+	ShippingCost float64 `json:"shipping_cost" example:"7.99"`
+	LineTotal    float64 `json:"line_total" example:"157.98"`
+
+	// Whether the part has in-stock listings but none ship to the destination
+	ShippingRestricted bool `json:"shipping_restricted,omitempty" example:"false"`
+
+	// Whether the listing only ships to a licensed dealer (FFL) in the destination state
+	FFLRequired bool `json:"ffl_required,omitempty" example:"false"`
+
+	// Reasons of the shipping restrictions that apply to the part's listings
+	ShippingNotes []string `json:"shipping_notes,omitempty" example:"Magazines ship only to California FFLs"`
+}
+
+// PrebuiltOffer is the cheapest in-stock listing for a prebuilt firearm
+type PrebuiltOffer struct {
+	ListingID    int     `json:"listing_id" example:"7"`
+	SellerID     int     `json:"seller_id" example:"4"`
+	SellerName   string  `json:"seller_name" example:"Palmetto State Armory"`
+	URL          string  `json:"url" example:"https://palmettostatearmory.com/product/standard-ar-15-rifle"`
+	Price        float64 `json:"price" example:"1249.99"`
+	ShippingCost float64 `json:"shipping_cost" example:"19.99"`
+	Total        float64 `json:"total" example:"1269.98"`
+
+	// Whether the listing only ships to a licensed dealer (FFL) in the destination state, and why
+	FFLRequired   bool     `json:"ffl_required,omitempty" example:"true"`
+	ShippingNotes []string `json:"shipping_notes,omitempty" example:"Firearms ship to an FFL only"`
+}
+
+// BuildVsBuyResult compares buying a prebuilt firearm with building it from parts
+type BuildVsBuyResult struct {
+	PrebuiltID   int    `json:"prebuilt_id" example:"1"`
+	PrebuiltName string `json:"prebuilt_name" example:"Standard AR-15 Rifle"`
+	Currency     string `json:"currency" example:"USD"`
+
+	// Destination state the listings were limited to, if any
+	ShipTo string `json:"ship_to,omitempty" example:"CA"`
+
+	// Catalog price of the prebuilt and its cheapest in-stock listing, if any
+	PrebuiltPrice         float64        `json:"prebuilt_price" example:"1299.99"`
+	PrebuiltCheapestOffer *PrebuiltOffer `json:"prebuilt_cheapest_offer,omitempty"`
+
+	// Whether the prebuilt has in-stock listings but none ship to the destination
+	PrebuiltShippingRestricted bool `json:"prebuilt_shipping_restricted,omitempty" example:"false"`
+
+	// Lowest of the catalog price and the cheapest listing total
+	BuyTotal float64 `json:"buy_total" example:"1269.98"`
+
+	// Every component of the prebuilt priced at its cheapest in-stock listing
+	Components    []BuildVsBuyComponent `json:"components"`
+	PartsTotal    float64               `json:"parts_total" example:"1149.50"`
+	ShippingTotal float64               `json:"shipping_total" example:"31.96"`
+	BuildTotal    float64               `json:"build_total" example:"1181.46"`
+
+	// Build total when parts are consolidated across sellers to save on shipping
+	OptimizedBuildTotal float64 `json:"optimized_build_total" example:"1165.47"`
+
+	// Components that could not be priced: no part ID or no in-stock listing
+	UnpricedComponents []string `json:"unpriced_components"`
+
+	// Parts whose in-stock listings cannot ship to the destination
+	RestrictedPartIDs []int `json:"restricted_part_ids"`
+
+	// Whether every component could be priced, making the comparison meaningful
+	Complete bool `json:"complete" example:"true"`
+
+	// Optimized build total minus buy total; negative means building is cheaper
+	Difference float64 `json:"difference" example:"-104.51"`
+
+	// Cheaper option, omitted when some components could not be priced
+	Cheaper string `json:"cheaper,omitempty" example:"build" enums:"build,buy"`
+}
+
+// @Summary     Compare building vs buying a prebuilt
+// @Description Price every part referenced in a prebuilt firearm's components tree, pricing an assembly through its sub-parts when they reference parts, and compare the parts build total with the prebuilt's price and cheapest listing. Given a destination state, listings of parts and of the prebuilt that cannot ship there are skipped and listings that must ship to an FFL are flagged.
+// @Tags        Prebuilt Firearms,Builds
+// @Accept      json
+// @Produce     json
+// @Param       id      path  int    true  "Prebuilt Firearm ID"
+// @Param       ship_to query string false "Destination state, e.g. CA or US-CA"
+// @Success     200 {object} BuildVsBuyResult
+// @Failure     400 {object} map[string]string
+// @Failure     404 {object} map[string]string
+// @Failure     422 {object} map[string]string
+// @Failure     500 {object} map[string]string
+// @Router      /prebuilt-firearms/{id}/build-vs-buy [get]
+func GetBuildVsBuy(c *gin.Context) {
+	state, ok := shipToParam(c, c.Query("ship_to"))
+	if !ok {
+		return
+	}
+	id := c.Param("id")
+	var prebuilt models.PrebuiltFirearm
+	if err := db.DB.First(&prebuilt, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Prebuilt firearm not found"})
+		return
+	}
+
+	components, err := flattenPrebuiltComponents(prebuilt.Parts)
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Prebuilt firearm has malformed parts data"})
+		return
+	}
+
+	result, err := compareBuildVsBuy(prebuilt, components, state)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to price prebuilt firearm"})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// compareBuildVsBuy prices the prebuilt's referenced parts and its own listings, using only
+// listings that can ship to the state when one is given
+func compareBuildVsBuy(prebuilt models.PrebuiltFirearm, components []prebuiltComponent, state string) (*BuildVsBuyResult, error) {
+	result := &BuildVsBuyResult{
+		PrebuiltID:         prebuilt.ID,
+		PrebuiltName:       prebuilt.Name,
+		Currency:           "USD",
+		ShipTo:             state,
+		PrebuiltPrice:      roundCents(prebuilt.Price),
+		Components:         []BuildVsBuyComponent{},
+		UnpricedComponents: []string{},
+		Complete:           true,
+	}
+
+	// An assembly whose sub-parts reference parts is bought as those sub-parts, not on top of them
+	var partIDs []int
+	for _, component := range components {
+		if component.PartID != nil && !hasPricedDescendant(component, components) {
+			partIDs = append(partIDs, *component.PartID)
+		}
+	}
+	partIDs = uniqueInts(partIDs)
+
+	partByID := make(map[int]models.Part)
+	if len(partIDs) > 0 {
+		var parts []models.Part
+		if err := db.DB.Where("id IN ?", partIDs).Find(&parts).Error; err != nil {
+			return nil, err
+		}
+		for _, part := range parts {
+			partByID[part.ID] = part
+		}
+	}
+
+	shippable, err := loadShippableListingsByPart(partIDs, state)
+	if err != nil {
+		return nil, err
+	}
+	listingsByPart := shippable.byPart
+	result.RestrictedPartIDs = shippable.restrictedPartIDs()
+
+	pricedParts := make(map[int]bool)
+	for _, component := range components {
+		line := BuildVsBuyComponent{Path: component.pathString()}
+		if component.PartID == nil {
+			// Structural entries without an ID only matter if nothing below them is priced
+			if !hasPricedDescendant(component, components) {
+				result.Complete = false
+				result.UnpricedComponents = append(result.UnpricedComponents, line.Path)
+				result.Components = append(result.Components, line)
+			}
+			continue
+		}
+		if hasPricedDescendant(component, components) {
+			continue
+		}
+
+		// The same part can appear under several assemblies but is only bought once
+		if pricedParts[*component.PartID] {
+			continue
+		}
+		pricedParts[*component.PartID] = true
+
+		line.PartID = *component.PartID
+		line.PartName = partByID[line.PartID].Name
+
+		listings := listingsByPart[line.PartID]
+		if len(listings) == 0 {
+			if reasons, restricted := shippable.restricted[line.PartID]; restricted {
+				line.ShippingRestricted = true
+				line.ShippingNotes = reasons
+			}
+			result.Complete = false
+			result.UnpricedComponents = append(result.UnpricedComponents, line.Path)
+			result.Components = append(result.Components, line)
+			continue
+		}
+
+		cheapest := listings[0]
+		line.InStock = true
+		line.ListingID = cheapest.ID
+		line.SellerName = cheapest.Seller.Name
+		line.Price = roundCents(cheapest.Price)
+		line.ShippingCost = roundCents(listingShipping(cheapest))
+		line.LineTotal = roundCents(line.Price + line.ShippingCost)
+		if status := shippable.status[cheapest.ID]; status.FFLRequired {
+			line.FFLRequired = true
+			line.ShippingNotes = status.Reasons
+		}
+
+		result.PartsTotal += line.Price
+		result.ShippingTotal += line.ShippingCost
+		result.Components = append(result.Components, line)
+	}
+
+	result.PartsTotal = roundCents(result.PartsTotal)
+	result.ShippingTotal = roundCents(result.ShippingTotal)
+	result.BuildTotal = roundCents(result.PartsTotal + result.ShippingTotal)
+	result.OptimizedBuildTotal = buildCart(partIDs, partByID, listingsByPart).Total
+
+	// Price the prebuilt itself
+	var prebuiltListings []models.ProductListing
+	err = db.DB.Preload("Seller").
+		Where("prebuilt_id = ?", prebuilt.ID).
+		Where("availability IN ?", inStockAvailabilities).
+		Find(&prebuiltListings).Error
+	if err != nil {
+		return nil, err
+	}
+
+	prebuiltStatus := map[int]ListingShippingStatus{}
+	if state != "" {
+		if prebuiltStatus, err = listingShippingStatuses(prebuiltListings, state); err != nil {
+			return nil, err
+		}
+		shipping := prebuiltListings[:0]
+		for _, listing := range prebuiltListings {
+			if prebuiltStatus[listing.ID].CanShip {
+				shipping = append(shipping, listing)
+			}
+		}
+		result.PrebuiltShippingRestricted = len(prebuiltListings) > 0 && len(shipping) == 0
+		prebuiltListings = shipping
+	}
+
+	result.BuyTotal = result.PrebuiltPrice
+	if len(prebuiltListings) > 0 {
+		sortListingsByLandedPrice(prebuiltListings)
+		cheapest := prebuiltListings[0]
+		offer := &PrebuiltOffer{
+			ListingID:    cheapest.ID,
+			SellerID:     cheapest.SellerID,
+			SellerName:   cheapest.Seller.Name,
+			URL:          cheapest.URL,
+			Price:        roundCents(cheapest.Price),
+			ShippingCost: roundCents(listingShipping(cheapest)),
+		}
+		offer.Total = roundCents(offer.Price + offer.ShippingCost)
+		if status := prebuiltStatus[cheapest.ID]; status.FFLRequired {
+			offer.FFLRequired = true
+			offer.ShippingNotes = status.Reasons
+		}
+		result.PrebuiltCheapestOffer = offer
+
+		if result.BuyTotal == 0 || offer.Total < result.BuyTotal {
+			result.BuyTotal = offer.Total
+		}
+	}
+
+	result.Difference = roundCents(result.OptimizedBuildTotal - result.BuyTotal)
+	if result.Complete {
+		result.Cheaper = "buy"
+		if result.Difference < 0 {
+			result.Cheaper = "build"
+		}
+	}
+	return result, nil
+}
+
+// hasPricedDescendant reports whether any entry below a component references a part
+func hasPricedDescendant(component prebuiltComponent, components []prebuiltComponent) bool {
+	for _, other := range components {
+		if other.PartID == nil || len(other.Path) <= len(component.Path) {
+			continue
+		}
+		matches := true
+		for i, name := range component.Path {
+			if other.Path[i] != name {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return true
+		}
+	}
+	return false
+}
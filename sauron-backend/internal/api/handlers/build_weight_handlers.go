@@ -0,0 +1,197 @@
+package handlers
+
+import (
+	"math"
+	"net/http"
+	"sauron-backend/internal/db"
+	"sauron-backend/internal/models"
+	"sort"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BuildWeightInput is the request body for weighing an unsaved slot selection
+type BuildWeightInput struct {
+	// Selected parts keyed by part category (slot) ID
+	Slots map[int]int `json:"slots" binding:"required"`
+}
+
+// BuildWeightLine is the weight of the part in a single slot
+type BuildWeightLine struct {
+	PartCategoryID int    `json:"part_category_id" example:"10"`
+	PartID         int    `json:"part_id" example:"5"`
+	PartName       string `json:"part_name" example:"16\" 5.56 NATO Barrel (AR-15)"`
+
+	// Weight in pounds, zero when missing
+	Weight float64 `json:"weight" example:"1.65"`
+
+	// Dimensions as recorded on the part, if any
+	Dimensions string `json:"dimensions,omitempty" example:"16 x 1 x 1 in"`
+
+	// Whether the part has no weight recorded, so the totals understate the build
+	MissingWeight bool `json:"missing_weight" example:"false"`
+}
+
+// BuildWeightAssembly groups the lines of a build under a top-level part category
+type BuildWeightAssembly struct {
+	// Top-level part category, e.g. Upper Receiver
+	PartCategoryID int    `json:"part_category_id" example:"2"`
+	CategoryName   string `json:"category_name" example:"Upper Receiver"`
+
+	Lines  []BuildWeightLine `json:"lines"`
+	Weight float64           `json:"weight" example:"3.42"`
+
+	// Parts in the assembly with no weight recorded
+	MissingWeightCount int `json:"missing_weight_count" example:"0"`
+}
+
+// BuildWeightResult is the total weight of a build with a per-assembly breakdown
+type BuildWeightResult struct {
+	// Build that was weighed, omitted for unsaved selections
+	BuildID int `json:"build_id,omitempty" example:"1"`
+
+	// Weights are in pounds
+	Unit        string                `json:"unit" example:"lb"`
+	TotalWeight float64               `json:"total_weight" example:"6.48"`
+	Assemblies  []BuildWeightAssembly `json:"assemblies"`
+
+	// Parts with no weight recorded, which are excluded from the totals
+	MissingWeightPartIDs []int `json:"missing_weight_part_ids"`
+
+	// Whether every part had a weight recorded
+	Complete bool `json:"complete" example:"true"`
+}
+
+// @Summary     Get build weight
+// @Description Sum the weight of a saved build's parts, grouped by top-level part category, flagging parts with no weight recorded
+// @Tags        Builds
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "Build ID"
+// @Success     200 {object} BuildWeightResult
+// @Failure     404 {object} map[string]string
+// @Failure     500 {object} map[string]string
+// @Router      /builds/{id}/weight [get]
+func GetBuildWeight(c *gin.Context) {
+	build, err := loadBuild(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Build not found"})
+		return
+	}
+
+	result, err := computeBuildWeight(buildSlots(build))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to weigh build"})
+		return
+	}
+	result.BuildID = build.ID
+
+	c.JSON(http.StatusOK, result)
+}
+
+// @Summary     Weigh a slot selection
+// @Description Sum the weight of an unsaved slot selection, grouped by top-level part category, flagging parts with no weight recorded
+// @Tags        Builds
+// @Accept      json
+// @Produce     json
+// @Param       selection body BuildWeightInput true "Slot selection to weigh"
+// @Success     200 {object} BuildWeightResult
+// @Failure     400 {object} map[string]string
+// @Failure     500 {object} map[string]string
+// @Router      /builds/weight [post]
+func WeighBuildSelection(c *gin.Context) {
+	var input BuildWeightInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := computeBuildWeight(input.Slots)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to weigh build"})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// computeBuildWeight sums part weights per top-level category of each slot
+func computeBuildWeight(slots map[int]int) (*BuildWeightResult, error) {
+	partIDs := make([]int, 0, len(slots))
+	for _, partID := range slots {
+		partIDs = append(partIDs, partID)
+	}
+
+	partByID := make(map[int]models.Part)
+	if len(partIDs) > 0 {
+		var parts []models.Part
+		if err := db.DB.Where("id IN ?", partIDs).Find(&parts).Error; err != nil {
+			return nil, err
+		}
+		for _, part := range parts {
+			partByID[part.ID] = part
+		}
+	}
+
+	tree, err := loadCategoryTree()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &BuildWeightResult{
+		Unit:                 "lb",
+		Assemblies:           []BuildWeightAssembly{},
+		MissingWeightPartIDs: []int{},
+		Complete:             true,
+	}
+	assemblyIndex := make(map[int]int)
+
+	for _, slotID := range sortedSlotIDs(slots) {
+		part := partByID[slots[slotID]]
+		line := BuildWeightLine{
+			PartCategoryID: slotID,
+			PartID:         slots[slotID],
+			PartName:       part.Name,
+			Weight:         part.Weight,
+			Dimensions:     part.Dimensions,
+			MissingWeight:  part.Weight <= 0,
+		}
+
+		topLevelID := tree.root(slotID)
+		index, ok := assemblyIndex[topLevelID]
+		if !ok {
+			index = len(result.Assemblies)
+			assemblyIndex[topLevelID] = index
+			result.Assemblies = append(result.Assemblies, BuildWeightAssembly{
+				PartCategoryID: topLevelID,
+				CategoryName:   tree[topLevelID].Name,
+				Lines:          []BuildWeightLine{},
+			})
+		}
+		assembly := &result.Assemblies[index]
+		assembly.Lines = append(assembly.Lines, line)
+
+		if line.MissingWeight {
+			assembly.MissingWeightCount++
+			result.Complete = false
+			result.MissingWeightPartIDs = append(result.MissingWeightPartIDs, line.PartID)
+			continue
+		}
+		assembly.Weight += line.Weight
+		result.TotalWeight += line.Weight
+	}
+
+	for i := range result.Assemblies {
+		result.Assemblies[i].Weight = roundWeight(result.Assemblies[i].Weight)
+	}
+	sort.SliceStable(result.Assemblies, func(i, j int) bool {
+		return result.Assemblies[i].PartCategoryID < result.Assemblies[j].PartCategoryID
+	})
+	result.TotalWeight = roundWeight(result.TotalWeight)
+	return result, nil
+}
+
+// roundWeight rounds a weight in pounds to two decimal places, the precision parts are stored at
+func roundWeight(weight float64) float64 {
+	return math.Round(weight*100) / 100
+}
@@ -0,0 +1,358 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sauron-backend/internal/db"
+	"sauron-backend/internal/models"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+var errUnknownCaliber = errors.New("unknown caliber")
+
+// CaliberInput is the request body for creating or updating a caliber
+type CaliberInput struct {
+	Name           string   `json:"name" binding:"required" example:"5.56x45mm NATO"`
+	Aliases        []string `json:"aliases" example:"5.56 NATO,5.56mm"`
+	Type           string   `json:"type" example:"rifle" enums:"rifle,pistol,rimfire,shotgun"`
+	BulletDiameter float64  `json:"bullet_diameter" example:"0.224"`
+	CaseLength     float64  `json:"case_length" example:"44.70"`
+	OverallLength  float64  `json:"overall_length" example:"57.40"`
+
+	// Cartridge this caliber was derived from
+	ParentCaliberID *int `json:"parent_caliber_id" example:"2"`
+
+	// Calibers that can be safely fired in a chamber for this caliber
+	FiresCaliberIDs []int `json:"fires_caliber_ids" example:"2"`
+}
+
+// caliberIndex looks calibers up by ID or by case-insensitive name or alias
+type caliberIndex struct {
+	byID   map[int]models.Caliber
+	byName map[string]int
+}
+
+// loadCaliberIndex loads every caliber with the calibers its chamber fires
+func loadCaliberIndex() (*caliberIndex, error) {
+	var calibers []models.Caliber
+	if err := db.DB.Preload("FiresCalibers").Order("id").Find(&calibers).Error; err != nil {
+		return nil, err
+	}
+
+	index := &caliberIndex{
+		byID:   make(map[int]models.Caliber, len(calibers)),
+		byName: make(map[string]int),
+	}
+	for _, caliber := range calibers {
+		index.byID[caliber.ID] = caliber
+		for _, name := range caliberNames(caliber) {
+			if _, taken := index.byName[normalizeCaliberName(name)]; !taken {
+				index.byName[normalizeCaliberName(name)] = caliber.ID
+			}
+		}
+	}
+	return index, nil
+}
+
+// lookup resolves a caliber ID, name or alias
+func (ix *caliberIndex) lookup(ref string) (models.Caliber, bool) {
+	if id, err := strconv.Atoi(ref); err == nil {
+		caliber, ok := ix.byID[id]
+		return caliber, ok
+	}
+	id, ok := ix.byName[normalizeCaliberName(ref)]
+	if !ok {
+		return models.Caliber{}, false
+	}
+	return ix.byID[id], true
+}
+
+// fires reports whether a chamber for one caliber can fire another
+func (ix *caliberIndex) fires(chamberID, ammoID int) bool {
+	for _, fired := range ix.byID[chamberID].FiresCalibers {
+		if fired.ID == ammoID {
+			return true
+		}
+	}
+	return false
+}
+
+// caliberNames returns a caliber's name followed by its aliases
+func caliberNames(caliber models.Caliber) []string {
+	names := []string{caliber.Name}
+	var aliases []string
+	if len(caliber.Aliases) > 0 {
+		_ = json.Unmarshal(caliber.Aliases, &aliases)
+	}
+	return append(names, aliases...)
+}
+
+func normalizeCaliberName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// resolveCaliberQuery resolves the ?caliber= query parameter, an ID, name or alias, to the
+// caliber IDs to filter on. With ?include_fired=true the calibers that caliber's chamber can
+// fire are included too. It returns nil when the parameter is absent and errUnknownCaliber
+// when nothing matches.
+func resolveCaliberQuery(c *gin.Context) ([]int, error) {
+	ref := c.Query("caliber")
+	if ref == "" {
+		return nil, nil
+	}
+	index, err := loadCaliberIndex()
+	if err != nil {
+		return nil, err
+	}
+	caliber, ok := index.lookup(ref)
+	if !ok {
+		return nil, errUnknownCaliber
+	}
+
+	ids := []int{caliber.ID}
+	if c.Query("include_fired") == "true" {
+		for _, fired := range caliber.FiresCalibers {
+			ids = append(ids, fired.ID)
+		}
+	}
+	return ids, nil
+}
+
+// respondToCaliberQueryError writes the response for a resolveCaliberQuery failure
+func respondToCaliberQueryError(c *gin.Context, err error) {
+	if errors.Is(err, errUnknownCaliber) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown caliber"})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load calibers"})
+}
+
+// @Summary     Get all calibers
+// @Description Get a list of all calibers, optionally only those matching a name or alias
+// @Tags        Calibers
+// @Accept      json
+// @Produce     json
+// @Param       q query string false "Case-insensitive caliber name or alias, e.g. 5.56 NATO"
+// @Success     200 {array} models.Caliber
+// @Failure     500 {object} map[string]string
+// @Router      /calibers [get]
+func GetCalibers(c *gin.Context) {
+	calibers := []models.Caliber{}
+	q := c.Query("q")
+	if q == "" {
+		db.DB.Preload("FiresCalibers").Order("id").Find(&calibers)
+		c.JSON(http.StatusOK, calibers)
+		return
+	}
+
+	index, err := loadCaliberIndex()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load calibers"})
+		return
+	}
+	if caliber, ok := index.lookup(q); ok {
+		calibers = append(calibers, caliber)
+	}
+	c.JSON(http.StatusOK, calibers)
+}
+
+// @Summary     Get a caliber by ID
+// @Description Get a caliber with its parent cartridge and the calibers its chamber can fire
+// @Tags        Calibers
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "Caliber ID"
+// @Success     200 {object} models.Caliber
+// @Failure     404 {object} map[string]string
+// @Router      /calibers/{id} [get]
+func GetCaliberByID(c *gin.Context) {
+	var caliber models.Caliber
+	if err := db.DB.Preload("ParentCaliber").Preload("FiresCalibers").First(&caliber, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Caliber not found"})
+		return
+	}
+	c.JSON(http.StatusOK, caliber)
+}
+
+// @Summary     Create a caliber
+// @Description Add a new caliber
+// @Tags        Calibers
+// @Accept      json
+// @Produce     json
+// @Param       caliber body CaliberInput true "Caliber Info"
+// @Success     201 {object} models.Caliber
+// @Failure     400 {object} map[string]string
+// @Failure     409 {object} map[string]string
+// @Failure     500 {object} map[string]string
+// @Router      /calibers [post]
+func CreateCaliber(c *gin.Context) {
+	var input CaliberInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var caliber models.Caliber
+	if !saveCaliber(c, &caliber, input) {
+		return
+	}
+	c.JSON(http.StatusCreated, caliber)
+}
+
+// @Summary     Update a caliber
+// @Description Update a caliber, replacing its aliases and the calibers its chamber can fire
+// @Tags        Calibers
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "Caliber ID"
+// @Param       caliber body CaliberInput true "Updated Caliber Info"
+// @Success     200 {object} models.Caliber
+// @Failure     400 {object} map[string]string
+// @Failure     404 {object} map[string]string
+// @Failure     409 {object} map[string]string
+// @Failure     500 {object} map[string]string
+// @Router      /calibers/{id} [put]
+func UpdateCaliber(c *gin.Context) {
+	var caliber models.Caliber
+	if err := db.DB.First(&caliber, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Caliber not found"})
+		return
+	}
+
+	var input CaliberInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if !saveCaliber(c, &caliber, input) {
+		return
+	}
+	c.JSON(http.StatusOK, caliber)
+}
+
+// @Summary     Delete a caliber
+// @Description Delete a caliber that no firearm model, prebuilt firearm, part or magazine variant references
+// @Tags        Calibers
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "Caliber ID"
+// @Success     204 "No Content"
+// @Failure     404 {object} map[string]string
+// @Failure     409 {object} map[string]string
+// @Router      /calibers/{id} [delete]
+func DeleteCaliber(c *gin.Context) {
+	var caliber models.Caliber
+	if err := db.DB.First(&caliber, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Caliber not found"})
+		return
+	}
+
+	references := []struct {
+		model  interface{}
+		column string
+	}{
+		{&models.FirearmModel{}, "caliber_id"},
+		{&models.PrebuiltFirearm{}, "caliber_id"},
+		{&models.Part{}, "caliber_id"},
+		{&models.Caliber{}, "parent_caliber_id"},
+		{&models.MagazineVariant{}, "caliber_id"},
+	}
+	for _, reference := range references {
+		var count int64
+		db.DB.Model(reference.model).Where(reference.column+" = ?", caliber.ID).Count(&count)
+		if count > 0 {
+			c.JSON(http.StatusConflict, gin.H{"error": "Caliber is still referenced and cannot be deleted"})
+			return
+		}
+	}
+
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM caliber_compatibilities WHERE chamber_caliber_id = ? OR ammo_caliber_id = ?", caliber.ID, caliber.ID).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM magazine_family_calibers WHERE caliber_id = ?", caliber.ID).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&caliber).Error; err != nil {
+			return err
+		}
+		return markCompatibilityIndexStale(tx)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete caliber"})
+		return
+	}
+	startCompatibilityRebuild()
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// saveCaliber applies the input to the caliber and stores it together with the calibers
+// its chamber fires, writing an error response and returning false on failure
+func saveCaliber(c *gin.Context, caliber *models.Caliber, input CaliberInput) bool {
+	index, err := loadCaliberIndex()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load calibers"})
+		return false
+	}
+
+	// Names and aliases must stay unambiguous so lookups resolve to a single caliber
+	for _, name := range append([]string{input.Name}, input.Aliases...) {
+		if id, taken := index.byName[normalizeCaliberName(name)]; taken && id != caliber.ID {
+			c.JSON(http.StatusConflict, gin.H{"error": "Caliber name or alias " + name + " is already used by " + index.byID[id].Name})
+			return false
+		}
+	}
+	if input.ParentCaliberID != nil {
+		if _, ok := index.byID[*input.ParentCaliberID]; !ok || *input.ParentCaliberID == caliber.ID {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid parent caliber"})
+			return false
+		}
+	}
+
+	fires := []models.Caliber{}
+	for _, id := range input.FiresCaliberIDs {
+		fired, ok := index.byID[id]
+		if !ok || id == caliber.ID {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid fired caliber " + strconv.Itoa(id)})
+			return false
+		}
+		fired.FiresCalibers = nil
+		fires = append(fires, fired)
+	}
+
+	if input.Aliases == nil {
+		input.Aliases = []string{}
+	}
+	aliasesJSON, _ := json.Marshal(input.Aliases)
+	caliber.Name = input.Name
+	caliber.Aliases = datatypes.JSON(aliasesJSON)
+	caliber.Type = input.Type
+	caliber.BulletDiameter = input.BulletDiameter
+	caliber.CaseLength = input.CaseLength
+	caliber.OverallLength = input.OverallLength
+	caliber.ParentCaliberID = input.ParentCaliberID
+
+	err = db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("ParentCaliber", "FiresCalibers").Save(caliber).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(caliber).Omit("FiresCalibers.*").Association("FiresCalibers").Replace(fires); err != nil {
+			return err
+		}
+		return markCompatibilityIndexStale(tx)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save caliber"})
+		return false
+	}
+	caliber.FiresCalibers = fires
+	startCompatibilityRebuild()
+	return true
+}
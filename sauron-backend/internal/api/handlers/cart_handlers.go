@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CartOptimizeInput is the request body for optimizing a cart
+type CartOptimizeInput struct {
+	// Parts to buy
+	PartIDs []int `json:"part_ids" binding:"required,min=1" example:"1,2,3"`
+
+	// Destination state; listings that cannot ship there are not used, e.g. CA or US-CA
+	ShipTo string `json:"ship_to" example:"CA"`
+}
+
+// @Summary     Optimize a cart
+// @Description Choose the sellers for a set of parts that minimize total landed cost. Shipping is charged once per seller, so consolidating parts at one seller can beat buying each part at its cheapest listing. Given a destination state, listings that cannot ship there are skipped and items that must ship to an FFL are flagged.
+// @Tags        Cart
+// @Accept      json
+// @Produce     json
+// @Param       cart body CartOptimizeInput true "Parts to buy"
+// @Success     200 {object} CartResult
+// @Failure     400 {object} map[string]string
+// @Failure     500 {object} map[string]string
+// @Router      /cart/optimize [post]
+func OptimizeCart(c *gin.Context) {
+	var input CartOptimizeInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	state, ok := shipToParam(c, input.ShipTo)
+	if !ok {
+		return
+	}
+
+	result, err := optimizeCart(input.PartIDs, state)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to optimize cart"})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// @Summary     Get optimized cart for a build
+// @Description Choose the sellers for a saved build's parts that minimize total landed cost, grouped by seller, optionally only from listings that can ship to a destination state
+// @Tags        Builds,Cart
+// @Accept      json
+// @Produce     json
+// @Param       id      path  int    true  "Build ID"
+// @Param       ship_to query string false "Destination state, e.g. CA or US-CA"
+// @Success     200 {object} CartResult
+// @Failure     400 {object} map[string]string
+// @Failure     404 {object} map[string]string
+// @Failure     500 {object} map[string]string
+// @Router      /builds/{id}/cart [get]
+func GetBuildCart(c *gin.Context) {
+	state, ok := shipToParam(c, c.Query("ship_to"))
+	if !ok {
+		return
+	}
+	build, err := loadBuild(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Build not found"})
+		return
+	}
+
+	partIDs := make([]int, 0, len(build.Parts))
+	for _, buildPart := range build.Parts {
+		partIDs = append(partIDs, buildPart.PartID)
+	}
+
+	result, err := optimizeCart(partIDs, state)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to optimize cart"})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,293 @@
+package handlers
+
+import (
+	"sauron-backend/internal/db"
+	"sauron-backend/internal/models"
+	"sort"
+)
+
+// cartSearchLimit caps the number of assignments the optimizer explores before it
+// settles for the best cart found so far
+const cartSearchLimit = 250000
+
+// CartItem is a single part in a seller's cart
+type CartItem struct {
+	PartID    int     `json:"part_id" example:"5"`
+	PartName  string  `json:"part_name" example:"Complete BCG (AR-15)"`
+	ListingID int     `json:"listing_id" example:"42"`
+	SKU       string  `json:"sku" example:"BRO-1234"`
+	URL       string  `json:"url" example:"https://www.brownells.com/product/complete-bcg-ar-15"`
+	Price     float64 `json:"price" example:"149.99"`
+
+	// Whether the listing only ships to a licensed dealer (FFL) in the destination state, and why
+	FFLRequired   bool     `json:"ffl_required,omitempty" example:"false"`
+	ShippingNotes []string `json:"shipping_notes,omitempty" example:"Receivers ship to an FFL only"`
+}
+
+// SellerCart groups the parts bought from one seller
+type SellerCart struct {
+	SellerID   int        `json:"seller_id" example:"1"`
+	SellerName string     `json:"seller_name" example:"Brownells"`
+	Items      []CartItem `json:"items"`
+
+	// Sum of item prices, the single shipping charge for the order, and their total
+	Subtotal float64 `json:"subtotal" example:"489.97"`
+	Shipping float64 `json:"shipping" example:"12.99"`
+	Total    float64 `json:"total" example:"502.96"`
+}
+
+// CartResult is the cheapest landed-cost assignment of parts to sellers
+type CartResult struct {
+	// Destination state the listings were limited to, if any
+	ShipTo string `json:"ship_to,omitempty" example:"CA"`
+
+	Currency string       `json:"currency" example:"USD"`
+	Sellers  []SellerCart `json:"sellers"`
+
+	PartsTotal    float64 `json:"parts_total" example:"1149.50"`
+	ShippingTotal float64 `json:"shipping_total" example:"25.98"`
+	Total         float64 `json:"total" example:"1175.48"`
+
+	// Total when every part is bought from its cheapest listing and shipped separately
+	NaiveTotal float64 `json:"naive_total" example:"1203.41"`
+	Savings    float64 `json:"savings" example:"27.93"`
+
+	// Parts with no in-stock listing, which are left out of the cart
+	UnavailablePartIDs []int `json:"unavailable_part_ids"`
+
+	// Unavailable parts whose in-stock listings cannot ship to the destination
+	RestrictedPartIDs []int `json:"restricted_part_ids"`
+
+	// False when the search limit was reached before the best cart was proven optimal
+	Optimal bool `json:"optimal" example:"true"`
+}
+
+// cartSolver searches seller assignments for a set of parts. Shipping is charged once
+// per seller, at the highest shipping cost among the non-free listings bought there.
+type cartSolver struct {
+	options  [][]models.ProductListing
+	minTail  []float64
+	shipping map[int]float64
+	current  []int
+	best     []int
+	bestCost float64
+	nodes    int
+	limit    int
+	complete bool
+}
+
+// newCartSolver prepares a solver for the given listing options, one slice per part
+func newCartSolver(options [][]models.ProductListing, limit int) *cartSolver {
+	s := &cartSolver{
+		options:  options,
+		minTail:  make([]float64, len(options)+1),
+		shipping: make(map[int]float64),
+		current:  make([]int, len(options)),
+		limit:    limit,
+		complete: true,
+	}
+
+	// Lower bound on what the remaining parts cost, ignoring shipping
+	for i := len(options) - 1; i >= 0; i-- {
+		cheapest := options[i][0].Price
+		for _, listing := range options[i] {
+			if listing.Price < cheapest {
+				cheapest = listing.Price
+			}
+		}
+		s.minTail[i] = s.minTail[i+1] + cheapest
+	}
+	return s
+}
+
+// cartCost returns the landed cost of buying listings[i][choice[i]] for every part
+func cartCost(options [][]models.ProductListing, choice []int) float64 {
+	total := 0.0
+	shipping := make(map[int]float64)
+	for i, index := range choice {
+		listing := options[i][index]
+		total += listing.Price
+		if ship := listingShipping(listing); ship > shipping[listing.SellerID] {
+			shipping[listing.SellerID] = ship
+		}
+	}
+	for _, ship := range shipping {
+		total += ship
+	}
+	return total
+}
+
+// solve runs a depth-first branch and bound search, starting from the cart that buys
+// each part at its cheapest landed price
+func (s *cartSolver) solve() []int {
+	s.best = make([]int, len(s.options))
+	s.bestCost = cartCost(s.options, s.best)
+	s.search(0, 0)
+	return s.best
+}
+
+func (s *cartSolver) search(depth int, cost float64) {
+	if cost+s.minTail[depth] >= s.bestCost {
+		return
+	}
+	if depth == len(s.options) {
+		s.bestCost = cost
+		copy(s.best, s.current)
+		return
+	}
+
+	for index, listing := range s.options[depth] {
+		if s.nodes >= s.limit {
+			s.complete = false
+			return
+		}
+		s.nodes++
+
+		previous := s.shipping[listing.SellerID]
+		ship := listingShipping(listing)
+		if ship < previous {
+			ship = previous
+		}
+
+		s.shipping[listing.SellerID] = ship
+		s.current[depth] = index
+		s.search(depth+1, cost+listing.Price+ship-previous)
+		s.shipping[listing.SellerID] = previous
+	}
+}
+
+// optimizeCart finds the seller assignment with the lowest landed cost for a set of parts,
+// using only listings that can ship to the state when one is given
+func optimizeCart(partIDs []int, state string) (*CartResult, error) {
+	partIDs = uniqueInts(partIDs)
+
+	partByID := make(map[int]models.Part)
+	if len(partIDs) > 0 {
+		var parts []models.Part
+		if err := db.DB.Where("id IN ?", partIDs).Find(&parts).Error; err != nil {
+			return nil, err
+		}
+		for _, part := range parts {
+			partByID[part.ID] = part
+		}
+	}
+
+	shippable, err := loadShippableListingsByPart(partIDs, state)
+	if err != nil {
+		return nil, err
+	}
+
+	result := buildCart(partIDs, partByID, shippable.byPart)
+	result.ShipTo = state
+	result.RestrictedPartIDs = shippable.restrictedPartIDs()
+	for i := range result.Sellers {
+		items := result.Sellers[i].Items
+		for j := range items {
+			if status := shippable.status[items[j].ListingID]; status.FFLRequired {
+				items[j].FFLRequired = true
+				items[j].ShippingNotes = status.Reasons
+			}
+		}
+	}
+	return result, nil
+}
+
+// buildCart runs the solver over the available listings and groups the chosen
+// listings into per-seller carts
+func buildCart(partIDs []int, partByID map[int]models.Part, listingsByPart map[int][]models.ProductListing) *CartResult {
+	result := &CartResult{
+		Currency:           "USD",
+		Sellers:            []SellerCart{},
+		UnavailablePartIDs: []int{},
+		RestrictedPartIDs:  []int{},
+		Optimal:            true,
+	}
+
+	// Most constrained parts first so the search prunes early
+	var available []int
+	for _, partID := range partIDs {
+		if len(listingsByPart[partID]) == 0 {
+			result.UnavailablePartIDs = append(result.UnavailablePartIDs, partID)
+			continue
+		}
+		available = append(available, partID)
+	}
+	sort.SliceStable(available, func(i, j int) bool {
+		return len(listingsByPart[available[i]]) < len(listingsByPart[available[j]])
+	})
+
+	if len(available) == 0 {
+		return result
+	}
+
+	options := make([][]models.ProductListing, len(available))
+	for i, partID := range available {
+		options[i] = listingsByPart[partID]
+		result.NaiveTotal += landedPrice(options[i][0])
+	}
+
+	solver := newCartSolver(options, cartSearchLimit)
+	choice := solver.solve()
+	result.Optimal = solver.complete
+
+	cartBySeller := make(map[int]*SellerCart)
+	var sellerOrder []int
+	for i, index := range choice {
+		listing := options[i][index]
+		cart, ok := cartBySeller[listing.SellerID]
+		if !ok {
+			cart = &SellerCart{SellerID: listing.SellerID, SellerName: listing.Seller.Name, Items: []CartItem{}}
+			cartBySeller[listing.SellerID] = cart
+			sellerOrder = append(sellerOrder, listing.SellerID)
+		}
+
+		cart.Items = append(cart.Items, CartItem{
+			PartID:    available[i],
+			PartName:  partByID[available[i]].Name,
+			ListingID: listing.ID,
+			SKU:       listing.SKU,
+			URL:       listing.URL,
+			Price:     roundCents(listing.Price),
+		})
+		cart.Subtotal += listing.Price
+		if ship := listingShipping(listing); ship > cart.Shipping {
+			cart.Shipping = ship
+		}
+		if listing.Currency != "" {
+			result.Currency = listing.Currency
+		}
+	}
+
+	sort.Ints(sellerOrder)
+	for _, sellerID := range sellerOrder {
+		cart := cartBySeller[sellerID]
+		sort.Slice(cart.Items, func(i, j int) bool { return cart.Items[i].PartID < cart.Items[j].PartID })
+		cart.Subtotal = roundCents(cart.Subtotal)
+		cart.Shipping = roundCents(cart.Shipping)
+		cart.Total = roundCents(cart.Subtotal + cart.Shipping)
+
+		result.PartsTotal += cart.Subtotal
+		result.ShippingTotal += cart.Shipping
+		result.Sellers = append(result.Sellers, *cart)
+	}
+
+	result.PartsTotal = roundCents(result.PartsTotal)
+	result.ShippingTotal = roundCents(result.ShippingTotal)
+	result.Total = roundCents(result.PartsTotal + result.ShippingTotal)
+	result.NaiveTotal = roundCents(result.NaiveTotal)
+	result.Savings = roundCents(result.NaiveTotal - result.Total)
+	return result
+}
+
+// uniqueInts returns the distinct values of a slice in their original order
+func uniqueInts(values []int) []int {
+	seen := make(map[int]bool, len(values))
+	result := make([]int, 0, len(values))
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			result = append(result, value)
+		}
+	}
+	return result
+}
@@ -0,0 +1,441 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"sauron-backend/internal/db"
+	"sauron-backend/internal/models"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Overall verdicts for a pair of parts
+const (
+	VerdictCompatible   = "compatible"
+	VerdictIncompatible = "incompatible"
+
+	// No rule failed, but at least one could not be evaluated for lack of attributes
+	VerdictUnverified = "unverified"
+)
+
+// ModelLinkage explains whether a firearm model uses both parts' categories
+type ModelLinkage struct {
+	Result string `json:"result" example:"pass" enums:"pass,fail"`
+
+	// Firearm models that use part A's category and part B's category
+	FirearmModelIDs   []int    `json:"firearm_model_ids"`
+	FirearmModelNames []string `json:"firearm_model_names"`
+
+	Message string `json:"message" example:"Both categories are used by AR-15"`
+}
+
+// FitmentCheck explains whether the firearm models two parts are declared to fit overlap
+type FitmentCheck struct {
+	Result string `json:"result" example:"fail" enums:"pass,fail"`
+
+	// Firearm models both parts are declared to fit
+	FirearmModelIDs   []int    `json:"firearm_model_ids"`
+	FirearmModelNames []string `json:"firearm_model_names"`
+
+	Message string `json:"message" example:"Glock 19 Slide is declared to fit Glock 19, but Glock 17 Frame is declared to fit Glock 17"`
+}
+
+// CompatibilityPart identifies a part in a compatibility verdict
+type CompatibilityPart struct {
+	ID             int    `json:"id" example:"5"`
+	Name           string `json:"name" example:"16\" 5.56 NATO Barrel (AR-15)"`
+	PartCategoryID *int   `json:"part_category_id,omitempty" example:"10"`
+	CategoryName   string `json:"category_name,omitempty" example:"Barrel"`
+}
+
+// CompatibilityVerdict explains why two parts are or are not compatible
+type CompatibilityVerdict struct {
+	PartA CompatibilityPart `json:"part_a"`
+	PartB CompatibilityPart `json:"part_b"`
+
+	// Whether part B would be listed by GET /parts/{part_a}/compatible; for sets of
+	// parts, whether neither declared fitments nor an attribute rule failed
+	Compatible bool   `json:"compatible" example:"false"`
+	Verdict    string `json:"verdict" example:"incompatible" enums:"compatible,incompatible,unverified"`
+
+	// Firearm model linkage between the two parts' categories
+	ModelLinkage ModelLinkage `json:"model_linkage"`
+
+	// Overlap of the firearm models the parts are declared to fit, present when both declare fitments
+	Fitment *FitmentCheck `json:"fitment,omitempty"`
+
+	// Every attribute rule that applied to the pair and its outcome
+	Rules []RuleCheck `json:"rules"`
+}
+
+// BuildCompatibilityInput is the request body for checking an unsaved set of parts
+type BuildCompatibilityInput struct {
+	PartIDs []int `json:"part_ids" binding:"required,min=2" example:"5,12,31"`
+}
+
+// BuildCompatibilityResult is the pairwise compatibility of a set of parts
+type BuildCompatibilityResult struct {
+	// Build that was checked, omitted for unsaved part sets
+	BuildID int `json:"build_id,omitempty" example:"1"`
+
+	// Whether no pair of parts is incompatible
+	Compatible bool `json:"compatible" example:"false"`
+
+	IncompatiblePairs int `json:"incompatible_pairs" example:"1"`
+	UnverifiedPairs   int `json:"unverified_pairs" example:"2"`
+
+	// Pairs that are incompatible or had a rule or declared fitments apply; every pair with ?all=true
+	Pairs []CompatibilityVerdict `json:"pairs"`
+}
+
+// compatibilityExplainer holds everything needed to explain compatibility verdicts
+type compatibilityExplainer struct {
+	engine *compatibilityEngine
+
+	// Firearm model IDs using each part category, and the categories each model uses
+	modelsByCategory  map[int][]int
+	categoriesByModel map[int]map[int]bool
+	modelNames        map[int]string
+
+	// Firearm models each part is declared to fit, for parts that declare any
+	fitsByPart map[int]map[int]bool
+}
+
+// loadCompatibilityExplainer loads the rules and firearm model category links
+func loadCompatibilityExplainer() (*compatibilityExplainer, error) {
+	engine, err := loadCompatibilityEngine()
+	if err != nil {
+		return nil, err
+	}
+
+	var relations []models.FirearmModelPartCategory
+	if err := db.DB.Order("firearm_model_id").Find(&relations).Error; err != nil {
+		return nil, err
+	}
+	var firearmModels []models.FirearmModel
+	if err := db.DB.Select("id", "name").Find(&firearmModels).Error; err != nil {
+		return nil, err
+	}
+	var fitments []models.PartFirearmModel
+	if err := db.DB.Find(&fitments).Error; err != nil {
+		return nil, err
+	}
+
+	explainer := &compatibilityExplainer{
+		engine:            engine,
+		modelsByCategory:  make(map[int][]int),
+		categoriesByModel: make(map[int]map[int]bool),
+		modelNames:        make(map[int]string, len(firearmModels)),
+		fitsByPart:        make(map[int]map[int]bool),
+	}
+	for _, relation := range relations {
+		explainer.modelsByCategory[relation.PartCategoryID] = append(explainer.modelsByCategory[relation.PartCategoryID], relation.FirearmModelID)
+		if explainer.categoriesByModel[relation.FirearmModelID] == nil {
+			explainer.categoriesByModel[relation.FirearmModelID] = make(map[int]bool)
+		}
+		explainer.categoriesByModel[relation.FirearmModelID][relation.PartCategoryID] = true
+	}
+	for _, model := range firearmModels {
+		explainer.modelNames[model.ID] = model.Name
+	}
+	for _, fitment := range fitments {
+		if explainer.fitsByPart[fitment.PartID] == nil {
+			explainer.fitsByPart[fitment.PartID] = make(map[int]bool)
+		}
+		explainer.fitsByPart[fitment.PartID][fitment.FirearmModelID] = true
+	}
+	return explainer, nil
+}
+
+// explain produces the verdict for part B from part A's point of view, applying the
+// same model linkage, declared fitments and rules as GetCompatibleParts
+func (e *compatibilityExplainer) explain(a, b models.Part) CompatibilityVerdict {
+	verdict := CompatibilityVerdict{
+		PartA:        e.describe(a),
+		PartB:        e.describe(b),
+		ModelLinkage: e.linkage(a, b),
+		Fitment:      e.fitment(a, b),
+	}
+
+	rulesPass, checks := e.engine.check(a, b)
+	verdict.Rules = checks
+	verdict.Compatible = verdict.ModelLinkage.Result == RuleResultPass && !fitmentFails(verdict.Fitment) && rulesPass
+
+	switch {
+	case !verdict.Compatible:
+		verdict.Verdict = VerdictIncompatible
+	case hasUnknownCheck(checks):
+		verdict.Verdict = VerdictUnverified
+	default:
+		verdict.Verdict = VerdictCompatible
+	}
+	return verdict
+}
+
+// linkage checks that part B's category is used by a firearm model that uses part A's
+// category. When no model uses part A's category, only parts in that same category link.
+func (e *compatibilityExplainer) linkage(a, b models.Part) ModelLinkage {
+	result := ModelLinkage{Result: RuleResultFail, FirearmModelIDs: []int{}, FirearmModelNames: []string{}}
+
+	if a.PartCategoryID == nil {
+		result.Message = fmt.Sprintf("Part %s has no part category, so compatibility cannot be determined", a.Name)
+		return result
+	}
+	if b.PartCategoryID == nil {
+		result.Message = fmt.Sprintf("Part %s has no part category", b.Name)
+		return result
+	}
+
+	categoryA, categoryB := *a.PartCategoryID, *b.PartCategoryID
+	modelIDs := e.modelsByCategory[categoryA]
+	if len(modelIDs) == 0 {
+		if categoryA == categoryB {
+			result.Result = RuleResultPass
+			result.Message = fmt.Sprintf("No firearm model uses category %s; both parts are in that category", e.categoryName(categoryA))
+		} else {
+			result.Message = fmt.Sprintf("No firearm model uses category %s, and %s is in a different category", e.categoryName(categoryA), b.Name)
+		}
+		return result
+	}
+
+	for _, modelID := range modelIDs {
+		if e.categoriesByModel[modelID][categoryB] {
+			result.FirearmModelIDs = append(result.FirearmModelIDs, modelID)
+			result.FirearmModelNames = append(result.FirearmModelNames, e.modelNames[modelID])
+		}
+	}
+	if len(result.FirearmModelIDs) == 0 {
+		result.Message = fmt.Sprintf("No firearm model that uses category %s also uses category %s", e.categoryName(categoryA), e.categoryName(categoryB))
+		return result
+	}
+
+	result.Result = RuleResultPass
+	result.Message = fmt.Sprintf("Categories %s and %s are both used by %s", e.categoryName(categoryA), e.categoryName(categoryB), strings.Join(result.FirearmModelNames, ", "))
+	return result
+}
+
+// fitment compares the firearm models both parts are declared to fit. It returns nil when
+// either part declares none, since such parts fit wherever their category does.
+func (e *compatibilityExplainer) fitment(a, b models.Part) *FitmentCheck {
+	fitsA, fitsB := e.fitsByPart[a.ID], e.fitsByPart[b.ID]
+	if fitsA == nil || fitsB == nil {
+		return nil
+	}
+
+	result := &FitmentCheck{Result: RuleResultFail, FirearmModelIDs: []int{}, FirearmModelNames: []string{}}
+	for _, modelID := range sortedModelIDs(fitsA) {
+		if fitsB[modelID] {
+			result.FirearmModelIDs = append(result.FirearmModelIDs, modelID)
+			result.FirearmModelNames = append(result.FirearmModelNames, e.modelNames[modelID])
+		}
+	}
+	if len(result.FirearmModelIDs) == 0 {
+		result.Message = fmt.Sprintf("%s is declared to fit %s, but %s is declared to fit %s", a.Name, e.modelList(fitsA), b.Name, e.modelList(fitsB))
+		return result
+	}
+
+	result.Result = RuleResultPass
+	result.Message = fmt.Sprintf("Both parts are declared to fit %s", strings.Join(result.FirearmModelNames, ", "))
+	return result
+}
+
+func (e *compatibilityExplainer) modelList(modelIDs map[int]bool) string {
+	names := make([]string, 0, len(modelIDs))
+	for _, modelID := range sortedModelIDs(modelIDs) {
+		names = append(names, e.modelNames[modelID])
+	}
+	return strings.Join(names, ", ")
+}
+
+func sortedModelIDs(modelIDs map[int]bool) []int {
+	ids := make([]int, 0, len(modelIDs))
+	for id := range modelIDs {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+func fitmentFails(fitment *FitmentCheck) bool {
+	return fitment != nil && fitment.Result == RuleResultFail
+}
+
+func (e *compatibilityExplainer) describe(part models.Part) CompatibilityPart {
+	described := CompatibilityPart{ID: part.ID, Name: part.Name, PartCategoryID: part.PartCategoryID}
+	if part.PartCategoryID != nil {
+		described.CategoryName = e.categoryName(*part.PartCategoryID)
+	}
+	return described
+}
+
+func (e *compatibilityExplainer) categoryName(categoryID int) string {
+	if category, ok := e.engine.tree[categoryID]; ok {
+		return category.Name
+	}
+	return strconv.Itoa(categoryID)
+}
+
+// @Summary     Explain compatibility of two parts
+// @Description Return a verdict for part_b from part_a's point of view with the reasons behind it: the firearm model linkage between their categories, the overlap of their declared fitments and every attribute rule that applied
+// @Tags        Compatibility
+// @Accept      json
+// @Produce     json
+// @Param       part_a query int true "Part ID to check from"
+// @Param       part_b query int true "Part ID to check against"
+// @Success     200 {object} CompatibilityVerdict
+// @Failure     400 {object} map[string]string
+// @Failure     404 {object} map[string]string
+// @Failure     500 {object} map[string]string
+// @Router      /compatibility/check [get]
+func CheckCompatibility(c *gin.Context) {
+	partAID, err := strconv.Atoi(c.Query("part_a"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid part_a ID"})
+		return
+	}
+	partBID, err := strconv.Atoi(c.Query("part_b"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid part_b ID"})
+		return
+	}
+
+	var partA, partB models.Part
+	if err := db.DB.First(&partA, partAID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("Part %d not found", partAID)})
+		return
+	}
+	if err := db.DB.First(&partB, partBID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("Part %d not found", partBID)})
+		return
+	}
+
+	explainer, err := loadCompatibilityExplainer()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load compatibility data"})
+		return
+	}
+
+	c.JSON(http.StatusOK, explainer.explain(partA, partB))
+}
+
+// @Summary     Check compatibility of a set of parts
+// @Description Check every pair in a set of parts against declared fitments, the attribute compatibility rules and firearm model linkage
+// @Tags        Compatibility
+// @Accept      json
+// @Produce     json
+// @Param       parts body BuildCompatibilityInput true "Parts to check"
+// @Param       all query bool false "Include every pair, not only incompatible pairs and pairs a rule applied to"
+// @Success     200 {object} BuildCompatibilityResult
+// @Failure     400 {object} map[string]string
+// @Failure     404 {object} map[string]string
+// @Failure     500 {object} map[string]string
+// @Router      /compatibility/check [post]
+func CheckPartsCompatibility(c *gin.Context) {
+	var input BuildCompatibilityInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	partIDs := uniqueInts(input.PartIDs)
+	var parts []models.Part
+	if err := db.DB.Where("id IN ?", partIDs).Find(&parts).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load parts"})
+		return
+	}
+	if len(parts) != len(partIDs) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "One or more parts not found"})
+		return
+	}
+
+	result, err := checkPartSetCompatibility(parts, c.Query("all") == "true")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load compatibility data"})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// @Summary     Check compatibility of a build
+// @Description Check every pair of parts in a saved build against declared fitments, the attribute compatibility rules and firearm model linkage
+// @Tags        Builds,Compatibility
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "Build ID"
+// @Param       all query bool false "Include every pair, not only incompatible pairs and pairs a rule applied to"
+// @Success     200 {object} BuildCompatibilityResult
+// @Failure     404 {object} map[string]string
+// @Failure     500 {object} map[string]string
+// @Router      /builds/{id}/compatibility [get]
+func GetBuildCompatibility(c *gin.Context) {
+	build, err := loadBuild(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Build not found"})
+		return
+	}
+
+	parts := make([]models.Part, 0, len(build.Parts))
+	seen := make(map[int]bool)
+	for _, buildPart := range build.Parts {
+		if !seen[buildPart.PartID] {
+			seen[buildPart.PartID] = true
+			parts = append(parts, buildPart.Part)
+		}
+	}
+
+	result, err := checkPartSetCompatibility(parts, c.Query("all") == "true")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load compatibility data"})
+		return
+	}
+	result.BuildID = build.ID
+
+	c.JSON(http.StatusOK, result)
+}
+
+// checkPartSetCompatibility explains every unordered pair of parts. Parts in a set are
+// meant to go together, so only declared fitments and the attribute rules decide each
+// pair's verdict; the model linkage is still reported for each pair.
+func checkPartSetCompatibility(parts []models.Part, includeAll bool) (*BuildCompatibilityResult, error) {
+	explainer, err := loadCompatibilityExplainer()
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(parts, func(i, j int) bool { return parts[i].ID < parts[j].ID })
+
+	result := &BuildCompatibilityResult{Compatible: true, Pairs: []CompatibilityVerdict{}}
+	for i := range parts {
+		for j := i + 1; j < len(parts); j++ {
+			verdict := explainer.explain(parts[i], parts[j])
+
+			failed := fitmentFails(verdict.Fitment)
+			for _, check := range verdict.Rules {
+				if check.Result == RuleResultFail {
+					failed = true
+				}
+			}
+			verdict.Compatible = !failed
+			switch {
+			case failed:
+				verdict.Verdict = VerdictIncompatible
+				result.Compatible = false
+				result.IncompatiblePairs++
+			case hasUnknownCheck(verdict.Rules):
+				verdict.Verdict = VerdictUnverified
+				result.UnverifiedPairs++
+			default:
+				verdict.Verdict = VerdictCompatible
+			}
+
+			if includeAll || failed || len(verdict.Rules) > 0 || verdict.Fitment != nil {
+				result.Pairs = append(result.Pairs, verdict)
+			}
+		}
+	}
+	return result, nil
+}
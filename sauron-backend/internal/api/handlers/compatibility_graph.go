@@ -0,0 +1,468 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"sauron-backend/internal/db"
+	"sauron-backend/internal/models"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// Formats the compatibility graph can be exported in
+const (
+	GraphFormatDOT     = "dot"
+	GraphFormatGraphML = "graphml"
+)
+
+var (
+	// ErrUnsupportedGraphFormat is returned for an export format other than dot or graphml
+	ErrUnsupportedGraphFormat = errors.New("unsupported graph format, use dot or graphml")
+
+	// ErrGraphModelNotFound is returned when filtering the graph by a firearm model that does not exist
+	ErrGraphModelNotFound = errors.New("firearm model not found")
+)
+
+// GraphExportOptions selects what ExportCompatibilityGraph includes
+type GraphExportOptions struct {
+	// dot or graphml
+	Format string
+
+	// Only this firearm model, its part categories and the parts that fit it; 0 for everything
+	FirearmModelID int
+
+	// Whether to include individual parts, or only firearm models and categories
+	IncludeParts bool
+}
+
+// graphAttr is a node or edge attribute; values are strings, ints or bools
+type graphAttr struct {
+	key   string
+	value interface{}
+}
+
+type graphNode struct {
+	id    string
+	kind  string
+	label string
+	attrs []graphAttr
+}
+
+type graphEdge struct {
+	source, target string
+	relation       string
+	directed       bool
+	attrs          []graphAttr
+}
+
+// compatibilityGraph is the taxonomy and compatibility graph ready to be rendered
+type compatibilityGraph struct {
+	nodes []graphNode
+	edges []graphEdge
+}
+
+func modelNodeID(id int) string    { return "model:" + strconv.Itoa(id) }
+func categoryNodeID(id int) string { return "category:" + strconv.Itoa(id) }
+func partNodeID(id int) string     { return "part:" + strconv.Itoa(id) }
+
+// ExportCompatibilityGraph renders firearm models, part categories and optionally parts as a
+// graph. Edges are the categories each model uses (uses), the category hierarchy
+// (subcategory_of), category membership (in_category), declared fitments (fits), compatible
+// part pairs from the compatibility index (compatible) and those pairs rolled up per pair
+// of categories (category_compatible). Category nodes carry part, model and compatible
+// category counts and an orphaned flag so unused or over-connected categories stand out.
+func ExportCompatibilityGraph(options GraphExportOptions) ([]byte, error) {
+	if options.Format != GraphFormatDOT && options.Format != GraphFormatGraphML {
+		return nil, ErrUnsupportedGraphFormat
+	}
+
+	graph, err := buildCompatibilityGraph(options)
+	if err != nil {
+		return nil, err
+	}
+	if options.Format == GraphFormatGraphML {
+		return graph.graphML()
+	}
+	return graph.dot(), nil
+}
+
+func buildCompatibilityGraph(options GraphExportOptions) (*compatibilityGraph, error) {
+	tree, err := loadCategoryTree()
+	if err != nil {
+		return nil, err
+	}
+
+	var firearmModels []models.FirearmModel
+	modelQuery := db.DB.Select("id", "name").Order("id")
+	if options.FirearmModelID != 0 {
+		modelQuery = modelQuery.Where("id = ?", options.FirearmModelID)
+	}
+	if err := modelQuery.Find(&firearmModels).Error; err != nil {
+		return nil, err
+	}
+	if options.FirearmModelID != 0 && len(firearmModels) == 0 {
+		return nil, ErrGraphModelNotFound
+	}
+
+	var relations []models.FirearmModelPartCategory
+	relationQuery := db.DB.Order("firearm_model_id, part_category_id")
+	if options.FirearmModelID != 0 {
+		relationQuery = relationQuery.Where("firearm_model_id = ?", options.FirearmModelID)
+	}
+	if err := relationQuery.Find(&relations).Error; err != nil {
+		return nil, err
+	}
+
+	// With a model filter, keep the model's categories and their ancestors for the hierarchy
+	included := make(map[int]bool)
+	for id := range tree {
+		included[id] = options.FirearmModelID == 0
+	}
+	modelCount := make(map[int]int)
+	for _, relation := range relations {
+		modelCount[relation.PartCategoryID]++
+		included[relation.PartCategoryID] = true
+		for _, ancestorID := range tree.ancestors(relation.PartCategoryID) {
+			included[ancestorID] = true
+		}
+	}
+
+	parts := graphParts(options.FirearmModelID)
+
+	type categoryCount struct {
+		CategoryID int
+		Count      int
+	}
+	var partCounts []categoryCount
+	if err := parts.Session(&gorm.Session{}).Select("part_category_id AS category_id, COUNT(*) AS count").Group("part_category_id").Scan(&partCounts).Error; err != nil {
+		return nil, err
+	}
+	partCount := make(map[int]int, len(partCounts))
+	for _, count := range partCounts {
+		partCount[count.CategoryID] = count.Count
+		// A part declared to fit the model may sit in a category the model does not use
+		included[count.CategoryID] = true
+		for _, ancestorID := range tree.ancestors(count.CategoryID) {
+			included[ancestorID] = true
+		}
+	}
+
+	// Compatible part pairs, each stored once from the lower part ID, rolled up by category
+	type categoryPair struct {
+		SourceID int
+		TargetID int
+		Pairs    int
+	}
+	var categoryPairs []categoryPair
+	err = db.DB.Table("part_compatibilities").
+		Select("a.part_category_id AS source_id, b.part_category_id AS target_id, COUNT(*) AS pairs").
+		Joins("JOIN parts a ON a.id = part_compatibilities.part_id").
+		Joins("JOIN parts b ON b.id = part_compatibilities.compatible_part_id").
+		Where("part_compatibilities.part_id < part_compatibilities.compatible_part_id").
+		Where("part_compatibilities.part_id IN (?)", parts.Session(&gorm.Session{}).Select("id")).
+		Where("part_compatibilities.compatible_part_id IN (?)", parts.Session(&gorm.Session{}).Select("id")).
+		Group("a.part_category_id, b.part_category_id").
+		Scan(&categoryPairs).Error
+	if err != nil {
+		return nil, err
+	}
+	pairsByCategories := make(map[[2]int]int)
+	compatibleCategories := make(map[int]map[int]bool)
+	for _, pair := range categoryPairs {
+		key := [2]int{pair.SourceID, pair.TargetID}
+		if key[0] > key[1] {
+			key[0], key[1] = key[1], key[0]
+		}
+		pairsByCategories[key] += pair.Pairs
+		for _, ends := range [][2]int{key, {key[1], key[0]}} {
+			if compatibleCategories[ends[0]] == nil {
+				compatibleCategories[ends[0]] = make(map[int]bool)
+			}
+			compatibleCategories[ends[0]][ends[1]] = true
+		}
+	}
+
+	graph := &compatibilityGraph{}
+	for _, model := range firearmModels {
+		graph.nodes = append(graph.nodes, graphNode{id: modelNodeID(model.ID), kind: "firearm_model", label: model.Name})
+	}
+
+	categoryIDs := make([]int, 0, len(tree))
+	for id := range tree {
+		if included[id] {
+			categoryIDs = append(categoryIDs, id)
+		}
+	}
+	sort.Ints(categoryIDs)
+	for _, id := range categoryIDs {
+		graph.nodes = append(graph.nodes, graphNode{
+			id:    categoryNodeID(id),
+			kind:  "part_category",
+			label: tree[id].Name,
+			attrs: []graphAttr{
+				{"path", tree.path(id)},
+				{"part_count", partCount[id]},
+				{"model_count", modelCount[id]},
+				{"compatible_categories", len(compatibleCategories[id])},
+				{"orphaned", isOrphanedCategory(tree, id, modelCount)},
+			},
+		})
+		if parentID := parentCategoryID(tree[id]); parentID != 0 && included[parentID] {
+			graph.edges = append(graph.edges, graphEdge{source: categoryNodeID(id), target: categoryNodeID(parentID), relation: "subcategory_of", directed: true})
+		}
+	}
+
+	for _, relation := range relations {
+		graph.edges = append(graph.edges, graphEdge{
+			source:   modelNodeID(relation.FirearmModelID),
+			target:   categoryNodeID(relation.PartCategoryID),
+			relation: "uses",
+			directed: true,
+			attrs:    []graphAttr{{"required", relation.IsRequired}},
+		})
+	}
+
+	pairKeys := make([][2]int, 0, len(pairsByCategories))
+	for key := range pairsByCategories {
+		pairKeys = append(pairKeys, key)
+	}
+	sort.Slice(pairKeys, func(i, j int) bool {
+		if pairKeys[i][0] != pairKeys[j][0] {
+			return pairKeys[i][0] < pairKeys[j][0]
+		}
+		return pairKeys[i][1] < pairKeys[j][1]
+	})
+	for _, key := range pairKeys {
+		graph.edges = append(graph.edges, graphEdge{
+			source:   categoryNodeID(key[0]),
+			target:   categoryNodeID(key[1]),
+			relation: "category_compatible",
+			attrs:    []graphAttr{{"pairs", pairsByCategories[key]}},
+		})
+	}
+
+	if options.IncludeParts {
+		if err := graph.addParts(parts, options.FirearmModelID); err != nil {
+			return nil, err
+		}
+	}
+	return graph, nil
+}
+
+// graphParts selects the categorised parts in the graph: all of them, or with a firearm model
+// those in its categories that do not declare fitments excluding it, plus any declared to fit it
+func graphParts(firearmModelID int) *gorm.DB {
+	query := db.DB.Model(&models.Part{}).Where("part_category_id IS NOT NULL")
+	if firearmModelID == 0 {
+		return query
+	}
+
+	fitted := db.DB.Model(&models.PartFirearmModel{}).Select("part_id").Where("firearm_model_id = ?", firearmModelID)
+	declared := db.DB.Model(&models.PartFirearmModel{}).Select("part_id")
+	modelCategories := db.DB.Model(&models.FirearmModelPartCategory{}).Select("part_category_id").Where("firearm_model_id = ?", firearmModelID)
+	return query.Where(
+		db.DB.Where("part_category_id IN (?) AND id NOT IN (?)", modelCategories, declared).
+			Or("id IN (?)", fitted),
+	)
+}
+
+// addParts adds part nodes with their category membership, declared fitments and compatible pairs
+func (g *compatibilityGraph) addParts(parts *gorm.DB, firearmModelID int) error {
+	var partRows []models.Part
+	if err := parts.Session(&gorm.Session{}).Select("id", "name", "part_category_id").Order("id").Find(&partRows).Error; err != nil {
+		return err
+	}
+	for _, part := range partRows {
+		g.nodes = append(g.nodes, graphNode{id: partNodeID(part.ID), kind: "part", label: part.Name})
+		g.edges = append(g.edges, graphEdge{source: partNodeID(part.ID), target: categoryNodeID(*part.PartCategoryID), relation: "in_category", directed: true})
+	}
+
+	var fitments []models.PartFirearmModel
+	fitmentQuery := db.DB.Where("part_id IN (?)", parts.Session(&gorm.Session{}).Select("id")).Order("part_id, firearm_model_id")
+	if firearmModelID != 0 {
+		fitmentQuery = fitmentQuery.Where("firearm_model_id = ?", firearmModelID)
+	}
+	if err := fitmentQuery.Find(&fitments).Error; err != nil {
+		return err
+	}
+	for _, fitment := range fitments {
+		g.edges = append(g.edges, graphEdge{
+			source:   partNodeID(fitment.PartID),
+			target:   modelNodeID(fitment.FirearmModelID),
+			relation: "fits",
+			directed: true,
+			attrs:    []graphAttr{{"requires_modification", fitment.RequiresModification}},
+		})
+	}
+
+	var compatibilities []models.PartCompatibility
+	err := db.DB.Where("part_id < compatible_part_id").
+		Where("part_id IN (?)", parts.Session(&gorm.Session{}).Select("id")).
+		Where("compatible_part_id IN (?)", parts.Session(&gorm.Session{}).Select("id")).
+		Order("part_id, compatible_part_id").
+		Find(&compatibilities).Error
+	if err != nil {
+		return err
+	}
+	for _, compatibility := range compatibilities {
+		g.edges = append(g.edges, graphEdge{
+			source:   partNodeID(compatibility.PartID),
+			target:   partNodeID(compatibility.CompatiblePartID),
+			relation: "compatible",
+			attrs:    []graphAttr{{"has_unknown", compatibility.HasUnknown}},
+		})
+	}
+	return nil
+}
+
+// isOrphanedCategory reports whether no firearm model uses a category or any category beneath it
+func isOrphanedCategory(tree categoryTree, categoryID int, modelCount map[int]int) bool {
+	for id := range tree {
+		if modelCount[id] > 0 && tree.isWithin(id, categoryID) {
+			return false
+		}
+	}
+	return true
+}
+
+// dot renders the graph in Graphviz DOT. Undirected relations are drawn without arrowheads.
+func (g *compatibilityGraph) dot() []byte {
+	shapes := map[string]string{"firearm_model": "box3d", "part_category": "folder", "part": "ellipse"}
+	styles := map[string]string{
+		"uses":                "solid",
+		"subcategory_of":      "dashed",
+		"in_category":         "dotted",
+		"fits":                "bold",
+		"compatible":          "solid",
+		"category_compatible": "bold",
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("digraph compatibility {\n")
+	buf.WriteString("  rankdir=LR;\n")
+	for _, node := range g.nodes {
+		fmt.Fprintf(&buf, "  %s [label=%s, shape=%s, type=%s", dotQuote(node.id), dotQuote(node.label), shapes[node.kind], dotQuote(node.kind))
+		for _, attr := range node.attrs {
+			fmt.Fprintf(&buf, ", %s=%s", attr.key, dotQuote(fmt.Sprint(attr.value)))
+			if attr.key == "orphaned" && attr.value == true {
+				buf.WriteString(", color=red")
+			}
+		}
+		buf.WriteString("];\n")
+	}
+	for _, edge := range g.edges {
+		fmt.Fprintf(&buf, "  %s -> %s [relation=%s, style=%s", dotQuote(edge.source), dotQuote(edge.target), dotQuote(edge.relation), styles[edge.relation])
+		if !edge.directed {
+			buf.WriteString(", dir=none")
+		}
+		for _, attr := range edge.attrs {
+			fmt.Fprintf(&buf, ", %s=%s", attr.key, dotQuote(fmt.Sprint(attr.value)))
+			if attr.key == "pairs" {
+				fmt.Fprintf(&buf, ", label=%s", dotQuote(fmt.Sprint(attr.value)))
+			}
+		}
+		buf.WriteString("];\n")
+	}
+	buf.WriteString("}\n")
+	return buf.Bytes()
+}
+
+// dotQuote quotes a DOT identifier or attribute value
+func dotQuote(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value) + `"`
+}
+
+// GraphML document structure, see http://graphml.graphdrawing.org/specification.html
+type graphMLDocument struct {
+	XMLName xml.Name     `xml:"graphml"`
+	XMLNS   string       `xml:"xmlns,attr"`
+	Keys    []graphMLKey `xml:"key"`
+	Graph   graphMLGraph `xml:"graph"`
+}
+
+type graphMLKey struct {
+	ID       string `xml:"id,attr"`
+	For      string `xml:"for,attr"`
+	AttrName string `xml:"attr.name,attr"`
+	AttrType string `xml:"attr.type,attr"`
+}
+
+type graphMLGraph struct {
+	ID          string        `xml:"id,attr"`
+	EdgeDefault string        `xml:"edgedefault,attr"`
+	Nodes       []graphMLNode `xml:"node"`
+	Edges       []graphMLEdge `xml:"edge"`
+}
+
+type graphMLNode struct {
+	ID   string        `xml:"id,attr"`
+	Data []graphMLData `xml:"data"`
+}
+
+type graphMLEdge struct {
+	Source   string        `xml:"source,attr"`
+	Target   string        `xml:"target,attr"`
+	Directed bool          `xml:"directed,attr"`
+	Data     []graphMLData `xml:"data"`
+}
+
+type graphMLData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+// graphML renders the graph as GraphML, declaring a key for every node and edge attribute
+func (g *compatibilityGraph) graphML() ([]byte, error) {
+	doc := graphMLDocument{
+		XMLNS: "http://graphml.graphdrawing.org/xmlns",
+		Graph: graphMLGraph{ID: "compatibility", EdgeDefault: "directed"},
+	}
+	declared := make(map[string]bool)
+	data := func(domain string, attr graphAttr) graphMLData {
+		id := domain + "_" + attr.key
+		if !declared[id] {
+			declared[id] = true
+			doc.Keys = append(doc.Keys, graphMLKey{ID: id, For: domain, AttrName: attr.key, AttrType: graphMLType(attr.value)})
+		}
+		return graphMLData{Key: id, Value: fmt.Sprint(attr.value)}
+	}
+
+	for _, node := range g.nodes {
+		attrs := append([]graphAttr{{"type", node.kind}, {"label", node.label}}, node.attrs...)
+		element := graphMLNode{ID: node.id}
+		for _, attr := range attrs {
+			element.Data = append(element.Data, data("node", attr))
+		}
+		doc.Graph.Nodes = append(doc.Graph.Nodes, element)
+	}
+	for _, edge := range g.edges {
+		attrs := append([]graphAttr{{"relation", edge.relation}}, edge.attrs...)
+		element := graphMLEdge{Source: edge.source, Target: edge.target, Directed: edge.directed}
+		for _, attr := range attrs {
+			element.Data = append(element.Data, data("edge", attr))
+		}
+		doc.Graph.Edges = append(doc.Graph.Edges, element)
+	}
+
+	out, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), append(out, '\n')...), nil
+}
+
+// graphMLType maps an attribute value to its GraphML attr.type
+func graphMLType(value interface{}) string {
+	switch value.(type) {
+	case bool:
+		return "boolean"
+	case int:
+		return "int"
+	}
+	return "string"
+}
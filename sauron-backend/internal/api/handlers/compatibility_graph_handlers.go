@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary     Export the compatibility graph
+// @Description Export firearm models, part categories and parts as a Graphviz DOT or GraphML graph for auditing the taxonomy. Edges are the categories each model uses, the category hierarchy, category membership, declared fitments and compatible parts, with compatible pairs also rolled up per pair of categories. Category nodes carry part, model and compatible category counts and flag categories no firearm model uses.
+// @Tags        Admin,Compatibility
+// @Produce     text/vnd.graphviz
+// @Produce     application/graphml+xml
+// @Param       format           query string false "Output format" Enums(dot, graphml) default(dot)
+// @Param       firearm_model_id query int    false "Only this firearm model, its part categories and the parts that fit it"
+// @Param       include_parts    query bool   false "Include individual parts and their edges (default true)"
+// @Success     200 {string} string "Graph document"
+// @Failure     400 {object} map[string]string
+// @Failure     404 {object} map[string]string
+// @Failure     500 {object} map[string]string
+// @Router      /admin/compatibility-graph [get]
+func ExportCompatibilityGraphFile(c *gin.Context) {
+	options := GraphExportOptions{
+		Format:       c.DefaultQuery("format", GraphFormatDOT),
+		IncludeParts: c.Query("include_parts") != "false",
+	}
+	if modelParam := c.Query("firearm_model_id"); modelParam != "" {
+		var err error
+		if options.FirearmModelID, err = strconv.Atoi(modelParam); err != nil || options.FirearmModelID < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid firearm model ID"})
+			return
+		}
+	}
+
+	content, err := ExportCompatibilityGraph(options)
+	switch {
+	case errors.Is(err, ErrUnsupportedGraphFormat):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, ErrGraphModelNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Firearm model not found"})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to export compatibility graph"})
+		return
+	}
+
+	contentType := "text/vnd.graphviz"
+	if options.Format == GraphFormatGraphML {
+		contentType = "application/graphml+xml"
+	}
+	c.Header("Content-Disposition", "attachment; filename=compatibility."+options.Format)
+	c.Data(http.StatusOK, contentType, content)
+}
@@ -0,0 +1,380 @@
+package handlers
+
+import (
+	"log"
+	"sauron-backend/internal/db"
+	"sauron-backend/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// compatibilityIndexLockKey is the Postgres advisory lock that serialises writes to the
+// compatibility graph across every API process, so concurrent refreshes of overlapping parts
+// cannot interleave their delete and insert
+const compatibilityIndexLockKey = 7_231_004
+
+// compatibilityIndexStateID is the ID of the single CompatibilityIndexState row
+const compatibilityIndexStateID = 1
+
+// compatibilityLinks records which firearm models use each part category
+type compatibilityLinks struct {
+	modelsByCategory  map[int][]int
+	categoriesByModel map[int][]int
+}
+
+// loadCompatibilityLinks loads every firearm model to part category relation
+func loadCompatibilityLinks() (*compatibilityLinks, error) {
+	var relations []models.FirearmModelPartCategory
+	if err := db.DB.Select("firearm_model_id", "part_category_id").Find(&relations).Error; err != nil {
+		return nil, err
+	}
+
+	links := &compatibilityLinks{
+		modelsByCategory:  make(map[int][]int),
+		categoriesByModel: make(map[int][]int),
+	}
+	for _, relation := range relations {
+		links.modelsByCategory[relation.PartCategoryID] = append(links.modelsByCategory[relation.PartCategoryID], relation.FirearmModelID)
+		links.categoriesByModel[relation.FirearmModelID] = append(links.categoriesByModel[relation.FirearmModelID], relation.PartCategoryID)
+	}
+	return links, nil
+}
+
+// candidateCategories returns the categories whose parts may pair with parts in a category:
+// every category used by a firearm model that uses it, or only the category itself when no
+// firearm model does. The relation is symmetric, so the graph is undirected.
+func (l *compatibilityLinks) candidateCategories(categoryID int) []int {
+	modelIDs := l.modelsByCategory[categoryID]
+	if len(modelIDs) == 0 {
+		return []int{categoryID}
+	}
+
+	seen := make(map[int]bool)
+	categoryIDs := []int{}
+	for _, modelID := range modelIDs {
+		for _, id := range l.categoriesByModel[modelID] {
+			if !seen[id] {
+				seen[id] = true
+				categoryIDs = append(categoryIDs, id)
+			}
+		}
+	}
+	return categoryIDs
+}
+
+// RebuildPartCompatibilityIndex recomputes the whole part compatibility graph, returning the
+// number of edges stored. Use it after changing data outside the API, e.g. after seeding.
+func RebuildPartCompatibilityIndex() (int, error) {
+	return rebuildPartCompatibilityIndex(false)
+}
+
+// EnsurePartCompatibilityIndex builds the compatibility graph when it is empty but the catalog
+// is not, as after seeding an empty database on startup, or when a scheduled rebuild did not
+// finish. Edges stored in both directions by older versions are reduced to one per pair.
+func EnsurePartCompatibilityIndex() error {
+	if err := db.DB.Where("part_id > compatible_part_id").Delete(&models.PartCompatibility{}).Error; err != nil {
+		return err
+	}
+
+	var edges, parts int64
+	if err := db.DB.Model(&models.PartCompatibility{}).Limit(1).Count(&edges).Error; err != nil {
+		return err
+	}
+	if err := db.DB.Model(&models.Part{}).Where("part_category_id IS NOT NULL").Count(&parts).Error; err != nil {
+		return err
+	}
+	stale, err := compatibilityIndexStale()
+	if err != nil {
+		return err
+	}
+	if parts == 0 || (edges > 0 && !stale) {
+		return nil
+	}
+
+	count, err := RebuildPartCompatibilityIndex()
+	if err != nil {
+		return err
+	}
+	log.Printf("Built part compatibility index with %d edges", count)
+	return nil
+}
+
+// reindexParts recomputes the compatibility edges of the given parts. The change that made it
+// necessary has already been saved, so on failure a full rebuild is scheduled to repair the
+// graph and the error is returned for the caller to report.
+func reindexParts(partIDs []int) error {
+	if len(partIDs) == 0 {
+		return nil
+	}
+
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := lockCompatibilityIndex(tx); err != nil {
+			return err
+		}
+		var parts []models.Part
+		if err := tx.Where("id IN ?", partIDs).Find(&parts).Error; err != nil {
+			return err
+		}
+		edges, err := computeCompatibilityEdges(partIDs, parts)
+		if err != nil {
+			return err
+		}
+		return replaceCompatibilityEdges(tx, partIDs, edges)
+	})
+	if err != nil {
+		log.Printf("Warning: failed to update part compatibility index for %d parts: %v", len(partIDs), err)
+		if markErr := markCompatibilityIndexStale(db.DB); markErr != nil {
+			log.Printf("Warning: failed to schedule part compatibility index rebuild: %v", markErr)
+		} else {
+			startCompatibilityRebuild()
+		}
+	}
+	return err
+}
+
+// reindexPartsInCategories recomputes the edges of every part in the given categories
+func reindexPartsInCategories(categoryIDs []int) error {
+	if len(categoryIDs) == 0 {
+		return nil
+	}
+
+	var partIDs []int
+	if err := db.DB.Model(&models.Part{}).Where("part_category_id IN ?", categoryIDs).Pluck("id", &partIDs).Error; err != nil {
+		return err
+	}
+	return reindexParts(partIDs)
+}
+
+// reindexPartsWithinCategories recomputes the edges of every part in the given categories or
+// their subcategories, as when a rule on those categories changes
+func reindexPartsWithinCategories(categoryIDs []int) error {
+	tree, err := loadCategoryTree()
+	if err != nil {
+		return err
+	}
+
+	within := []int{}
+	for id := range tree {
+		for _, ancestorID := range categoryIDs {
+			if tree.isWithin(id, ancestorID) {
+				within = append(within, id)
+				break
+			}
+		}
+	}
+	return reindexPartsInCategories(within)
+}
+
+// markCompatibilityIndexStale records that the graph needs a full rebuild, for changes such as
+// a ruleset import or a caliber edit that can affect any pair. Call it in the transaction that
+// saves the change and start the rebuild once it commits; until the rebuild finishes,
+// GetCompatibleParts evaluates compatibility at query time.
+func markCompatibilityIndexStale(tx *gorm.DB) error {
+	if _, err := loadCompatibilityIndexState(tx); err != nil {
+		return err
+	}
+	return tx.Model(&models.CompatibilityIndexState{}).Where("id = ?", compatibilityIndexStateID).
+		Update("version", gorm.Expr("version + 1")).Error
+}
+
+// startCompatibilityRebuild rebuilds the graph in the background if it is stale. A failure
+// leaves it stale, so queries stay correct and the next start of the API retries the rebuild.
+func startCompatibilityRebuild() {
+	go func() {
+		count, err := rebuildPartCompatibilityIndex(true)
+		if err != nil {
+			log.Printf("Warning: failed to rebuild part compatibility index: %v", err)
+			return
+		}
+		if count >= 0 {
+			log.Printf("Rebuilt part compatibility index with %d edges", count)
+		}
+	}()
+}
+
+// compatibilityIndexStale reports whether a full rebuild of the graph is still pending
+func compatibilityIndexStale() (bool, error) {
+	state, err := loadCompatibilityIndexState(db.DB)
+	if err != nil {
+		return false, err
+	}
+	return state.BuiltVersion < state.Version, nil
+}
+
+// loadCompatibilityIndexState returns the index state row, creating it when missing
+func loadCompatibilityIndexState(tx *gorm.DB) (models.CompatibilityIndexState, error) {
+	state := models.CompatibilityIndexState{ID: compatibilityIndexStateID}
+	err := tx.Where(models.CompatibilityIndexState{ID: compatibilityIndexStateID}).FirstOrCreate(&state).Error
+	return state, err
+}
+
+// lockCompatibilityIndex takes the graph's advisory lock until the transaction ends
+func lockCompatibilityIndex(tx *gorm.DB) error {
+	return tx.Exec("SELECT pg_advisory_xact_lock(?)", compatibilityIndexLockKey).Error
+}
+
+// rebuildPartCompatibilityIndex replaces the whole graph and records the version it was built
+// for. With onlyIfStale it returns -1 without rebuilding when no rebuild is pending, so
+// rebuilds scheduled while another was running collapse into one.
+func rebuildPartCompatibilityIndex(onlyIfStale bool) (int, error) {
+	count := -1
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := lockCompatibilityIndex(tx); err != nil {
+			return err
+		}
+		state, err := loadCompatibilityIndexState(tx)
+		if err != nil {
+			return err
+		}
+		if onlyIfStale && state.BuiltVersion >= state.Version {
+			return nil
+		}
+
+		var parts []models.Part
+		if err := tx.Where("part_category_id IS NOT NULL").Find(&parts).Error; err != nil {
+			return err
+		}
+		edges, err := computeCompatibilityEdges(nil, parts)
+		if err != nil {
+			return err
+		}
+		if err := replaceCompatibilityEdges(tx, nil, edges); err != nil {
+			return err
+		}
+		count = len(edges)
+		return tx.Model(&models.CompatibilityIndexState{}).Where("id = ?", state.ID).
+			Update("built_version", state.Version).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// computeCompatibilityEdges computes the edges of the given parts, one per pair from the
+// lower part ID. A nil partIDs computes the whole graph, in which case parts must be every
+// categorised part. Pairs are compatible when their categories share a firearm model, their
+// declared fitments share a firearm model and no compatibility rule fails for them.
+func computeCompatibilityEdges(partIDs []int, parts []models.Part) ([]models.PartCompatibility, error) {
+	engine, err := loadCompatibilityEngine()
+	if err != nil {
+		return nil, err
+	}
+	links, err := loadCompatibilityLinks()
+	if err != nil {
+		return nil, err
+	}
+
+	refreshed := make(map[int]bool, len(parts))
+	categorySet := make(map[int]bool)
+	for _, part := range parts {
+		refreshed[part.ID] = true
+		if part.PartCategoryID != nil {
+			for _, id := range links.candidateCategories(*part.PartCategoryID) {
+				categorySet[id] = true
+			}
+		}
+	}
+
+	// A full rebuild already holds every candidate
+	candidates := parts
+	if partIDs != nil {
+		candidates = nil
+		if len(categorySet) > 0 {
+			categoryIDs := make([]int, 0, len(categorySet))
+			for id := range categorySet {
+				categoryIDs = append(categoryIDs, id)
+			}
+			if err := db.DB.Where("part_category_id IN ?", categoryIDs).Find(&candidates).Error; err != nil {
+				return nil, err
+			}
+		}
+	}
+	candidatesByCategory := make(map[int][]models.Part)
+	for _, candidate := range candidates {
+		if candidate.PartCategoryID != nil {
+			candidatesByCategory[*candidate.PartCategoryID] = append(candidatesByCategory[*candidate.PartCategoryID], candidate)
+		}
+	}
+
+	var fitsByPart map[int]map[int]bool
+	if partIDs == nil {
+		fitsByPart, err = loadAllDeclaredFitments()
+	} else {
+		fitmentPartIDs := append([]int{}, partIDs...)
+		for _, candidate := range candidates {
+			fitmentPartIDs = append(fitmentPartIDs, candidate.ID)
+		}
+		fitsByPart, err = loadDeclaredFitments(fitmentPartIDs)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	edges := []models.PartCompatibility{}
+	for _, part := range parts {
+		if part.PartCategoryID == nil {
+			continue
+		}
+		for _, categoryID := range links.candidateCategories(*part.PartCategoryID) {
+			for _, candidate := range candidatesByCategory[categoryID] {
+				// Pairs of two refreshed parts are evaluated once, from the lower ID
+				if candidate.ID == part.ID || (refreshed[candidate.ID] && candidate.ID < part.ID) {
+					continue
+				}
+				if !sharesDeclaredFitment(fitsByPart, part.ID, candidate.ID) {
+					continue
+				}
+				compatible, checks := engine.check(part, candidate)
+				if !compatible {
+					continue
+				}
+				edge := models.PartCompatibility{PartID: part.ID, CompatiblePartID: candidate.ID, HasUnknown: hasUnknownCheck(checks), ComputedAt: now}
+				if candidate.ID < part.ID {
+					edge.PartID, edge.CompatiblePartID = candidate.ID, part.ID
+				}
+				edges = append(edges, edge)
+			}
+		}
+	}
+	return edges, nil
+}
+
+// replaceCompatibilityEdges deletes the stored edges of the given parts, or of every part when
+// partIDs is nil, and inserts the new ones. The caller must hold the graph's advisory lock.
+func replaceCompatibilityEdges(tx *gorm.DB, partIDs []int, edges []models.PartCompatibility) error {
+	stale := tx.Session(&gorm.Session{AllowGlobalUpdate: true})
+	if partIDs != nil {
+		stale = tx.Where("part_id IN ? OR compatible_part_id IN ?", partIDs, partIDs)
+	}
+	if err := stale.Delete(&models.PartCompatibility{}).Error; err != nil {
+		return err
+	}
+	if len(edges) == 0 {
+		return nil
+	}
+	return tx.Omit("Part", "CompatiblePart").
+		Clauses(clause.OnConflict{UpdateAll: true}).
+		CreateInBatches(&edges, 1000).Error
+}
+
+// compatibleNeighbors returns the parts compatible with a part by evaluating its pairs directly,
+// keyed by part ID with whether a rule could not be evaluated, for use while the graph is stale
+func compatibleNeighbors(part models.Part) (map[int]bool, error) {
+	edges, err := computeCompatibilityEdges([]int{part.ID}, []models.Part{part})
+	if err != nil {
+		return nil, err
+	}
+	neighbors := make(map[int]bool, len(edges))
+	for _, edge := range edges {
+		otherID := edge.CompatiblePartID
+		if otherID == part.ID {
+			otherID = edge.PartID
+		}
+		neighbors[otherID] = edge.HasUnknown
+	}
+	return neighbors, nil
+}
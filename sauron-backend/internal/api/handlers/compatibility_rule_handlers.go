@@ -0,0 +1,184 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"sauron-backend/internal/db"
+	"sauron-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary     Get compatibility rules
+// @Description Get all attribute-based compatibility rules, optionally filtered to those involving a part category
+// @Tags        Compatibility
+// @Accept      json
+// @Produce     json
+// @Param       category_id query int false "Only rules whose source or target is this part category"
+// @Success     200 {array} models.CompatibilityRule
+// @Router      /compatibility-rules [get]
+func GetCompatibilityRules(c *gin.Context) {
+	query := db.DB.Order("id")
+	if categoryID := c.Query("category_id"); categoryID != "" {
+		query = query.Where("source_category_id = ? OR target_category_id = ?", categoryID, categoryID)
+	}
+
+	var rules []models.CompatibilityRule
+	query.Preload("Exceptions").Find(&rules)
+	c.JSON(http.StatusOK, rules)
+}
+
+// @Summary     Get compatibility rule by ID
+// @Description Get a specific compatibility rule
+// @Tags        Compatibility
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "Rule ID"
+// @Success     200 {object} models.CompatibilityRule
+// @Failure     404 {object} map[string]string
+// @Router      /compatibility-rules/{id} [get]
+func GetCompatibilityRuleByID(c *gin.Context) {
+	var rule models.CompatibilityRule
+	if err := db.DB.Preload("Exceptions").First(&rule, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Compatibility rule not found"})
+		return
+	}
+	c.JSON(http.StatusOK, rule)
+}
+
+// @Summary     Create a compatibility rule
+// @Description Create a rule comparing an attribute of parts in one category with an attribute of parts in another. Exceptions are managed through rulesets.
+// @Tags        Compatibility
+// @Accept      json
+// @Produce     json
+// @Param       rule body models.CompatibilityRule true "Rule to create"
+// @Success     201 {object} models.CompatibilityRule
+// @Failure     400 {object} map[string]string
+// @Failure     500 {object} map[string]string
+// @Router      /compatibility-rules [post]
+func CreateCompatibilityRule(c *gin.Context) {
+	var rule models.CompatibilityRule
+	if err := c.ShouldBindJSON(&rule); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	rule.ID = 0
+	rule.RuleSetID = nil
+	if !checkCompatibilityRule(c, &rule) {
+		return
+	}
+
+	if err := db.DB.Omit("SourceCategory", "TargetCategory", "Exceptions").Create(&rule).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create compatibility rule"})
+		return
+	}
+	if err := reindexPartsWithinCategories([]int{rule.SourceCategoryID}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Compatibility rule was saved but the compatibility index could not be updated"})
+		return
+	}
+	c.JSON(http.StatusCreated, rule)
+}
+
+// @Summary     Update a compatibility rule
+// @Description Update an existing compatibility rule
+// @Tags        Compatibility
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "Rule ID"
+// @Param       rule body models.CompatibilityRule true "Updated rule"
+// @Success     200 {object} models.CompatibilityRule
+// @Failure     400 {object} map[string]string
+// @Failure     404 {object} map[string]string
+// @Failure     500 {object} map[string]string
+// @Router      /compatibility-rules/{id} [put]
+func UpdateCompatibilityRule(c *gin.Context) {
+	var rule models.CompatibilityRule
+	if err := db.DB.First(&rule, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Compatibility rule not found"})
+		return
+	}
+	id, ruleSetID, previousSourceID := rule.ID, rule.RuleSetID, rule.SourceCategoryID
+	if err := c.ShouldBindJSON(&rule); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	rule.ID, rule.RuleSetID = id, ruleSetID
+	if !checkCompatibilityRule(c, &rule) {
+		return
+	}
+
+	if err := db.DB.Omit("SourceCategory", "TargetCategory", "Exceptions").Save(&rule).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update compatibility rule"})
+		return
+	}
+	if err := reindexPartsWithinCategories([]int{previousSourceID, rule.SourceCategoryID}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Compatibility rule was saved but the compatibility index could not be updated"})
+		return
+	}
+	c.JSON(http.StatusOK, rule)
+}
+
+// @Summary     Delete a compatibility rule
+// @Description Delete a specific compatibility rule
+// @Tags        Compatibility
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "Rule ID"
+// @Success     204 "No Content"
+// @Failure     404 {object} map[string]string
+// @Router      /compatibility-rules/{id} [delete]
+func DeleteCompatibilityRule(c *gin.Context) {
+	var rule models.CompatibilityRule
+	if err := db.DB.First(&rule, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Compatibility rule not found"})
+		return
+	}
+	if err := db.DB.Delete(&rule).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete compatibility rule"})
+		return
+	}
+	if err := reindexPartsWithinCategories([]int{rule.SourceCategoryID}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Compatibility rule was deleted but the compatibility index could not be updated"})
+		return
+	}
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// checkCompatibilityRule validates a rule's operator, attributes and categories,
+// writing a 400 response and returning false when it is invalid
+func checkCompatibilityRule(c *gin.Context, rule *models.CompatibilityRule) bool {
+	if err := validateRuleFields(rule); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return false
+	}
+
+	var count int64
+	db.DB.Model(&models.PartCategory{}).Where("id IN ?", []int{rule.SourceCategoryID, rule.TargetCategoryID}).Count(&count)
+	expected := int64(2)
+	if rule.SourceCategoryID == rule.TargetCategoryID {
+		expected = 1
+	}
+	if count != expected {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "source_category_id and target_category_id must be existing part categories"})
+		return false
+	}
+	return true
+}
+
+// validateRuleFields checks a rule's operator, attributes and tolerance, defaulting the
+// operator to eq
+func validateRuleFields(rule *models.CompatibilityRule) error {
+	if rule.Operator == "" {
+		rule.Operator = models.RuleOperatorEqual
+	}
+	if !validRuleOperators[rule.Operator] {
+		return errors.New("Invalid operator, expected one of eq, ne, lt, lte, gt, gte")
+	}
+	if rule.Name == "" || rule.SourceAttribute == "" || rule.TargetAttribute == "" {
+		return errors.New("name, source_attribute and target_attribute are required")
+	}
+	if rule.Tolerance < 0 {
+		return errors.New("tolerance cannot be negative")
+	}
+	return nil
+}
@@ -0,0 +1,317 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sauron-backend/internal/db"
+	"sauron-backend/internal/models"
+	"strconv"
+	"strings"
+)
+
+// Outcomes of evaluating a compatibility rule against a pair of parts
+const (
+	RuleResultPass    = "pass"
+	RuleResultFail    = "fail"
+	RuleResultUnknown = "unknown"
+)
+
+// validRuleOperators lists the operators a compatibility rule may use
+var validRuleOperators = map[string]bool{
+	models.RuleOperatorEqual:          true,
+	models.RuleOperatorNotEqual:       true,
+	models.RuleOperatorLessThan:       true,
+	models.RuleOperatorLessOrEqual:    true,
+	models.RuleOperatorGreaterThan:    true,
+	models.RuleOperatorGreaterOrEqual: true,
+}
+
+// RuleCheck is the outcome of one compatibility rule for a pair of parts
+type RuleCheck struct {
+	RuleID   int    `json:"rule_id" example:"1"`
+	RuleName string `json:"rule_name" example:"Gas block fits barrel journal"`
+
+	// pass, fail, or unknown when either part lacks the attribute
+	Result string `json:"result" example:"fail" enums:"pass,fail,unknown"`
+
+	// Part and attribute read as the rule's source and target
+	SourcePartID    int    `json:"source_part_id" example:"31"`
+	SourceAttribute string `json:"source_attribute" example:"gas_block_bore_diameter"`
+	TargetPartID    int    `json:"target_part_id" example:"7"`
+	TargetAttribute string `json:"target_attribute" example:"gas_block_journal_diameter"`
+
+	Message string `json:"message" example:"gas_block_bore_diameter 0.625 must equal gas_block_journal_diameter 0.75"`
+}
+
+// compatibilityEngine evaluates compatibility rules against pairs of parts
+type compatibilityEngine struct {
+	tree     categoryTree
+	rules    []models.CompatibilityRule
+	calibers *caliberIndex
+}
+
+// loadCompatibilityEngine loads the category tree and every compatibility rule with its exceptions
+func loadCompatibilityEngine() (*compatibilityEngine, error) {
+	tree, err := loadCategoryTree()
+	if err != nil {
+		return nil, err
+	}
+
+	var rules []models.CompatibilityRule
+	if err := db.DB.Preload("Exceptions").Order("id").Find(&rules).Error; err != nil {
+		return nil, err
+	}
+
+	calibers, err := loadCaliberIndex()
+	if err != nil {
+		return nil, err
+	}
+	return &compatibilityEngine{tree: tree, rules: rules, calibers: calibers}, nil
+}
+
+// check evaluates every rule that applies to the pair in either direction. The pair is
+// compatible unless a rule fails; rules missing an attribute are reported as unknown.
+func (e *compatibilityEngine) check(a, b models.Part) (bool, []RuleCheck) {
+	compatible := true
+	checks := []RuleCheck{}
+	attrsA, attrsB := e.attributes(a), e.attributes(b)
+
+	for _, rule := range e.rules {
+		if e.applies(rule, a, b) {
+			result := e.evaluate(rule, a, attrsA, b, attrsB)
+			compatible = compatible && result.Result != RuleResultFail
+			checks = append(checks, result)
+		}
+		if a.ID != b.ID && e.applies(rule, b, a) {
+			result := e.evaluate(rule, b, attrsB, a, attrsA)
+			compatible = compatible && result.Result != RuleResultFail
+			checks = append(checks, result)
+		}
+	}
+	return compatible, checks
+}
+
+// hasUnknownCheck reports whether any rule could not be evaluated
+func hasUnknownCheck(checks []RuleCheck) bool {
+	for _, check := range checks {
+		if check.Result == RuleResultUnknown {
+			return true
+		}
+	}
+	return false
+}
+
+// applies reports whether source and target fall in the rule's source and target categories
+func (e *compatibilityEngine) applies(rule models.CompatibilityRule, source, target models.Part) bool {
+	if source.PartCategoryID == nil || target.PartCategoryID == nil {
+		return false
+	}
+	return e.tree.isWithin(*source.PartCategoryID, rule.SourceCategoryID) &&
+		e.tree.isWithin(*target.PartCategoryID, rule.TargetCategoryID)
+}
+
+// evaluate checks a rule for a source and target part. Rules comparing the caliber of both
+// parts for equality compare caliber IDs instead of names: the calibers are equal when they
+// are the same or the target's chamber is rated to fire the source's caliber, as a .223 Wylde
+// barrel is for a 5.56 NATO bolt. Every other rule compares attributes.
+func (e *compatibilityEngine) evaluate(rule models.CompatibilityRule, source models.Part, sourceAttrs map[string]interface{}, target models.Part, targetAttrs map[string]interface{}) RuleCheck {
+	if rule.SourceAttribute != models.PartAttributeCaliber || rule.TargetAttribute != models.PartAttributeCaliber ||
+		(rule.Operator != models.RuleOperatorEqual && rule.Operator != models.RuleOperatorNotEqual) {
+		return evaluateRule(rule, source.ID, sourceAttrs, target.ID, targetAttrs)
+	}
+
+	check := RuleCheck{
+		RuleID:          rule.ID,
+		RuleName:        rule.Name,
+		SourcePartID:    source.ID,
+		SourceAttribute: rule.SourceAttribute,
+		TargetPartID:    target.ID,
+		TargetAttribute: rule.TargetAttribute,
+	}
+	if reason, exempt := ruleExemption(rule, source.ID, target.ID); exempt {
+		check.Result = RuleResultPass
+		check.Message = "Exempt: " + reason
+		return check
+	}
+
+	sourceCaliber, hasSource := e.caliberID(source)
+	targetCaliber, hasTarget := e.caliberID(target)
+	if !hasSource || !hasTarget {
+		check.Result = RuleResultUnknown
+		check.Message = "Attribute caliber is not set"
+		return check
+	}
+
+	sourceName, targetName := e.calibers.byID[sourceCaliber].Name, e.calibers.byID[targetCaliber].Name
+	var equal bool
+	switch {
+	case sourceCaliber == targetCaliber:
+		equal = true
+		check.Message = fmt.Sprintf("caliber %s is the same as caliber %s", sourceName, targetName)
+	case e.calibers.fires(targetCaliber, sourceCaliber):
+		equal = true
+		check.Message = fmt.Sprintf("a %s chamber fires caliber %s", targetName, sourceName)
+	default:
+		check.Message = fmt.Sprintf("caliber %s is neither the same as caliber %s nor fired by its chamber", sourceName, targetName)
+	}
+
+	check.Result = RuleResultFail
+	if equal == (rule.Operator == models.RuleOperatorEqual) {
+		check.Result = RuleResultPass
+	}
+	return check
+}
+
+// caliberID returns the caliber a part references, if it references one that exists
+func (e *compatibilityEngine) caliberID(part models.Part) (int, bool) {
+	if part.CaliberID == nil {
+		return 0, false
+	}
+	_, ok := e.calibers.byID[*part.CaliberID]
+	return *part.CaliberID, ok
+}
+
+// partAttributes decodes a part's attribute JSON, returning an empty map when absent or malformed
+func partAttributes(part models.Part) map[string]interface{} {
+	attrs := make(map[string]interface{})
+	if len(part.Attributes) > 0 {
+		_ = json.Unmarshal(part.Attributes, &attrs)
+	}
+	return attrs
+}
+
+// attributes returns a part's attributes with its caliber set to the canonical name of the
+// caliber the part references
+func (e *compatibilityEngine) attributes(part models.Part) map[string]interface{} {
+	attrs := partAttributes(part)
+
+	if id, ok := e.caliberID(part); ok {
+		attrs[models.PartAttributeCaliber] = e.calibers.byID[id].Name
+	}
+	return attrs
+}
+
+// evaluateRule compares the source part's attribute with the target part's, unless the
+// pairing is one of the rule's exceptions
+func evaluateRule(rule models.CompatibilityRule, sourceID int, sourceAttrs map[string]interface{}, targetID int, targetAttrs map[string]interface{}) RuleCheck {
+	check := RuleCheck{
+		RuleID:          rule.ID,
+		RuleName:        rule.Name,
+		SourcePartID:    sourceID,
+		SourceAttribute: rule.SourceAttribute,
+		TargetPartID:    targetID,
+		TargetAttribute: rule.TargetAttribute,
+	}
+
+	if reason, exempt := ruleExemption(rule, sourceID, targetID); exempt {
+		check.Result = RuleResultPass
+		check.Message = "Exempt: " + reason
+		return check
+	}
+
+	left, hasLeft := sourceAttrs[rule.SourceAttribute]
+	right, hasRight := targetAttrs[rule.TargetAttribute]
+	if !hasLeft || !hasRight {
+		check.Result = RuleResultUnknown
+		missing := rule.SourceAttribute
+		if hasLeft {
+			missing = rule.TargetAttribute
+		}
+		check.Message = fmt.Sprintf("Attribute %s is not set", missing)
+		return check
+	}
+
+	ok, comparable := compareAttributes(left, right, rule.Operator, rule.Tolerance)
+	switch {
+	case !comparable:
+		check.Result = RuleResultUnknown
+		check.Message = fmt.Sprintf("Cannot compare %v and %v with %s", left, right, rule.Operator)
+	case ok:
+		check.Result = RuleResultPass
+		check.Message = fmt.Sprintf("%s %v %s %s %v", rule.SourceAttribute, left, rule.Operator, rule.TargetAttribute, right)
+	default:
+		check.Result = RuleResultFail
+		check.Message = fmt.Sprintf("%s %v must be %s %s %v", rule.SourceAttribute, left, operatorPhrase(rule.Operator), rule.TargetAttribute, right)
+	}
+	return check
+}
+
+// ruleExemption returns the reason of the first exception of a rule that covers the pair
+func ruleExemption(rule models.CompatibilityRule, sourceID, targetID int) (string, bool) {
+	for _, exception := range rule.Exceptions {
+		if (exception.SourcePartID == nil || *exception.SourcePartID == sourceID) &&
+			(exception.TargetPartID == nil || *exception.TargetPartID == targetID) {
+			return exception.Reason, true
+		}
+	}
+	return "", false
+}
+
+// compareAttributes applies an operator to two attribute values. Numbers (or numeric
+// strings) compare numerically within the tolerance; other values compare as
+// case-insensitive strings and only support eq and ne. The second result is false
+// when the values cannot be compared with the operator.
+func compareAttributes(left, right interface{}, operator string, tolerance float64) (bool, bool) {
+	leftNum, leftIsNum := attributeNumber(left)
+	rightNum, rightIsNum := attributeNumber(right)
+	if leftIsNum && rightIsNum {
+		diff := leftNum - rightNum
+		equal := math.Abs(diff) <= tolerance
+		switch operator {
+		case models.RuleOperatorEqual:
+			return equal, true
+		case models.RuleOperatorNotEqual:
+			return !equal, true
+		case models.RuleOperatorLessThan:
+			return diff < 0 && !equal, true
+		case models.RuleOperatorLessOrEqual:
+			return diff < 0 || equal, true
+		case models.RuleOperatorGreaterThan:
+			return diff > 0 && !equal, true
+		case models.RuleOperatorGreaterOrEqual:
+			return diff > 0 || equal, true
+		}
+		return false, false
+	}
+
+	equal := strings.EqualFold(strings.TrimSpace(fmt.Sprint(left)), strings.TrimSpace(fmt.Sprint(right)))
+	switch operator {
+	case models.RuleOperatorEqual:
+		return equal, true
+	case models.RuleOperatorNotEqual:
+		return !equal, true
+	}
+	return false, false
+}
+
+// attributeNumber reads a JSON attribute value as a number if it is one
+func attributeNumber(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case string:
+		n, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return n, err == nil
+	}
+	return 0, false
+}
+
+// operatorPhrase spells out an operator for failure messages
+func operatorPhrase(operator string) string {
+	switch operator {
+	case models.RuleOperatorEqual:
+		return "equal to"
+	case models.RuleOperatorNotEqual:
+		return "different from"
+	case models.RuleOperatorLessThan:
+		return "less than"
+	case models.RuleOperatorLessOrEqual:
+		return "at most"
+	case models.RuleOperatorGreaterThan:
+		return "greater than"
+	case models.RuleOperatorGreaterOrEqual:
+		return "at least"
+	}
+	return operator
+}
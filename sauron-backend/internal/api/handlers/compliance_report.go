@@ -0,0 +1,306 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sauron-backend/internal/db"
+	"sauron-backend/internal/models"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Formats the compliance report can be exported in
+const (
+	ComplianceReportFormatCSV  = "csv"
+	ComplianceReportFormatJSON = "json"
+)
+
+// Outcomes of checking a prebuilt firearm against a jurisdiction's laws
+const (
+	ComplianceStatusPass         = "pass"
+	ComplianceStatusFail         = "fail"
+	ComplianceStatusUndetermined = "undetermined"
+
+	// The prebuilt firearm's data is too broken to check, see its data issues
+	ComplianceStatusError = "error"
+)
+
+// ErrUnsupportedReportFormat is returned for a compliance report format other than csv or json
+var ErrUnsupportedReportFormat = errors.New("format must be csv or json")
+
+// Specifications keys law checks read from a prebuilt firearm
+var complianceSpecKeys = []string{models.PartAttributeBarrelLength, models.PartAttributeOverallLength, "magazine_capacity"}
+
+// ComplianceReportOptions selects what ExportComplianceReport covers
+type ComplianceReportOptions struct {
+	// csv or json
+	Format string
+
+	// Jurisdiction codes or IDs to report on; every jurisdiction when empty
+	Jurisdictions []string
+
+	// Date to check the laws as of
+	AsOf time.Time
+}
+
+// ComplianceReport is the matrix of every prebuilt firearm checked against every jurisdiction
+type ComplianceReport struct {
+	GeneratedAt time.Time `json:"generated_at" example:"2025-01-01T12:00:00Z"`
+	AsOf        time.Time `json:"as_of" example:"2025-01-01T00:00:00Z"`
+
+	// Codes of the jurisdictions checked, in the order of each firearm's results
+	Jurisdictions []string `json:"jurisdictions" example:"US,US-CA,US-NY"`
+
+	Firearms []PrebuiltCompliance `json:"firearms"`
+}
+
+// PrebuiltCompliance is one prebuilt firearm's row of the compliance report
+type PrebuiltCompliance struct {
+	PrebuiltFirearmID int    `json:"prebuilt_firearm_id" example:"2"`
+	Name              string `json:"name" example:"Colt LE6920"`
+	FirearmModelID    int    `json:"firearm_model_id" example:"1"`
+
+	// Jurisdictions whose laws the firearm violates, e.g. for "not available in" badges
+	UnavailableIn []string `json:"unavailable_in" example:"US-CA,US-NY"`
+
+	// Problems with the firearm's components or specifications found while checking it
+	DataIssues []string `json:"data_issues" example:"magazine capacity is unknown: no magazine part records it and specifications have no magazine_capacity"`
+
+	Results []ComplianceCell `json:"results"`
+}
+
+// ComplianceCell is the outcome of checking one prebuilt firearm against one jurisdiction
+type ComplianceCell struct {
+	Jurisdiction string `json:"jurisdiction" example:"US-CA"`
+	Status       string `json:"status" example:"fail" enums:"pass,fail,undetermined,error"`
+
+	// Violated rules for a fail, undecidable rules for undetermined, and rules taking
+	// effect later that the firearm would violate
+	Reasons []string `json:"reasons" example:"Large-capacity magazine ban (Cal. Penal Code § 32310): PMAG 30-Round (AR-15) holds 30 rounds, more than the limit of 10"`
+}
+
+// ExportComplianceReport checks every prebuilt firearm against the selected jurisdictions and
+// renders the matrix as CSV, one row per firearm and one column per jurisdiction, or JSON
+func ExportComplianceReport(options ComplianceReportOptions) ([]byte, error) {
+	if options.Format != ComplianceReportFormatCSV && options.Format != ComplianceReportFormatJSON {
+		return nil, ErrUnsupportedReportFormat
+	}
+	report, err := buildComplianceReport(options)
+	if err != nil {
+		return nil, err
+	}
+	if options.Format == ComplianceReportFormatJSON {
+		return json.MarshalIndent(report, "", "  ")
+	}
+	return report.csv()
+}
+
+// buildComplianceReport checks every prebuilt firearm against the selected jurisdictions.
+// A firearm whose model or components cannot be read gets an error in every column rather
+// than failing the whole report.
+func buildComplianceReport(options ComplianceReportOptions) (*ComplianceReport, error) {
+	jurisdictions, err := complianceJurisdictions(options.Jurisdictions)
+	if err != nil {
+		return nil, err
+	}
+	var prebuilts []models.PrebuiltFirearm
+	if err := db.DB.Order("id").Find(&prebuilts).Error; err != nil {
+		return nil, err
+	}
+
+	report := &ComplianceReport{
+		GeneratedAt:   time.Now().UTC(),
+		AsOf:          options.AsOf,
+		Jurisdictions: make([]string, len(jurisdictions)),
+		Firearms:      make([]PrebuiltCompliance, 0, len(prebuilts)),
+	}
+	for i, jurisdiction := range jurisdictions {
+		report.Jurisdictions[i] = jurisdiction.Code
+	}
+
+	for _, prebuilt := range prebuilts {
+		row := PrebuiltCompliance{
+			PrebuiltFirearmID: prebuilt.ID,
+			Name:              prebuilt.Name,
+			FirearmModelID:    prebuilt.FirearmModelID,
+			UnavailableIn:     []string{},
+			DataIssues:        []string{},
+			Results:           make([]ComplianceCell, 0, len(jurisdictions)),
+		}
+
+		profile, issue, err := complianceProfile(prebuilt)
+		if err != nil {
+			return nil, err
+		}
+		if issue != "" {
+			row.DataIssues = append(row.DataIssues, issue)
+			for _, jurisdiction := range jurisdictions {
+				row.Results = append(row.Results, ComplianceCell{Jurisdiction: jurisdiction.Code, Status: ComplianceStatusError, Reasons: []string{issue}})
+			}
+			report.Firearms = append(report.Firearms, row)
+			continue
+		}
+		row.DataIssues = append(row.DataIssues, prebuiltSpecIssues(prebuilt, profile)...)
+
+		for _, jurisdiction := range jurisdictions {
+			result, err := checkLaws(jurisdiction, profile, options.AsOf)
+			if err != nil {
+				return nil, err
+			}
+			cell := complianceCell(result)
+			if cell.Status == ComplianceStatusFail {
+				row.UnavailableIn = append(row.UnavailableIn, jurisdiction.Code)
+			}
+			row.Results = append(row.Results, cell)
+		}
+		report.Firearms = append(report.Firearms, row)
+	}
+	return report, nil
+}
+
+// complianceJurisdictions resolves the jurisdictions to report on, federal before state and
+// then by code, or returns every jurisdiction when none are given
+func complianceJurisdictions(refs []string) ([]models.Jurisdiction, error) {
+	if len(refs) == 0 {
+		var jurisdictions []models.Jurisdiction
+		err := db.DB.Order("CASE WHEN level = 'federal' THEN 0 ELSE 1 END, code").Find(&jurisdictions).Error
+		return jurisdictions, err
+	}
+
+	jurisdictions := make([]models.Jurisdiction, 0, len(refs))
+	seen := make(map[int]bool, len(refs))
+	for _, ref := range refs {
+		jurisdiction, err := resolveJurisdiction(ref)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", err, ref)
+		}
+		if !seen[jurisdiction.ID] {
+			seen[jurisdiction.ID] = true
+			jurisdictions = append(jurisdictions, *jurisdiction)
+		}
+	}
+	return jurisdictions, nil
+}
+
+// complianceProfile builds a prebuilt firearm's law profile, returning a data issue instead
+// when its firearm model is missing or its components are not valid JSON
+func complianceProfile(prebuilt models.PrebuiltFirearm) (*firearmProfile, string, error) {
+	if _, err := flattenPrebuiltComponents(prebuilt.Parts); err != nil {
+		return nil, "components are not valid JSON: " + err.Error(), nil
+	}
+	profile, err := profilePrebuilt(prebuilt)
+	if errors.Is(err, errFirearmModelNotFound) {
+		return nil, fmt.Sprintf("firearm model %d does not exist", prebuilt.FirearmModelID), nil
+	}
+	return profile, "", err
+}
+
+// prebuiltSpecIssues reports specifications law checks cannot read or that disagree with the
+// firearm's parts, and lengths and capacity that neither records
+func prebuiltSpecIssues(prebuilt models.PrebuiltFirearm, profile *firearmProfile) []string {
+	issues := []string{}
+	if profile.class == "" {
+		issues = append(issues, "firearm class is unknown: its parts do not classify it and its model has no rifle, pistol or shotgun category, so class-specific rules are undetermined")
+	}
+
+	specs := map[string]interface{}{}
+	if len(prebuilt.Specifications) > 0 {
+		if err := json.Unmarshal(prebuilt.Specifications, &specs); err != nil {
+			return append(issues, "specifications are not a JSON object: "+err.Error())
+		}
+	}
+
+	for _, key := range complianceSpecKeys {
+		value, present := specs[key]
+		if !present {
+			continue
+		}
+		number, ok := specNumber(value)
+		if !ok {
+			issues = append(issues, fmt.Sprintf("specifications %s %v is not a number", key, value))
+			continue
+		}
+		switch key {
+		case models.PartAttributeBarrelLength:
+			if profile.barrelSource != "specifications" && profile.barrelLength != nil && math.Abs(number-*profile.barrelLength) > 0.05 {
+				issues = append(issues, fmt.Sprintf("specifications %s is %g in but %s is %g in", key, number, profile.barrelSource, *profile.barrelLength))
+			}
+		case models.PartAttributeOverallLength:
+			if profile.overallSource != "specifications" && profile.overallLength != nil && math.Abs(number-*profile.overallLength) > 0.05 {
+				issues = append(issues, fmt.Sprintf("specifications %s is %g in but %s is %g in", key, number, profile.overallSource, *profile.overallLength))
+			}
+		}
+	}
+
+	if profile.barrelLength == nil {
+		issues = append(issues, "barrel length is unknown: no barrel part records it and specifications have no barrel_length")
+	}
+	if profile.magazineCapacity == nil {
+		issues = append(issues, "magazine capacity is unknown: no magazine part records it and specifications have no magazine_capacity")
+	}
+	return issues
+}
+
+// complianceCell summarizes a law check as pass, fail or undetermined with its reasons
+func complianceCell(result *LawCheckResult) ComplianceCell {
+	cell := ComplianceCell{Jurisdiction: result.Jurisdiction, Status: ComplianceStatusPass, Reasons: []string{}}
+	switch {
+	case !result.Compliant:
+		cell.Status = ComplianceStatusFail
+		for _, violation := range result.Violations {
+			cell.Reasons = append(cell.Reasons, complianceReason(violation))
+		}
+	case len(result.Undetermined) > 0:
+		cell.Status = ComplianceStatusUndetermined
+		for _, undetermined := range result.Undetermined {
+			cell.Reasons = append(cell.Reasons, complianceReason(undetermined))
+		}
+	}
+	for _, upcoming := range result.Upcoming {
+		cell.Reasons = append(cell.Reasons, "from "+upcoming.EffectiveFrom.Format("2006-01-02")+", "+complianceReason(upcoming))
+	}
+	return cell
+}
+
+// complianceReason describes a rule outcome with its citation
+func complianceReason(outcome LawRuleResult) string {
+	return fmt.Sprintf("%s (%s): %s", outcome.Name, outcome.Citation, outcome.Message)
+}
+
+// csv renders the report with one row per firearm and one column per jurisdiction. Each
+// jurisdiction cell holds the status, followed for anything but a bare pass by the reasons.
+func (r *ComplianceReport) csv() ([]byte, error) {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+
+	header := append([]string{"prebuilt_firearm_id", "name", "firearm_model_id", "unavailable_in", "data_issues"}, r.Jurisdictions...)
+	if err := writer.Write(header); err != nil {
+		return nil, err
+	}
+	for _, firearm := range r.Firearms {
+		record := []string{
+			strconv.Itoa(firearm.PrebuiltFirearmID),
+			firearm.Name,
+			strconv.Itoa(firearm.FirearmModelID),
+			strings.Join(firearm.UnavailableIn, " "),
+			strings.Join(firearm.DataIssues, "; "),
+		}
+		for _, cell := range firearm.Results {
+			value := cell.Status
+			if len(cell.Reasons) > 0 {
+				value += ": " + strings.Join(cell.Reasons, "; ")
+			}
+			record = append(record, value)
+		}
+		if err := writer.Write(record); err != nil {
+			return nil, err
+		}
+	}
+	writer.Flush()
+	return buf.Bytes(), writer.Error()
+}
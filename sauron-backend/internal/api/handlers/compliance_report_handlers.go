@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary     Export the prebuilt firearm compliance report
+// @Description Check every prebuilt firearm against the laws of every jurisdiction, or the given ones, and export the matrix of pass, fail or undetermined with the violated or undecidable rules and their citations. Each firearm also lists the jurisdictions it is unavailable in and data issues found in its components and specifications, such as lengths that are missing, unreadable or disagree with its parts.
+// @Tags        Admin,Laws
+// @Produce     text/csv
+// @Produce     json
+// @Param       format        query string false "Output format" Enums(csv, json) default(csv)
+// @Param       jurisdictions query string false "Comma-separated jurisdiction codes or IDs, e.g. US-CA,US-NY; all when omitted"
+// @Param       as_of         query string false "Date to check the laws as of, YYYY-MM-DD or RFC 3339; defaults to now"
+// @Success     200 {object} ComplianceReport
+// @Failure     400 {object} map[string]string
+// @Failure     404 {object} map[string]string
+// @Failure     500 {object} map[string]string
+// @Router      /admin/compliance-report [get]
+func ExportComplianceReportFile(c *gin.Context) {
+	asOf, err := parseAsOf(c.Query("as_of"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "as_of must be a date (YYYY-MM-DD) or an RFC 3339 timestamp"})
+		return
+	}
+	options := ComplianceReportOptions{
+		Format:        c.DefaultQuery("format", ComplianceReportFormatCSV),
+		Jurisdictions: jurisdictionRefs(c.Query("jurisdictions")),
+		AsOf:          asOf,
+	}
+
+	content, err := ExportComplianceReport(options)
+	switch {
+	case errors.Is(err, ErrUnsupportedReportFormat):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, ErrJurisdictionNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build compliance report"})
+		return
+	}
+
+	contentType := "text/csv"
+	if options.Format == ComplianceReportFormatJSON {
+		contentType = "application/json"
+	}
+	c.Header("Content-Disposition", "attachment; filename=compliance-report."+options.Format)
+	c.Data(http.StatusOK, contentType, content)
+}
+
+// jurisdictionRefs splits a comma-separated list of jurisdiction codes or IDs
+func jurisdictionRefs(list string) []string {
+	refs := []string{}
+	for _, ref := range strings.Split(list, ",") {
+		if ref = strings.TrimSpace(ref); ref != "" {
+			refs = append(refs, ref)
+		}
+	}
+	return refs
+}
@@ -6,18 +6,32 @@ import (
 	"sauron-backend/internal/models"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 // @Summary     Get all firearm models
-// @Description Get a list of all firearm models in the database
+// @Description Get a list of all firearm models in the database, optionally only those chambered in a caliber
 // @Tags        Firearm Models
 // @Accept      json
 // @Produce     json
+// @Param       caliber query string false "Filter by caliber ID, name or alias"
 // @Success     200 {array}  models.FirearmModel
+// @Failure     400 {object} map[string]string
 // @Router      /firearm-models [get]
 func GetFirearmModels(c *gin.Context) {
+	caliberIDs, err := resolveCaliberQuery(c)
+	if err != nil {
+		respondToCaliberQueryError(c, err)
+		return
+	}
+
+	query := db.DB.Preload("Caliber")
+	if caliberIDs != nil {
+		query = query.Where("caliber_id IN ?", caliberIDs)
+	}
+
 	var models []models.FirearmModel
-	db.DB.Find(&models)
+	query.Find(&models)
 	c.JSON(http.StatusOK, models)
 }
 
@@ -36,6 +50,9 @@ func CreateFirearmModel(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if !checkFirearmModelMechanism(c, &model) {
+		return
+	}
 	db.DB.Create(&model)
 	c.JSON(http.StatusCreated, model)
 }
@@ -52,7 +69,7 @@ func CreateFirearmModel(c *gin.Context) {
 func GetFirearmModelByID(c *gin.Context) {
 	id := c.Param("id")
 	var model models.FirearmModel
-	if err := db.DB.First(&model, id).Error; err != nil {
+	if err := db.DB.Preload("Caliber").First(&model, id).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Model not found"})
 		return
 	}
@@ -81,24 +98,72 @@ func UpdateFirearmModel(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if !checkFirearmModelMechanism(c, &model) {
+		return
+	}
 	db.DB.Save(&model)
 	c.JSON(http.StatusOK, model)
 }
 
 // @Summary     Delete a firearm model
-// @Description Delete a specific firearm model
+// @Description Delete a specific firearm model. Law rules limited to the model must be deleted or changed first, so their history records who did it.
 // @Tags        Firearm Models
 // @Accept      json
 // @Produce     json
 // @Param       id path int true "Firearm Model ID"
 // @Success     204 "No Content"
 // @Failure     404 {object} map[string]string
+// @Failure     409 {object} map[string]string
+// @Failure     500 {object} map[string]string
 // @Router      /firearm-models/{id} [delete]
 func DeleteFirearmModel(c *gin.Context) {
 	id := c.Param("id")
-	if err := db.DB.Delete(&models.FirearmModel{}, id).Error; err != nil {
+
+	var count int64
+	db.DB.Model(&models.LawRule{}).Where("firearm_model_id = ?", id).Count(&count)
+	if count > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "Firearm model still has law rules limited to it and cannot be deleted"})
+		return
+	}
+
+	// Parts whose compatibility depends on the model through its categories or their fitments
+	var partIDs []int
+	db.DB.Model(&models.Part{}).
+		Where("part_category_id IN (?)", db.DB.Model(&models.FirearmModelPartCategory{}).Select("part_category_id").Where("firearm_model_id = ?", id)).
+		Or("id IN (?)", db.DB.Model(&models.PartFirearmModel{}).Select("part_id").Where("firearm_model_id = ?", id)).
+		Pluck("id", &partIDs)
+
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM magazine_family_firearm_models WHERE firearm_model_id = ?", id).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.FirearmModel{}, id).Error
+	})
+	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Model not found"})
 		return
 	}
+	if err := reindexParts(partIDs); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Model was deleted but the compatibility index could not be updated"})
+		return
+	}
 	c.JSON(http.StatusNoContent, nil)
 }
+
+// checkFirearmModelMechanism validates a firearm model's action and magazine types, which may
+// be left empty when unknown, writing a 400 response and returning false when either is invalid
+func checkFirearmModelMechanism(c *gin.Context, model *models.FirearmModel) bool {
+	switch model.ActionType {
+	case "", models.ActionTypeSemiAuto, models.ActionTypeBolt, models.ActionTypePump, models.ActionTypeLever, models.ActionTypeSingle:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "action_type must be semi_auto, bolt, pump, lever or single_shot"})
+		return false
+	}
+	switch model.MagazineType {
+	case "", models.MagazineTypeDetachable, models.MagazineTypeFixed, models.MagazineTypeTube:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "magazine_type must be detachable, fixed or tube"})
+		return false
+	}
+	return true
+}
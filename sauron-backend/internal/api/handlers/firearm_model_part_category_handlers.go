@@ -148,6 +148,10 @@ func AddCategoryToFirearmModel(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to associate category with firearm model"})
 		return
 	}
+	if err := reindexPartsInCategories([]int{categoryID}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Category association was saved but the compatibility index could not be updated"})
+		return
+	}
 
 	c.JSON(http.StatusCreated, relation)
 }
@@ -244,6 +248,10 @@ func RemoveCategoryFromFirearmModel(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Relationship not found"})
 		return
 	}
+	if err := reindexPartsInCategories([]int{categoryID}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Category association was removed but the compatibility index could not be updated"})
+		return
+	}
 
 	c.Status(http.StatusNoContent)
 }
@@ -0,0 +1,187 @@
+package handlers
+
+import (
+	"net/http"
+	"sauron-backend/internal/db"
+	"sauron-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary     Get all jurisdictions
+// @Description Get the federal and state jurisdictions whose firearm laws can be checked, optionally only the children of one
+// @Tags        Laws
+// @Accept      json
+// @Produce     json
+// @Param       parent query string false "Only jurisdictions under this parent code or ID"
+// @Success     200 {array}  models.Jurisdiction
+// @Failure     404 {object} map[string]string
+// @Router      /jurisdictions [get]
+func GetJurisdictions(c *gin.Context) {
+	query := db.DB.Order("code")
+	if parentRef := c.Query("parent"); parentRef != "" {
+		parent, err := resolveJurisdiction(parentRef)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Parent jurisdiction not found"})
+			return
+		}
+		query = query.Where("parent_jurisdiction_id = ?", parent.ID)
+	}
+
+	jurisdictions := []models.Jurisdiction{}
+	query.Find(&jurisdictions)
+	c.JSON(http.StatusOK, jurisdictions)
+}
+
+// @Summary     Get a jurisdiction
+// @Description Get a specific jurisdiction by code, e.g. US-CA, or ID
+// @Tags        Laws
+// @Accept      json
+// @Produce     json
+// @Param       id path string true "Jurisdiction code or ID"
+// @Success     200 {object} models.Jurisdiction
+// @Failure     404 {object} map[string]string
+// @Router      /jurisdictions/{id} [get]
+func GetJurisdictionByID(c *gin.Context) {
+	jurisdiction, err := resolveJurisdiction(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Jurisdiction not found"})
+		return
+	}
+	c.JSON(http.StatusOK, jurisdiction)
+}
+
+// @Summary     Create a jurisdiction
+// @Description Add a new jurisdiction, normally a state under the federal jurisdiction
+// @Tags        Laws
+// @Accept      json
+// @Produce     json
+// @Param       jurisdiction body models.Jurisdiction true "Jurisdiction to create"
+// @Success     201 {object} models.Jurisdiction
+// @Failure     400 {object} map[string]string
+// @Failure     409 {object} map[string]string
+// @Router      /jurisdictions [post]
+func CreateJurisdiction(c *gin.Context) {
+	var jurisdiction models.Jurisdiction
+	if err := c.ShouldBindJSON(&jurisdiction); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	jurisdiction.ID = 0
+	jurisdiction.ParentJurisdiction = nil
+	if !checkJurisdiction(c, jurisdiction) {
+		return
+	}
+
+	if err := db.DB.Create(&jurisdiction).Error; err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "Jurisdiction " + jurisdiction.Code + " already exists"})
+		return
+	}
+	c.JSON(http.StatusCreated, jurisdiction)
+}
+
+// @Summary     Update a jurisdiction
+// @Description Update an existing jurisdiction
+// @Tags        Laws
+// @Accept      json
+// @Produce     json
+// @Param       id path string true "Jurisdiction code or ID"
+// @Param       jurisdiction body models.Jurisdiction true "Updated jurisdiction"
+// @Success     200 {object} models.Jurisdiction
+// @Failure     400 {object} map[string]string
+// @Failure     404 {object} map[string]string
+// @Failure     409 {object} map[string]string
+// @Router      /jurisdictions/{id} [put]
+func UpdateJurisdiction(c *gin.Context) {
+	jurisdiction, err := resolveJurisdiction(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Jurisdiction not found"})
+		return
+	}
+	id := jurisdiction.ID
+	if err := c.ShouldBindJSON(jurisdiction); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	jurisdiction.ID = id
+	jurisdiction.ParentJurisdiction = nil
+	if !checkJurisdiction(c, *jurisdiction) {
+		return
+	}
+
+	if err := db.DB.Save(jurisdiction).Error; err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "Jurisdiction " + jurisdiction.Code + " already exists"})
+		return
+	}
+	c.JSON(http.StatusOK, jurisdiction)
+}
+
+// @Summary     Delete a jurisdiction
+// @Description Delete a jurisdiction that has no law rules and no child jurisdictions
+// @Tags        Laws
+// @Accept      json
+// @Produce     json
+// @Param       id path string true "Jurisdiction code or ID"
+// @Success     204 "No Content"
+// @Failure     404 {object} map[string]string
+// @Failure     409 {object} map[string]string
+// @Router      /jurisdictions/{id} [delete]
+func DeleteJurisdiction(c *gin.Context) {
+	jurisdiction, err := resolveJurisdiction(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Jurisdiction not found"})
+		return
+	}
+
+	var count int64
+	db.DB.Model(&models.LawRule{}).Where("jurisdiction_id = ?", jurisdiction.ID).Count(&count)
+	if count > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "Jurisdiction still has law rules and cannot be deleted"})
+		return
+	}
+	db.DB.Model(&models.Jurisdiction{}).Where("parent_jurisdiction_id = ?", jurisdiction.ID).Count(&count)
+	if count > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "Jurisdiction still has child jurisdictions and cannot be deleted"})
+		return
+	}
+
+	if err := db.DB.Delete(jurisdiction).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete jurisdiction"})
+		return
+	}
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// checkJurisdiction validates a jurisdiction, writing a 400 response and returning false when
+// it is invalid. A jurisdiction may not be its own ancestor.
+func checkJurisdiction(c *gin.Context, jurisdiction models.Jurisdiction) bool {
+	if jurisdiction.Code == "" || jurisdiction.Name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "code and name are required"})
+		return false
+	}
+	if jurisdiction.Level != models.JurisdictionLevelFederal && jurisdiction.Level != models.JurisdictionLevelState {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "level must be federal or state"})
+		return false
+	}
+	if jurisdiction.ParentJurisdictionID == nil {
+		return true
+	}
+
+	var parent models.Jurisdiction
+	if err := db.DB.First(&parent, *jurisdiction.ParentJurisdictionID).Error; err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Parent jurisdiction not found"})
+		return false
+	}
+	ancestors, err := jurisdictionChain(parent)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load parent jurisdictions"})
+		return false
+	}
+	for _, ancestor := range ancestors {
+		if ancestor.ID == jurisdiction.ID {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "A jurisdiction cannot be its own ancestor"})
+			return false
+		}
+	}
+	return true
+}
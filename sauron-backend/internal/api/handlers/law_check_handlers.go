@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"sauron-backend/internal/db"
+	"sauron-backend/internal/models"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LawCheckInput is the request body for checking an unsaved slot selection against a jurisdiction's laws
+type LawCheckInput struct {
+	// Jurisdiction code or ID
+	Jurisdiction string `json:"jurisdiction" binding:"required" example:"US-CA"`
+
+	// Firearm model the selection is for
+	FirearmModelID int `json:"firearm_model_id" binding:"required" example:"1"`
+
+	// Selected parts keyed by part category (slot) ID
+	Slots map[int]int `json:"slots"`
+
+	// Date to check the laws as of, YYYY-MM-DD or RFC 3339; defaults to now
+	AsOf string `json:"as_of" example:"2025-01-01"`
+}
+
+// @Summary     Check a build or prebuilt firearm against a jurisdiction's laws
+// @Description Evaluate a saved build or a prebuilt firearm against the law rules of a jurisdiction and its parents, e.g. federal law for a state, in effect on a date, and list each violated rule with its citation. Rules that cannot be decided from the recorded data are listed as undetermined, and rules taking effect later that the firearm would violate are listed as upcoming.
+// @Tags        Laws
+// @Accept      json
+// @Produce     json
+// @Param       jurisdiction        query string true  "Jurisdiction code or ID, e.g. US-CA"
+// @Param       build_id            query int    false "Build to check"
+// @Param       prebuilt_firearm_id query int    false "Prebuilt firearm to check"
+// @Param       as_of               query string false "Date to check the laws as of, YYYY-MM-DD or RFC 3339; defaults to now"
+// @Success     200 {object} LawCheckResult
+// @Failure     400 {object} map[string]string
+// @Failure     404 {object} map[string]string
+// @Failure     500 {object} map[string]string
+// @Router      /laws/check [get]
+func CheckLaws(c *gin.Context) {
+	buildID, prebuiltID := c.Query("build_id"), c.Query("prebuilt_firearm_id")
+	if (buildID == "") == (prebuiltID == "") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Exactly one of build_id and prebuilt_firearm_id is required"})
+		return
+	}
+	jurisdiction, asOf, ok := lawCheckScope(c, c.Query("jurisdiction"), c.Query("as_of"))
+	if !ok {
+		return
+	}
+
+	var (
+		profile *firearmProfile
+		err     error
+	)
+	if buildID != "" {
+		build, loadErr := loadBuild(buildID)
+		if loadErr != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Build not found"})
+			return
+		}
+		profile, err = profileSelection(build.FirearmModelID, buildSlots(build))
+		respondWithLawCheck(c, *jurisdiction, asOf, profile, err, func(result *LawCheckResult) { result.BuildID = build.ID })
+		return
+	}
+
+	var prebuilt models.PrebuiltFirearm
+	if err := db.DB.First(&prebuilt, prebuiltID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Prebuilt firearm not found"})
+		return
+	}
+	profile, err = profilePrebuilt(prebuilt)
+	respondWithLawCheck(c, *jurisdiction, asOf, profile, err, func(result *LawCheckResult) { result.PrebuiltFirearmID = prebuilt.ID })
+}
+
+// @Summary     Check a slot selection against a jurisdiction's laws
+// @Description Evaluate an unsaved slot selection against the law rules of a jurisdiction and its parents in effect on a date and list each violated rule with its citation, and the upcoming rules it would violate
+// @Tags        Laws
+// @Accept      json
+// @Produce     json
+// @Param       selection body LawCheckInput true "Jurisdiction and slot selection"
+// @Success     200 {object} LawCheckResult
+// @Failure     400 {object} map[string]string
+// @Failure     404 {object} map[string]string
+// @Failure     500 {object} map[string]string
+// @Router      /laws/check [post]
+func CheckSelectionLaws(c *gin.Context) {
+	var input LawCheckInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	jurisdiction, asOf, ok := lawCheckScope(c, input.Jurisdiction, input.AsOf)
+	if !ok {
+		return
+	}
+
+	profile, err := profileSelection(input.FirearmModelID, input.Slots)
+	respondWithLawCheck(c, *jurisdiction, asOf, profile, err, nil)
+}
+
+// lawCheckScope resolves the jurisdiction and date to check the laws of, writing the error
+// response and returning false when either is invalid
+func lawCheckScope(c *gin.Context, ref, asOfParam string) (*models.Jurisdiction, time.Time, bool) {
+	if ref == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "jurisdiction is required"})
+		return nil, time.Time{}, false
+	}
+	asOf, err := parseAsOf(asOfParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "as_of must be a date (YYYY-MM-DD) or an RFC 3339 timestamp"})
+		return nil, time.Time{}, false
+	}
+	jurisdiction, err := resolveJurisdiction(ref)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Jurisdiction " + ref + " not found"})
+		return nil, time.Time{}, false
+	}
+	return jurisdiction, asOf, true
+}
+
+// respondWithLawCheck checks a firearm profile against a jurisdiction's laws as of a date and
+// writes the result, letting the caller identify the firearm checked
+func respondWithLawCheck(c *gin.Context, jurisdiction models.Jurisdiction, asOf time.Time, profile *firearmProfile, err error, identify func(*LawCheckResult)) {
+	if errors.Is(err, errFirearmModelNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Firearm model not found"})
+		return
+	}
+	if err != nil {
+		log.Printf("Error reading firearm parts for law check: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read the firearm's parts"})
+		return
+	}
+
+	result, err := checkLaws(jurisdiction, profile, asOf)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check laws"})
+		return
+	}
+	if identify != nil {
+		identify(result)
+	}
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"sauron-backend/internal/models"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// Request headers identifying who changes law rules and why, as the API has no accounts
+const (
+	changedByHeader    = "X-User"
+	changeReasonHeader = "X-Change-Reason"
+)
+
+// lawRuleBookkeepingFields are left out when listing what a change touched
+var lawRuleBookkeepingFields = map[string]bool{
+	"id": true, "version": true, "created_at": true, "updated_at": true,
+	"jurisdiction": true, "firearm_model": true,
+}
+
+// changeAuthor reads who is making a change and why from the request headers, writing a 400
+// response and returning false when the author is missing
+func changeAuthor(c *gin.Context) (changedBy, reason string, ok bool) {
+	changedBy = strings.TrimSpace(c.GetHeader(changedByHeader))
+	if changedBy == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "The " + changedByHeader + " header is required to change law rules"})
+		return "", "", false
+	}
+	return changedBy, strings.TrimSpace(c.GetHeader(changeReasonHeader)), true
+}
+
+// recordLawRuleChange appends an entry to the audit trail of a law rule. before is nil for
+// creations and after is nil for deletions.
+func recordLawRuleChange(tx *gorm.DB, action string, before, after *models.LawRule, changedBy, reason string) error {
+	current := after
+	if current == nil {
+		current = before
+	}
+	if current == nil {
+		return errors.New("a law rule change needs the rule before or after it")
+	}
+	change := models.LawRuleChange{
+		LawRuleID:      current.ID,
+		JurisdictionID: current.JurisdictionID,
+		Version:        current.Version,
+		Action:         action,
+		ChangedBy:      changedBy,
+		Reason:         reason,
+	}
+
+	beforeJSON, beforeFields, err := lawRuleSnapshot(before)
+	if err != nil {
+		return err
+	}
+	afterJSON, afterFields, err := lawRuleSnapshot(after)
+	if err != nil {
+		return err
+	}
+	change.Before, change.After = beforeJSON, afterJSON
+
+	changed := []string{}
+	seen := make(map[string]bool)
+	for _, fields := range []map[string]interface{}{beforeFields, afterFields} {
+		for name := range fields {
+			if seen[name] || lawRuleBookkeepingFields[name] {
+				continue
+			}
+			seen[name] = true
+			if !reflect.DeepEqual(beforeFields[name], afterFields[name]) {
+				changed = append(changed, name)
+			}
+		}
+	}
+	sort.Strings(changed)
+	changedJSON, _ := json.Marshal(changed)
+	change.ChangedFields = datatypes.JSON(changedJSON)
+
+	return tx.Create(&change).Error
+}
+
+// lawRuleSnapshot serializes a rule without its associations for the audit trail, returning
+// nothing for a nil rule
+func lawRuleSnapshot(rule *models.LawRule) (datatypes.JSON, map[string]interface{}, error) {
+	if rule == nil {
+		return nil, nil, nil
+	}
+	snapshot := *rule
+	snapshot.Jurisdiction, snapshot.FirearmModel = nil, nil
+	content, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, nil, err
+	}
+	fields := map[string]interface{}{}
+	if err := json.Unmarshal(content, &fields); err != nil {
+		return nil, nil, err
+	}
+	return datatypes.JSON(content), fields, nil
+}
+
+// lawRuleInEffect reports whether a rule applies on a date: on or after its effective date
+// and before its expiry date
+func lawRuleInEffect(rule models.LawRule, at time.Time) bool {
+	if rule.EffectiveFrom != nil && at.Before(*rule.EffectiveFrom) {
+		return false
+	}
+	return rule.EffectiveUntil == nil || at.Before(*rule.EffectiveUntil)
+}
+
+// parseAsOf reads the date a compliance check is run for, either YYYY-MM-DD or RFC 3339.
+// An empty value means now.
+func parseAsOf(value string) (time.Time, error) {
+	if value == "" {
+		return time.Now(), nil
+	}
+	if date, err := time.Parse("2006-01-02", value); err == nil {
+		return date, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
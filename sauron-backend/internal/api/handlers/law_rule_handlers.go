@@ -0,0 +1,355 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sauron-backend/internal/db"
+	"sauron-backend/internal/models"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// errLawRuleVersionConflict is returned when a law rule changed since the edit was based on it
+var errLawRuleVersionConflict = errors.New("law rule version conflict")
+
+// @Summary     Get all law rules
+// @Description Get the firearm law rules of every jurisdiction, or of one jurisdiction without those it inherits, optionally only those in effect on a date
+// @Tags        Laws
+// @Accept      json
+// @Produce     json
+// @Param       jurisdiction query string false "Only rules of this jurisdiction code or ID"
+// @Param       as_of        query string false "Only rules in effect on this date, YYYY-MM-DD or RFC 3339"
+// @Success     200 {array}  models.LawRule
+// @Failure     400 {object} map[string]string
+// @Failure     404 {object} map[string]string
+// @Router      /law-rules [get]
+func GetLawRules(c *gin.Context) {
+	query := db.DB.Preload("Jurisdiction").Order("jurisdiction_id, id")
+	if ref := c.Query("jurisdiction"); ref != "" {
+		jurisdiction, err := resolveJurisdiction(ref)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Jurisdiction not found"})
+			return
+		}
+		query = query.Where("jurisdiction_id = ?", jurisdiction.ID)
+	}
+	if asOfParam := c.Query("as_of"); asOfParam != "" {
+		asOf, err := parseAsOf(asOfParam)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "as_of must be a date (YYYY-MM-DD) or an RFC 3339 timestamp"})
+			return
+		}
+		query = query.Where("(effective_from IS NULL OR effective_from <= ?) AND (effective_until IS NULL OR effective_until > ?)", asOf, asOf)
+	}
+
+	rules := []models.LawRule{}
+	query.Find(&rules)
+	c.JSON(http.StatusOK, rules)
+}
+
+// @Summary     Get a law rule by ID
+// @Description Get a specific law rule with its jurisdiction
+// @Tags        Laws
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "Law Rule ID"
+// @Success     200 {object} models.LawRule
+// @Failure     404 {object} map[string]string
+// @Router      /law-rules/{id} [get]
+func GetLawRuleByID(c *gin.Context) {
+	var rule models.LawRule
+	if err := db.DB.Preload("Jurisdiction").First(&rule, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Law rule not found"})
+		return
+	}
+	c.JSON(http.StatusOK, rule)
+}
+
+// @Summary     Create a law rule
+// @Description Add a firearm law rule to a jurisdiction at version 1 and record who added it in the rule's history
+// @Tags        Laws
+// @Accept      json
+// @Produce     json
+// @Param       X-User          header string true  "Who is making the change; free text, not authenticated"
+// @Param       X-Change-Reason header string false "Why the change is made"
+// @Param       rule body models.LawRule true "Law rule to create"
+// @Success     201 {object} models.LawRule
+// @Failure     400 {object} map[string]string
+// @Router      /law-rules [post]
+func CreateLawRule(c *gin.Context) {
+	changedBy, reason, ok := changeAuthor(c)
+	if !ok {
+		return
+	}
+	var rule models.LawRule
+	if err := c.ShouldBindJSON(&rule); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	rule.ID, rule.Version = 0, 1
+	if !checkLawRule(c, &rule) {
+		return
+	}
+
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&rule).Error; err != nil {
+			return err
+		}
+		return recordLawRuleChange(tx, models.LawRuleChangeCreated, nil, &rule, changedBy, reason)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create law rule"})
+		return
+	}
+	db.DB.Preload("Jurisdiction").First(&rule, rule.ID)
+	c.JSON(http.StatusCreated, rule)
+}
+
+// @Summary     Update a law rule
+// @Description Update an existing law rule, increasing its version and recording what changed in the rule's history along with the author given in X-User. X-User is not authenticated, so the history records who the caller says they are. Fields left out of the body keep their values; a version in the body must match the current one, so edits based on a stale copy are refused. To schedule a change in the law, set effective_until on this rule and add the new rule with a matching effective_from instead.
+// @Tags        Laws
+// @Accept      json
+// @Produce     json
+// @Param       X-User          header string true  "Who is making the change; free text, not authenticated"
+// @Param       X-Change-Reason header string false "Why the change is made"
+// @Param       id path int true "Law Rule ID"
+// @Param       rule body models.LawRule true "Updated law rule"
+// @Success     200 {object} models.LawRule
+// @Failure     400 {object} map[string]string
+// @Failure     404 {object} map[string]string
+// @Failure     409 {object} map[string]string
+// @Router      /law-rules/{id} [put]
+func UpdateLawRule(c *gin.Context) {
+	changedBy, reason, ok := changeAuthor(c)
+	if !ok {
+		return
+	}
+	var current models.LawRule
+	if err := db.DB.First(&current, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Law rule not found"})
+		return
+	}
+	// Fields left out of the body keep the values of the current rule
+	rule := current
+	if err := c.ShouldBindJSON(&rule); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	expectedVersion := rule.Version
+	rule.ID, rule.CreatedAt = current.ID, current.CreatedAt
+	if !checkLawRule(c, &rule) {
+		return
+	}
+
+	// The rule is read again under a row lock so the audit entry's before snapshot is what the
+	// update overwrites, and the update only applies to the version the edit was based on
+	var before models.LawRule
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&before, current.ID).Error; err != nil {
+			return err
+		}
+		if before.Version != expectedVersion {
+			return errLawRuleVersionConflict
+		}
+		rule.Version = before.Version + 1
+		result := tx.Model(&rule).Where("version = ?", before.Version).Select("*").Updates(&rule)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errLawRuleVersionConflict
+		}
+		return recordLawRuleChange(tx, models.LawRuleChangeUpdated, &before, &rule, changedBy, reason)
+	})
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Law rule not found"})
+		return
+	case errors.Is(err, errLawRuleVersionConflict):
+		c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("Law rule was changed by someone else and is now at version %d; reload it and try again", before.Version)})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update law rule"})
+		return
+	}
+	db.DB.Preload("Jurisdiction").First(&rule, rule.ID)
+	c.JSON(http.StatusOK, rule)
+}
+
+// @Summary     Delete a law rule
+// @Description Delete a law rule that was entered in error, keeping its history. Laws that are repealed should be given an effective_until date instead, so checks as of earlier dates still apply them.
+// @Tags        Laws
+// @Accept      json
+// @Produce     json
+// @Param       X-User          header string true  "Who is making the change; free text, not authenticated"
+// @Param       X-Change-Reason header string false "Why the change is made"
+// @Param       id path int true "Law Rule ID"
+// @Success     204 "No Content"
+// @Failure     400 {object} map[string]string
+// @Failure     404 {object} map[string]string
+// @Router      /law-rules/{id} [delete]
+func DeleteLawRule(c *gin.Context) {
+	changedBy, reason, ok := changeAuthor(c)
+	if !ok {
+		return
+	}
+	var rule models.LawRule
+	if err := db.DB.First(&rule, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Law rule not found"})
+		return
+	}
+
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&rule).Error; err != nil {
+			return err
+		}
+		return recordLawRuleChange(tx, models.LawRuleChangeDeleted, &rule, nil, changedBy, reason)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete law rule"})
+		return
+	}
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// @Summary     Get the history of a law rule
+// @Description Get every recorded change to a law rule, oldest first, including for rules that have since been deleted
+// @Tags        Laws
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "Law Rule ID"
+// @Success     200 {array}  models.LawRuleChange
+// @Failure     404 {object} map[string]string
+// @Router      /law-rules/{id}/history [get]
+func GetLawRuleHistory(c *gin.Context) {
+	changes := []models.LawRuleChange{}
+	db.DB.Where("law_rule_id = ?", c.Param("id")).Order("id").Find(&changes)
+	if len(changes) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No history found for law rule"})
+		return
+	}
+	c.JSON(http.StatusOK, changes)
+}
+
+// @Summary     Get the law rule audit trail
+// @Description Get changes to law rules across jurisdictions, newest first, optionally filtered by jurisdiction, author or date
+// @Tags        Laws
+// @Accept      json
+// @Produce     json
+// @Param       jurisdiction query string false "Only changes to rules of this jurisdiction code or ID"
+// @Param       changed_by   query string false "Only changes made by this person or system"
+// @Param       since        query string false "Only changes made on or after this date, YYYY-MM-DD or RFC 3339"
+// @Param       limit        query int    false "Maximum number of changes to return (default 100, max 1000)"
+// @Success     200 {array}  models.LawRuleChange
+// @Failure     400 {object} map[string]string
+// @Failure     404 {object} map[string]string
+// @Router      /law-rule-changes [get]
+func GetLawRuleChanges(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	if err != nil || limit < 1 || limit > 1000 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 1000"})
+		return
+	}
+
+	query := db.DB.Order("id DESC").Limit(limit)
+	if ref := c.Query("jurisdiction"); ref != "" {
+		jurisdiction, err := resolveJurisdiction(ref)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Jurisdiction not found"})
+			return
+		}
+		query = query.Where("jurisdiction_id = ?", jurisdiction.ID)
+	}
+	if changedBy := c.Query("changed_by"); changedBy != "" {
+		query = query.Where("changed_by = ?", changedBy)
+	}
+	if sinceParam := c.Query("since"); sinceParam != "" {
+		since, err := parseAsOf(sinceParam)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "since must be a date (YYYY-MM-DD) or an RFC 3339 timestamp"})
+			return
+		}
+		query = query.Where("created_at >= ?", since)
+	}
+
+	changes := []models.LawRuleChange{}
+	query.Find(&changes)
+	c.JSON(http.StatusOK, changes)
+}
+
+// checkLawRule validates a law rule and clears fields its type does not use, writing a 400
+// response and returning false when it is invalid
+func checkLawRule(c *gin.Context, rule *models.LawRule) bool {
+	rule.Jurisdiction = nil
+	rule.FirearmModel = nil
+
+	if rule.Name == "" || rule.Citation == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "name and citation are required"})
+		return false
+	}
+	var count int64
+	db.DB.Model(&models.Jurisdiction{}).Where("id = ?", rule.JurisdictionID).Count(&count)
+	if count == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Jurisdiction not found"})
+		return false
+	}
+
+	switch rule.Type {
+	case models.LawRuleMinBarrelLength, models.LawRuleMinOverallLength, models.LawRuleMaxMagazineCapacity:
+		if rule.Value <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "value must be positive for " + rule.Type + " rules"})
+			return false
+		}
+		rule.Feature, rule.Features = "", nil
+	case models.LawRuleBannedFeature:
+		if rule.Feature == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "feature is required for banned_feature rules"})
+			return false
+		}
+		rule.Value, rule.Features = 0, nil
+	case models.LawRuleAssaultWeaponFeatures:
+		var features []string
+		if len(rule.Features) > 0 {
+			if err := json.Unmarshal(rule.Features, &features); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "features must be an array of feature names"})
+				return false
+			}
+		}
+		if len(features) == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "features is required for assault_weapon_features rules"})
+			return false
+		}
+		rule.Value, rule.Feature = 0, ""
+	case models.LawRuleSuppressorProhibited:
+		rule.Value, rule.Feature, rule.Features = 0, "", nil
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "type must be one of min_barrel_length, min_overall_length, max_magazine_capacity, banned_feature, assault_weapon_features or suppressor_prohibited"})
+		return false
+	}
+
+	if rule.EffectiveFrom != nil && rule.EffectiveUntil != nil && !rule.EffectiveUntil.After(*rule.EffectiveFrom) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "effective_until must be after effective_from"})
+		return false
+	}
+
+	switch rule.FirearmClass {
+	case "", models.FirearmClassRifle, models.FirearmClassPistol, models.FirearmClassShotgun:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "firearm_class must be rifle, pistol or shotgun"})
+		return false
+	}
+
+	if rule.FirearmModelID != nil {
+		db.DB.Model(&models.FirearmModel{}).Where("id = ?", *rule.FirearmModelID).Count(&count)
+		if count == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Firearm model not found"})
+			return false
+		}
+	}
+	return true
+}
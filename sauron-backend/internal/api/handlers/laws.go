@@ -0,0 +1,560 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sauron-backend/internal/db"
+	"sauron-backend/internal/models"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Outcomes of evaluating a law rule
+const (
+	LawStatusSatisfied    = "satisfied"
+	LawStatusViolated     = "violated"
+	LawStatusUndetermined = "undetermined"
+)
+
+// ErrJurisdictionNotFound is returned when a jurisdiction code or ID matches nothing
+var ErrJurisdictionNotFound = errors.New("jurisdiction not found")
+
+// LawRuleResult is the outcome of one law rule for a firearm
+type LawRuleResult struct {
+	RuleID       int    `json:"rule_id" example:"4"`
+	Jurisdiction string `json:"jurisdiction" example:"US-CA"`
+	Name         string `json:"name" example:"Large-capacity magazine ban"`
+	Type         string `json:"type" example:"max_magazine_capacity"`
+	Status       string `json:"status" example:"violated" enums:"satisfied,violated,undetermined"`
+
+	// What was found, e.g. the offending part
+	Message string `json:"message" example:"PMAG 30-Round (AR-15) holds 30 rounds, more than the limit of 10"`
+
+	// Explanation of the rule
+	Description string `json:"description,omitempty" example:"Magazines holding more than 10 rounds may not be manufactured, imported, sold or possessed"`
+
+	// Part that violates the rule, if a single part does
+	PartID int `json:"part_id,omitempty" example:"42"`
+
+	Citation    string `json:"citation" example:"Cal. Penal Code § 32310"`
+	CitationURL string `json:"citation_url,omitempty" example:"https://leginfo.legislature.ca.gov/faces/codes_displaySection.xhtml?lawCode=PEN&sectionNum=32310"`
+
+	// Bill or regulation behind the rule and the version of the rule that was applied
+	Source  string `json:"source,omitempty" example:"SB 1446 (2016)"`
+	Version int    `json:"version" example:"2"`
+
+	// Dates the rule is in effect, if limited
+	EffectiveFrom  *time.Time `json:"effective_from,omitempty" example:"2017-07-01T00:00:00Z"`
+	EffectiveUntil *time.Time `json:"effective_until,omitempty"`
+}
+
+// LawCheckResult is the outcome of checking a firearm against a jurisdiction's laws,
+// including those of its parent jurisdictions
+type LawCheckResult struct {
+	Jurisdiction     string `json:"jurisdiction" example:"US-CA"`
+	JurisdictionName string `json:"jurisdiction_name" example:"California"`
+
+	// Firearm that was checked; both are omitted for unsaved selections
+	BuildID           int `json:"build_id,omitempty" example:"1"`
+	PrebuiltFirearmID int `json:"prebuilt_firearm_id,omitempty" example:"2"`
+
+	// Firearm class the rules were applied for: rifle, pistol or shotgun as the firearm's NFA
+	// classification has it, firearm for one that is none of them, or its model's category
+	// when the classification is undetermined; empty when that is not known either
+	FirearmClass string `json:"firearm_class" example:"rifle"`
+
+	// Date the laws were checked as of
+	AsOf time.Time `json:"as_of" example:"2025-01-01T00:00:00Z"`
+
+	// Whether no applicable rule in effect is violated; undetermined rules do not count against it
+	Compliant bool `json:"compliant" example:"false"`
+
+	// Number of applicable rules in effect that were evaluated
+	RulesChecked int `json:"rules_checked" example:"9"`
+
+	Violations   []LawRuleResult `json:"violations"`
+	Undetermined []LawRuleResult `json:"undetermined"`
+
+	// Rules that take effect after as_of and that the firearm would violate, soonest first
+	Upcoming []LawRuleResult `json:"upcoming"`
+}
+
+// firearmProfile holds the facts about a firearm that law rules are evaluated against
+type firearmProfile struct {
+	modelID int
+
+	// Class law rules are applied for, derived from the NFA classification once the parts
+	// are known, and the class the model's category gives, which the classification starts from
+	class      string
+	modelClass string
+
+	barrelLength  *float64
+	barrelSource  string
+	overallLength *float64
+	overallSource string
+
+	// Largest magazine selected, if any
+	magazineCapacity *int
+	magazinePart     models.Part
+
+	// How the firearm cycles and is fed, from its model unless a part or, for prebuilt
+	// firearms, the specifications say otherwise; empty when unknown
+	actionType     string
+	actionSource   string
+	magazineType   string
+	magazineSource string
+
+	// Parts that have each feature, by feature name
+	features map[string]models.Part
+
+	// Parts with each NFA role, by NFA tag
+	nfaParts map[string]models.Part
+}
+
+// firearmClassOf maps a firearm model's free-text category, e.g. "Rifles", to a firearm class
+func firearmClassOf(category string) string {
+	normalized := strings.TrimSuffix(strings.ToLower(strings.TrimSpace(category)), "s")
+	switch normalized {
+	case models.FirearmClassRifle, models.FirearmClassPistol, models.FirearmClassShotgun:
+		return normalized
+	case "handgun":
+		return models.FirearmClassPistol
+	}
+	return ""
+}
+
+// newFirearmProfile starts the profile of a firearm of a model
+func newFirearmProfile(modelID int) (*firearmProfile, error) {
+	var model models.FirearmModel
+	if err := db.DB.First(&model, modelID).Error; err != nil {
+		return nil, errFirearmModelNotFound
+	}
+	return &firearmProfile{
+		modelID:        model.ID,
+		class:          firearmClassOf(model.Category),
+		modelClass:     firearmClassOf(model.Category),
+		actionType:     model.ActionType,
+		actionSource:   "firearm model",
+		magazineType:   model.MagazineType,
+		magazineSource: "firearm model",
+		features:       make(map[string]models.Part),
+		nfaParts:       make(map[string]models.Part),
+	}, nil
+}
+
+// profileSelection builds the profile of a slot selection from its parts
+func profileSelection(modelID int, slots map[int]int) (*firearmProfile, error) {
+	profile, err := newFirearmProfile(modelID)
+	if err != nil {
+		return nil, err
+	}
+
+	partIDs := make([]int, 0, len(slots))
+	for _, slotID := range sortedSlotIDs(slots) {
+		partIDs = append(partIDs, slots[slotID])
+	}
+	if err := profile.addParts(partIDs); err != nil {
+		return nil, err
+	}
+	profile.classify()
+	return profile, nil
+}
+
+// profilePrebuilt builds the profile of a prebuilt firearm from the parts its components
+// reference, filling in lengths and magazine capacity from its specifications where the
+// parts do not record them
+func profilePrebuilt(prebuilt models.PrebuiltFirearm) (*firearmProfile, error) {
+	profile, err := newFirearmProfile(prebuilt.FirearmModelID)
+	if err != nil {
+		return nil, err
+	}
+
+	components, err := flattenPrebuiltComponents(prebuilt.Parts)
+	if err != nil {
+		return nil, fmt.Errorf("prebuilt firearm %d has invalid components: %w", prebuilt.ID, err)
+	}
+	partIDs := []int{}
+	for _, component := range components {
+		if component.PartID != nil {
+			partIDs = append(partIDs, *component.PartID)
+		}
+	}
+	if err := profile.addParts(partIDs); err != nil {
+		return nil, err
+	}
+
+	specs := map[string]interface{}{}
+	if len(prebuilt.Specifications) > 0 {
+		_ = json.Unmarshal(prebuilt.Specifications, &specs)
+	}
+	if length, ok := specNumber(specs[models.PartAttributeBarrelLength]); ok && profile.barrelLength == nil {
+		profile.barrelLength, profile.barrelSource = &length, "specifications"
+	}
+	if length, ok := specNumber(specs[models.PartAttributeOverallLength]); ok && profile.overallLength == nil {
+		profile.overallLength, profile.overallSource = &length, "specifications"
+	}
+	if capacity, ok := specNumber(specs["magazine_capacity"]); ok && profile.magazineCapacity == nil {
+		rounds := int(capacity)
+		profile.magazineCapacity = &rounds
+		profile.magazinePart = models.Part{Name: "the supplied magazine"}
+	}
+	if actionType, ok := specs[models.PartAttributeActionType].(string); ok && actionType != "" && profile.actionSource == "firearm model" {
+		profile.actionType, profile.actionSource = actionType, "specifications"
+	}
+	if magazineType, ok := specs[models.PartAttributeMagazineType].(string); ok && magazineType != "" && profile.magazineSource == "firearm model" {
+		profile.magazineType, profile.magazineSource = magazineType, "specifications"
+	}
+	profile.classify()
+	return profile, nil
+}
+
+// classify sets the class law rules are applied for from the firearm's NFA classification, so
+// that e.g. a braced short-barreled build of a rifle model is checked as the pistol it is.
+// Machine guns and undetermined classifications keep the class of the model's category.
+func (p *firearmProfile) classify() {
+	switch classifyNFA(p).Classification {
+	case NFAClassRifle, NFAClassShortBarreledRifle:
+		p.class = models.FirearmClassRifle
+	case NFAClassPistol, NFAClassAnyOtherWeapon:
+		p.class = models.FirearmClassPistol
+	case NFAClassShotgun, NFAClassShortBarreledShotgun:
+		p.class = models.FirearmClassShotgun
+	case NFAClassFirearm:
+		p.class = models.FirearmClassOther
+	default:
+		p.class = p.modelClass
+	}
+}
+
+// nfaTagFeatures maps NFA tags to the law feature they also imply under a different name
+var nfaTagFeatures = map[string]string{
+	models.NFATagVerticalForegrip: models.LawFeatureForwardGrip,
+}
+
+// addParts records the lengths, magazine capacity, action and magazine type, features and NFA
+// roles of the given parts. The shortest barrel and overall length are kept, as they are what
+// length minimums care about, and a fixed magazine wins over a detachable one, as a
+// fixed-magazine kit is what converts a detachable-magazine firearm.
+func (p *firearmProfile) addParts(partIDs []int) error {
+	if len(partIDs) == 0 {
+		return nil
+	}
+	var parts []models.Part
+	if err := db.DB.Where("id IN ?", partIDs).Order("id").Find(&parts).Error; err != nil {
+		return err
+	}
+	magazines, err := loadMagazineCatalog()
+	if err != nil {
+		return err
+	}
+	tree, err := loadCategoryTree()
+	if err != nil {
+		return err
+	}
+
+	for _, part := range parts {
+		if tag := partNFATag(tree, part); tag != "" {
+			if _, seen := p.nfaParts[tag]; !seen {
+				p.nfaParts[tag] = part
+			}
+			// A tagged part has the feature its tag names, whatever its attributes say
+			for _, feature := range []string{tag, nfaTagFeatures[tag]} {
+				if _, seen := p.features[feature]; feature != "" && !seen {
+					p.features[feature] = part
+				}
+			}
+		}
+
+		attributes := map[string]interface{}{}
+		if len(part.Attributes) > 0 {
+			_ = json.Unmarshal(part.Attributes, &attributes)
+		}
+
+		if length, ok := specNumber(attributes[models.PartAttributeBarrelLength]); ok && (p.barrelLength == nil || length < *p.barrelLength) {
+			p.barrelLength, p.barrelSource = &length, part.Name
+		}
+		if length, ok := specNumber(attributes[models.PartAttributeOverallLength]); ok && (p.overallLength == nil || length < *p.overallLength) {
+			p.overallLength, p.overallSource = &length, part.Name
+		}
+
+		capacity, isMagazine := 0, false
+		if entry, ok := magazines.variantByPart[part.ID]; ok {
+			capacity, isMagazine = entry.variant.Capacity, true
+		} else if rounds, ok := specNumber(attributes[models.PartAttributeCapacity]); ok {
+			capacity, isMagazine = int(rounds), true
+		}
+		if isMagazine && (p.magazineCapacity == nil || capacity > *p.magazineCapacity) {
+			p.magazineCapacity, p.magazinePart = &capacity, part
+		}
+
+		if actionType, ok := attributes[models.PartAttributeActionType].(string); ok && actionType != "" {
+			p.actionType, p.actionSource = actionType, part.Name
+		}
+		if magazineType, ok := attributes[models.PartAttributeMagazineType].(string); ok && magazineType != "" &&
+			(p.magazineSource == "firearm model" || magazineType == models.MagazineTypeFixed) {
+			p.magazineType, p.magazineSource = magazineType, part.Name
+		}
+
+		for name, value := range attributes {
+			if present, ok := value.(bool); ok && present {
+				if _, seen := p.features[name]; !seen {
+					p.features[name] = part
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// specNumber reads a number from an attribute or specification value, accepting strings
+// that start with one such as "14.5 inches"
+func specNumber(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case string:
+		fields := strings.Fields(strings.TrimSpace(v))
+		if len(fields) == 0 {
+			return 0, false
+		}
+		number, err := strconv.ParseFloat(strings.TrimRight(fields[0], "\"in"), 64)
+		return number, err == nil
+	}
+	return 0, false
+}
+
+// resolveJurisdiction looks a jurisdiction up by ID or case-insensitive code
+func resolveJurisdiction(ref string) (*models.Jurisdiction, error) {
+	var jurisdiction models.Jurisdiction
+	query := db.DB.Where("UPPER(code) = ?", strings.ToUpper(strings.TrimSpace(ref)))
+	if id, err := strconv.Atoi(ref); err == nil {
+		query = db.DB.Where("id = ?", id)
+	}
+	if err := query.First(&jurisdiction).Error; err != nil {
+		return nil, ErrJurisdictionNotFound
+	}
+	return &jurisdiction, nil
+}
+
+// jurisdictionChain returns a jurisdiction followed by its ancestors, nearest first
+func jurisdictionChain(jurisdiction models.Jurisdiction) ([]models.Jurisdiction, error) {
+	chain := []models.Jurisdiction{jurisdiction}
+	seen := map[int]bool{jurisdiction.ID: true}
+	for current := jurisdiction; current.ParentJurisdictionID != nil; {
+		var parent models.Jurisdiction
+		if err := db.DB.First(&parent, *current.ParentJurisdictionID).Error; err != nil {
+			return nil, err
+		}
+		if seen[parent.ID] {
+			break
+		}
+		seen[parent.ID] = true
+		chain = append(chain, parent)
+		current = parent
+	}
+	return chain, nil
+}
+
+// checkLaws evaluates every rule of a jurisdiction and its ancestors that applies to the
+// firearm and is in effect on asOf, outermost jurisdiction first. Rules that take effect later
+// are evaluated too and reported as upcoming when the firearm would violate them.
+func checkLaws(jurisdiction models.Jurisdiction, profile *firearmProfile, asOf time.Time) (*LawCheckResult, error) {
+	chain, err := jurisdictionChain(jurisdiction)
+	if err != nil {
+		return nil, err
+	}
+	codes := make(map[int]string, len(chain))
+	ids := make([]int, 0, len(chain))
+	for i := len(chain) - 1; i >= 0; i-- {
+		codes[chain[i].ID] = chain[i].Code
+		ids = append(ids, chain[i].ID)
+	}
+
+	var rules []models.LawRule
+	if err := db.DB.Where("jurisdiction_id IN ?", ids).Order("id").Find(&rules).Error; err != nil {
+		return nil, err
+	}
+
+	result := &LawCheckResult{
+		Jurisdiction:     jurisdiction.Code,
+		JurisdictionName: jurisdiction.Name,
+		FirearmClass:     profile.class,
+		AsOf:             asOf,
+		Compliant:        true,
+		Violations:       []LawRuleResult{},
+		Undetermined:     []LawRuleResult{},
+		Upcoming:         []LawRuleResult{},
+	}
+	for _, id := range ids {
+		for _, rule := range rules {
+			if rule.JurisdictionID != id || !lawRuleApplies(rule, profile) {
+				continue
+			}
+			inEffect := lawRuleInEffect(rule, asOf)
+			upcoming := !inEffect && rule.EffectiveFrom != nil && rule.EffectiveFrom.After(asOf)
+			if !inEffect && !upcoming {
+				continue
+			}
+
+			outcome := evaluateLawRule(rule, profile)
+			outcome.Jurisdiction = codes[rule.JurisdictionID]
+			if upcoming {
+				if outcome.Status == LawStatusViolated {
+					result.Upcoming = append(result.Upcoming, outcome)
+				}
+				continue
+			}
+
+			result.RulesChecked++
+			switch outcome.Status {
+			case LawStatusViolated:
+				result.Compliant = false
+				result.Violations = append(result.Violations, outcome)
+			case LawStatusUndetermined:
+				result.Undetermined = append(result.Undetermined, outcome)
+			}
+		}
+	}
+	sort.SliceStable(result.Upcoming, func(i, j int) bool {
+		return result.Upcoming[i].EffectiveFrom.Before(*result.Upcoming[j].EffectiveFrom)
+	})
+	return result, nil
+}
+
+// lawRuleApplies reports whether a rule's firearm class and model limits cover the firearm.
+// A firearm of unknown class is covered by class-limited rules so they are not silently
+// skipped; they then come out undetermined.
+func lawRuleApplies(rule models.LawRule, profile *firearmProfile) bool {
+	if rule.FirearmModelID != nil && *rule.FirearmModelID != profile.modelID {
+		return false
+	}
+	return rule.FirearmClass == "" || profile.class == "" || rule.FirearmClass == profile.class
+}
+
+// evaluateLawRule applies one rule to a firearm
+func evaluateLawRule(rule models.LawRule, profile *firearmProfile) LawRuleResult {
+	outcome := LawRuleResult{
+		RuleID:         rule.ID,
+		Name:           rule.Name,
+		Type:           rule.Type,
+		Status:         LawStatusSatisfied,
+		Description:    rule.Description,
+		Citation:       rule.Citation,
+		CitationURL:    rule.CitationURL,
+		Source:         rule.Source,
+		Version:        rule.Version,
+		EffectiveFrom:  rule.EffectiveFrom,
+		EffectiveUntil: rule.EffectiveUntil,
+	}
+	if rule.FirearmClass != "" && profile.class == "" {
+		outcome.Status = LawStatusUndetermined
+		outcome.Message = fmt.Sprintf("The rule only applies to %ss and the firearm's class is not known", rule.FirearmClass)
+		return outcome
+	}
+
+	switch rule.Type {
+	case models.LawRuleMinBarrelLength, models.LawRuleMinOverallLength:
+		length, source, what := profile.barrelLength, profile.barrelSource, "Barrel length"
+		if rule.Type == models.LawRuleMinOverallLength {
+			length, source, what = profile.overallLength, profile.overallSource, "Overall length"
+		}
+		switch {
+		case length == nil:
+			outcome.Status = LawStatusUndetermined
+			outcome.Message = fmt.Sprintf("%s is not recorded for any part", what)
+		case *length < rule.Value:
+			outcome.Status = LawStatusViolated
+			outcome.Message = fmt.Sprintf("%s is %g in (%s), below the %g in minimum", what, *length, source, rule.Value)
+		default:
+			outcome.Message = fmt.Sprintf("%s is %g in, at least the %g in minimum", what, *length, rule.Value)
+		}
+
+	case models.LawRuleMaxMagazineCapacity:
+		switch {
+		case profile.magazineCapacity == nil:
+			outcome.Message = "No magazine is included"
+		case float64(*profile.magazineCapacity) > rule.Value:
+			outcome.Status = LawStatusViolated
+			outcome.PartID = profile.magazinePart.ID
+			outcome.Message = fmt.Sprintf("%s holds %d rounds, more than the limit of %g", profile.magazinePart.Name, *profile.magazineCapacity, rule.Value)
+		default:
+			outcome.Message = fmt.Sprintf("Largest magazine holds %d rounds, within the limit of %g", *profile.magazineCapacity, rule.Value)
+		}
+
+	case models.LawRuleBannedFeature, models.LawRuleSuppressorProhibited:
+		feature := rule.Feature
+		if rule.Type == models.LawRuleSuppressorProhibited {
+			feature = models.LawFeatureSuppressor
+		}
+		if part, ok := profile.features[feature]; ok {
+			outcome.Status = LawStatusViolated
+			outcome.PartID = part.ID
+			outcome.Message = fmt.Sprintf("%s has the prohibited feature %s", part.Name, strings.ReplaceAll(feature, "_", " "))
+		} else {
+			outcome.Message = fmt.Sprintf("No part has the feature %s", strings.ReplaceAll(feature, "_", " "))
+		}
+
+	case models.LawRuleAssaultWeaponFeatures:
+		evaluateAssaultWeaponFeatures(rule, profile, &outcome)
+
+	default:
+		outcome.Status = LawStatusUndetermined
+		outcome.Message = fmt.Sprintf("Unknown rule type %s", rule.Type)
+	}
+	return outcome
+}
+
+// evaluateAssaultWeaponFeatures applies an assault weapon feature rule: the listed features
+// are only prohibited on a semi-automatic firearm that takes a detachable magazine, so a
+// featured firearm with another action or a fixed magazine satisfies the rule
+func evaluateAssaultWeaponFeatures(rule models.LawRule, profile *firearmProfile, outcome *LawRuleResult) {
+	features := lawRuleFeatures(rule)
+	var found []string
+	var first models.Part
+	for _, feature := range features {
+		if part, ok := profile.features[feature]; ok {
+			if len(found) == 0 {
+				first = part
+			}
+			found = append(found, fmt.Sprintf("%s (%s)", strings.ReplaceAll(feature, "_", " "), part.Name))
+		}
+	}
+	if len(found) == 0 {
+		names := make([]string, len(features))
+		for i, feature := range features {
+			names[i] = strings.ReplaceAll(feature, "_", " ")
+		}
+		outcome.Message = fmt.Sprintf("No part has any of the features %s", strings.Join(names, ", "))
+		return
+	}
+	has := strings.Join(found, ", ")
+
+	switch {
+	case profile.actionType == "":
+		outcome.Status = LawStatusUndetermined
+		outcome.Message = fmt.Sprintf("The firearm has %s, but its action type is not known", has)
+	case profile.actionType != models.ActionTypeSemiAuto:
+		outcome.Message = fmt.Sprintf("The firearm has %s, but is not semi-automatic (%s action, from %s)", has, profile.actionType, profile.actionSource)
+	case profile.magazineType == "":
+		outcome.Status = LawStatusUndetermined
+		outcome.Message = fmt.Sprintf("The semi-automatic firearm has %s, but whether its magazine is detachable is not known", has)
+	case profile.magazineType != models.MagazineTypeDetachable:
+		outcome.Message = fmt.Sprintf("The firearm has %s, but its magazine is not detachable (%s magazine, from %s)", has, profile.magazineType, profile.magazineSource)
+	default:
+		outcome.Status = LawStatusViolated
+		outcome.PartID = first.ID
+		outcome.Message = fmt.Sprintf("The firearm is semi-automatic with a detachable magazine and has the prohibited %s", has)
+	}
+}
+
+// lawRuleFeatures reads the features an assault weapon feature rule lists
+func lawRuleFeatures(rule models.LawRule) []string {
+	features := []string{}
+	if len(rule.Features) > 0 {
+		_ = json.Unmarshal(rule.Features, &features)
+	}
+	return features
+}
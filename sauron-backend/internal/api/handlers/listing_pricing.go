@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"encoding/json"
+	"math"
+	"sauron-backend/internal/db"
+	"sauron-backend/internal/models"
+	"sort"
+)
+
+// listingShippingInfo mirrors the ShippingInfo JSON stored on product listings
+type listingShippingInfo struct {
+	FreeShipping bool    `json:"free_shipping"`
+	ShippingCost float64 `json:"shipping_cost"`
+	HandlingTime string  `json:"handling_time"`
+}
+
+// inStockAvailabilities are the availability values that can be bought right now
+var inStockAvailabilities = []string{"in_stock", "low_stock", "limited_stock"}
+
+// listingShipping returns the shipping charged for a listing, which is zero when
+// the listing offers free shipping or has no shipping information
+func listingShipping(listing models.ProductListing) float64 {
+	if len(listing.ShippingInfo) == 0 {
+		return 0
+	}
+
+	var info listingShippingInfo
+	if err := json.Unmarshal(listing.ShippingInfo, &info); err != nil || info.FreeShipping {
+		return 0
+	}
+	return info.ShippingCost
+}
+
+// landedPrice is the price of a listing including its shipping
+func landedPrice(listing models.ProductListing) float64 {
+	return listing.Price + listingShipping(listing)
+}
+
+// loadInStockListingsByPart fetches the in-stock listings for a set of parts, with their sellers,
+// grouped by part ID and ordered from cheapest to most expensive landed price
+func loadInStockListingsByPart(partIDs []int) (map[int][]models.ProductListing, error) {
+	byPart := make(map[int][]models.ProductListing)
+	if len(partIDs) == 0 {
+		return byPart, nil
+	}
+
+	var listings []models.ProductListing
+	err := db.DB.Preload("Seller").
+		Where("part_id IN ?", partIDs).
+		Where("availability IN ?", inStockAvailabilities).
+		Find(&listings).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, listing := range listings {
+		byPart[*listing.PartID] = append(byPart[*listing.PartID], listing)
+	}
+	for partID := range byPart {
+		sortListingsByLandedPrice(byPart[partID])
+	}
+	return byPart, nil
+}
+
+// sortListingsByLandedPrice orders listings from cheapest to most expensive landed price,
+// breaking ties by listing ID so results are stable
+func sortListingsByLandedPrice(listings []models.ProductListing) {
+	sort.SliceStable(listings, func(i, j int) bool {
+		pi, pj := landedPrice(listings[i]), landedPrice(listings[j])
+		if pi != pj {
+			return pi < pj
+		}
+		return listings[i].ID < listings[j].ID
+	})
+}
+
+// roundCents rounds a currency amount to two decimal places
+func roundCents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
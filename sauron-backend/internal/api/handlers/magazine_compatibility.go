@@ -0,0 +1,242 @@
+package handlers
+
+import (
+	"sauron-backend/internal/db"
+	"sauron-backend/internal/models"
+	"sort"
+)
+
+// MagazineFit is a magazine variant that fits a firearm
+type MagazineFit struct {
+	MagazineFamilyID int    `json:"magazine_family_id" example:"1"`
+	MagazineFamily   string `json:"magazine_family" example:"STANAG"`
+
+	VariantID int `json:"variant_id" example:"3"`
+	Capacity  int `json:"capacity" example:"30"`
+
+	// Caliber the variant is made for, empty when it feeds every caliber of its family
+	CaliberID *int   `json:"caliber_id,omitempty" example:"2"`
+	Caliber   string `json:"caliber,omitempty" example:"5.56x45mm NATO"`
+
+	// Catalog part sold as the variant, if any
+	PartID   *int   `json:"part_id,omitempty" example:"45"`
+	PartName string `json:"part_name,omitempty" example:"PMAG 30-Round (AR-15)"`
+}
+
+// MagazineMatrixRow lists the capacities of a magazine family for one firearm model and caliber
+type MagazineMatrixRow struct {
+	MagazineFamilyID int    `json:"magazine_family_id" example:"1"`
+	MagazineFamily   string `json:"magazine_family" example:"STANAG"`
+	FirearmModelID   int    `json:"firearm_model_id" example:"1"`
+	FirearmModel     string `json:"firearm_model" example:"AR-15"`
+	CaliberID        int    `json:"caliber_id" example:"2"`
+	Caliber          string `json:"caliber" example:"5.56x45mm NATO"`
+
+	// Capacities available for the caliber, smallest first
+	Capacities []int `json:"capacities" example:"10,20,30,40"`
+}
+
+// magazineCatalog holds every magazine family with its models, calibers and variants
+type magazineCatalog struct {
+	families       []models.MagazineFamily
+	variantByPart  map[int]magazineEntry
+	calibers       *caliberIndex
+	modelsByFamily map[int]map[int]bool
+}
+
+// magazineEntry is a variant together with the family it belongs to
+type magazineEntry struct {
+	family  *models.MagazineFamily
+	variant models.MagazineVariant
+}
+
+// loadMagazineCatalog loads every magazine family and indexes its variants by catalog part
+func loadMagazineCatalog() (*magazineCatalog, error) {
+	var families []models.MagazineFamily
+	if err := db.DB.Preload("FirearmModels").Preload("Calibers").Preload("Variants.Part").Order("id").Find(&families).Error; err != nil {
+		return nil, err
+	}
+	calibers, err := loadCaliberIndex()
+	if err != nil {
+		return nil, err
+	}
+
+	catalog := &magazineCatalog{
+		families:       families,
+		variantByPart:  make(map[int]magazineEntry),
+		calibers:       calibers,
+		modelsByFamily: make(map[int]map[int]bool, len(families)),
+	}
+	for i := range catalog.families {
+		family := &catalog.families[i]
+		sort.Slice(family.Variants, func(a, b int) bool {
+			if family.Variants[a].Capacity != family.Variants[b].Capacity {
+				return family.Variants[a].Capacity < family.Variants[b].Capacity
+			}
+			return family.Variants[a].ID < family.Variants[b].ID
+		})
+		catalog.modelsByFamily[family.ID] = make(map[int]bool, len(family.FirearmModels))
+		for _, model := range family.FirearmModels {
+			catalog.modelsByFamily[family.ID][model.ID] = true
+		}
+		for _, variant := range family.Variants {
+			if variant.PartID != nil {
+				catalog.variantByPart[*variant.PartID] = magazineEntry{family: family, variant: variant}
+			}
+		}
+	}
+	return catalog, nil
+}
+
+// variantCalibers returns the calibers a variant feeds: its own caliber, or every caliber of
+// its family when it has none
+func (m *magazineCatalog) variantCalibers(entry magazineEntry) []int {
+	if entry.variant.CaliberID != nil {
+		return []int{*entry.variant.CaliberID}
+	}
+	ids := make([]int, 0, len(entry.family.Calibers))
+	for _, caliber := range entry.family.Calibers {
+		ids = append(ids, caliber.ID)
+	}
+	return ids
+}
+
+// feeds reports whether a variant feeds a chamber of one of the given calibers: it is made for
+// the chamber's caliber or for a caliber that chamber can safely fire. An empty chamber set
+// means the caliber is not known, and any variant is accepted.
+func (m *magazineCatalog) feeds(entry magazineEntry, chamberCaliberIDs []int) bool {
+	if len(chamberCaliberIDs) == 0 {
+		return true
+	}
+	fed := make(map[int]bool)
+	for _, id := range m.variantCalibers(entry) {
+		fed[id] = true
+	}
+	for _, chamberID := range chamberCaliberIDs {
+		if fed[chamberID] {
+			return true
+		}
+		for _, fired := range m.calibers.byID[chamberID].FiresCalibers {
+			if fed[fired.ID] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// fits reports whether a variant fits a firearm model chambered in one of the given calibers
+func (m *magazineCatalog) fits(entry magazineEntry, modelID int, chamberCaliberIDs []int) bool {
+	return m.modelsByFamily[entry.family.ID][modelID] && m.feeds(entry, chamberCaliberIDs)
+}
+
+// fitsFor returns every variant that fits a firearm model chambered in one of the given
+// calibers, grouped by family and ordered by capacity
+func (m *magazineCatalog) fitsFor(modelID int, chamberCaliberIDs []int) []MagazineFit {
+	fits := []MagazineFit{}
+	for i := range m.families {
+		family := &m.families[i]
+		for _, variant := range family.Variants {
+			entry := magazineEntry{family: family, variant: variant}
+			if m.fits(entry, modelID, chamberCaliberIDs) {
+				fits = append(fits, m.fit(entry))
+			}
+		}
+	}
+	return fits
+}
+
+// fit describes a variant in a response
+func (m *magazineCatalog) fit(entry magazineEntry) MagazineFit {
+	fit := MagazineFit{
+		MagazineFamilyID: entry.family.ID,
+		MagazineFamily:   entry.family.Name,
+		VariantID:        entry.variant.ID,
+		Capacity:         entry.variant.Capacity,
+		CaliberID:        entry.variant.CaliberID,
+		PartID:           entry.variant.PartID,
+	}
+	if entry.variant.CaliberID != nil {
+		fit.Caliber = m.calibers.byID[*entry.variant.CaliberID].Name
+	}
+	if entry.variant.Part != nil {
+		fit.PartName = entry.variant.Part.Name
+	}
+	return fit
+}
+
+// matrix flattens the catalog into one row per family, firearm model and caliber
+func (m *magazineCatalog) matrix(modelID int, caliberIDs []int) []MagazineMatrixRow {
+	wantCaliber := make(map[int]bool, len(caliberIDs))
+	for _, id := range caliberIDs {
+		wantCaliber[id] = true
+	}
+
+	rows := []MagazineMatrixRow{}
+	for i := range m.families {
+		family := &m.families[i]
+		for _, model := range family.FirearmModels {
+			if modelID != 0 && model.ID != modelID {
+				continue
+			}
+			for _, caliber := range family.Calibers {
+				if caliberIDs != nil && !wantCaliber[caliber.ID] {
+					continue
+				}
+
+				seen := make(map[int]bool)
+				capacities := []int{}
+				for _, variant := range family.Variants {
+					if (variant.CaliberID == nil || *variant.CaliberID == caliber.ID) && !seen[variant.Capacity] {
+						seen[variant.Capacity] = true
+						capacities = append(capacities, variant.Capacity)
+					}
+				}
+
+				rows = append(rows, MagazineMatrixRow{
+					MagazineFamilyID: family.ID,
+					MagazineFamily:   family.Name,
+					FirearmModelID:   model.ID,
+					FirearmModel:     model.Name,
+					CaliberID:        caliber.ID,
+					Caliber:          caliber.Name,
+					Capacities:       capacities,
+				})
+			}
+		}
+	}
+	return rows
+}
+
+// selectionChamberCalibers returns the calibers a slot selection is chambered in: those of its
+// caliber-specific parts other than magazines, or the firearm model's default caliber when no
+// such part is selected. Parts that do not exist are ignored.
+func selectionChamberCalibers(modelID int, slots map[int]int, catalog *magazineCatalog) ([]int, error) {
+	partIDs := make([]int, 0, len(slots))
+	for _, slotID := range sortedSlotIDs(slots) {
+		if _, isMagazine := catalog.variantByPart[slots[slotID]]; !isMagazine {
+			partIDs = append(partIDs, slots[slotID])
+		}
+	}
+
+	var caliberIDs []int
+	if len(partIDs) > 0 {
+		if err := db.DB.Model(&models.Part{}).
+			Where("id IN ? AND caliber_id IS NOT NULL", partIDs).
+			Distinct().Order("caliber_id").Pluck("caliber_id", &caliberIDs).Error; err != nil {
+			return nil, err
+		}
+	}
+	if len(caliberIDs) > 0 {
+		return caliberIDs, nil
+	}
+
+	var model models.FirearmModel
+	if err := db.DB.Select("id", "caliber_id").First(&model, modelID).Error; err != nil {
+		return nil, errFirearmModelNotFound
+	}
+	if model.CaliberID != nil {
+		return []int{*model.CaliberID}, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,219 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"sauron-backend/internal/db"
+	"sauron-backend/internal/models"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BuildMagazinesInput is the request body for finding the magazines of an unsaved slot selection
+type BuildMagazinesInput struct {
+	// Firearm model the selection is for
+	FirearmModelID int `json:"firearm_model_id" binding:"required" example:"1"`
+
+	// Selected parts keyed by part category (slot) ID
+	Slots map[int]int `json:"slots"`
+}
+
+// BuildMagazinesResult lists the magazines that fit a build
+type BuildMagazinesResult struct {
+	// Build that was checked, omitted for unsaved selections
+	BuildID        int `json:"build_id,omitempty" example:"1"`
+	FirearmModelID int `json:"firearm_model_id" example:"1"`
+
+	// Calibers the build is chambered in, from its parts or else its firearm model
+	CaliberIDs []int `json:"caliber_ids" example:"2"`
+
+	Magazines []MagazineFit `json:"magazines"`
+}
+
+// MagazineFirearmsResult lists the guns that take a magazine part
+type MagazineFirearmsResult struct {
+	PartID   int    `json:"part_id" example:"45"`
+	PartName string `json:"part_name" example:"PMAG 30-Round (AR-15)"`
+
+	// Magazine variant the part is sold as
+	Magazine MagazineFit `json:"magazine"`
+
+	// Calibers the magazine feeds
+	CaliberIDs []int `json:"caliber_ids" example:"1,2"`
+
+	FirearmModels    []models.FirearmModel    `json:"firearm_models"`
+	PrebuiltFirearms []models.PrebuiltFirearm `json:"prebuilt_firearms"`
+}
+
+// @Summary     Get the magazine compatibility matrix
+// @Description Get one row per magazine family, firearm model and caliber with the capacities available, optionally for a single firearm model or caliber
+// @Tags        Magazines
+// @Accept      json
+// @Produce     json
+// @Param       firearm_model_id query int false "Only rows for this firearm model"
+// @Param       caliber query string false "Only rows for this caliber ID, name or alias"
+// @Success     200 {array} MagazineMatrixRow
+// @Failure     400 {object} map[string]string
+// @Failure     500 {object} map[string]string
+// @Router      /magazine-compatibility [get]
+func GetMagazineCompatibilityMatrix(c *gin.Context) {
+	modelID := 0
+	if modelParam := c.Query("firearm_model_id"); modelParam != "" {
+		var err error
+		if modelID, err = strconv.Atoi(modelParam); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid firearm model ID"})
+			return
+		}
+	}
+	caliberIDs, err := resolveCaliberQuery(c)
+	if err != nil {
+		respondToCaliberQueryError(c, err)
+		return
+	}
+
+	catalog, err := loadMagazineCatalog()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load magazines"})
+		return
+	}
+	c.JSON(http.StatusOK, catalog.matrix(modelID, caliberIDs))
+}
+
+// @Summary     Get the magazines that fit a build
+// @Description Get the magazine variants whose family a saved build's firearm model takes and that feed the caliber the build is chambered in
+// @Tags        Builds,Magazines
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "Build ID"
+// @Success     200 {object} BuildMagazinesResult
+// @Failure     404 {object} map[string]string
+// @Failure     500 {object} map[string]string
+// @Router      /builds/{id}/magazines [get]
+func GetBuildMagazines(c *gin.Context) {
+	build, err := loadBuild(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Build not found"})
+		return
+	}
+
+	result, err := magazinesForSelection(build.FirearmModelID, buildSlots(build))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to find magazines"})
+		return
+	}
+	result.BuildID = build.ID
+	c.JSON(http.StatusOK, result)
+}
+
+// @Summary     Find the magazines that fit a slot selection
+// @Description Get the magazine variants whose family the firearm model takes and that feed the caliber an unsaved slot selection is chambered in
+// @Tags        Builds,Magazines
+// @Accept      json
+// @Produce     json
+// @Param       selection body BuildMagazinesInput true "Slot selection"
+// @Success     200 {object} BuildMagazinesResult
+// @Failure     400 {object} map[string]string
+// @Failure     404 {object} map[string]string
+// @Failure     500 {object} map[string]string
+// @Router      /builds/magazines [post]
+func FindBuildSelectionMagazines(c *gin.Context) {
+	var input BuildMagazinesInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := magazinesForSelection(input.FirearmModelID, input.Slots)
+	if errors.Is(err, errFirearmModelNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Firearm model not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to find magazines"})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// @Summary     Get the guns that take a magazine
+// @Description Get the firearm models whose magazine well takes a magazine part's family, and the prebuilt firearms of those models chambered in a caliber the magazine feeds or with no caliber recorded
+// @Tags        Parts,Magazines
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "Part ID of the magazine"
+// @Success     200 {object} MagazineFirearmsResult
+// @Failure     404 {object} map[string]string
+// @Failure     500 {object} map[string]string
+// @Router      /parts/{id}/firearms [get]
+func GetMagazineFirearms(c *gin.Context) {
+	var part models.Part
+	if err := db.DB.First(&part, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Part not found"})
+		return
+	}
+
+	catalog, err := loadMagazineCatalog()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load magazines"})
+		return
+	}
+	entry, ok := catalog.variantByPart[part.ID]
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Part is not a variant of any magazine family"})
+		return
+	}
+
+	result := MagazineFirearmsResult{
+		PartID:           part.ID,
+		PartName:         part.Name,
+		Magazine:         catalog.fit(entry),
+		CaliberIDs:       catalog.variantCalibers(entry),
+		FirearmModels:    []models.FirearmModel{},
+		PrebuiltFirearms: []models.PrebuiltFirearm{},
+	}
+
+	// Models are chambered per build, so only the prebuilts are filtered by caliber
+	modelIDs := []int{}
+	for _, model := range entry.family.FirearmModels {
+		result.FirearmModels = append(result.FirearmModels, model)
+		modelIDs = append(modelIDs, model.ID)
+	}
+
+	if len(modelIDs) > 0 {
+		var prebuilts []models.PrebuiltFirearm
+		if err := db.DB.Preload("Caliber").Where("firearm_model_id IN ?", modelIDs).Order("id").Find(&prebuilts).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load prebuilt firearms"})
+			return
+		}
+		for _, prebuilt := range prebuilts {
+			if prebuilt.CaliberID == nil || catalog.feeds(entry, []int{*prebuilt.CaliberID}) {
+				result.PrebuiltFirearms = append(result.PrebuiltFirearms, prebuilt)
+			}
+		}
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// magazinesForSelection finds the magazine variants that fit a firearm model chambered as the
+// slot selection is
+func magazinesForSelection(modelID int, slots map[int]int) (*BuildMagazinesResult, error) {
+	catalog, err := loadMagazineCatalog()
+	if err != nil {
+		return nil, err
+	}
+	caliberIDs, err := selectionChamberCalibers(modelID, slots, catalog)
+	if err != nil {
+		return nil, err
+	}
+	if caliberIDs == nil {
+		caliberIDs = []int{}
+	}
+
+	return &BuildMagazinesResult{
+		FirearmModelID: modelID,
+		CaliberIDs:     caliberIDs,
+		Magazines:      catalog.fitsFor(modelID, caliberIDs),
+	}, nil
+}
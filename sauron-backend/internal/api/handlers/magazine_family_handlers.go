@@ -0,0 +1,348 @@
+package handlers
+
+import (
+	"net/http"
+	"sauron-backend/internal/db"
+	"sauron-backend/internal/models"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// MagazineFamilyInput is the request body for creating or updating a magazine family
+type MagazineFamilyInput struct {
+	Name        string `json:"name" binding:"required" example:"STANAG"`
+	Description string `json:"description" example:"NATO STANAG 4179 pattern AR-15/M16 box magazine"`
+
+	// Firearm models whose magazine well takes the family
+	FirearmModelIDs []int `json:"firearm_model_ids" example:"1"`
+
+	// Calibers the family feeds
+	CaliberIDs []int `json:"caliber_ids" example:"1,2"`
+}
+
+// MagazineVariantInput is the request body for adding or updating a magazine variant
+type MagazineVariantInput struct {
+	Capacity int `json:"capacity" binding:"required" example:"30"`
+
+	// Caliber the variant is made for, one of its family's calibers; omit when it feeds them all
+	CaliberID *int `json:"caliber_id" example:"2"`
+
+	// Catalog part sold as the variant
+	PartID *int `json:"part_id" example:"45"`
+
+	Notes string `json:"notes" example:"Gen M3 with window"`
+}
+
+// @Summary     Get all magazine families
+// @Description Get every magazine family with the firearm models that take it, the calibers it feeds and its capacity variants
+// @Tags        Magazines
+// @Accept      json
+// @Produce     json
+// @Success     200 {array} models.MagazineFamily
+// @Router      /magazine-families [get]
+func GetMagazineFamilies(c *gin.Context) {
+	families := []models.MagazineFamily{}
+	db.DB.Preload("FirearmModels").Preload("Calibers").Preload("Variants").Order("id").Find(&families)
+	c.JSON(http.StatusOK, families)
+}
+
+// @Summary     Get a magazine family by ID
+// @Description Get a magazine family with its firearm models, calibers and capacity variants
+// @Tags        Magazines
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "Magazine Family ID"
+// @Success     200 {object} models.MagazineFamily
+// @Failure     404 {object} map[string]string
+// @Router      /magazine-families/{id} [get]
+func GetMagazineFamilyByID(c *gin.Context) {
+	var family models.MagazineFamily
+	if err := db.DB.Preload("FirearmModels").Preload("Calibers").Preload("Variants.Part").First(&family, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Magazine family not found"})
+		return
+	}
+	c.JSON(http.StatusOK, family)
+}
+
+// @Summary     Create a magazine family
+// @Description Add a magazine family with the firearm models that take it and the calibers it feeds
+// @Tags        Magazines
+// @Accept      json
+// @Produce     json
+// @Param       family body MagazineFamilyInput true "Magazine family"
+// @Success     201 {object} models.MagazineFamily
+// @Failure     400 {object} map[string]string
+// @Failure     409 {object} map[string]string
+// @Router      /magazine-families [post]
+func CreateMagazineFamily(c *gin.Context) {
+	var input MagazineFamilyInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var family models.MagazineFamily
+	if !saveMagazineFamily(c, &family, input) {
+		return
+	}
+	c.JSON(http.StatusCreated, family)
+}
+
+// @Summary     Update a magazine family
+// @Description Update a magazine family, replacing its firearm models and calibers
+// @Tags        Magazines
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "Magazine Family ID"
+// @Param       family body MagazineFamilyInput true "Updated magazine family"
+// @Success     200 {object} models.MagazineFamily
+// @Failure     400 {object} map[string]string
+// @Failure     404 {object} map[string]string
+// @Failure     409 {object} map[string]string
+// @Router      /magazine-families/{id} [put]
+func UpdateMagazineFamily(c *gin.Context) {
+	var family models.MagazineFamily
+	if err := db.DB.First(&family, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Magazine family not found"})
+		return
+	}
+
+	var input MagazineFamilyInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if !saveMagazineFamily(c, &family, input) {
+		return
+	}
+	c.JSON(http.StatusOK, family)
+}
+
+// @Summary     Delete a magazine family
+// @Description Delete a magazine family together with its capacity variants
+// @Tags        Magazines
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "Magazine Family ID"
+// @Success     204 "No Content"
+// @Failure     404 {object} map[string]string
+// @Failure     500 {object} map[string]string
+// @Router      /magazine-families/{id} [delete]
+func DeleteMagazineFamily(c *gin.Context) {
+	var family models.MagazineFamily
+	if err := db.DB.First(&family, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Magazine family not found"})
+		return
+	}
+
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&family).Association("FirearmModels").Clear(); err != nil {
+			return err
+		}
+		if err := tx.Model(&family).Association("Calibers").Clear(); err != nil {
+			return err
+		}
+		if err := tx.Where("magazine_family_id = ?", family.ID).Delete(&models.MagazineVariant{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&family).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete magazine family"})
+		return
+	}
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// @Summary     Get a magazine family's variants
+// @Description Get the capacity variants of a magazine family, smallest first
+// @Tags        Magazines
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "Magazine Family ID"
+// @Success     200 {array} models.MagazineVariant
+// @Failure     404 {object} map[string]string
+// @Router      /magazine-families/{id}/variants [get]
+func GetMagazineVariants(c *gin.Context) {
+	var family models.MagazineFamily
+	if err := db.DB.First(&family, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Magazine family not found"})
+		return
+	}
+
+	variants := []models.MagazineVariant{}
+	db.DB.Preload("Caliber").Preload("Part").Where("magazine_family_id = ?", family.ID).Order("capacity, id").Find(&variants)
+	c.JSON(http.StatusOK, variants)
+}
+
+// @Summary     Add a magazine variant
+// @Description Add a capacity variant to a magazine family, optionally for one of its calibers and tied to a catalog part
+// @Tags        Magazines
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "Magazine Family ID"
+// @Param       variant body MagazineVariantInput true "Magazine variant"
+// @Success     201 {object} models.MagazineVariant
+// @Failure     400 {object} map[string]string
+// @Failure     404 {object} map[string]string
+// @Failure     409 {object} map[string]string
+// @Router      /magazine-families/{id}/variants [post]
+func CreateMagazineVariant(c *gin.Context) {
+	var family models.MagazineFamily
+	if err := db.DB.Preload("Calibers").First(&family, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Magazine family not found"})
+		return
+	}
+
+	variant := models.MagazineVariant{MagazineFamilyID: family.ID}
+	if !bindMagazineVariant(c, &variant, family) {
+		return
+	}
+	if err := db.DB.Omit("MagazineFamily", "Caliber", "Part").Create(&variant).Error; err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "The part is already a variant of a magazine family"})
+		return
+	}
+	c.JSON(http.StatusCreated, variant)
+}
+
+// @Summary     Update a magazine variant
+// @Description Update a capacity variant of a magazine family
+// @Tags        Magazines
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "Magazine Family ID"
+// @Param       variant_id path int true "Magazine Variant ID"
+// @Param       variant body MagazineVariantInput true "Updated magazine variant"
+// @Success     200 {object} models.MagazineVariant
+// @Failure     400 {object} map[string]string
+// @Failure     404 {object} map[string]string
+// @Failure     409 {object} map[string]string
+// @Router      /magazine-families/{id}/variants/{variant_id} [put]
+func UpdateMagazineVariant(c *gin.Context) {
+	var family models.MagazineFamily
+	if err := db.DB.Preload("Calibers").First(&family, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Magazine family not found"})
+		return
+	}
+	var variant models.MagazineVariant
+	if err := db.DB.Where("magazine_family_id = ?", family.ID).First(&variant, c.Param("variant_id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Magazine variant not found"})
+		return
+	}
+
+	if !bindMagazineVariant(c, &variant, family) {
+		return
+	}
+	if err := db.DB.Omit("MagazineFamily", "Caliber", "Part").Save(&variant).Error; err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "The part is already a variant of a magazine family"})
+		return
+	}
+	c.JSON(http.StatusOK, variant)
+}
+
+// @Summary     Remove a magazine variant
+// @Description Delete a capacity variant of a magazine family
+// @Tags        Magazines
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "Magazine Family ID"
+// @Param       variant_id path int true "Magazine Variant ID"
+// @Success     204 "No Content"
+// @Failure     404 {object} map[string]string
+// @Router      /magazine-families/{id}/variants/{variant_id} [delete]
+func DeleteMagazineVariant(c *gin.Context) {
+	result := db.DB.Where("magazine_family_id = ?", c.Param("id")).Delete(&models.MagazineVariant{}, c.Param("variant_id"))
+	if result.Error != nil || result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Magazine variant not found"})
+		return
+	}
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// saveMagazineFamily applies the input to the family and stores it together with its firearm
+// models and calibers, writing an error response and returning false on failure
+func saveMagazineFamily(c *gin.Context, family *models.MagazineFamily, input MagazineFamilyInput) bool {
+	firearmModels := []models.FirearmModel{}
+	if len(input.FirearmModelIDs) > 0 {
+		db.DB.Where("id IN ?", input.FirearmModelIDs).Find(&firearmModels)
+	}
+	if len(firearmModels) != len(uniqueInts(input.FirearmModelIDs)) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown firearm model in firearm_model_ids"})
+		return false
+	}
+
+	calibers := []models.Caliber{}
+	if len(input.CaliberIDs) > 0 {
+		db.DB.Where("id IN ?", input.CaliberIDs).Find(&calibers)
+	}
+	if len(calibers) != len(uniqueInts(input.CaliberIDs)) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown caliber in caliber_ids"})
+		return false
+	}
+
+	family.Name = input.Name
+	family.Description = input.Description
+
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("FirearmModels", "Calibers", "Variants").Save(family).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(family).Omit("FirearmModels.*").Association("FirearmModels").Replace(firearmModels); err != nil {
+			return err
+		}
+		return tx.Model(family).Omit("Calibers.*").Association("Calibers").Replace(calibers)
+	})
+	if err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "Magazine family " + input.Name + " already exists"})
+		return false
+	}
+	family.FirearmModels = firearmModels
+	family.Calibers = calibers
+	return true
+}
+
+// bindMagazineVariant validates the request body and applies it to the variant, writing an
+// error response and returning false when it is invalid
+func bindMagazineVariant(c *gin.Context, variant *models.MagazineVariant, family models.MagazineFamily) bool {
+	var input MagazineVariantInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return false
+	}
+	if input.Capacity < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "capacity must be at least 1"})
+		return false
+	}
+
+	variant.Caliber = nil
+	if input.CaliberID != nil {
+		for i := range family.Calibers {
+			if family.Calibers[i].ID == *input.CaliberID {
+				variant.Caliber = &family.Calibers[i]
+			}
+		}
+		if variant.Caliber == nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Caliber " + strconv.Itoa(*input.CaliberID) + " is not fed by magazine family " + family.Name})
+			return false
+		}
+	}
+
+	variant.Part = nil
+	if input.PartID != nil {
+		var part models.Part
+		if err := db.DB.First(&part, *input.PartID).Error; err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown part " + strconv.Itoa(*input.PartID)})
+			return false
+		}
+		variant.Part = &part
+	}
+
+	variant.Capacity = input.Capacity
+	variant.CaliberID = input.CaliberID
+	variant.PartID = input.PartID
+	variant.Notes = input.Notes
+	return true
+}
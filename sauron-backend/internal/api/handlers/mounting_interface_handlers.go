@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"net/http"
+	"sauron-backend/internal/db"
+	"sauron-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary     Get all mounting interfaces
+// @Description Get the accessory mounting standards rail sections can use, e.g. Picatinny, M-LOK and KeyMod
+// @Tags        Mounting Interfaces
+// @Accept      json
+// @Produce     json
+// @Success     200 {array} models.MountingInterface
+// @Router      /mounting-interfaces [get]
+func GetMountingInterfaces(c *gin.Context) {
+	interfaces := []models.MountingInterface{}
+	db.DB.Order("id").Find(&interfaces)
+	c.JSON(http.StatusOK, interfaces)
+}
+
+// @Summary     Get a mounting interface by ID
+// @Description Get a specific mounting interface
+// @Tags        Mounting Interfaces
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "Mounting Interface ID"
+// @Success     200 {object} models.MountingInterface
+// @Failure     404 {object} map[string]string
+// @Router      /mounting-interfaces/{id} [get]
+func GetMountingInterfaceByID(c *gin.Context) {
+	var mountingInterface models.MountingInterface
+	if err := db.DB.First(&mountingInterface, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Mounting interface not found"})
+		return
+	}
+	c.JSON(http.StatusOK, mountingInterface)
+}
+
+// @Summary     Create a mounting interface
+// @Description Add a new accessory mounting standard
+// @Tags        Mounting Interfaces
+// @Accept      json
+// @Produce     json
+// @Param       interface body models.MountingInterface true "Mounting interface to create"
+// @Success     201 {object} models.MountingInterface
+// @Failure     400 {object} map[string]string
+// @Failure     409 {object} map[string]string
+// @Router      /mounting-interfaces [post]
+func CreateMountingInterface(c *gin.Context) {
+	var mountingInterface models.MountingInterface
+	if err := c.ShouldBindJSON(&mountingInterface); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	mountingInterface.ID = 0
+	if !checkMountingInterface(c, mountingInterface) {
+		return
+	}
+
+	if err := db.DB.Create(&mountingInterface).Error; err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "Mounting interface " + mountingInterface.Name + " already exists"})
+		return
+	}
+	c.JSON(http.StatusCreated, mountingInterface)
+}
+
+// @Summary     Update a mounting interface
+// @Description Update an existing mounting interface
+// @Tags        Mounting Interfaces
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "Mounting Interface ID"
+// @Param       interface body models.MountingInterface true "Updated mounting interface"
+// @Success     200 {object} models.MountingInterface
+// @Failure     400 {object} map[string]string
+// @Failure     404 {object} map[string]string
+// @Failure     409 {object} map[string]string
+// @Router      /mounting-interfaces/{id} [put]
+func UpdateMountingInterface(c *gin.Context) {
+	var mountingInterface models.MountingInterface
+	if err := db.DB.First(&mountingInterface, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Mounting interface not found"})
+		return
+	}
+	id := mountingInterface.ID
+	if err := c.ShouldBindJSON(&mountingInterface); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	mountingInterface.ID = id
+	if !checkMountingInterface(c, mountingInterface) {
+		return
+	}
+
+	if err := db.DB.Save(&mountingInterface).Error; err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "Mounting interface " + mountingInterface.Name + " already exists"})
+		return
+	}
+	c.JSON(http.StatusOK, mountingInterface)
+}
+
+// @Summary     Delete a mounting interface
+// @Description Delete a mounting interface that no part mount uses
+// @Tags        Mounting Interfaces
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "Mounting Interface ID"
+// @Success     204 "No Content"
+// @Failure     404 {object} map[string]string
+// @Failure     409 {object} map[string]string
+// @Router      /mounting-interfaces/{id} [delete]
+func DeleteMountingInterface(c *gin.Context) {
+	var mountingInterface models.MountingInterface
+	if err := db.DB.First(&mountingInterface, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Mounting interface not found"})
+		return
+	}
+
+	var count int64
+	db.DB.Model(&models.PartMount{}).Where("mounting_interface_id = ?", mountingInterface.ID).Count(&count)
+	if count > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "Mounting interface is still used by part mounts and cannot be deleted"})
+		return
+	}
+
+	if err := db.DB.Delete(&mountingInterface).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete mounting interface"})
+		return
+	}
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// checkMountingInterface validates a mounting interface, writing a 400 response and
+// returning false when it is invalid
+func checkMountingInterface(c *gin.Context, mountingInterface models.MountingInterface) bool {
+	if mountingInterface.Name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "name is required"})
+		return false
+	}
+	if mountingInterface.SlotPitch < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "slot_pitch must not be negative"})
+		return false
+	}
+	return true
+}
@@ -0,0 +1,222 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"sauron-backend/internal/models"
+)
+
+// Classifications of a configured firearm under federal law
+const (
+	NFAClassRifle                = "rifle"
+	NFAClassShortBarreledRifle   = "short_barreled_rifle"
+	NFAClassPistol               = "pistol"
+	NFAClassAnyOtherWeapon       = "any_other_weapon"
+	NFAClassShotgun              = "shotgun"
+	NFAClassShortBarreledShotgun = "short_barreled_shotgun"
+	NFAClassMachineGun           = "machine_gun"
+
+	// Neither a rifle, shotgun nor pistol and not NFA-regulated, e.g. a long gun without a stock
+	NFAClassFirearm = "firearm"
+
+	// Barrel length is not recorded, so whether the firearm is short-barreled is unknown
+	NFAClassUndetermined = "undetermined"
+)
+
+// Codes of NFA warnings that are not a classification
+const (
+	NFAWarningSuppressor  = "suppressor"
+	NFAWarningPistolBrace = "pistol_brace"
+)
+
+// Federal minimum lengths in inches below which a rifle or shotgun is NFA-regulated
+const (
+	nfaMinRifleBarrelLength   = 16
+	nfaMinShotgunBarrelLength = 18
+	nfaMinOverallLength       = 26
+)
+
+var validNFATags = map[string]bool{
+	models.NFATagSuppressor:       true,
+	models.NFATagShortBarrel:      true,
+	models.NFATagShoulderStock:    true,
+	models.NFATagPistolBrace:      true,
+	models.NFATagVerticalForegrip: true,
+	models.NFATagFullAuto:         true,
+}
+
+// NFAWarning flags an NFA-regulated configuration or part
+type NFAWarning struct {
+	// Classification or NFA item the warning is about
+	Code string `json:"code" example:"short_barreled_rifle"`
+
+	// Part that makes the configuration regulated, if a single part does
+	PartID int `json:"part_id,omitempty" example:"42"`
+
+	Message  string `json:"message" example:"This configuration is a short-barreled rifle (SBR): the 10.5 in barrel is under 16 in and B5 SOPMOD Stock (AR-15) is a shoulder stock. It must be registered on an approved ATF Form 1 or Form 4 before it is assembled."`
+	Citation string `json:"citation,omitempty" example:"26 U.S.C. § 5845(a)(3), (c)"`
+}
+
+// NFAClassification is the federal classification of a configured firearm
+type NFAClassification struct {
+	// Firearm that was classified; both are omitted for unsaved selections
+	BuildID           int `json:"build_id,omitempty" example:"1"`
+	PrebuiltFirearmID int `json:"prebuilt_firearm_id,omitempty" example:"2"`
+
+	FirearmModelID int `json:"firearm_model_id" example:"1"`
+
+	Classification string `json:"classification" example:"short_barreled_rifle" enums:"rifle,short_barreled_rifle,pistol,any_other_weapon,shotgun,short_barreled_shotgun,machine_gun,firearm,undetermined"`
+
+	// Whether the classification or a part makes the firearm NFA-regulated
+	NFARegulated bool `json:"nfa_regulated" example:"true"`
+
+	// Shortest barrel and overall length in inches, if recorded
+	BarrelLength  *float64 `json:"barrel_length,omitempty" example:"10.5"`
+	OverallLength *float64 `json:"overall_length,omitempty" example:"27.5"`
+
+	// How the firearm is meant to be held: shoulder_stock, pistol_brace or none
+	Stock string `json:"stock" example:"shoulder_stock" enums:"shoulder_stock,pistol_brace,none"`
+
+	Warnings []NFAWarning `json:"warnings"`
+}
+
+// validateNFATag checks that a part or category NFA tag is empty or known
+func validateNFATag(tag string) error {
+	if tag != "" && !validNFATags[tag] {
+		return errors.New("nfa_tag must be one of suppressor, short_barrel, shoulder_stock, pistol_brace, vertical_foregrip or full_auto")
+	}
+	return nil
+}
+
+// partNFATag returns a part's NFA tag, or that of its nearest tagged category
+func partNFATag(tree categoryTree, part models.Part) string {
+	if part.NFATag != "" || part.PartCategoryID == nil {
+		return part.NFATag
+	}
+	for _, categoryID := range append([]int{*part.PartCategoryID}, tree.ancestors(*part.PartCategoryID)...) {
+		if tag := tree[categoryID].NFATag; tag != "" {
+			return tag
+		}
+	}
+	return ""
+}
+
+// classifyNFA derives a firearm's federal classification from its barrel length, its stock
+// and the NFA roles of its parts. Long guns are judged by whether they have a shoulder stock,
+// handguns and stockless guns by whether their barrel makes them a pistol, and automatic
+// parts override both.
+func classifyNFA(profile *firearmProfile) NFAClassification {
+	result := NFAClassification{
+		FirearmModelID: profile.modelID,
+		BarrelLength:   profile.barrelLength,
+		OverallLength:  profile.overallLength,
+		Stock:          "none",
+		Warnings:       []NFAWarning{},
+	}
+	stock, shouldered := profile.nfaParts[models.NFATagShoulderStock]
+	brace, braced := profile.nfaParts[models.NFATagPistolBrace]
+	switch {
+	case shouldered:
+		result.Stock = models.NFATagShoulderStock
+	case braced:
+		result.Stock = models.NFATagPistolBrace
+	}
+
+	minBarrel := float64(nfaMinRifleBarrelLength)
+	if profile.modelClass == models.FirearmClassShotgun {
+		minBarrel = nfaMinShotgunBarrelLength
+	}
+	barrelDescription := fmt.Sprintf("%s is a short barrel", profile.nfaParts[models.NFATagShortBarrel].Name)
+	short, barrelKnown := false, false
+	if profile.barrelLength != nil {
+		short, barrelKnown = *profile.barrelLength < minBarrel, true
+		barrelDescription = fmt.Sprintf("the %g in barrel is under %g in", *profile.barrelLength, minBarrel)
+	} else if _, tagged := profile.nfaParts[models.NFATagShortBarrel]; tagged {
+		short, barrelKnown = true, true
+	}
+	shortOverall := profile.overallLength != nil && *profile.overallLength < nfaMinOverallLength
+
+	warn := func(code string, part models.Part, citation, format string, args ...interface{}) {
+		result.Warnings = append(result.Warnings, NFAWarning{Code: code, PartID: part.ID, Message: fmt.Sprintf(format, args...), Citation: citation})
+	}
+
+	switch {
+	case hasNFAPart(profile, models.NFATagFullAuto):
+		part := profile.nfaParts[models.NFATagFullAuto]
+		result.Classification = NFAClassMachineGun
+		warn(NFAClassMachineGun, part, "26 U.S.C. § 5845(b); 18 U.S.C. § 922(o)",
+			"This configuration is a machine gun: %s makes it fire automatically. Only machine guns lawfully registered before May 19, 1986 may be transferred to or possessed by individuals.", part.Name)
+
+	case profile.modelClass == models.FirearmClassShotgun:
+		switch {
+		case shouldered && short, !shouldered && shortOverall:
+			result.Classification = NFAClassShortBarreledShotgun
+			reason := barrelDescription
+			if !short {
+				reason = fmt.Sprintf("its %g in overall length is under %d in", *profile.overallLength, nfaMinOverallLength)
+			}
+			warn(NFAClassShortBarreledShotgun, profile.nfaParts[models.NFATagShortBarrel], "26 U.S.C. § 5845(a)(1)-(2), (d)",
+				"This configuration is a short-barreled shotgun (SBS): %s. It must be registered on an approved ATF Form 1 or Form 4 before it is assembled.", reason)
+		case shouldered && !barrelKnown, !shouldered && short && profile.overallLength == nil:
+			result.Classification = NFAClassUndetermined
+		case shouldered:
+			result.Classification = NFAClassShotgun
+		default:
+			result.Classification = NFAClassFirearm
+		}
+
+	case shouldered:
+		switch {
+		case short || shortOverall:
+			result.Classification = NFAClassShortBarreledRifle
+			reason := barrelDescription
+			if !short {
+				reason = fmt.Sprintf("its %g in overall length is under %d in", *profile.overallLength, nfaMinOverallLength)
+			}
+			warn(NFAClassShortBarreledRifle, stock, "26 U.S.C. § 5845(a)(3)-(4), (c)",
+				"This configuration is a short-barreled rifle (SBR): %s and %s is a shoulder stock. It must be registered on an approved ATF Form 1 or Form 4 before it is assembled.", reason, stock.Name)
+		case !barrelKnown:
+			result.Classification = NFAClassUndetermined
+		default:
+			result.Classification = NFAClassRifle
+		}
+
+	case profile.modelClass == models.FirearmClassPistol || short:
+		result.Classification = NFAClassPistol
+		if foregrip, ok := profile.nfaParts[models.NFATagVerticalForegrip]; ok {
+			result.Classification = NFAClassAnyOtherWeapon
+			warn(NFAClassAnyOtherWeapon, foregrip, "26 U.S.C. § 5845(e)",
+				"This configuration is an any other weapon (AOW): %s is a vertical foregrip on a pistol. It must be registered on an approved ATF Form 1 or Form 4 before it is assembled.", foregrip.Name)
+		}
+
+	case !barrelKnown:
+		result.Classification = NFAClassUndetermined
+
+	default:
+		result.Classification = NFAClassFirearm
+	}
+
+	if braced && result.Classification == NFAClassPistol {
+		warn(NFAWarningPistolBrace, brace, "",
+			"%s is a pistol stabilizing brace. ATF's treatment of braced pistols has changed repeatedly; confirm the brace is not configured or used as a shoulder stock, which would make this a short-barreled rifle.", brace.Name)
+	}
+	if suppressor, ok := profile.nfaParts[models.NFATagSuppressor]; ok {
+		warn(NFAWarningSuppressor, suppressor, "26 U.S.C. § 5845(a)(7); 18 U.S.C. § 921(a)(25)",
+			"%s is a suppressor, an NFA item that must be registered to you on an approved ATF Form 4 or Form 1.", suppressor.Name)
+	}
+
+	switch result.Classification {
+	case NFAClassShortBarreledRifle, NFAClassShortBarreledShotgun, NFAClassAnyOtherWeapon, NFAClassMachineGun:
+		result.NFARegulated = true
+	}
+	if hasNFAPart(profile, models.NFATagSuppressor) {
+		result.NFARegulated = true
+	}
+	return result
+}
+
+// hasNFAPart reports whether any part of the firearm has an NFA role
+func hasNFAPart(profile *firearmProfile, tag string) bool {
+	_, ok := profile.nfaParts[tag]
+	return ok
+}
@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"sauron-backend/internal/db"
+	"sauron-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BuildNFAInput is the request body for classifying an unsaved slot selection
+type BuildNFAInput struct {
+	// Firearm model the selection is for
+	FirearmModelID int `json:"firearm_model_id" binding:"required" example:"1"`
+
+	// Selected parts keyed by part category (slot) ID
+	Slots map[int]int `json:"slots"`
+}
+
+// @Summary     Classify a build under the NFA
+// @Description Derive a saved build's federal classification (rifle, short-barreled rifle, pistol, any other weapon, shotgun, short-barreled shotgun or machine gun) from its barrel length, stock and the NFA tags of its parts, with a warning for each NFA-regulated configuration or item
+// @Tags        Builds,Laws
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "Build ID"
+// @Success     200 {object} NFAClassification
+// @Failure     404 {object} map[string]string
+// @Failure     500 {object} map[string]string
+// @Router      /builds/{id}/nfa [get]
+func GetBuildNFAClassification(c *gin.Context) {
+	build, err := loadBuild(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Build not found"})
+		return
+	}
+
+	profile, err := profileSelection(build.FirearmModelID, buildSlots(build))
+	respondWithNFAClassification(c, profile, err, func(result *NFAClassification) { result.BuildID = build.ID })
+}
+
+// @Summary     Classify a slot selection under the NFA
+// @Description Derive an unsaved slot selection's federal classification from its barrel length, stock and the NFA tags of its parts, with a warning for each NFA-regulated configuration or item
+// @Tags        Builds,Laws
+// @Accept      json
+// @Produce     json
+// @Param       selection body BuildNFAInput true "Slot selection"
+// @Success     200 {object} NFAClassification
+// @Failure     400 {object} map[string]string
+// @Failure     404 {object} map[string]string
+// @Failure     500 {object} map[string]string
+// @Router      /builds/nfa [post]
+func ClassifyBuildSelectionNFA(c *gin.Context) {
+	var input BuildNFAInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	profile, err := profileSelection(input.FirearmModelID, input.Slots)
+	respondWithNFAClassification(c, profile, err, nil)
+}
+
+// @Summary     Classify a prebuilt firearm under the NFA
+// @Description Derive a prebuilt firearm's federal classification from the parts its components reference and its specifications
+// @Tags        Prebuilt Firearms,Laws
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "Prebuilt Firearm ID"
+// @Success     200 {object} NFAClassification
+// @Failure     404 {object} map[string]string
+// @Failure     500 {object} map[string]string
+// @Router      /prebuilt-firearms/{id}/nfa [get]
+func GetPrebuiltFirearmNFAClassification(c *gin.Context) {
+	var prebuilt models.PrebuiltFirearm
+	if err := db.DB.First(&prebuilt, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Prebuilt firearm not found"})
+		return
+	}
+
+	profile, err := profilePrebuilt(prebuilt)
+	respondWithNFAClassification(c, profile, err, func(result *NFAClassification) { result.PrebuiltFirearmID = prebuilt.ID })
+}
+
+// respondWithNFAClassification classifies a firearm profile and writes the result, letting the
+// caller identify the firearm classified
+func respondWithNFAClassification(c *gin.Context, profile *firearmProfile, err error, identify func(*NFAClassification)) {
+	if errors.Is(err, errFirearmModelNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Firearm model not found"})
+		return
+	}
+	if err != nil {
+		log.Printf("Error reading firearm parts for NFA classification: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read the firearm's parts"})
+		return
+	}
+
+	result := classifyNFA(profile)
+	if identify != nil {
+		identify(&result)
+	}
+	c.JSON(http.StatusOK, result)
+}
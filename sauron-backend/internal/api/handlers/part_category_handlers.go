@@ -101,6 +101,19 @@ func CreatePartCategory(c *gin.Context) {
 		return
 	}
 
+	if err := validateAttributeSchema(category.AttributeSchema); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := validateNFATag(category.NFATag); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := validateShippingClass(category.ShippingClass); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	if err := db.DB.Create(&category).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create part category"})
 		return
@@ -134,17 +147,39 @@ func UpdatePartCategory(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Part category not found"})
 		return
 	}
+	previousParentID := parentCategoryID(category)
 
 	if err := c.ShouldBindJSON(&category); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
+	if err := validateAttributeSchema(category.AttributeSchema); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := validateNFATag(category.NFATag); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := validateShippingClass(category.ShippingClass); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	if err := db.DB.Save(&category).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update part category"})
 		return
 	}
 
+	// Moving a category changes which rules apply to the parts beneath it
+	if parentCategoryID(category) != previousParentID {
+		if err := reindexPartsWithinCategories([]int{category.ID}); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Part category was saved but the compatibility index could not be updated"})
+			return
+		}
+	}
+
 	c.JSON(http.StatusOK, category)
 }
 
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"sauron-backend/internal/db"
+	"sauron-backend/internal/models"
+	"strings"
+)
+
+// categoryTree indexes part categories by ID so parent chains can be walked in memory
+type categoryTree map[int]models.PartCategory
+
+// loadCategoryTree fetches all part categories into a categoryTree
+func loadCategoryTree() (categoryTree, error) {
+	var categories []models.PartCategory
+	if err := db.DB.Find(&categories).Error; err != nil {
+		return nil, err
+	}
+
+	tree := make(categoryTree, len(categories))
+	for _, category := range categories {
+		tree[category.ID] = category
+	}
+	return tree, nil
+}
+
+// ancestors returns the parent chain of a category, nearest parent first
+func (t categoryTree) ancestors(categoryID int) []int {
+	var result []int
+	seen := map[int]bool{categoryID: true}
+
+	current, ok := t[categoryID]
+	for ok && current.ParentCategoryID != nil {
+		parentID := *current.ParentCategoryID
+		// Guard against cycles in malformed data
+		if seen[parentID] {
+			break
+		}
+		seen[parentID] = true
+		result = append(result, parentID)
+		current, ok = t[parentID]
+	}
+	return result
+}
+
+// isWithin reports whether categoryID is ancestorID or one of its descendants
+func (t categoryTree) isWithin(categoryID, ancestorID int) bool {
+	if categoryID == ancestorID {
+		return true
+	}
+	for _, id := range t.ancestors(categoryID) {
+		if id == ancestorID {
+			return true
+		}
+	}
+	return false
+}
+
+// path joins a category's names from its top-level ancestor down, e.g. "Upper Receiver > Barrel"
+func (t categoryTree) path(categoryID int) string {
+	category, ok := t[categoryID]
+	if !ok {
+		return ""
+	}
+
+	chain := t.ancestors(categoryID)
+	names := make([]string, 0, len(chain)+1)
+	for i := len(chain) - 1; i >= 0; i-- {
+		names = append(names, t[chain[i]].Name)
+	}
+	names = append(names, category.Name)
+	return strings.Join(names, " > ")
+}
+
+// root returns the top-level ancestor of a category, or the category itself if it has no parent
+func (t categoryTree) root(categoryID int) int {
+	chain := t.ancestors(categoryID)
+	if len(chain) == 0 {
+		return categoryID
+	}
+	return chain[len(chain)-1]
+}
+
+// parentCategoryID returns a category's parent ID, or 0 for a top-level category
+func parentCategoryID(category models.PartCategory) int {
+	if category.ParentCategoryID == nil {
+		return 0
+	}
+	return *category.ParentCategoryID
+}
@@ -0,0 +1,251 @@
+package handlers
+
+import (
+	"net/http"
+	"sauron-backend/internal/db"
+	"sauron-backend/internal/models"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// PartFitmentInput is the request body for declaring that a part fits a firearm model
+type PartFitmentInput struct {
+	// Installation notes, e.g. generation or variant caveats
+	Notes string `json:"notes" example:"Gen 3 and later only"`
+
+	// Whether the part needs gunsmithing or fitting to work with the model
+	RequiresModification bool `json:"requires_modification" example:"false"`
+}
+
+// @Summary Get parts that fit a firearm model
+// @Description Retrieves the parts explicitly declared to fit a firearm model, with fitment notes
+// @Tags Firearm Models,Parts
+// @Accept json
+// @Produce json
+// @Param id path int true "Firearm Model ID"
+// @Param category_id query int false "Only parts in this part category or its subcategories"
+// @Param requires_modification query bool false "Filter by whether fitting requires modification"
+// @Success 200 {array} models.PartFirearmModel
+// @Failure 400 {object} map[string]string "Invalid firearm model ID"
+// @Failure 404 {object} map[string]string "Firearm model not found"
+// @Failure 500 {object} map[string]string "Server error"
+// @Router /firearm-models/{id}/parts [get]
+func GetFirearmModelParts(c *gin.Context) {
+	modelID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid firearm model ID"})
+		return
+	}
+
+	var model models.FirearmModel
+	if err := db.DB.First(&model, modelID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Firearm model not found"})
+		return
+	}
+
+	query := db.DB.Preload("Part").Where("firearm_model_id = ?", modelID).Order("part_id")
+	if requiresModification, exists := c.GetQuery("requires_modification"); exists {
+		query = query.Where("requires_modification = ?", requiresModification == "true")
+	}
+
+	var fitments []models.PartFirearmModel
+	if err := query.Find(&fitments).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch parts"})
+		return
+	}
+
+	if categoryParam := c.Query("category_id"); categoryParam != "" {
+		categoryID, err := strconv.Atoi(categoryParam)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category ID"})
+			return
+		}
+		tree, err := loadCategoryTree()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load part categories"})
+			return
+		}
+
+		filtered := []models.PartFirearmModel{}
+		for _, fitment := range fitments {
+			if fitment.Part != nil && fitment.Part.PartCategoryID != nil && tree.isWithin(*fitment.Part.PartCategoryID, categoryID) {
+				filtered = append(filtered, fitment)
+			}
+		}
+		fitments = filtered
+	}
+
+	c.JSON(http.StatusOK, fitments)
+}
+
+// @Summary Get firearm models a part fits
+// @Description Retrieves the firearm models a part is explicitly declared to fit, with fitment notes
+// @Tags Parts,Firearm Models
+// @Accept json
+// @Produce json
+// @Param id path int true "Part ID"
+// @Success 200 {array} models.PartFirearmModel
+// @Failure 400 {object} map[string]string "Invalid part ID"
+// @Failure 404 {object} map[string]string "Part not found"
+// @Failure 500 {object} map[string]string "Server error"
+// @Router /parts/{id}/fits [get]
+func GetPartFitments(c *gin.Context) {
+	partID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid part ID"})
+		return
+	}
+
+	var part models.Part
+	if err := db.DB.First(&part, partID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Part not found"})
+		return
+	}
+
+	var fitments []models.PartFirearmModel
+	if err := db.DB.Preload("FirearmModel").Where("part_id = ?", partID).Order("firearm_model_id").Find(&fitments).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch fitments"})
+		return
+	}
+
+	c.JSON(http.StatusOK, fitments)
+}
+
+// @Summary Declare that a part fits a firearm model
+// @Description Creates or updates the fitment of a part to a firearm model
+// @Tags Parts,Firearm Models
+// @Accept json
+// @Produce json
+// @Param id path int true "Part ID"
+// @Param model_id path int true "Firearm Model ID"
+// @Param fitment body PartFitmentInput false "Fitment details"
+// @Success 200 {object} models.PartFirearmModel
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 404 {object} map[string]string "Part or firearm model not found"
+// @Failure 500 {object} map[string]string "Server error"
+// @Router /parts/{id}/fits/{model_id} [put]
+func SetPartFitment(c *gin.Context) {
+	partID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid part ID"})
+		return
+	}
+	modelID, err := strconv.Atoi(c.Param("model_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid firearm model ID"})
+		return
+	}
+
+	var part models.Part
+	if err := db.DB.First(&part, partID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Part not found"})
+		return
+	}
+	var model models.FirearmModel
+	if err := db.DB.First(&model, modelID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Firearm model not found"})
+		return
+	}
+
+	var input PartFitmentInput
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	var fitment models.PartFirearmModel
+	db.DB.Where("part_id = ? AND firearm_model_id = ?", partID, modelID).First(&fitment)
+	fitment.PartID = partID
+	fitment.FirearmModelID = modelID
+	fitment.Notes = input.Notes
+	fitment.RequiresModification = input.RequiresModification
+
+	if err := db.DB.Omit("Part", "FirearmModel").Save(&fitment).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save fitment"})
+		return
+	}
+	if err := reindexParts([]int{partID}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Fitment was saved but the compatibility index could not be updated"})
+		return
+	}
+
+	c.JSON(http.StatusOK, fitment)
+}
+
+// @Summary Remove a part's fitment to a firearm model
+// @Description Deletes the declaration that a part fits a firearm model
+// @Tags Parts,Firearm Models
+// @Accept json
+// @Produce json
+// @Param id path int true "Part ID"
+// @Param model_id path int true "Firearm Model ID"
+// @Success 204 "No Content"
+// @Failure 404 {object} map[string]string "Fitment not found"
+// @Router /parts/{id}/fits/{model_id} [delete]
+func RemovePartFitment(c *gin.Context) {
+	partID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Fitment not found"})
+		return
+	}
+	result := db.DB.Where("part_id = ? AND firearm_model_id = ?", partID, c.Param("model_id")).Delete(&models.PartFirearmModel{})
+	if result.Error != nil || result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Fitment not found"})
+		return
+	}
+	if err := reindexParts([]int{partID}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Fitment was deleted but the compatibility index could not be updated"})
+		return
+	}
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// loadDeclaredFitments returns, for each given part that declares any fitment, the set of
+// firearm models it is declared to fit. Parts without declarations are absent from the map.
+func loadDeclaredFitments(partIDs []int) (map[int]map[int]bool, error) {
+	if len(partIDs) == 0 {
+		return make(map[int]map[int]bool), nil
+	}
+
+	return queryDeclaredFitments(db.DB.Where("part_id IN ?", partIDs))
+}
+
+// loadAllDeclaredFitments returns the declared fitments of every part, keyed like loadDeclaredFitments
+func loadAllDeclaredFitments() (map[int]map[int]bool, error) {
+	return queryDeclaredFitments(db.DB)
+}
+
+func queryDeclaredFitments(query *gorm.DB) (map[int]map[int]bool, error) {
+	var fitments []models.PartFirearmModel
+	if err := query.Find(&fitments).Error; err != nil {
+		return nil, err
+	}
+
+	fitsByPart := make(map[int]map[int]bool)
+	for _, fitment := range fitments {
+		if fitsByPart[fitment.PartID] == nil {
+			fitsByPart[fitment.PartID] = make(map[int]bool)
+		}
+		fitsByPart[fitment.PartID][fitment.FirearmModelID] = true
+	}
+	return fitsByPart, nil
+}
+
+// sharesDeclaredFitment reports whether two parts can share a firearm model according to their
+// declared fitments. A part without declarations is assumed to fit wherever its category does.
+func sharesDeclaredFitment(fitsByPart map[int]map[int]bool, a, b int) bool {
+	fitsA, fitsB := fitsByPart[a], fitsByPart[b]
+	if fitsA == nil || fitsB == nil {
+		return true
+	}
+	for modelID := range fitsA {
+		if fitsB[modelID] {
+			return true
+		}
+	}
+	return false
+}
@@ -9,6 +9,7 @@ import (
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 // PartItem represents a node in the part hierarchy
@@ -20,7 +21,7 @@ type PartItem struct {
 
 // Get all parts
 // @Summary Get all parts
-// @Description Get all parts with optional filtering by category, subcategory, or category_id
+// @Description Get all parts with optional filtering by category, subcategory, category_id or caliber
 // @Tags Parts
 // @Accept json
 // @Produce json
@@ -28,7 +29,10 @@ type PartItem struct {
 // @Param subcategory query string false "Filter by subcategory name"
 // @Param category_id query int false "Filter by part category ID (new schema)"
 // @Param is_prebuilt query bool false "Filter by prebuilt status"
+// @Param caliber query string false "Filter by caliber ID, name or alias"
+// @Param include_fired query bool false "With caliber, also include parts for calibers that caliber's chamber can fire"
 // @Success 200 {array} models.Part
+// @Failure 400 {object} map[string]string "Unknown caliber"
 // @Failure 500 {object} map[string]string "Server error"
 // @Router /parts [get]
 func GetParts(c *gin.Context) {
@@ -61,6 +65,16 @@ func GetParts(c *gin.Context) {
 		query = query.Where("is_prebuilt = ?", isPrebuilt)
 	}
 
+	// Filter by caliber
+	caliberIDs, err := resolveCaliberQuery(c)
+	if err != nil {
+		respondToCaliberQueryError(c, err)
+		return
+	}
+	if caliberIDs != nil {
+		query = query.Where("caliber_id IN ?", caliberIDs)
+	}
+
 	// Execute query
 	query.Find(&parts)
 
@@ -69,13 +83,14 @@ func GetParts(c *gin.Context) {
 
 // Create a new part
 // @Summary Create a part
-// @Description Create a new part in the database
+// @Description Create a new part in the database. Its attributes must satisfy the attribute schema of its part category.
 // @Tags Parts
 // @Accept json
 // @Produce json
 // @Param part body models.Part true "Part object to create"
 // @Success 201 {object} models.Part
 // @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 422 {object} map[string]interface{} "Attributes do not satisfy the category schema"
 // @Failure 500 {object} map[string]string "Server error"
 // @Router /parts [post]
 func CreatePart(c *gin.Context) {
@@ -84,7 +99,18 @@ func CreatePart(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if err := validateNFATag(part.NFATag); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !checkPartAttributes(c, part) {
+		return
+	}
 	db.DB.Create(&part)
+	if err := reindexParts([]int{part.ID}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Part was saved but the compatibility index could not be updated"})
+		return
+	}
 	c.JSON(http.StatusCreated, part)
 }
 
@@ -112,7 +138,7 @@ func GetPartByID(c *gin.Context) {
 
 // Update a part
 // @Summary Update a part
-// @Description Update an existing part
+// @Description Update an existing part. Its attributes must satisfy the attribute schema of its part category.
 // @Tags Parts
 // @Accept json
 // @Produce json
@@ -121,6 +147,7 @@ func GetPartByID(c *gin.Context) {
 // @Success 200 {object} models.Part
 // @Failure 400 {object} map[string]string "Invalid input"
 // @Failure 404 {object} map[string]string "Part not found"
+// @Failure 422 {object} map[string]interface{} "Attributes do not satisfy the category schema"
 // @Failure 500 {object} map[string]string "Server error"
 // @Router /parts/{id} [put]
 func UpdatePart(c *gin.Context) {
@@ -134,7 +161,18 @@ func UpdatePart(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if err := validateNFATag(part.NFATag); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !checkPartAttributes(c, part) {
+		return
+	}
 	db.DB.Save(&part)
+	if err := reindexParts([]int{part.ID}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Part was saved but the compatibility index could not be updated"})
+		return
+	}
 	c.JSON(http.StatusOK, part)
 }
 
@@ -178,13 +216,18 @@ func GetPartsByCategory(c *gin.Context) {
 
 // Get parts compatible with a specific part
 // @Summary Get compatible parts
-// @Description Get all parts compatible with a specific part
+// @Description Get a page of the parts compatible with a specific part: parts in categories used by the same firearm models, excluding any declared to fit only other firearm models or that fail an attribute compatibility rule. Results come from the precomputed compatibility index, ordered by part ID; while a full rebuild of the index is pending they are evaluated at query time instead.
 // @Tags Parts,Compatibility
 // @Accept json
 // @Produce json
 // @Param id path int true "Part ID"
+// @Param strict query bool false "Also exclude parts when a rule cannot be evaluated because an attribute is missing"
+// @Param category_id query int false "Only compatible parts in this part category or its subcategories"
+// @Param limit query int false "Maximum number of parts to return (default 100, max 500)"
+// @Param offset query int false "Number of parts to skip"
 // @Success 200 {array} models.Part
-// @Failure 400 {object} map[string]string "Invalid ID format"
+// @Header 200 {integer} X-Total-Count "Number of compatible parts across all pages"
+// @Failure 400 {object} map[string]string "Invalid ID format or paging parameters"
 // @Failure 404 {object} map[string]string "Part not found"
 // @Failure 500 {object} map[string]string "Server error"
 // @Router /parts/{id}/compatible [get]
@@ -195,81 +238,103 @@ func GetCompatibleParts(c *gin.Context) {
 		return
 	}
 
-	// First get the part and its category
 	var part models.Part
-	if err := db.DB.Preload("PartCategory").First(&part, partID).Error; err != nil {
+	if err := db.DB.First(&part, partID).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Part not found"})
 		return
 	}
 
-	// If no part_category_id, return an empty array - can't determine compatibility
-	if part.PartCategoryID == nil {
-		c.JSON(http.StatusOK, []models.Part{})
+	limit, offset, ok := pageParams(c, 100, 500)
+	if !ok {
 		return
 	}
 
-	// Find all firearm models that this part category is used in
-	var compatibleFirearmModels []int
-	rows, err := db.DB.Raw(`
-		SELECT firearm_model_id 
-		FROM firearm_model_part_categories 
-		WHERE part_category_id = ?
-	`, *part.PartCategoryID).Rows()
-
+	stale, err := compatibilityIndexStale()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to find compatible models"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read compatibility index state"})
 		return
 	}
-	defer rows.Close()
 
-	for rows.Next() {
-		var modelID int
-		if err := rows.Scan(&modelID); err != nil {
-			continue
+	strict := c.Query("strict") == "true"
+	var query *gorm.DB
+	if stale {
+		// A full rebuild is pending, so the stored edges may be out of date
+		neighbors, err := compatibleNeighbors(part)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to evaluate compatible parts"})
+			return
+		}
+		neighborIDs := []int{}
+		for id, hasUnknown := range neighbors {
+			if !strict || !hasUnknown {
+				neighborIDs = append(neighborIDs, id)
+			}
+		}
+		query = db.DB.Model(&models.Part{}).Where("parts.id IN ?", neighborIDs)
+	} else {
+		// Each pair is stored once, from the lower part ID
+		query = db.DB.Model(&models.Part{}).
+			Joins("JOIN part_compatibilities ON (part_compatibilities.part_id = ? AND part_compatibilities.compatible_part_id = parts.id) OR (part_compatibilities.compatible_part_id = ? AND part_compatibilities.part_id = parts.id)", partID, partID)
+		if strict {
+			query = query.Where("part_compatibilities.has_unknown = ?", false)
 		}
-		compatibleFirearmModels = append(compatibleFirearmModels, modelID)
 	}
+	if categoryParam := c.Query("category_id"); categoryParam != "" {
+		categoryID, err := strconv.Atoi(categoryParam)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category ID"})
+			return
+		}
+		tree, err := loadCategoryTree()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load part categories"})
+			return
+		}
+		categoryIDs := []int{}
+		for id := range tree {
+			if tree.isWithin(id, categoryID) {
+				categoryIDs = append(categoryIDs, id)
+			}
+		}
+		query = query.Where("parts.part_category_id IN ?", categoryIDs)
+	}
+	query = query.Session(&gorm.Session{})
 
-	if len(compatibleFirearmModels) == 0 {
-		// If no compatible models found, just return parts with the same category
-		var compatibleParts []models.Part
-		db.DB.Where("id != ?", partID).
-			Where("part_category_id = ?", *part.PartCategoryID).
-			Find(&compatibleParts)
-
-		c.JSON(http.StatusOK, compatibleParts)
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count compatible parts"})
 		return
 	}
 
-	// Find all part categories used by these firearm models
-	var compatibleCategoryIDs []int
-	rows, err = db.DB.Raw(`
-		SELECT DISTINCT part_category_id 
-		FROM firearm_model_part_categories 
-		WHERE firearm_model_id IN (?)
-	`, compatibleFirearmModels).Rows()
-
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to find compatible categories"})
+	compatibleParts := []models.Part{}
+	if err := query.Order("parts.id").Limit(limit).Offset(offset).Find(&compatibleParts).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to find compatible parts"})
 		return
 	}
-	defer rows.Close()
 
-	for rows.Next() {
-		var categoryID int
-		if err := rows.Scan(&categoryID); err != nil {
-			continue
+	c.Header("X-Total-Count", strconv.FormatInt(total, 10))
+	c.JSON(http.StatusOK, compatibleParts)
+}
+
+// pageParams reads the ?limit= and ?offset= query parameters, writing a 400 response and
+// returning false when either is invalid
+func pageParams(c *gin.Context, defaultLimit, maxLimit int) (int, int, bool) {
+	limit, offset := defaultLimit, 0
+	if limitParam := c.Query("limit"); limitParam != "" {
+		var err error
+		if limit, err = strconv.Atoi(limitParam); err != nil || limit < 1 || limit > maxLimit {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and " + strconv.Itoa(maxLimit)})
+			return 0, 0, false
 		}
-		compatibleCategoryIDs = append(compatibleCategoryIDs, categoryID)
 	}
-
-	// Get all parts that are in these categories (excluding the original part)
-	var compatibleParts []models.Part
-	db.DB.Where("id != ?", partID).
-		Where("part_category_id IN (?)", compatibleCategoryIDs).
-		Find(&compatibleParts)
-
-	c.JSON(http.StatusOK, compatibleParts)
+	if offsetParam := c.Query("offset"); offsetParam != "" {
+		var err error
+		if offset, err = strconv.Atoi(offsetParam); err != nil || offset < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "offset must be a non-negative integer"})
+			return 0, 0, false
+		}
+	}
+	return limit, offset, true
 }
 
 // Get all unique part categories
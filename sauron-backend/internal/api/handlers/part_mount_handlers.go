@@ -0,0 +1,181 @@
+package handlers
+
+import (
+	"net/http"
+	"sauron-backend/internal/db"
+	"sauron-backend/internal/models"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// validMountPositions lists the positions each mount role may use
+var validMountPositions = map[string]map[string]bool{
+	models.MountRoleProvides: {
+		models.MountPositionTop:    true,
+		models.MountPositionBottom: true,
+		models.MountPositionLeft:   true,
+		models.MountPositionRight:  true,
+	},
+	models.MountRoleConsumes: {
+		models.MountPositionTop:    true,
+		models.MountPositionBottom: true,
+		models.MountPositionLeft:   true,
+		models.MountPositionRight:  true,
+		models.MountPositionAny:    true,
+	},
+}
+
+// PartMountInput is the request body for adding or updating a part's rail mount
+type PartMountInput struct {
+	MountingInterfaceID int `json:"mounting_interface_id" binding:"required" example:"1"`
+
+	// provides for rail sections on the part, consumes for rail space the part occupies
+	Role string `json:"role" binding:"required" example:"consumes" enums:"provides,consumes"`
+
+	// Where the section is, or where the accessory must go; any is only valid for consumes
+	Position string `json:"position" binding:"required" example:"top" enums:"top,bottom,left,right,any"`
+
+	// Slots the section offers, or the accessory occupies
+	Slots int `json:"slots" binding:"required" example:"4"`
+
+	Notes string `json:"notes" example:"Co-witnesses with iron sights"`
+}
+
+// @Summary     Get a part's rail mounts
+// @Description Get the rail sections a part provides and the rail space it consumes
+// @Tags        Parts,Mounting Interfaces
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "Part ID"
+// @Success     200 {array} models.PartMount
+// @Failure     404 {object} map[string]string
+// @Router      /parts/{id}/mounts [get]
+func GetPartMounts(c *gin.Context) {
+	var part models.Part
+	if err := db.DB.First(&part, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Part not found"})
+		return
+	}
+
+	mounts := []models.PartMount{}
+	db.DB.Preload("MountingInterface").Where("part_id = ?", part.ID).Order("role desc, id").Find(&mounts)
+	c.JSON(http.StatusOK, mounts)
+}
+
+// @Summary     Add a rail mount to a part
+// @Description Record a rail section the part provides or rail space it consumes. A part may do both, e.g. an M-LOK to Picatinny rail section.
+// @Tags        Parts,Mounting Interfaces
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "Part ID"
+// @Param       mount body PartMountInput true "Rail mount"
+// @Success     201 {object} models.PartMount
+// @Failure     400 {object} map[string]string
+// @Failure     404 {object} map[string]string
+// @Failure     409 {object} map[string]string
+// @Router      /parts/{id}/mounts [post]
+func CreatePartMount(c *gin.Context) {
+	var part models.Part
+	if err := db.DB.First(&part, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Part not found"})
+		return
+	}
+
+	mount := models.PartMount{PartID: part.ID}
+	if !bindPartMount(c, &mount) {
+		return
+	}
+	if err := db.DB.Omit("Part", "MountingInterface").Create(&mount).Error; err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "The part already has a " + mount.Role + " mount in position " + mount.Position + " on this interface"})
+		return
+	}
+	c.JSON(http.StatusCreated, mount)
+}
+
+// @Summary     Update a part's rail mount
+// @Description Update a rail section or rail space recorded on a part
+// @Tags        Parts,Mounting Interfaces
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "Part ID"
+// @Param       mount_id path int true "Mount ID"
+// @Param       mount body PartMountInput true "Updated rail mount"
+// @Success     200 {object} models.PartMount
+// @Failure     400 {object} map[string]string
+// @Failure     404 {object} map[string]string
+// @Failure     409 {object} map[string]string
+// @Router      /parts/{id}/mounts/{mount_id} [put]
+func UpdatePartMount(c *gin.Context) {
+	var mount models.PartMount
+	if err := db.DB.Where("part_id = ?", c.Param("id")).First(&mount, c.Param("mount_id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Mount not found"})
+		return
+	}
+
+	if !bindPartMount(c, &mount) {
+		return
+	}
+	if err := db.DB.Omit("Part", "MountingInterface").Save(&mount).Error; err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "The part already has a " + mount.Role + " mount in position " + mount.Position + " on this interface"})
+		return
+	}
+	c.JSON(http.StatusOK, mount)
+}
+
+// @Summary     Remove a part's rail mount
+// @Description Delete a rail section or rail space recorded on a part
+// @Tags        Parts,Mounting Interfaces
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "Part ID"
+// @Param       mount_id path int true "Mount ID"
+// @Success     204 "No Content"
+// @Failure     404 {object} map[string]string
+// @Router      /parts/{id}/mounts/{mount_id} [delete]
+func DeletePartMount(c *gin.Context) {
+	result := db.DB.Where("part_id = ?", c.Param("id")).Delete(&models.PartMount{}, c.Param("mount_id"))
+	if result.Error != nil || result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Mount not found"})
+		return
+	}
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// bindPartMount validates the request body and applies it to the mount, writing an error
+// response and returning false when it is invalid
+func bindPartMount(c *gin.Context, mount *models.PartMount) bool {
+	var input PartMountInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return false
+	}
+
+	positions, ok := validMountPositions[input.Role]
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "role must be provides or consumes"})
+		return false
+	}
+	if !positions[input.Position] {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid position " + input.Position + " for a " + input.Role + " mount"})
+		return false
+	}
+	if input.Slots < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "slots must be at least 1"})
+		return false
+	}
+
+	var mountingInterface models.MountingInterface
+	if err := db.DB.First(&mountingInterface, input.MountingInterfaceID).Error; err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown mounting interface " + strconv.Itoa(input.MountingInterfaceID)})
+		return false
+	}
+
+	mount.MountingInterfaceID = input.MountingInterfaceID
+	mount.MountingInterface = &mountingInterface
+	mount.Role = input.Role
+	mount.Position = input.Position
+	mount.Slots = input.Slots
+	mount.Notes = input.Notes
+	return true
+}
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"gorm.io/datatypes"
+)
+
+// prebuiltComponent is one entry of a prebuilt firearm's hierarchical parts JSON
+type prebuiltComponent struct {
+	// Names from the top-level entry down to this one
+	Path []string
+
+	// Part referenced by the entry's "id" field, if any
+	PartID *int
+}
+
+// name is the entry's own name, the last element of its path
+func (pc prebuiltComponent) name() string {
+	return pc.Path[len(pc.Path)-1]
+}
+
+// pathString joins the path for display, e.g. "Upper Assembly > Bolt Carrier Group"
+func (pc prebuiltComponent) pathString() string {
+	return strings.Join(pc.Path, " > ")
+}
+
+// flattenPrebuiltComponents walks a parts tree shaped like
+// {"Upper Assembly": {"id": 1, "sub_parts": {"Bolt Carrier Group": {"id": 5}}}}
+// and returns every entry in depth-first order. Entries whose value is a plain
+// string (e.g. "required") are returned without a part ID.
+func flattenPrebuiltComponents(raw datatypes.JSON) ([]prebuiltComponent, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+
+	var tree map[string]interface{}
+	if err := json.Unmarshal(raw, &tree); err != nil {
+		return nil, err
+	}
+
+	var components []prebuiltComponent
+	walkPrebuiltComponents(tree, nil, &components)
+	return components, nil
+}
+
+func walkPrebuiltComponents(tree map[string]interface{}, parent []string, components *[]prebuiltComponent) {
+	names := make([]string, 0, len(tree))
+	for name := range tree {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		path := append(append([]string{}, parent...), name)
+		component := prebuiltComponent{Path: path}
+
+		node, isObject := tree[name].(map[string]interface{})
+		if isObject {
+			if id, ok := node["id"].(float64); ok && id > 0 {
+				partID := int(id)
+				component.PartID = &partID
+			}
+		}
+		*components = append(*components, component)
+
+		if isObject {
+			if subParts, ok := node["sub_parts"].(map[string]interface{}); ok {
+				walkPrebuiltComponents(subParts, path, components)
+			}
+		}
+	}
+}
@@ -9,15 +9,28 @@ import (
 )
 
 // @Summary     Get all prebuilt firearms
-// @Description Get a list of all prebuilt firearms in the database
+// @Description Get a list of all prebuilt firearms in the database, optionally only those chambered in a caliber
 // @Tags        Prebuilt Firearms
 // @Accept      json
 // @Produce     json
+// @Param       caliber query string false "Filter by caliber ID, name or alias"
 // @Success     200 {array}  models.PrebuiltFirearm
+// @Failure     400 {object} map[string]string
 // @Router      /prebuilt-firearms [get]
 func GetPrebuiltFirearms(c *gin.Context) {
+	caliberIDs, err := resolveCaliberQuery(c)
+	if err != nil {
+		respondToCaliberQueryError(c, err)
+		return
+	}
+
+	query := db.DB.Preload("Caliber")
+	if caliberIDs != nil {
+		query = query.Where("caliber_id IN ?", caliberIDs)
+	}
+
 	var firearms []models.PrebuiltFirearm
-	db.DB.Find(&firearms)
+	query.Find(&firearms)
 	c.JSON(http.StatusOK, firearms)
 }
 
@@ -52,7 +65,7 @@ func CreatePrebuiltFirearm(c *gin.Context) {
 func GetPrebuiltFirearmByID(c *gin.Context) {
 	id := c.Param("id")
 	var firearm models.PrebuiltFirearm
-	if err := db.DB.First(&firearm, id).Error; err != nil {
+	if err := db.DB.Preload("Caliber").First(&firearm, id).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Prebuilt firearm not found"})
 		return
 	}
@@ -114,6 +127,6 @@ func DeletePrebuiltFirearm(c *gin.Context) {
 func GetPrebuiltFirearmsByModel(c *gin.Context) {
 	modelID := c.Param("modelId")
 	var firearms []models.PrebuiltFirearm
-	db.DB.Where("firearm_model_id = ?", modelID).Find(&firearms)
+	db.DB.Preload("Caliber").Where("firearm_model_id = ?", modelID).Find(&firearms)
 	c.JSON(http.StatusOK, firearms)
 }
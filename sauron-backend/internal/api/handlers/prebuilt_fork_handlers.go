@@ -0,0 +1,288 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"sauron-backend/internal/db"
+	"sauron-backend/internal/models"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// ForkPrebuiltInput is the optional request body for forking a prebuilt firearm
+type ForkPrebuiltInput struct {
+	// Name of the new build, defaults to the prebuilt's name
+	Name string `json:"name" example:"My Standard AR-15"`
+
+	// Description of the new build, defaults to the prebuilt's description
+	Description string `json:"description" example:"Forked from the Standard AR-15 Rifle"`
+}
+
+// ForkUnresolvedEntry is a component of a prebuilt that could not be placed in a slot
+type ForkUnresolvedEntry struct {
+	// Position of the component in the prebuilt's parts tree
+	Path string `json:"path" example:"Lower Assembly > Lower Parts Kit"`
+
+	// Part referenced by the component, if any
+	PartID int `json:"part_id,omitempty" example:"12"`
+
+	// Why the component could not be placed
+	Reason string `json:"reason" example:"No part category named Lower Parts Kit"`
+}
+
+// ForkPrebuiltResult is the build created from a prebuilt firearm
+type ForkPrebuiltResult struct {
+	Build *models.Build `json:"build"`
+
+	// Components of the prebuilt that were not carried over into the build
+	Unresolved []ForkUnresolvedEntry `json:"unresolved"`
+
+	// Validation of the new build, listing any required slots still to be filled
+	Validation *BuildValidationResult `json:"validation"`
+}
+
+// @Summary     Fork a prebuilt firearm into a build
+// @Description Create an editable build pre-filled from a prebuilt firearm's components tree. Component names are matched to the firearm model's part category slots; entries that cannot be placed are reported.
+// @Tags        Prebuilt Firearms,Builds
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "Prebuilt Firearm ID"
+// @Param       fork body ForkPrebuiltInput false "Build details"
+// @Success     201 {object} ForkPrebuiltResult
+// @Failure     400 {object} map[string]string
+// @Failure     404 {object} map[string]string
+// @Failure     422 {object} map[string]string
+// @Failure     500 {object} map[string]string
+// @Router      /prebuilt-firearms/{id}/fork [post]
+func ForkPrebuiltFirearm(c *gin.Context) {
+	id := c.Param("id")
+	var prebuilt models.PrebuiltFirearm
+	if err := db.DB.First(&prebuilt, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Prebuilt firearm not found"})
+		return
+	}
+
+	var input ForkPrebuiltInput
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	if input.Name == "" {
+		input.Name = prebuilt.Name
+	}
+	if input.Description == "" {
+		input.Description = prebuilt.Description
+	}
+
+	components, err := flattenPrebuiltComponents(prebuilt.Parts)
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Prebuilt firearm has malformed parts data"})
+		return
+	}
+
+	slots, unresolved, err := resolvePrebuiltSlots(prebuilt.FirearmModelID, components)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to resolve prebuilt components"})
+		return
+	}
+
+	// Drop any selection the validator rejects so the build can still be saved
+	validation, err := validateBuildSelection(prebuilt.FirearmModelID, slots)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to validate forked build"})
+		return
+	}
+	blocking := validation.blockingErrors(true)
+	for _, e := range blocking {
+		unresolved = append(unresolved, ForkUnresolvedEntry{
+			Path:   slotPath(slots, components, e.PartCategoryID),
+			PartID: e.PartID,
+			Reason: e.Message,
+		})
+		delete(slots, e.PartCategoryID)
+	}
+	if len(blocking) > 0 {
+		if validation, err = validateBuildSelection(prebuilt.FirearmModelID, slots); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to validate forked build"})
+			return
+		}
+	}
+
+	build := models.Build{
+		Name:           input.Name,
+		Description:    input.Description,
+		FirearmModelID: prebuilt.FirearmModelID,
+	}
+	err = db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&build).Error; err != nil {
+			return err
+		}
+		if err := saveBuildParts(tx, build.ID, slots); err != nil {
+			return err
+		}
+		return recordBuildRevision(tx, build, slots)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create build"})
+		return
+	}
+
+	created, err := loadBuild(build.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load build"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, ForkPrebuiltResult{
+		Build:      created,
+		Unresolved: unresolved,
+		Validation: validation,
+	})
+}
+
+// resolvePrebuiltSlots maps each component that references a part onto a slot of the
+// firearm model by matching the component name against part category names. When the
+// name does not match a slot, the part's own category is used instead.
+func resolvePrebuiltSlots(modelID int, components []prebuiltComponent) (map[int]int, []ForkUnresolvedEntry, error) {
+	var relations []models.FirearmModelPartCategory
+	if err := db.DB.Where("firearm_model_id = ?", modelID).Find(&relations).Error; err != nil {
+		return nil, nil, err
+	}
+	isSlot := make(map[int]bool, len(relations))
+	for _, relation := range relations {
+		isSlot[relation.PartCategoryID] = true
+	}
+
+	tree, err := loadCategoryTree()
+	if err != nil {
+		return nil, nil, err
+	}
+	categoriesByName := make(map[string][]int)
+	for id, category := range tree {
+		key := strings.ToLower(strings.TrimSpace(category.Name))
+		categoriesByName[key] = append(categoriesByName[key], id)
+	}
+
+	var partIDs []int
+	for _, component := range components {
+		if component.PartID != nil {
+			partIDs = append(partIDs, *component.PartID)
+		}
+	}
+	partByID := make(map[int]models.Part)
+	if len(partIDs) > 0 {
+		var parts []models.Part
+		if err := db.DB.Where("id IN ?", uniqueInts(partIDs)).Find(&parts).Error; err != nil {
+			return nil, nil, err
+		}
+		for _, part := range parts {
+			partByID[part.ID] = part
+		}
+	}
+
+	slots := make(map[int]int)
+	unresolved := []ForkUnresolvedEntry{}
+	slotByPath := make(map[string]int)
+
+	for _, component := range components {
+		path := component.pathString()
+
+		// Pick the slot whose name matches, preferring one under the parent's slot
+		parentSlot := 0
+		if len(component.Path) > 1 {
+			parentSlot = slotByPath[strings.Join(component.Path[:len(component.Path)-1], " > ")]
+		}
+		slotID := pickSlot(categoriesByName[strings.ToLower(strings.TrimSpace(component.name()))], parentSlot, isSlot, tree)
+		if slotID != 0 {
+			slotByPath[path] = slotID
+		}
+
+		if component.PartID == nil {
+			// Grouping entries are fine as long as something below them names a part
+			if !hasPricedDescendant(component, components) {
+				unresolved = append(unresolved, ForkUnresolvedEntry{
+					Path:   path,
+					Reason: "Component has no part ID",
+				})
+			}
+			continue
+		}
+
+		partID := *component.PartID
+		part, ok := partByID[partID]
+		if !ok {
+			unresolved = append(unresolved, ForkUnresolvedEntry{
+				Path:   path,
+				PartID: partID,
+				Reason: fmt.Sprintf("Part %d does not exist", partID),
+			})
+			continue
+		}
+
+		// Fall back to the part's own category when the name does not match a slot
+		if slotID == 0 && part.PartCategoryID != nil && isSlot[*part.PartCategoryID] {
+			slotID = *part.PartCategoryID
+			slotByPath[path] = slotID
+		}
+		if slotID == 0 {
+			unresolved = append(unresolved, ForkUnresolvedEntry{
+				Path:   path,
+				PartID: partID,
+				Reason: "No part category slot named " + component.name() + " for this firearm model",
+			})
+			continue
+		}
+
+		if existing, taken := slots[slotID]; taken && existing != partID {
+			unresolved = append(unresolved, ForkUnresolvedEntry{
+				Path:   path,
+				PartID: partID,
+				Reason: fmt.Sprintf("Slot %s is already filled by part %d", tree[slotID].Name, existing),
+			})
+			continue
+		}
+		slots[slotID] = partID
+	}
+
+	return slots, unresolved, nil
+}
+
+// pickSlot chooses among same-named categories the one that is a slot of the model,
+// preferring a slot under the parent component's slot and then the lowest ID
+func pickSlot(candidates []int, parentSlot int, isSlot map[int]bool, tree categoryTree) int {
+	sorted := append([]int{}, candidates...)
+	sort.Ints(sorted)
+
+	fallback := 0
+	for _, candidate := range sorted {
+		if !isSlot[candidate] {
+			continue
+		}
+		if parentSlot == 0 || tree.isWithin(candidate, parentSlot) {
+			return candidate
+		}
+		if fallback == 0 {
+			fallback = candidate
+		}
+	}
+	return fallback
+}
+
+// slotPath finds the components tree path that filled a slot, for error reporting
+func slotPath(slots map[int]int, components []prebuiltComponent, slotID int) string {
+	partID, ok := slots[slotID]
+	if !ok {
+		return ""
+	}
+	for _, component := range components {
+		if component.PartID != nil && *component.PartID == partID {
+			return component.pathString()
+		}
+	}
+	return ""
+}
@@ -6,19 +6,27 @@ import (
 	"sauron-backend/internal/models"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 // @Summary     Get all product listings
-// @Description Get a list of all product listings in the database
+// @Description Get a list of all product listings in the database, optionally only those that can ship to a state, with their shipping status there
 // @Tags        Product Listings
 // @Accept      json
 // @Produce     json
-// @Success     200 {array}  models.ProductListing
+// @Param       ship_to query string false "Leave out listings that cannot ship to this state and flag those that must ship to an FFL, e.g. CA or US-CA"
+// @Success     200 {array}  ProductListingWithShipping
+// @Failure     400 {object} map[string]string
+// @Failure     500 {object} map[string]string
 // @Router      /listings [get]
 func GetProductListings(c *gin.Context) {
+	state, ok := shipToParam(c, c.Query("ship_to"))
+	if !ok {
+		return
+	}
 	var listings []models.ProductListing
 	db.DB.Preload("Seller").Find(&listings)
-	c.JSON(http.StatusOK, listings)
+	respondWithShippableListings(c, listings, state)
 }
 
 // @Summary     Create a new product listing
@@ -96,7 +104,13 @@ func UpdateProductListing(c *gin.Context) {
 // @Router      /listings/{id} [delete]
 func DeleteProductListing(c *gin.Context) {
 	id := c.Param("id")
-	if err := db.DB.Delete(&models.ProductListing{}, id).Error; err != nil {
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("product_listing_id = ?", id).Delete(&models.ShippingRestriction{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.ProductListing{}, id).Error
+	})
+	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Listing not found"})
 		return
 	}
@@ -104,48 +118,69 @@ func DeleteProductListing(c *gin.Context) {
 }
 
 // @Summary     Get listings by part ID
-// @Description Get all product listings for a specific part
+// @Description Get all product listings for a specific part, optionally only those that can ship to a state, with their shipping status there
 // @Tags        Product Listings
 // @Accept      json
 // @Produce     json
-// @Param       partId path int true "Part ID"
-// @Success     200 {array} models.ProductListing
+// @Param       partId  path  int    true  "Part ID"
+// @Param       ship_to query string false "Leave out listings that cannot ship to this state and flag those that must ship to an FFL, e.g. CA or US-CA"
+// @Success     200 {array}  ProductListingWithShipping
+// @Failure     400 {object} map[string]string
+// @Failure     500 {object} map[string]string
 // @Router      /listings/part/{partId} [get]
 func GetListingsByPartID(c *gin.Context) {
+	state, ok := shipToParam(c, c.Query("ship_to"))
+	if !ok {
+		return
+	}
 	partID := c.Param("partId")
 	var listings []models.ProductListing
 	db.DB.Preload("Seller").Where("part_id = ?", partID).Find(&listings)
-	c.JSON(http.StatusOK, listings)
+	respondWithShippableListings(c, listings, state)
 }
 
 // @Summary     Get listings by prebuilt ID
-// @Description Get all product listings for a specific prebuilt firearm
+// @Description Get all product listings for a specific prebuilt firearm, optionally only those that can ship to a state, with their shipping status there
 // @Tags        Product Listings
 // @Accept      json
 // @Produce     json
-// @Param       prebuiltId path int true "Prebuilt Firearm ID"
-// @Success     200 {array} models.ProductListing
+// @Param       prebuiltId path  int    true  "Prebuilt Firearm ID"
+// @Param       ship_to    query string false "Leave out listings that cannot ship to this state and flag those that must ship to an FFL, e.g. CA or US-CA"
+// @Success     200 {array}  ProductListingWithShipping
+// @Failure     400 {object} map[string]string
+// @Failure     500 {object} map[string]string
 // @Router      /listings/prebuilt/{prebuiltId} [get]
 func GetListingsByPrebuiltID(c *gin.Context) {
+	state, ok := shipToParam(c, c.Query("ship_to"))
+	if !ok {
+		return
+	}
 	prebuiltID := c.Param("prebuiltId")
 	var listings []models.ProductListing
 	db.DB.Preload("Seller").Where("prebuilt_id = ?", prebuiltID).Find(&listings)
-	c.JSON(http.StatusOK, listings)
+	respondWithShippableListings(c, listings, state)
 }
 
 // @Summary     Get listings by seller
-// @Description Get all product listings for a specific seller
+// @Description Get all product listings for a specific seller, optionally only those that can ship to a state, with their shipping status there
 // @Tags        Product Listings
 // @Accept      json
 // @Produce     json
-// @Param       sellerId path int true "Seller ID"
-// @Success     200 {array} models.ProductListing
+// @Param       sellerId path  int    true  "Seller ID"
+// @Param       ship_to  query string false "Leave out listings that cannot ship to this state and flag those that must ship to an FFL, e.g. CA or US-CA"
+// @Success     200 {array}  ProductListingWithShipping
+// @Failure     400 {object} map[string]string
+// @Failure     500 {object} map[string]string
 // @Router      /listings/seller/{sellerId} [get]
 func GetListingsBySeller(c *gin.Context) {
+	state, ok := shipToParam(c, c.Query("ship_to"))
+	if !ok {
+		return
+	}
 	sellerID := c.Param("sellerId")
 	var listings []models.ProductListing
 	db.DB.Preload("Seller").Where("seller_id = ?", sellerID).Find(&listings)
-	c.JSON(http.StatusOK, listings)
+	respondWithShippableListings(c, listings, state)
 }
 
 // @Summary     Update listing availability
@@ -183,3 +218,38 @@ func UpdateListingAvailability(c *gin.Context) {
 	db.DB.Save(&listing)
 	c.JSON(http.StatusOK, listing)
 }
+
+// ProductListingWithShipping is a listing with its shipping status for the destination state
+// a listing query was limited to
+type ProductListingWithShipping struct {
+	models.ProductListing
+
+	// Shipping status for the ship_to state, omitted when none was given
+	Shipping *ListingShippingStatus `json:"shipping,omitempty"`
+}
+
+// respondWithShippableListings writes listings with their shipping status when a state is
+// given, leaving out those that cannot ship there
+func respondWithShippableListings(c *gin.Context, listings []models.ProductListing, state string) {
+	statuses := map[int]ListingShippingStatus{}
+	if state != "" {
+		var err error
+		if statuses, err = listingShippingStatuses(listings, state); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check shipping restrictions"})
+			return
+		}
+	}
+
+	result := make([]ProductListingWithShipping, 0, len(listings))
+	for _, listing := range listings {
+		item := ProductListingWithShipping{ProductListing: listing}
+		if status, ok := statuses[listing.ID]; ok {
+			if !status.CanShip {
+				continue
+			}
+			item.Shipping = &status
+		}
+		result = append(result, item)
+	}
+	c.JSON(http.StatusOK, result)
+}
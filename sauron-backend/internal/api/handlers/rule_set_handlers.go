@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"sauron-backend/internal/db"
+	"sauron-backend/internal/models"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary     Get ruleset versions
+// @Description Get every imported compatibility ruleset version, newest first
+// @Tags        Compatibility
+// @Accept      json
+// @Produce     json
+// @Success     200 {array} models.RuleSet
+// @Router      /rule-sets [get]
+func GetRuleSets(c *gin.Context) {
+	ruleSets := []models.RuleSet{}
+	db.DB.Order("version desc").Find(&ruleSets)
+	c.JSON(http.StatusOK, ruleSets)
+}
+
+// @Summary     Export a ruleset as YAML
+// @Description Export the current compatibility rules as a YAML ruleset, or a previously imported version exactly as it was imported
+// @Tags        Compatibility
+// @Produce     application/x-yaml
+// @Param       version query int false "Ruleset version to export instead of the current rules"
+// @Success     200 {string} string "YAML ruleset"
+// @Failure     400 {object} map[string]string
+// @Failure     404 {object} map[string]string
+// @Failure     500 {object} map[string]string
+// @Router      /rule-sets/export [get]
+func ExportRuleSetYAML(c *gin.Context) {
+	version := 0
+	if versionParam := c.Query("version"); versionParam != "" {
+		var err error
+		if version, err = strconv.Atoi(versionParam); err != nil || version < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ruleset version"})
+			return
+		}
+	}
+
+	content, err := ExportRuleSet(version)
+	if errors.Is(err, ErrRuleSetNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Ruleset version not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to export ruleset"})
+		return
+	}
+	c.Data(http.StatusOK, "application/x-yaml", content)
+}
+
+// @Summary     Import a YAML ruleset
+// @Description Validate a YAML ruleset and report the rules it adds, removes and changes and the saved builds it would newly invalidate. Unless dry_run is set, all compatibility rules are then atomically replaced by the ruleset as a new version.
+// @Tags        Compatibility
+// @Accept      application/x-yaml
+// @Produce     json
+// @Param       ruleset body string true "YAML ruleset"
+// @Param       dry_run query bool false "Only report the effect of the import"
+// @Success     200 {object} RuleSetImportReport "Dry run report"
+// @Success     201 {object} RuleSetImportReport "Imported"
+// @Failure     400 {object} map[string]interface{} "Invalid ruleset"
+// @Failure     409 {object} map[string]string "Version is not newer than the latest ruleset"
+// @Failure     500 {object} map[string]string
+// @Router      /rule-sets/import [post]
+func ImportRuleSetYAML(c *gin.Context) {
+	content, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read request body"})
+		return
+	}
+	dryRun := c.Query("dry_run") == "true"
+
+	report, err := ImportRuleSet(content, "api", dryRun)
+	var invalid *RuleSetValidationError
+	switch {
+	case errors.As(err, &invalid):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ruleset", "problems": invalid.Problems})
+		return
+	case errors.Is(err, ErrStaleRuleSet):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to import ruleset"})
+		return
+	}
+
+	status := http.StatusCreated
+	if dryRun {
+		status = http.StatusOK
+	}
+	c.JSON(status, report)
+}
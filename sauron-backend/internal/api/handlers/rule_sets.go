@@ -0,0 +1,560 @@
+package handlers
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"sauron-backend/internal/db"
+	"sauron-backend/internal/models"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrRuleSetNotFound is returned when exporting a ruleset version that was never imported
+	ErrRuleSetNotFound = errors.New("ruleset version not found")
+
+	// ErrStaleRuleSet is returned when importing a ruleset whose version is not newer than the latest one
+	ErrStaleRuleSet = errors.New("ruleset version is not newer than the latest imported ruleset")
+)
+
+// RuleSetValidationError lists everything wrong with a ruleset document
+type RuleSetValidationError struct {
+	Problems []string
+}
+
+func (e *RuleSetValidationError) Error() string {
+	return "invalid ruleset: " + strings.Join(e.Problems, "; ")
+}
+
+// RuleSetImportReport summarises what importing a ruleset changes
+type RuleSetImportReport struct {
+	// Whether the ruleset was only checked and not applied
+	DryRun bool `json:"dry_run" example:"true"`
+
+	// Version the ruleset was, or would be, imported as
+	Version         int `json:"version" example:"4"`
+	PreviousVersion int `json:"previous_version" example:"3"`
+
+	Name      string `json:"name" example:"AR-15 core rules"`
+	Checksum  string `json:"checksum" example:"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"`
+	RuleCount int    `json:"rule_count" example:"3"`
+
+	// Rules, by name, the ruleset adds, drops or redefines compared to the current rules
+	AddedRules   []string `json:"added_rules"`
+	RemovedRules []string `json:"removed_rules"`
+	ChangedRules []string `json:"changed_rules"`
+
+	// Saved builds that pass the current rules but fail the ruleset
+	BuildsChecked     int                `json:"builds_checked" example:"12"`
+	InvalidatedBuilds []InvalidatedBuild `json:"invalidated_builds"`
+}
+
+// InvalidatedBuild is a saved build a ruleset would make incompatible
+type InvalidatedBuild struct {
+	BuildID   int    `json:"build_id" example:"7"`
+	BuildName string `json:"build_name" example:"Lightweight 16in Carbine"`
+
+	// Rule checks that fail under the ruleset
+	Failures []RuleCheck `json:"failures"`
+}
+
+// ruleSetDocument is the YAML layout domain experts maintain rulesets in. Categories and
+// parts are referenced by name, or by ID where a name is ambiguous.
+type ruleSetDocument struct {
+	Version     int           `yaml:"version,omitempty"`
+	Name        string        `yaml:"name"`
+	Description string        `yaml:"description,omitempty"`
+	Rules       []ruleSetRule `yaml:"rules"`
+}
+
+type ruleSetRule struct {
+	Name        string             `yaml:"name"`
+	Description string             `yaml:"description,omitempty"`
+	Source      ruleSetOperand     `yaml:"source"`
+	Target      ruleSetOperand     `yaml:"target"`
+	Operator    string             `yaml:"operator,omitempty"`
+	Tolerance   float64            `yaml:"tolerance,omitempty"`
+	Exceptions  []ruleSetException `yaml:"exceptions,omitempty"`
+}
+
+type ruleSetOperand struct {
+	Category  string `yaml:"category"`
+	Attribute string `yaml:"attribute"`
+}
+
+type ruleSetException struct {
+	SourcePart string `yaml:"source_part,omitempty"`
+	TargetPart string `yaml:"target_part,omitempty"`
+	Reason     string `yaml:"reason"`
+}
+
+// ImportRuleSet validates a YAML ruleset and reports which rules it changes and which
+// saved builds it would newly invalidate. Unless dryRun is set it then atomically
+// replaces every compatibility rule with the ruleset, recorded as a new version.
+func ImportRuleSet(content []byte, source string, dryRun bool) (*RuleSetImportReport, error) {
+	document, err := parseRuleSetDocument(content)
+	if err != nil {
+		return nil, err
+	}
+
+	resolver, err := loadRuleSetResolver()
+	if err != nil {
+		return nil, err
+	}
+	rules, err := resolver.rules(document)
+	if err != nil {
+		return nil, err
+	}
+
+	previous, err := latestRuleSetVersion(db.DB)
+	if err != nil {
+		return nil, err
+	}
+	version := document.Version
+	if version == 0 {
+		version = previous + 1
+	} else if version <= previous {
+		return nil, fmt.Errorf("%w: version %d, latest version %d", ErrStaleRuleSet, version, previous)
+	}
+
+	checksum := sha256.Sum256(content)
+	report := &RuleSetImportReport{
+		DryRun:          dryRun,
+		Version:         version,
+		PreviousVersion: previous,
+		Name:            document.Name,
+		Checksum:        hex.EncodeToString(checksum[:]),
+		RuleCount:       len(rules),
+	}
+
+	current, err := loadCompatibilityEngine()
+	if err != nil {
+		return nil, err
+	}
+	report.AddedRules, report.RemovedRules, report.ChangedRules = diffRuleSets(current.rules, rules)
+
+	candidate := &compatibilityEngine{tree: current.tree, rules: rules, calibers: current.calibers}
+	report.BuildsChecked, report.InvalidatedBuilds, err = newlyInvalidatedBuilds(current, candidate)
+	if err != nil {
+		return nil, err
+	}
+
+	if dryRun {
+		return report, nil
+	}
+
+	ruleSet := models.RuleSet{
+		Version:     version,
+		Name:        document.Name,
+		Description: document.Description,
+		Source:      source,
+		Checksum:    report.Checksum,
+		Content:     string(content),
+		RuleCount:   len(rules),
+		Active:      true,
+	}
+	err = db.DB.Transaction(func(tx *gorm.DB) error {
+		// Serialise imports so a concurrent one cannot slip in between the check and the swap
+		if err := tx.Exec("LOCK TABLE rule_sets IN EXCLUSIVE MODE").Error; err != nil {
+			return err
+		}
+		latest, err := latestRuleSetVersion(tx)
+		if err != nil {
+			return err
+		}
+		if latest != previous {
+			return fmt.Errorf("%w: version %d was imported meanwhile", ErrStaleRuleSet, latest)
+		}
+
+		if err := tx.Model(&models.RuleSet{}).Where("active = ?", true).Update("active", false).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(&ruleSet).Error; err != nil {
+			return err
+		}
+
+		everything := tx.Session(&gorm.Session{AllowGlobalUpdate: true})
+		if err := everything.Delete(&models.CompatibilityRuleException{}).Error; err != nil {
+			return err
+		}
+		if err := everything.Delete(&models.CompatibilityRule{}).Error; err != nil {
+			return err
+		}
+		for i := range rules {
+			rules[i].RuleSetID = &ruleSet.ID
+			if err := tx.Omit("SourceCategory", "TargetCategory").Create(&rules[i]).Error; err != nil {
+				return err
+			}
+		}
+		return markCompatibilityIndexStale(tx)
+	})
+	if err != nil {
+		return nil, err
+	}
+	startCompatibilityRebuild()
+	return report, nil
+}
+
+// ExportRuleSet renders a ruleset as YAML. Version 0 exports the current rules, including
+// any edits made through the API since the last import, without a version so the file
+// can be edited and imported as the next one; other versions return the YAML exactly as
+// it was imported.
+func ExportRuleSet(version int) ([]byte, error) {
+	if version > 0 {
+		var ruleSet models.RuleSet
+		if err := db.DB.Where("version = ?", version).First(&ruleSet).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, ErrRuleSetNotFound
+			}
+			return nil, err
+		}
+		return []byte(ruleSet.Content), nil
+	}
+
+	resolver, err := loadRuleSetResolver()
+	if err != nil {
+		return nil, err
+	}
+	var rules []models.CompatibilityRule
+	if err := db.DB.Preload("Exceptions").Order("id").Find(&rules).Error; err != nil {
+		return nil, err
+	}
+
+	document := ruleSetDocument{Name: "Compatibility rules", Rules: []ruleSetRule{}}
+	var latest models.RuleSet
+	if err := db.DB.Order("version desc").First(&latest).Error; err == nil {
+		document.Name = latest.Name
+		document.Description = latest.Description
+	}
+
+	for _, rule := range rules {
+		entry := ruleSetRule{
+			Name:        rule.Name,
+			Description: rule.Description,
+			Source:      ruleSetOperand{Category: resolver.categoryReference(rule.SourceCategoryID), Attribute: rule.SourceAttribute},
+			Target:      ruleSetOperand{Category: resolver.categoryReference(rule.TargetCategoryID), Attribute: rule.TargetAttribute},
+			Operator:    rule.Operator,
+			Tolerance:   rule.Tolerance,
+		}
+		for _, exception := range rule.Exceptions {
+			entry.Exceptions = append(entry.Exceptions, ruleSetException{
+				SourcePart: resolver.partReference(exception.SourcePartID),
+				TargetPart: resolver.partReference(exception.TargetPartID),
+				Reason:     exception.Reason,
+			})
+		}
+		document.Rules = append(document.Rules, entry)
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(document); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// parseRuleSetDocument decodes a ruleset, rejecting unknown keys so typos surface
+func parseRuleSetDocument(content []byte) (*ruleSetDocument, error) {
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+
+	var document ruleSetDocument
+	if err := decoder.Decode(&document); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, &RuleSetValidationError{Problems: []string{"ruleset is empty"}}
+		}
+		return nil, &RuleSetValidationError{Problems: []string{err.Error()}}
+	}
+	return &document, nil
+}
+
+// latestRuleSetVersion returns the highest imported version, or 0 if none was imported
+func latestRuleSetVersion(tx *gorm.DB) (int, error) {
+	var version int
+	err := tx.Model(&models.RuleSet{}).Select("COALESCE(MAX(version), 0)").Scan(&version).Error
+	return version, err
+}
+
+// ruleSetResolver maps the category and part references in a ruleset to IDs and back
+type ruleSetResolver struct {
+	categoriesByName map[string][]int
+	categoryNames    map[int]string
+	partsByName      map[string][]int
+	partNames        map[int]string
+}
+
+func loadRuleSetResolver() (*ruleSetResolver, error) {
+	var categories []models.PartCategory
+	if err := db.DB.Select("id", "name").Find(&categories).Error; err != nil {
+		return nil, err
+	}
+	var parts []models.Part
+	if err := db.DB.Select("id", "name").Find(&parts).Error; err != nil {
+		return nil, err
+	}
+
+	resolver := &ruleSetResolver{
+		categoriesByName: make(map[string][]int),
+		categoryNames:    make(map[int]string, len(categories)),
+		partsByName:      make(map[string][]int),
+		partNames:        make(map[int]string, len(parts)),
+	}
+	for _, category := range categories {
+		key := strings.ToLower(strings.TrimSpace(category.Name))
+		resolver.categoriesByName[key] = append(resolver.categoriesByName[key], category.ID)
+		resolver.categoryNames[category.ID] = category.Name
+	}
+	for _, part := range parts {
+		key := strings.ToLower(strings.TrimSpace(part.Name))
+		resolver.partsByName[key] = append(resolver.partsByName[key], part.ID)
+		resolver.partNames[part.ID] = part.Name
+	}
+	return resolver, nil
+}
+
+// resolveReference finds the ID a name or numeric ID refers to
+func resolveReference(ref, kind string, byName map[string][]int, names map[int]string) (int, error) {
+	ids := byName[strings.ToLower(strings.TrimSpace(ref))]
+	if len(ids) == 1 {
+		return ids[0], nil
+	}
+	if len(ids) > 1 {
+		sort.Ints(ids)
+		return 0, fmt.Errorf("%s name %q is ambiguous, use one of the IDs %v", kind, ref, ids)
+	}
+	if id, err := strconv.Atoi(ref); err == nil {
+		if _, ok := names[id]; ok {
+			return id, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown %s %q", kind, ref)
+}
+
+// reference names an ID for export, falling back to the ID when the name is ambiguous
+func reference(id int, byName map[string][]int, names map[int]string) string {
+	name, ok := names[id]
+	if !ok || len(byName[strings.ToLower(strings.TrimSpace(name))]) != 1 {
+		return strconv.Itoa(id)
+	}
+	return name
+}
+
+func (r *ruleSetResolver) categoryReference(id int) string {
+	return reference(id, r.categoriesByName, r.categoryNames)
+}
+
+func (r *ruleSetResolver) partReference(id *int) string {
+	if id == nil {
+		return ""
+	}
+	return reference(*id, r.partsByName, r.partNames)
+}
+
+// rules converts a ruleset document to unsaved rules, collecting every problem found
+func (r *ruleSetResolver) rules(document *ruleSetDocument) ([]models.CompatibilityRule, error) {
+	var problems []string
+	if strings.TrimSpace(document.Name) == "" {
+		problems = append(problems, "name is required")
+	}
+	if document.Version < 0 {
+		problems = append(problems, "version cannot be negative")
+	}
+
+	rules := []models.CompatibilityRule{}
+	seen := make(map[string]bool)
+	for i, entry := range document.Rules {
+		label := fmt.Sprintf("rule %d", i+1)
+		if entry.Name != "" {
+			label = fmt.Sprintf("rule %q", entry.Name)
+		}
+		problem := func(format string, args ...interface{}) {
+			problems = append(problems, label+": "+fmt.Sprintf(format, args...))
+		}
+
+		switch {
+		case entry.Name == "":
+			problem("name is required")
+		case seen[entry.Name]:
+			problem("name is used by more than one rule")
+		}
+		seen[entry.Name] = true
+		if entry.Source.Attribute == "" || entry.Target.Attribute == "" {
+			problem("source.attribute and target.attribute are required")
+		}
+
+		rule := models.CompatibilityRule{
+			Name:            entry.Name,
+			Description:     entry.Description,
+			SourceAttribute: entry.Source.Attribute,
+			TargetAttribute: entry.Target.Attribute,
+			Operator:        entry.Operator,
+			Tolerance:       entry.Tolerance,
+		}
+		if entry.Name != "" && rule.SourceAttribute != "" && rule.TargetAttribute != "" {
+			if err := validateRuleFields(&rule); err != nil {
+				problem("%s", err)
+			}
+		}
+
+		var err error
+		if rule.SourceCategoryID, err = resolveReference(entry.Source.Category, "category", r.categoriesByName, r.categoryNames); err != nil {
+			problem("source: %s", err)
+		}
+		if rule.TargetCategoryID, err = resolveReference(entry.Target.Category, "category", r.categoriesByName, r.categoryNames); err != nil {
+			problem("target: %s", err)
+		}
+
+		for j, entryException := range entry.Exceptions {
+			if entryException.SourcePart == "" && entryException.TargetPart == "" {
+				problem("exception %d must name a source_part or a target_part", j+1)
+				continue
+			}
+			if strings.TrimSpace(entryException.Reason) == "" {
+				problem("exception %d needs a reason", j+1)
+			}
+
+			exception := models.CompatibilityRuleException{Reason: entryException.Reason}
+			if entryException.SourcePart != "" {
+				id, err := resolveReference(entryException.SourcePart, "part", r.partsByName, r.partNames)
+				if err != nil {
+					problem("exception %d: %s", j+1, err)
+				}
+				exception.SourcePartID = &id
+			}
+			if entryException.TargetPart != "" {
+				id, err := resolveReference(entryException.TargetPart, "part", r.partsByName, r.partNames)
+				if err != nil {
+					problem("exception %d: %s", j+1, err)
+				}
+				exception.TargetPartID = &id
+			}
+			rule.Exceptions = append(rule.Exceptions, exception)
+		}
+
+		rules = append(rules, rule)
+	}
+
+	if len(problems) > 0 {
+		return nil, &RuleSetValidationError{Problems: problems}
+	}
+	return rules, nil
+}
+
+// diffRuleSets compares two sets of rules by name
+func diffRuleSets(current, next []models.CompatibilityRule) (added, removed, changed []string) {
+	signatures := func(rules []models.CompatibilityRule) map[string]string {
+		byName := make(map[string][]string)
+		for _, rule := range rules {
+			byName[rule.Name] = append(byName[rule.Name], ruleSignature(rule))
+		}
+		result := make(map[string]string, len(byName))
+		for name, list := range byName {
+			sort.Strings(list)
+			result[name] = strings.Join(list, "\n")
+		}
+		return result
+	}
+	before, after := signatures(current), signatures(next)
+
+	added, removed, changed = []string{}, []string{}, []string{}
+	for name, signature := range after {
+		previous, ok := before[name]
+		switch {
+		case !ok:
+			added = append(added, name)
+		case previous != signature:
+			changed = append(changed, name)
+		}
+	}
+	for name := range before {
+		if _, ok := after[name]; !ok {
+			removed = append(removed, name)
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(removed)
+	sort.Strings(changed)
+	return added, removed, changed
+}
+
+// ruleSignature captures everything about a rule that affects its outcome or explanation
+func ruleSignature(rule models.CompatibilityRule) string {
+	exceptions := make([]string, 0, len(rule.Exceptions))
+	for _, exception := range rule.Exceptions {
+		exceptions = append(exceptions, fmt.Sprintf("%s>%s:%s", optionalID(exception.SourcePartID), optionalID(exception.TargetPartID), exception.Reason))
+	}
+	sort.Strings(exceptions)
+
+	return fmt.Sprintf("%s|%d.%s|%s|%d.%s|%g|%s",
+		rule.Description,
+		rule.SourceCategoryID, rule.SourceAttribute,
+		rule.Operator,
+		rule.TargetCategoryID, rule.TargetAttribute,
+		rule.Tolerance,
+		strings.Join(exceptions, ","))
+}
+
+func optionalID(id *int) string {
+	if id == nil {
+		return "*"
+	}
+	return strconv.Itoa(*id)
+}
+
+// newlyInvalidatedBuilds returns the saved builds that have no failing rule under the
+// current engine but do under the candidate, along with how many builds were checked
+func newlyInvalidatedBuilds(current, candidate *compatibilityEngine) (int, []InvalidatedBuild, error) {
+	var builds []models.Build
+	if err := db.DB.Preload("Parts.Part").Order("id").Find(&builds).Error; err != nil {
+		return 0, nil, err
+	}
+
+	invalidated := []InvalidatedBuild{}
+	for _, build := range builds {
+		parts := make([]models.Part, 0, len(build.Parts))
+		seen := make(map[int]bool)
+		for _, buildPart := range build.Parts {
+			if !seen[buildPart.PartID] {
+				seen[buildPart.PartID] = true
+				parts = append(parts, buildPart.Part)
+			}
+		}
+
+		if len(failingChecks(current, parts)) > 0 {
+			continue
+		}
+		if failures := failingChecks(candidate, parts); len(failures) > 0 {
+			invalidated = append(invalidated, InvalidatedBuild{BuildID: build.ID, BuildName: build.Name, Failures: failures})
+		}
+	}
+	return len(builds), invalidated, nil
+}
+
+// failingChecks returns every failing rule check between pairs of the parts
+func failingChecks(engine *compatibilityEngine, parts []models.Part) []RuleCheck {
+	failures := []RuleCheck{}
+	for i := range parts {
+		for j := i + 1; j < len(parts); j++ {
+			_, checks := engine.check(parts[i], parts[j])
+			for _, check := range checks {
+				if check.Result == RuleResultFail {
+					failures = append(failures, check)
+				}
+			}
+		}
+	}
+	return failures
+}
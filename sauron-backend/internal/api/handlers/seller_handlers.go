@@ -6,6 +6,7 @@ import (
 	"sauron-backend/internal/models"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 // @Summary     Get all sellers
@@ -96,7 +97,13 @@ func UpdateSeller(c *gin.Context) {
 // @Router      /sellers/{id} [delete]
 func DeleteSeller(c *gin.Context) {
 	id := c.Param("id")
-	if err := db.DB.Delete(&models.Seller{}, id).Error; err != nil {
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("seller_id = ?", id).Delete(&models.ShippingRestriction{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Seller{}, id).Error
+	})
+	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Seller not found"})
 		return
 	}
@@ -0,0 +1,222 @@
+package handlers
+
+import (
+	"net/http"
+	"sauron-backend/internal/db"
+	"sauron-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary     Get all shipping restrictions
+// @Description Get the destination restrictions sellers and listings declare, optionally filtered by seller, listing or state. The seller filter covers only seller-wide restrictions.
+// @Tags        Shipping Restrictions
+// @Accept      json
+// @Produce     json
+// @Param       seller_id  query int    false "Only seller-wide restrictions of this seller"
+// @Param       listing_id query int    false "Only restrictions of this listing"
+// @Param       state      query string false "Only restrictions covering this state, including those covering every state"
+// @Success     200 {array}  models.ShippingRestriction
+// @Failure     400 {object} map[string]string
+// @Router      /shipping-restrictions [get]
+func GetShippingRestrictions(c *gin.Context) {
+	query := db.DB.Order("id")
+	if sellerID := c.Query("seller_id"); sellerID != "" {
+		query = query.Where("seller_id = ?", sellerID)
+	}
+	if listingID := c.Query("listing_id"); listingID != "" {
+		query = query.Where("product_listing_id = ?", listingID)
+	}
+	if stateParam := c.Query("state"); stateParam != "" {
+		state, err := normalizeStateCode(stateParam)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		query = query.Where("(state = '' OR state IS NULL OR state = ?)", state)
+	}
+
+	restrictions := []models.ShippingRestriction{}
+	query.Find(&restrictions)
+	c.JSON(http.StatusOK, restrictions)
+}
+
+// @Summary     Get a shipping restriction
+// @Description Get a specific shipping restriction
+// @Tags        Shipping Restrictions
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "Shipping Restriction ID"
+// @Success     200 {object} models.ShippingRestriction
+// @Failure     404 {object} map[string]string
+// @Router      /shipping-restrictions/{id} [get]
+func GetShippingRestrictionByID(c *gin.Context) {
+	var restriction models.ShippingRestriction
+	if err := db.DB.First(&restriction, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Shipping restriction not found"})
+		return
+	}
+	c.JSON(http.StatusOK, restriction)
+}
+
+// @Summary     Create a shipping restriction
+// @Description Declare a state a seller, or one of its listings, does not ship to or only ships to through an FFL, optionally limited to magazines, ammunition or firearms
+// @Tags        Shipping Restrictions
+// @Accept      json
+// @Produce     json
+// @Param       restriction body models.ShippingRestriction true "Shipping restriction to create"
+// @Success     201 {object} models.ShippingRestriction
+// @Failure     400 {object} map[string]string
+// @Failure     500 {object} map[string]string
+// @Router      /shipping-restrictions [post]
+func CreateShippingRestriction(c *gin.Context) {
+	var restriction models.ShippingRestriction
+	if err := c.ShouldBindJSON(&restriction); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	restriction.ID = 0
+	if !checkShippingRestriction(c, &restriction) {
+		return
+	}
+
+	if err := db.DB.Create(&restriction).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create shipping restriction"})
+		return
+	}
+	c.JSON(http.StatusCreated, restriction)
+}
+
+// @Summary     Update a shipping restriction
+// @Description Replace an existing shipping restriction; fields left out of the body are cleared
+// @Tags        Shipping Restrictions
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "Shipping Restriction ID"
+// @Param       restriction body models.ShippingRestriction true "Updated shipping restriction"
+// @Success     200 {object} models.ShippingRestriction
+// @Failure     400 {object} map[string]string
+// @Failure     404 {object} map[string]string
+// @Failure     500 {object} map[string]string
+// @Router      /shipping-restrictions/{id} [put]
+func UpdateShippingRestriction(c *gin.Context) {
+	var restriction models.ShippingRestriction
+	if err := db.DB.First(&restriction, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Shipping restriction not found"})
+		return
+	}
+	// Bound into a fresh struct so a restriction can move between a seller and a listing
+	// without the client having to null the field it no longer uses
+	var input models.ShippingRestriction
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	input.ID, input.CreatedAt = restriction.ID, restriction.CreatedAt
+	if !checkShippingRestriction(c, &input) {
+		return
+	}
+
+	if err := db.DB.Save(&input).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update shipping restriction"})
+		return
+	}
+	c.JSON(http.StatusOK, input)
+}
+
+// @Summary     Delete a shipping restriction
+// @Description Delete a specific shipping restriction
+// @Tags        Shipping Restrictions
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "Shipping Restriction ID"
+// @Success     204 "No Content"
+// @Failure     404 {object} map[string]string
+// @Router      /shipping-restrictions/{id} [delete]
+func DeleteShippingRestriction(c *gin.Context) {
+	result := db.DB.Delete(&models.ShippingRestriction{}, c.Param("id"))
+	if result.Error != nil || result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Shipping restriction not found"})
+		return
+	}
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// @Summary     Check whether a listing ships to a state
+// @Description Apply the shipping restrictions of a listing and its seller to a destination state and report whether the listing can ship there and whether it must go through an FFL
+// @Tags        Product Listings,Shipping Restrictions
+// @Accept      json
+// @Produce     json
+// @Param       id      path  int    true "Product Listing ID"
+// @Param       ship_to query string true "Destination state code, e.g. CA or US-CA"
+// @Success     200 {object} ListingShippingStatus
+// @Failure     400 {object} map[string]string
+// @Failure     404 {object} map[string]string
+// @Failure     500 {object} map[string]string
+// @Router      /listings/{id}/shipping [get]
+func GetListingShippingStatus(c *gin.Context) {
+	if c.Query("ship_to") == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ship_to is required"})
+		return
+	}
+	state, ok := shipToParam(c, c.Query("ship_to"))
+	if !ok {
+		return
+	}
+	var listing models.ProductListing
+	if err := db.DB.First(&listing, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Listing not found"})
+		return
+	}
+
+	statuses, err := listingShippingStatuses([]models.ProductListing{listing}, state)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check shipping restrictions"})
+		return
+	}
+	c.JSON(http.StatusOK, statuses[listing.ID])
+}
+
+// checkShippingRestriction validates a shipping restriction, normalizing its state, and
+// writes the error response when it is invalid
+func checkShippingRestriction(c *gin.Context, restriction *models.ShippingRestriction) bool {
+	restriction.Seller = nil
+	restriction.ProductListing = nil
+
+	if (restriction.SellerID == nil) == (restriction.ProductListingID == nil) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Exactly one of seller_id and product_listing_id is required"})
+		return false
+	}
+	var count int64
+	if restriction.SellerID != nil {
+		db.DB.Model(&models.Seller{}).Where("id = ?", *restriction.SellerID).Count(&count)
+		if count == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Seller not found"})
+			return false
+		}
+	} else {
+		db.DB.Model(&models.ProductListing{}).Where("id = ?", *restriction.ProductListingID).Count(&count)
+		if count == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Listing not found"})
+			return false
+		}
+	}
+
+	if restriction.State != "" {
+		state, err := normalizeStateCode(restriction.State)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return false
+		}
+		restriction.State = state
+	}
+	if restriction.Type != models.ShippingRestrictionNoShip && restriction.Type != models.ShippingRestrictionFFLRequired {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "type must be no_ship or ffl_required"})
+		return false
+	}
+	if err := validateShippingClass(restriction.ShippingClass); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return false
+	}
+	return true
+}
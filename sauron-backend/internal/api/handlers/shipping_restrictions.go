@@ -0,0 +1,249 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"sauron-backend/internal/db"
+	"sauron-backend/internal/models"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+var validShippingClasses = map[string]bool{
+	models.ShippingClassMagazine:   true,
+	models.ShippingClassAmmunition: true,
+	models.ShippingClassFirearm:    true,
+}
+
+// ListingShippingStatus is whether a listing can be shipped to a destination state
+type ListingShippingStatus struct {
+	ListingID int    `json:"listing_id" example:"12"`
+	ShipTo    string `json:"ship_to" example:"CA"`
+
+	// Shipping class of the listed item, if it has one
+	ShippingClass string `json:"shipping_class,omitempty" example:"magazine"`
+
+	// Whether the seller ships the item to the state at all
+	CanShip bool `json:"can_ship" example:"false"`
+
+	// Whether the item only ships to a licensed dealer (FFL) in the state
+	FFLRequired bool `json:"ffl_required" example:"false"`
+
+	// Reasons of the restrictions that apply
+	Reasons []string `json:"reasons" example:"We do not ship magazines to California"`
+}
+
+// shippableListings are the in-stock listings of a set of parts that can ship to a destination
+type shippableListings struct {
+	// Listings that can ship, by part ID, cheapest landed price first
+	byPart map[int][]models.ProductListing
+
+	// Shipping status of every in-stock listing considered, by listing ID
+	status map[int]ListingShippingStatus
+
+	// Parts with in-stock listings none of which can ship to the destination, with the
+	// reasons the restrictions give
+	restricted map[int][]string
+}
+
+// validateShippingClass checks that a part category shipping class is empty or known
+func validateShippingClass(class string) error {
+	if class != "" && !validShippingClasses[class] {
+		return errors.New("shipping_class must be one of magazine, ammunition or firearm")
+	}
+	return nil
+}
+
+// normalizeStateCode turns a destination state such as "ca" or the jurisdiction code "US-CA"
+// into its two-letter code
+func normalizeStateCode(value string) (string, error) {
+	code := strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(value)), "US-")
+	if len(code) != 2 || code[0] < 'A' || code[0] > 'Z' || code[1] < 'A' || code[1] > 'Z' {
+		return "", errors.New("state must be a two-letter state code such as CA or a jurisdiction code such as US-CA")
+	}
+	return code, nil
+}
+
+// shipToParam reads the optional ship_to destination state, writing the error response and
+// returning false when it is invalid
+func shipToParam(c *gin.Context, value string) (string, bool) {
+	if value == "" {
+		return "", true
+	}
+	state, err := normalizeStateCode(value)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ship_to: " + err.Error()})
+		return "", false
+	}
+	return state, true
+}
+
+// listingShippingClass is the shipping class of a listed item: prebuilt firearms are
+// firearms, parts take the class of their nearest classed category, and parts sold as a
+// magazine variant are magazines whatever their category
+func listingShippingClass(tree categoryTree, listing models.ProductListing, partCategory map[int]int, magazineParts map[int]bool) string {
+	if listing.PrebuiltID != nil {
+		return models.ShippingClassFirearm
+	}
+	if listing.PartID == nil {
+		return ""
+	}
+	if magazineParts[*listing.PartID] {
+		return models.ShippingClassMagazine
+	}
+	categoryID, ok := partCategory[*listing.PartID]
+	if !ok {
+		return ""
+	}
+	for _, id := range append([]int{categoryID}, tree.ancestors(categoryID)...) {
+		if class := tree[id].ShippingClass; class != "" {
+			return class
+		}
+	}
+	return ""
+}
+
+// listingShippingStatuses works out whether each listing can ship to a state from the
+// restrictions of its seller and of the listing itself. A no_ship restriction makes the
+// listing unshippable and an ffl_required one flags it; either applies when it covers the
+// state, or every state, and the item's shipping class, or every item.
+func listingShippingStatuses(listings []models.ProductListing, state string) (map[int]ListingShippingStatus, error) {
+	statuses := make(map[int]ListingShippingStatus, len(listings))
+	if len(listings) == 0 {
+		return statuses, nil
+	}
+
+	listingIDs := make([]int, 0, len(listings))
+	sellerIDs := make([]int, 0, len(listings))
+	partIDs := []int{}
+	for _, listing := range listings {
+		listingIDs = append(listingIDs, listing.ID)
+		sellerIDs = append(sellerIDs, listing.SellerID)
+		if listing.PartID != nil {
+			partIDs = append(partIDs, *listing.PartID)
+		}
+	}
+
+	var restrictions []models.ShippingRestriction
+	err := db.DB.Where("(seller_id IN ? OR product_listing_id IN ?)", uniqueInts(sellerIDs), listingIDs).
+		Where("(state = '' OR state IS NULL OR state = ?)", state).
+		Order("id").Find(&restrictions).Error
+	if err != nil {
+		return nil, err
+	}
+
+	tree, err := loadCategoryTree()
+	if err != nil {
+		return nil, err
+	}
+	partCategory := make(map[int]int, len(partIDs))
+	magazineParts := make(map[int]bool)
+	if len(partIDs) > 0 {
+		partIDs = uniqueInts(partIDs)
+		var parts []models.Part
+		if err := db.DB.Select("id", "part_category_id").Where("id IN ?", partIDs).Find(&parts).Error; err != nil {
+			return nil, err
+		}
+		for _, part := range parts {
+			if part.PartCategoryID != nil {
+				partCategory[part.ID] = *part.PartCategoryID
+			}
+		}
+		var variantPartIDs []int
+		if err := db.DB.Model(&models.MagazineVariant{}).Where("part_id IN ?", partIDs).Pluck("part_id", &variantPartIDs).Error; err != nil {
+			return nil, err
+		}
+		for _, partID := range variantPartIDs {
+			magazineParts[partID] = true
+		}
+	}
+
+	for _, listing := range listings {
+		status := ListingShippingStatus{
+			ListingID:     listing.ID,
+			ShipTo:        state,
+			ShippingClass: listingShippingClass(tree, listing, partCategory, magazineParts),
+			CanShip:       true,
+			Reasons:       []string{},
+		}
+		for _, restriction := range restrictions {
+			covers := (restriction.ProductListingID != nil && *restriction.ProductListingID == listing.ID) ||
+				(restriction.SellerID != nil && *restriction.SellerID == listing.SellerID)
+			if !covers || (restriction.ShippingClass != "" && restriction.ShippingClass != status.ShippingClass) {
+				continue
+			}
+			switch restriction.Type {
+			case models.ShippingRestrictionNoShip:
+				status.CanShip = false
+			case models.ShippingRestrictionFFLRequired:
+				status.FFLRequired = true
+			}
+			if restriction.Reason != "" {
+				status.Reasons = append(status.Reasons, restriction.Reason)
+			}
+		}
+		statuses[listing.ID] = status
+	}
+	return statuses, nil
+}
+
+// loadShippableListingsByPart fetches the in-stock listings for a set of parts like
+// loadInStockListingsByPart, leaving out those that cannot ship to the state. Every listing
+// ships when state is empty.
+func loadShippableListingsByPart(partIDs []int, state string) (*shippableListings, error) {
+	byPart, err := loadInStockListingsByPart(partIDs)
+	if err != nil {
+		return nil, err
+	}
+	result := &shippableListings{byPart: byPart, status: map[int]ListingShippingStatus{}, restricted: map[int][]string{}}
+	if state == "" {
+		return result, nil
+	}
+
+	var all []models.ProductListing
+	for _, listings := range byPart {
+		all = append(all, listings...)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].ID < all[j].ID })
+	if result.status, err = listingShippingStatuses(all, state); err != nil {
+		return nil, err
+	}
+
+	for partID, listings := range byPart {
+		shippable := make([]models.ProductListing, 0, len(listings))
+		reasons := []string{}
+		seen := map[string]bool{}
+		for _, listing := range listings {
+			status := result.status[listing.ID]
+			if status.CanShip {
+				shippable = append(shippable, listing)
+				continue
+			}
+			for _, reason := range status.Reasons {
+				if !seen[reason] {
+					seen[reason] = true
+					reasons = append(reasons, reason)
+				}
+			}
+		}
+		if len(shippable) == 0 {
+			delete(byPart, partID)
+			result.restricted[partID] = reasons
+			continue
+		}
+		byPart[partID] = shippable
+	}
+	return result, nil
+}
+
+// restrictedPartIDs lists, in ascending order, the parts whose listings cannot ship to the destination
+func (s *shippableListings) restrictedPartIDs() []int {
+	partIDs := make([]int, 0, len(s.restricted))
+	for partID := range s.restricted {
+		partIDs = append(partIDs, partID)
+	}
+	sort.Ints(partIDs)
+	return partIDs
+}
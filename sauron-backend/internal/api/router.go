@@ -15,7 +15,7 @@ func SetupRouter() *gin.Engine {
 		AllowOrigins:     []string{"http://localhost:3000"},
 		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
 		AllowHeaders:     []string{"Origin", "Content-Type", "Accept"},
-		ExposeHeaders:    []string{"Content-Length"},
+		ExposeHeaders:    []string{"Content-Length", "X-Total-Count"},
 		AllowCredentials: true,
 	}))
 
@@ -35,9 +35,54 @@ func SetupRouter() *gin.Engine {
 	// NEW: Hierarchical categories endpoint
 	router.GET("/firearm-models/:id/categories-hierarchy", handlers.GetFirearmModelCategoriesHierarchy)
 
+	// Explicit part fitment to firearm models
+	router.GET("/firearm-models/:id/parts", handlers.GetFirearmModelParts)
+
 	// NEW: Alternative endpoint for part categories by firearm - maps to the same handler
 	router.GET("/part-categories/firearm/:id", handlers.GetFirearmModelCategories)
 
+	// Calibers
+	router.GET("/calibers", handlers.GetCalibers)
+	router.POST("/calibers", handlers.CreateCaliber)
+	router.GET("/calibers/:id", handlers.GetCaliberByID)
+	router.PUT("/calibers/:id", handlers.UpdateCaliber)
+	router.DELETE("/calibers/:id", handlers.DeleteCaliber)
+
+	// Mounting Interfaces
+	router.GET("/mounting-interfaces", handlers.GetMountingInterfaces)
+	router.POST("/mounting-interfaces", handlers.CreateMountingInterface)
+	router.GET("/mounting-interfaces/:id", handlers.GetMountingInterfaceByID)
+	router.PUT("/mounting-interfaces/:id", handlers.UpdateMountingInterface)
+	router.DELETE("/mounting-interfaces/:id", handlers.DeleteMountingInterface)
+
+	// Magazines
+	router.GET("/magazine-families", handlers.GetMagazineFamilies)
+	router.POST("/magazine-families", handlers.CreateMagazineFamily)
+	router.GET("/magazine-families/:id", handlers.GetMagazineFamilyByID)
+	router.PUT("/magazine-families/:id", handlers.UpdateMagazineFamily)
+	router.DELETE("/magazine-families/:id", handlers.DeleteMagazineFamily)
+	router.GET("/magazine-families/:id/variants", handlers.GetMagazineVariants)
+	router.POST("/magazine-families/:id/variants", handlers.CreateMagazineVariant)
+	router.PUT("/magazine-families/:id/variants/:variant_id", handlers.UpdateMagazineVariant)
+	router.DELETE("/magazine-families/:id/variants/:variant_id", handlers.DeleteMagazineVariant)
+	router.GET("/magazine-compatibility", handlers.GetMagazineCompatibilityMatrix)
+
+	// Laws
+	router.GET("/jurisdictions", handlers.GetJurisdictions)
+	router.POST("/jurisdictions", handlers.CreateJurisdiction)
+	router.GET("/jurisdictions/:id", handlers.GetJurisdictionByID)
+	router.PUT("/jurisdictions/:id", handlers.UpdateJurisdiction)
+	router.DELETE("/jurisdictions/:id", handlers.DeleteJurisdiction)
+	router.GET("/law-rules", handlers.GetLawRules)
+	router.POST("/law-rules", handlers.CreateLawRule)
+	router.GET("/law-rules/:id", handlers.GetLawRuleByID)
+	router.PUT("/law-rules/:id", handlers.UpdateLawRule)
+	router.DELETE("/law-rules/:id", handlers.DeleteLawRule)
+	router.GET("/law-rules/:id/history", handlers.GetLawRuleHistory)
+	router.GET("/law-rule-changes", handlers.GetLawRuleChanges)
+	router.GET("/laws/check", handlers.CheckLaws)
+	router.POST("/laws/check", handlers.CheckSelectionLaws)
+
 	// Parts
 	router.GET("/parts", handlers.GetParts)
 	router.POST("/parts", handlers.CreatePart)
@@ -46,6 +91,14 @@ func SetupRouter() *gin.Engine {
 	router.DELETE("/parts/:id", handlers.DeletePart)
 	router.GET("/parts/category/:category", handlers.GetPartsByCategory)
 	router.GET("/parts/:id/compatible", handlers.GetCompatibleParts)
+	router.GET("/parts/:id/fits", handlers.GetPartFitments)
+	router.PUT("/parts/:id/fits/:model_id", handlers.SetPartFitment)
+	router.DELETE("/parts/:id/fits/:model_id", handlers.RemovePartFitment)
+	router.GET("/parts/:id/mounts", handlers.GetPartMounts)
+	router.POST("/parts/:id/mounts", handlers.CreatePartMount)
+	router.PUT("/parts/:id/mounts/:mount_id", handlers.UpdatePartMount)
+	router.DELETE("/parts/:id/mounts/:mount_id", handlers.DeletePartMount)
+	router.GET("/parts/:id/firearms", handlers.GetMagazineFirearms)
 
 	// Legacy Part metadata endpoints (will be deprecated)
 	router.GET("/legacy/part-categories", handlers.GetLegacyPartCategories)
@@ -60,9 +113,21 @@ func SetupRouter() *gin.Engine {
 	router.POST("/part-categories", handlers.CreatePartCategory)
 	router.PUT("/part-categories/:id", handlers.UpdatePartCategory)
 	router.DELETE("/part-categories/:id", handlers.DeletePartCategory)
-
-	// Compatibility Rules - Removed as per new schema design
-	// These routes are no longer needed as compatibility information is now embedded in the models
+	router.GET("/part-categories/:id/attribute-schema", handlers.GetPartCategoryAttributeSchema)
+
+	// Compatibility Rules
+	router.GET("/compatibility-rules", handlers.GetCompatibilityRules)
+	router.POST("/compatibility-rules", handlers.CreateCompatibilityRule)
+	router.GET("/compatibility-rules/:id", handlers.GetCompatibilityRuleByID)
+	router.PUT("/compatibility-rules/:id", handlers.UpdateCompatibilityRule)
+	router.DELETE("/compatibility-rules/:id", handlers.DeleteCompatibilityRule)
+
+	// Versioned YAML rulesets
+	router.GET("/rule-sets", handlers.GetRuleSets)
+	router.GET("/rule-sets/export", handlers.ExportRuleSetYAML)
+	router.POST("/rule-sets/import", handlers.ImportRuleSetYAML)
+	router.GET("/compatibility/check", handlers.CheckCompatibility)
+	router.POST("/compatibility/check", handlers.CheckPartsCompatibility)
 
 	// Prebuilt Firearms
 	router.GET("/prebuilt-firearms", handlers.GetPrebuiltFirearms)
@@ -71,6 +136,9 @@ func SetupRouter() *gin.Engine {
 	router.PUT("/prebuilt-firearms/:id", handlers.UpdatePrebuiltFirearm)
 	router.DELETE("/prebuilt-firearms/:id", handlers.DeletePrebuiltFirearm)
 	router.GET("/prebuilt-firearms/model/:modelId", handlers.GetPrebuiltFirearmsByModel)
+	router.GET("/prebuilt-firearms/:id/build-vs-buy", handlers.GetBuildVsBuy)
+	router.POST("/prebuilt-firearms/:id/fork", handlers.ForkPrebuiltFirearm)
+	router.GET("/prebuilt-firearms/:id/nfa", handlers.GetPrebuiltFirearmNFAClassification)
 
 	// User Suggestions
 	router.GET("/user-suggestions", handlers.GetUserSuggestions)
@@ -99,6 +167,14 @@ func SetupRouter() *gin.Engine {
 	router.GET("/listings/prebuilt/:prebuiltId", handlers.GetListingsByPrebuiltID)
 	router.GET("/listings/seller/:sellerId", handlers.GetListingsBySeller)
 	router.PATCH("/listings/:id/availability", handlers.UpdateListingAvailability)
+	router.GET("/listings/:id/shipping", handlers.GetListingShippingStatus)
+
+	// Shipping Restrictions
+	router.GET("/shipping-restrictions", handlers.GetShippingRestrictions)
+	router.POST("/shipping-restrictions", handlers.CreateShippingRestriction)
+	router.GET("/shipping-restrictions/:id", handlers.GetShippingRestrictionByID)
+	router.PUT("/shipping-restrictions/:id", handlers.UpdateShippingRestriction)
+	router.DELETE("/shipping-restrictions/:id", handlers.DeleteShippingRestriction)
 
 	// Manufacturers
 	router.GET("/manufacturers", handlers.GetManufacturers)
@@ -107,5 +183,39 @@ func SetupRouter() *gin.Engine {
 	router.PUT("/manufacturers/:id", handlers.UpdateManufacturer)
 	router.DELETE("/manufacturers/:id", handlers.DeleteManufacturer)
 
+	// Builds
+	router.GET("/builds", handlers.GetBuilds)
+	router.POST("/builds", handlers.CreateBuild)
+	router.POST("/builds/validate", handlers.ValidateBuild)
+	router.POST("/builds/cost", handlers.PriceBuildSelection)
+	router.POST("/builds/complete", handlers.CompleteBuild)
+	router.POST("/builds/weight", handlers.WeighBuildSelection)
+	router.POST("/builds/rail-space", handlers.CheckBuildSelectionRailSpace)
+	router.POST("/builds/magazines", handlers.FindBuildSelectionMagazines)
+	router.POST("/builds/nfa", handlers.ClassifyBuildSelectionNFA)
+	router.GET("/builds/diff", handlers.DiffBuilds)
+	router.GET("/builds/:id", handlers.GetBuildByID)
+	router.PUT("/builds/:id", handlers.UpdateBuild)
+	router.DELETE("/builds/:id", handlers.DeleteBuild)
+	router.GET("/builds/:id/cost", handlers.GetBuildCost)
+	router.GET("/builds/:id/cart", handlers.GetBuildCart)
+	router.GET("/builds/:id/revisions", handlers.GetBuildRevisions)
+	router.GET("/builds/:id/revisions/:revision", handlers.GetBuildRevision)
+	router.GET("/builds/:id/diff", handlers.DiffBuildRevisions)
+	router.GET("/builds/:id/export", handlers.ExportBuildBOM)
+	router.GET("/builds/:id/weight", handlers.GetBuildWeight)
+	router.GET("/builds/:id/rail-space", handlers.GetBuildRailSpace)
+	router.GET("/builds/:id/magazines", handlers.GetBuildMagazines)
+	router.GET("/builds/:id/nfa", handlers.GetBuildNFAClassification)
+	router.GET("/builds/:id/compatibility", handlers.GetBuildCompatibility)
+
+	// Cart
+	router.POST("/cart/optimize", handlers.OptimizeCart)
+
+	// Admin
+	admin := router.Group("/admin")
+	admin.GET("/compatibility-graph", handlers.ExportCompatibilityGraphFile)
+	admin.GET("/compliance-report", handlers.ExportComplianceReportFile)
+
 	return router
 }
@@ -37,15 +37,33 @@ func ConnectDB() {
 	err = DB.AutoMigrate(
 		&models.Manufacturer{},
 		&models.Seller{},
+		&models.Caliber{},
+		&models.MountingInterface{},
+		&models.Jurisdiction{},
 		&models.PartCategory{}, // Migrate part categories first
 		&models.FirearmModel{},
 		&models.FirearmModelPartCategory{}, // Then the relationship table
 		&models.Part{},
+		&models.PartFirearmModel{},
+		&models.PartMount{},
+		&models.MagazineFamily{},
+		&models.MagazineVariant{},
+		&models.LawRule{},
+		&models.LawRuleChange{},
+		&models.PartCompatibility{},
+		&models.CompatibilityIndexState{},
+		&models.RuleSet{},
+		&models.CompatibilityRule{},
+		&models.CompatibilityRuleException{},
 		&models.PartSellerLink{},
 		&models.PrebuiltFirearm{},
 		&models.PrebuiltSellerLink{},
 		&models.UserSuggestion{},
 		&models.ProductListing{},
+		&models.ShippingRestriction{},
+		&models.Build{},
+		&models.BuildPart{},
+		&models.BuildRevision{},
 	)
 	if err != nil {
 		log.Fatal("Failed to migrate tables:", err)
@@ -109,15 +127,33 @@ func InitDB() {
 	err = DB.AutoMigrate(
 		&models.Manufacturer{},
 		&models.Seller{},
+		&models.Caliber{},
+		&models.MountingInterface{},
+		&models.Jurisdiction{},
 		&models.PartCategory{}, // Migrate part categories first
 		&models.FirearmModel{},
 		&models.FirearmModelPartCategory{}, // Then the relationship table
 		&models.Part{},
+		&models.PartFirearmModel{},
+		&models.PartMount{},
+		&models.MagazineFamily{},
+		&models.MagazineVariant{},
+		&models.LawRule{},
+		&models.LawRuleChange{},
+		&models.PartCompatibility{},
+		&models.CompatibilityIndexState{},
+		&models.RuleSet{},
+		&models.CompatibilityRule{},
+		&models.CompatibilityRuleException{},
 		&models.PartSellerLink{},
 		&models.PrebuiltFirearm{},
 		&models.PrebuiltSellerLink{},
 		&models.UserSuggestion{},
 		&models.ProductListing{},
+		&models.ShippingRestriction{},
+		&models.Build{},
+		&models.BuildPart{},
+		&models.BuildRevision{},
 	)
 	if err != nil {
 		log.Fatal("Failed to migrate tables:", err)
@@ -17,15 +17,33 @@ func WipeDatabase() error {
 
 	// List of all models to wipe in a specific order due to dependencies
 	models := []interface{}{
+		&models.BuildRevision{},
+		&models.BuildPart{},
+		&models.Build{},
+		&models.ShippingRestriction{},
 		&models.ProductListing{},
 		&models.PartSellerLink{},
 		&models.PrebuiltSellerLink{},
 		&models.UserSuggestion{},
+		&models.CompatibilityRuleException{},
+		&models.CompatibilityRule{},
+		&models.RuleSet{},
+		&models.PartCompatibility{},
+		&models.CompatibilityIndexState{},
+		&models.PartFirearmModel{},
+		&models.PartMount{},
+		&models.MagazineVariant{},
+		&models.MagazineFamily{},
+		&models.LawRuleChange{},
+		&models.LawRule{},
+		&models.Jurisdiction{},
 		&models.FirearmModelPartCategory{}, // Must be deleted before FirearmModel and PartCategory
 		&models.Part{},
 		&models.PrebuiltFirearm{},
 		&models.FirearmModel{},
 		&models.PartCategory{}, // Delete after all tables that reference it
+		&models.Caliber{},      // Delete after firearm models, prebuilts and parts
+		&models.MountingInterface{},
 		&models.Seller{},
 		&models.Manufacturer{},
 	}
@@ -40,6 +58,13 @@ func WipeDatabase() error {
 		log.Printf("Wiped table for %T", model)
 	}
 
+	// Join tables without a model of their own
+	for _, table := range []string{"caliber_compatibilities", "magazine_family_firearm_models", "magazine_family_calibers"} {
+		if err := DB.Exec("DELETE FROM " + table).Error; err != nil {
+			log.Printf("Error wiping %s: %v", table, err)
+		}
+	}
+
 	// Re-enable foreign key constraint checks
 	DB.Exec("SET session_replication_role = 'origin';")
 
@@ -69,6 +94,9 @@ func GetDBStats() map[string]int64 {
 	DB.Model(&models.ProductListing{}).Count(&count)
 	stats["product_listings"] = count
 
+	DB.Model(&models.ShippingRestriction{}).Count(&count)
+	stats["shipping_restrictions"] = count
+
 	DB.Model(&models.PrebuiltFirearm{}).Count(&count)
 	stats["prebuilt_firearms"] = count
 
@@ -81,6 +109,45 @@ func GetDBStats() map[string]int64 {
 	DB.Model(&models.UserSuggestion{}).Count(&count)
 	stats["user_suggestions"] = count
 
+	DB.Model(&models.Build{}).Count(&count)
+	stats["builds"] = count
+
+	DB.Model(&models.CompatibilityRule{}).Count(&count)
+	stats["compatibility_rules"] = count
+
+	DB.Model(&models.RuleSet{}).Count(&count)
+	stats["rule_sets"] = count
+
+	DB.Model(&models.PartFirearmModel{}).Count(&count)
+	stats["part_firearm_models"] = count
+
+	DB.Model(&models.PartCompatibility{}).Count(&count)
+	stats["part_compatibilities"] = count
+
+	DB.Model(&models.Caliber{}).Count(&count)
+	stats["calibers"] = count
+
+	DB.Model(&models.MountingInterface{}).Count(&count)
+	stats["mounting_interfaces"] = count
+
+	DB.Model(&models.PartMount{}).Count(&count)
+	stats["part_mounts"] = count
+
+	DB.Model(&models.MagazineFamily{}).Count(&count)
+	stats["magazine_families"] = count
+
+	DB.Model(&models.MagazineVariant{}).Count(&count)
+	stats["magazine_variants"] = count
+
+	DB.Model(&models.Jurisdiction{}).Count(&count)
+	stats["jurisdictions"] = count
+
+	DB.Model(&models.LawRule{}).Count(&count)
+	stats["law_rules"] = count
+
+	DB.Model(&models.LawRuleChange{}).Count(&count)
+	stats["law_rule_changes"] = count
+
 	return stats
 }
 
@@ -94,6 +161,37 @@ func CleanOrphanedRecords() {
 	// Example: Clean ProductListings with missing Seller references
 	DB.Exec("DELETE FROM product_listings WHERE seller_id NOT IN (SELECT id FROM sellers)")
 
+	// Clean shipping restrictions of removed sellers or listings
+	DB.Exec("DELETE FROM shipping_restrictions WHERE seller_id IS NOT NULL AND seller_id NOT IN (SELECT id FROM sellers)")
+	DB.Exec("DELETE FROM shipping_restrictions WHERE product_listing_id IS NOT NULL AND product_listing_id NOT IN (SELECT id FROM product_listings)")
+
+	// Clean BuildParts with missing Build or Part references
+	DB.Exec("DELETE FROM build_parts WHERE build_id NOT IN (SELECT id FROM builds)")
+	DB.Exec("DELETE FROM build_parts WHERE part_id NOT IN (SELECT id FROM parts)")
+
+	// Clean CompatibilityRules that reference missing PartCategories
+	DB.Exec("DELETE FROM compatibility_rules WHERE source_category_id NOT IN (SELECT id FROM part_categories) OR target_category_id NOT IN (SELECT id FROM part_categories)")
+
+	// Clean rule exceptions whose rule was removed
+	DB.Exec("DELETE FROM compatibility_rule_exceptions WHERE rule_id NOT IN (SELECT id FROM compatibility_rules)")
+
+	// Clean part fitments with missing Part or FirearmModel references
+	DB.Exec("DELETE FROM part_firearm_models WHERE part_id NOT IN (SELECT id FROM parts) OR firearm_model_id NOT IN (SELECT id FROM firearm_models)")
+
+	// Clean compatibility index edges with missing Part references
+	DB.Exec("DELETE FROM part_compatibilities WHERE part_id NOT IN (SELECT id FROM parts) OR compatible_part_id NOT IN (SELECT id FROM parts)")
+
+	// Clean part mounts with missing Part or MountingInterface references
+	DB.Exec("DELETE FROM part_mounts WHERE part_id NOT IN (SELECT id FROM parts) OR mounting_interface_id NOT IN (SELECT id FROM mounting_interfaces)")
+
+	// Clean magazine variants of removed families, and family links to removed models or calibers
+	DB.Exec("DELETE FROM magazine_variants WHERE magazine_family_id NOT IN (SELECT id FROM magazine_families)")
+	DB.Exec("DELETE FROM magazine_family_firearm_models WHERE magazine_family_id NOT IN (SELECT id FROM magazine_families) OR firearm_model_id NOT IN (SELECT id FROM firearm_models)")
+	DB.Exec("DELETE FROM magazine_family_calibers WHERE magazine_family_id NOT IN (SELECT id FROM magazine_families) OR caliber_id NOT IN (SELECT id FROM calibers)")
+
+	// Clean law rules of removed jurisdictions
+	DB.Exec("DELETE FROM law_rules WHERE jurisdiction_id NOT IN (SELECT id FROM jurisdictions)")
+
 	// Add additional cleanup as needed based on data model
 
 	log.Println("Orphaned records cleaning complete")
@@ -138,15 +236,33 @@ func ResetDatabase() error {
 	err := DB.AutoMigrate(
 		&models.Manufacturer{},
 		&models.Seller{},
+		&models.Caliber{},
+		&models.MountingInterface{},
+		&models.Jurisdiction{},
 		&models.PartCategory{},
 		&models.FirearmModel{},
 		&models.FirearmModelPartCategory{},
 		&models.Part{},
+		&models.PartFirearmModel{},
+		&models.PartMount{},
+		&models.MagazineFamily{},
+		&models.MagazineVariant{},
+		&models.LawRule{},
+		&models.LawRuleChange{},
+		&models.PartCompatibility{},
+		&models.CompatibilityIndexState{},
+		&models.RuleSet{},
+		&models.CompatibilityRule{},
+		&models.CompatibilityRuleException{},
 		&models.PartSellerLink{},
 		&models.PrebuiltFirearm{},
 		&models.PrebuiltSellerLink{},
 		&models.UserSuggestion{},
 		&models.ProductListing{},
+		&models.ShippingRestriction{},
+		&models.Build{},
+		&models.BuildPart{},
+		&models.BuildRevision{},
 	)
 	if err != nil {
 		log.Fatalf("Failed to create new schema: %v", err)
@@ -7,6 +7,8 @@ import (
 	"log"
 	"math/rand"
 	"sauron-backend/internal/models"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -118,9 +120,10 @@ var categoryData = []models.PartCategory{
 		Description: "Optional accessories",
 	},
 	{
-		ID:          18,
-		Name:        "Magazines and Feeding Devices",
-		Description: "Magazine options",
+		ID:            18,
+		Name:          "Magazines and Feeding Devices",
+		Description:   "Magazine options",
+		ShippingClass: models.ShippingClassMagazine,
 	},
 	{
 		ID:          19,
@@ -146,12 +149,16 @@ var categoryData = []models.PartCategory{
 		Name:             "Buffer System",
 		ParentCategoryID: intPtr(1),
 		Description:      "Buffer and recoil components",
+		AttributeSchema: attributeSchemaJSON(
+			models.AttributeDefinition{Name: models.PartAttributeBufferTubeSpec, Type: models.AttributeTypeString, Enum: []string{"mil-spec", "commercial", "a2-rifle"}, Description: "Buffer tube diameter standard"},
+		),
 	},
 	{
 		ID:               6,
 		Name:             "Lower Receiver",
 		ParentCategoryID: intPtr(1),
 		Description:      "Lower receiver structure",
+		ShippingClass:    models.ShippingClassFirearm,
 	},
 	{
 		ID:               7,
@@ -178,12 +185,22 @@ var categoryData = []models.PartCategory{
 		Name:             "Barrel",
 		ParentCategoryID: intPtr(2),
 		Description:      "Barrel and gas system components",
+		AttributeSchema: attributeSchemaJSON(
+			models.AttributeDefinition{Name: models.PartAttributeCaliber, Type: models.AttributeTypeString, Required: true, Description: "Chambered cartridge, derived from caliber_id"},
+			models.AttributeDefinition{Name: models.PartAttributeThreadPitch, Type: models.AttributeTypeString, Enum: []string{"1/2x28", "5/8x24", "1/2x36", "M14x1 LH"}, Description: "Muzzle thread pitch"},
+			models.AttributeDefinition{Name: models.PartAttributeGasBlockJournalDiameter, Type: models.AttributeTypeNumber, Unit: "in", Description: "Outer diameter of the barrel where the gas block mounts"},
+			models.AttributeDefinition{Name: models.PartAttributeGasSystemLength, Type: models.AttributeTypeString, Enum: []string{"pistol", "carbine", "mid-length", "rifle"}, Description: "Gas port position"},
+		),
 	},
 	{
 		ID:               11,
 		Name:             "Gas System",
 		ParentCategoryID: intPtr(2),
 		Description:      "Gas operation components",
+		AttributeSchema: attributeSchemaJSON(
+			models.AttributeDefinition{Name: models.PartAttributeGasBlockBoreDiameter, Type: models.AttributeTypeNumber, Unit: "in", Description: "Inner diameter of the gas block"},
+			models.AttributeDefinition{Name: models.PartAttributeGasSystemLength, Type: models.AttributeTypeString, Enum: []string{"pistol", "carbine", "mid-length", "rifle"}, Description: "Gas tube length"},
+		),
 	},
 	{
 		ID:               12,
@@ -196,6 +213,9 @@ var categoryData = []models.PartCategory{
 		Name:             "Bolt Carrier Group",
 		ParentCategoryID: intPtr(2),
 		Description:      "Bolt and carrier components",
+		AttributeSchema: attributeSchemaJSON(
+			models.AttributeDefinition{Name: models.PartAttributeCaliber, Type: models.AttributeTypeString, Required: true, Description: "Cartridge the bolt face is cut for, derived from caliber_id"},
+		),
 	},
 	{
 		ID:               14,
@@ -237,6 +257,22 @@ var categoryData = []models.PartCategory{
 		ParentCategoryID: intPtr(20),
 		Description:      "Two-point sling option",
 	},
+
+	// NFA-relevant Sub-Categories
+	{
+		ID:               23,
+		Name:             "Stock",
+		ParentCategoryID: intPtr(1),
+		Description:      "Shoulder stocks and pistol braces",
+		NFATag:           models.NFATagShoulderStock,
+	},
+	{
+		ID:               24,
+		Name:             "Suppressor",
+		ParentCategoryID: intPtr(2),
+		Description:      "Sound suppressors, NFA-regulated where legal to own",
+		NFATag:           models.NFATagSuppressor,
+	},
 }
 
 // Helper function to create pointer to int
@@ -244,6 +280,12 @@ func intPtr(i int) *int {
 	return &i
 }
 
+// attributeSchemaJSON encodes attribute definitions for PartCategory.AttributeSchema
+func attributeSchemaJSON(definitions ...models.AttributeDefinition) datatypes.JSON {
+	schemaJSON, _ := json.Marshal(definitions)
+	return datatypes.JSON(schemaJSON)
+}
+
 // SeedCategoriesAndRelationships creates part categories in the database
 func SeedCategoriesAndRelationships() {
 	log.Println("Seeding part categories...")
@@ -269,53 +311,29 @@ func SeedCategoriesAndRelationships() {
 	log.Println("Part category seeding completed")
 }
 
-// getDefaultCaliberForModel returns appropriate caliber based on the firearm model name
-func getDefaultCaliberForModel(modelName string) string {
-	// AR platform rifles typically come in 5.56 NATO
-	if strings.Contains(modelName, "AR-15") || strings.Contains(modelName, "M4") ||
-		strings.Contains(modelName, "M16") || strings.Contains(modelName, "SIG M400") {
-		return "5.56x45mm NATO"
-	}
-
-	// AK platform rifles typically come in 7.62x39mm
-	if strings.Contains(modelName, "AK-47") || strings.Contains(modelName, "AKM") {
-		return "7.62x39mm"
-	}
-
-	// AK-74 variants use 5.45x39mm
-	if strings.Contains(modelName, "AK-74") {
-		return "5.45x39mm"
-	}
-
-	// Battle rifles and some hunting rifles
-	if strings.Contains(modelName, "AR-10") || strings.Contains(modelName, "SR-25") {
-		return "7.62x51mm NATO"
-	}
-
-	// Common pistol calibers
-	if strings.Contains(modelName, "Glock") || strings.Contains(modelName, "SIG P320") ||
-		strings.Contains(modelName, "M&P") || strings.Contains(modelName, "CZ P-10") {
-		return "9x19mm Parabellum"
-	}
-
-	// 1911 style pistols typically come in .45 ACP
-	if strings.Contains(modelName, "1911") || strings.Contains(modelName, "P220") {
-		return ".45 ACP"
+// defaultCaliberForModel returns the name of the caliber a firearm model is chambered in by
+// default, or "" when the model is chambered in several
+func defaultCaliberForModel(modelName string) string {
+	for _, caliber := range CaliberData {
+		for _, keyword := range caliber.ModelKeywords {
+			if strings.Contains(modelName, keyword) {
+				return caliber.Name
+			}
+		}
 	}
+	return ""
+}
 
-	// Common revolver calibers
-	if strings.Contains(modelName, "Model 686") || strings.Contains(modelName, "Python") {
-		return ".357 Magnum"
+// caliberIDByName looks up a seeded caliber's ID, returning nil if it does not exist
+func caliberIDByName(name string) *int {
+	if name == "" {
+		return nil
 	}
-
-	// Shotguns are typically 12 gauge
-	if strings.Contains(modelName, "Mossberg") || strings.Contains(modelName, "Remington") ||
-		strings.Contains(modelName, "Benelli") || strings.Contains(modelName, "Beretta") {
-		return "12 Gauge"
+	var caliber models.Caliber
+	if err := DB.Where("name = ?", name).First(&caliber).Error; err != nil {
+		return nil
 	}
-
-	// Default for other models
-	return "Multiple"
+	return &caliber.ID
 }
 
 // SeedDatabase populates the database with initial mock data
@@ -333,33 +351,103 @@ func SeedDatabase() {
 	log.Println("Seeding sellers...")
 	seedSellers()
 
-	// 3. Seed FirearmModels (depends on manufacturers)
+	// 3. Seed Calibers (no foreign key dependencies)
+	log.Println("Seeding calibers...")
+	seedCalibers()
+
+	// 4. Seed FirearmModels (depends on manufacturers and calibers)
 	log.Println("Seeding firearm models...")
 	seedFirearmModels()
 
-	// 4. Seed Parts (depends on manufacturers)
+	// 5. Seed Parts (depends on manufacturers and calibers)
 	log.Println("Seeding parts...")
 	seedParts()
 
-	// 5. Seed categories and relationships
+	// 6. Seed categories and relationships
 	log.Println("Seeding categories and relationships...")
 	SeedCategoriesAndRelationships()
 
-	// 6. Seed model to category relationships (new step)
+	// 7. Seed model to category relationships (new step)
 	log.Println("Seeding model to category relationships...")
 	seedModelCategoryRelationships()
 
-	// 7. Seed PrebuiltFirearms (depends on firearm models)
+	// 8. Seed PrebuiltFirearms (depends on firearm models)
 	log.Println("Seeding prebuilt firearms...")
 	seedPrebuiltFirearms()
 
-	// 8. Seed ProductListings (depends on parts and sellers)
+	// 9. Seed ProductListings (depends on parts and sellers)
 	log.Println("Seeding product listings...")
 	seedProductListings()
 
+	// 10. Seed compatibility rules (depends on part categories)
+	log.Println("Seeding compatibility rules...")
+	seedCompatibilityRules()
+
+	// 11. Seed part fitments (depends on parts and firearm models)
+	log.Println("Seeding part fitments...")
+	seedPartFitments()
+
+	// 12. Seed mounting interfaces and part mounts (depends on parts)
+	log.Println("Seeding mounting interfaces and part mounts...")
+	seedMountingInterfaces()
+	seedPartMounts()
+
+	// 13. Seed magazine families and variants (depends on firearm models, calibers and parts)
+	log.Println("Seeding magazine families...")
+	seedMagazineFamilies()
+
+	// 14. Seed jurisdictions and their law rules
+	log.Println("Seeding jurisdictions and law rules...")
+	seedJurisdictions()
+	seedLawRules()
+
+	// 15. Seed seller shipping restrictions (depends on sellers)
+	log.Println("Seeding shipping restrictions...")
+	seedShippingRestrictions()
+
 	log.Println("Database seeding completed!")
 }
 
+// seedCalibers creates the calibers, then links each to its parent cartridge and to the
+// calibers its chamber can fire once they all exist
+func seedCalibers() {
+	for _, data := range CaliberData {
+		aliasesJSON, _ := json.Marshal(data.Aliases)
+		caliber := models.Caliber{
+			Name:           data.Name,
+			Aliases:        datatypes.JSON(aliasesJSON),
+			Type:           data.Type,
+			BulletDiameter: data.BulletDiameter,
+			CaseLength:     data.CaseLength,
+			OverallLength:  data.OverallLength,
+		}
+		if result := DB.Where("name = ?", data.Name).FirstOrCreate(&caliber); result.Error != nil {
+			log.Printf("Error seeding caliber %s: %v", data.Name, result.Error)
+		} else {
+			log.Printf("Created caliber: %s", data.Name)
+		}
+	}
+
+	for _, data := range CaliberData {
+		var caliber models.Caliber
+		if err := DB.Where("name = ?", data.Name).First(&caliber).Error; err != nil {
+			continue
+		}
+
+		if parentID := caliberIDByName(data.Parent); parentID != nil {
+			DB.Model(&caliber).Update("parent_caliber_id", *parentID)
+		}
+
+		var fires []models.Caliber
+		if len(data.Fires) > 0 {
+			DB.Where("name IN ?", data.Fires).Find(&fires)
+		}
+		if err := DB.Model(&caliber).Association("FiresCalibers").Replace(fires); err != nil {
+			log.Printf("Error linking calibers fired by %s: %v", data.Name, err)
+		}
+	}
+}
+
 // Seed manufacturers
 func seedManufacturers() {
 	for _, mfg := range ManufacturerData {
@@ -437,10 +525,10 @@ func seedFirearmModels() {
 	subCategory := "Assault"
 	variant := "Standard" // Adding a variant to ensure it's not null
 
-	// Create specifications object with caliber information
+	// Create specifications object; the caliber is referenced by caliber_id
+	caliberName := defaultCaliberForModel(modelName)
 	specs := map[string]interface{}{
-		"caliber": "5.56x45mm NATO",
-		"weight":  "6.5 lbs",
+		"weight": "6.5 lbs",
 	}
 	specsJSON, _ := json.Marshal(specs)
 	log.Printf("DEBUG: Created specifications JSON: %s", string(specsJSON))
@@ -466,6 +554,9 @@ func seedFirearmModels() {
 		Category:       mainCategory,
 		Subcategory:    subCategory,
 		Variant:        variant,
+		ActionType:     models.ActionTypeSemiAuto,
+		MagazineType:   models.MagazineTypeDetachable,
+		CaliberID:      caliberIDByName(caliberName),
 		Specifications: datatypes.JSON(specsJSON),
 		Images:         datatypes.JSON(imagesJSON),
 		PriceRange:     priceRange,
@@ -891,6 +982,9 @@ func seedParts() {
 		categoryMap[category.Name] = category.ID
 	}
 
+	// Caliber-sensitive parts are made for the AR-15's default caliber
+	caliberID := caliberIDByName(defaultCaliberForModel("AR-15"))
+
 	// Create parts for AR-15 only
 	for mainCategory, subCategories := range AllParts {
 		for subCategory, parts := range subCategories {
@@ -928,6 +1022,15 @@ func seedParts() {
 					Images:         datatypes.JSON(imagesJSON),
 					Weight:         0.5 + rand.Float64(),
 					Dimensions:     "5 x 3 x 2 in",
+					Attributes:     getPartAttributesJSON(subCategory, partName),
+				}
+				if isCaliberSensitive(subCategory) {
+					part.CaliberID = caliberID
+				}
+
+				// Braces are seeded under Stock, so override the category's shoulder stock tag
+				if strings.Contains(partName, "Brace") {
+					part.NFATag = models.NFATagPistolBrace
 				}
 
 				// Set Complete Lower Receiver (AR-15) as prebuilt
@@ -963,6 +1066,8 @@ func seedAssemblyParts(availableManufacturers []models.Manufacturer) {
 		categoryMap[category.Name] = category.ID
 	}
 
+	caliberID := caliberIDByName(defaultCaliberForModel("AR-15"))
+
 	// Sample prebuilt assemblies data
 	assemblies := []struct {
 		Name        string
@@ -1017,6 +1122,10 @@ func seedAssemblyParts(availableManufacturers []models.Manufacturer) {
 			Images:         datatypes.JSON(imagesJSON),
 			Weight:         1.5 + rand.Float64(),
 			Dimensions:     "10 x 8 x 4 in",
+			Attributes:     getPartAttributesJSON(assembly.Subcategory, assembly.Name),
+		}
+		if isCaliberSensitive(assembly.Subcategory) {
+			part.CaliberID = caliberID
 		}
 
 		if result := DB.Create(&part); result.Error != nil {
@@ -1027,6 +1136,404 @@ func seedAssemblyParts(availableManufacturers []models.Manufacturer) {
 	}
 }
 
+// isCaliberSensitive reports whether parts in a seed subcategory are made for a specific caliber
+func isCaliberSensitive(subCategory string) bool {
+	switch subCategory {
+	case "Barrel", "Bolt Carrier Group", "Magazines":
+		return true
+	}
+	return false
+}
+
+// getPartAttributesJSON returns the interface attributes compatibility rules read and the
+// lengths and features law rules read for an AR-15 part, or nil if the part has none
+func getPartAttributesJSON(subCategory, partName string) datatypes.JSON {
+	attributes := map[string]interface{}{}
+
+	switch subCategory {
+	case "Barrel":
+		attributes[models.PartAttributeThreadPitch] = "1/2x28"
+		attributes[models.PartAttributeGasBlockJournalDiameter] = 0.750
+		attributes[models.LawFeatureThreadedBarrel] = true
+		if end := strings.Index(partName, "\""); end > 0 {
+			if length, err := strconv.ParseFloat(partName[:end], 64); err == nil {
+				attributes[models.PartAttributeBarrelLength] = length
+			}
+		}
+		switch {
+		case strings.HasPrefix(partName, "16\""):
+			attributes[models.PartAttributeGasSystemLength] = "mid-length"
+		case strings.HasPrefix(partName, "18\"") || strings.HasPrefix(partName, "20\""):
+			attributes[models.PartAttributeGasSystemLength] = "rifle"
+		default:
+			attributes[models.PartAttributeGasSystemLength] = "carbine"
+		}
+	case "Gas System":
+		switch {
+		case strings.Contains(partName, "Gas Block"):
+			attributes[models.PartAttributeGasBlockBoreDiameter] = 0.750
+		case strings.HasPrefix(partName, "Carbine"):
+			attributes[models.PartAttributeGasSystemLength] = "carbine"
+		case strings.HasPrefix(partName, "Mid-Length"):
+			attributes[models.PartAttributeGasSystemLength] = "mid-length"
+		case strings.HasPrefix(partName, "Rifle-Length"):
+			attributes[models.PartAttributeGasSystemLength] = "rifle"
+		}
+	case "Muzzle Devices":
+		attributes[models.PartAttributeThreadPitch] = "1/2x28"
+		if strings.Contains(partName, "Flash") {
+			attributes[models.LawFeatureFlashHider] = true
+		}
+	case "Buffer System":
+		if strings.Contains(partName, "Buffer Tube") {
+			attributes[models.PartAttributeBufferTubeSpec] = "mil-spec"
+		}
+	case "Magazine Release":
+		if strings.Contains(partName, "Fixed Magazine") {
+			attributes[models.PartAttributeMagazineType] = models.MagazineTypeFixed
+		}
+	case "Grip":
+		attributes[models.LawFeaturePistolGrip] = true
+	case "Stock":
+		switch {
+		case strings.Contains(partName, "Fixed A2"):
+			attributes[models.PartAttributeBufferTubeSpec] = "a2-rifle"
+		case strings.Contains(partName, "Brace"):
+			attributes[models.PartAttributeBufferTubeSpec] = "mil-spec"
+		default:
+			attributes[models.PartAttributeBufferTubeSpec] = "mil-spec"
+			attributes[models.LawFeatureAdjustableStock] = true
+		}
+	case "Suppressor":
+		attributes[models.PartAttributeThreadPitch] = "1/2x28"
+		attributes[models.LawFeatureSuppressor] = true
+	}
+
+	if len(attributes) == 0 {
+		return nil
+	}
+	attributesJSON, _ := json.Marshal(attributes)
+	return datatypes.JSON(attributesJSON)
+}
+
+// seedCompatibilityRules creates the attribute rules between AR-15 part categories
+func seedCompatibilityRules() {
+	rules := []models.CompatibilityRule{
+		{
+			Name:             "Bolt caliber matches barrel",
+			Description:      "The bolt carrier group must be chambered for the same caliber as the barrel",
+			SourceCategoryID: 13, // Bolt Carrier Group
+			SourceAttribute:  models.PartAttributeCaliber,
+			TargetCategoryID: 10, // Barrel
+			TargetAttribute:  models.PartAttributeCaliber,
+			Operator:         models.RuleOperatorEqual,
+		},
+		{
+			Name:             "Gas block fits barrel journal",
+			Description:      "The gas block inner diameter must equal the barrel's gas block journal diameter",
+			SourceCategoryID: 11, // Gas System
+			SourceAttribute:  models.PartAttributeGasBlockBoreDiameter,
+			TargetCategoryID: 10, // Barrel
+			TargetAttribute:  models.PartAttributeGasBlockJournalDiameter,
+			Operator:         models.RuleOperatorEqual,
+			Tolerance:        0.001,
+		},
+		{
+			Name:             "Gas tube matches barrel gas system",
+			Description:      "The gas tube length must match the barrel's gas system length",
+			SourceCategoryID: 11, // Gas System
+			SourceAttribute:  models.PartAttributeGasSystemLength,
+			TargetCategoryID: 10, // Barrel
+			TargetAttribute:  models.PartAttributeGasSystemLength,
+			Operator:         models.RuleOperatorEqual,
+		},
+	}
+
+	for _, rule := range rules {
+		var count int64
+		DB.Model(&models.CompatibilityRule{}).Where("name = ?", rule.Name).Count(&count)
+		if count > 0 {
+			log.Printf("Compatibility rule already exists: %s", rule.Name)
+			continue
+		}
+
+		if result := DB.Create(&rule); result.Error != nil {
+			log.Printf("Error seeding compatibility rule %s: %v", rule.Name, result.Error)
+		} else {
+			log.Printf("Created compatibility rule: %s", rule.Name)
+		}
+	}
+}
+
+// seedPartFitments declares that parts named for a firearm model, e.g. "Bolt (AR-15)",
+// fit that model
+func seedPartFitments() {
+	var firearmModels []models.FirearmModel
+	DB.Find(&firearmModels)
+
+	for _, model := range firearmModels {
+		var parts []models.Part
+		DB.Where("name LIKE ?", "%("+model.Name+")%").Find(&parts)
+
+		for _, part := range parts {
+			fitment := models.PartFirearmModel{PartID: part.ID, FirearmModelID: model.ID}
+			if result := DB.Where(fitment).FirstOrCreate(&fitment); result.Error != nil {
+				log.Printf("Error seeding fitment of %s to %s: %v", part.Name, model.Name, result.Error)
+			}
+		}
+		log.Printf("Declared %d parts as fitting %s", len(parts), model.Name)
+	}
+}
+
+// seedMountingInterfaces creates the accessory mounting interfaces
+func seedMountingInterfaces() {
+	for _, data := range MountingInterfaceData {
+		mountingInterface := models.MountingInterface{
+			Name:        data.Name,
+			Description: data.Description,
+			SlotPitch:   data.SlotPitch,
+		}
+		if result := DB.Where("name = ?", data.Name).FirstOrCreate(&mountingInterface); result.Error != nil {
+			log.Printf("Error seeding mounting interface %s: %v", data.Name, result.Error)
+		} else {
+			log.Printf("Created mounting interface: %s", data.Name)
+		}
+	}
+}
+
+// seedPartMounts records the rail sections seed parts provide and the rail space seed
+// accessories consume
+func seedPartMounts() {
+	var mountingInterfaces []models.MountingInterface
+	DB.Find(&mountingInterfaces)
+	interfaceIDByName := make(map[string]int, len(mountingInterfaces))
+	for _, mountingInterface := range mountingInterfaces {
+		interfaceIDByName[mountingInterface.Name] = mountingInterface.ID
+	}
+
+	for partName, mounts := range PartMountData {
+		var parts []models.Part
+		DB.Where("name = ?", partName).Find(&parts)
+
+		for _, part := range parts {
+			for _, data := range mounts {
+				interfaceID, ok := interfaceIDByName[data.Interface]
+				if !ok {
+					log.Printf("Unknown mounting interface %s for %s", data.Interface, partName)
+					continue
+				}
+
+				mount := models.PartMount{
+					PartID:              part.ID,
+					MountingInterfaceID: interfaceID,
+					Role:                data.Role,
+					Position:            data.Position,
+				}
+				if result := DB.Where(mount).Attrs(models.PartMount{Slots: data.Slots, Notes: data.Notes}).FirstOrCreate(&mount); result.Error != nil {
+					log.Printf("Error seeding %s mount of %s: %v", data.Interface, partName, result.Error)
+				}
+			}
+		}
+		log.Printf("Seeded mounts of %d parts named %s", len(parts), partName)
+	}
+}
+
+// seedMagazineFamilies creates the magazine families, links them to the firearm models named
+// by their keywords and the calibers they feed, and adds their capacity variants
+func seedMagazineFamilies() {
+	var firearmModels []models.FirearmModel
+	DB.Find(&firearmModels)
+
+	for _, data := range MagazineFamilyData {
+		family := models.MagazineFamily{Name: data.Name, Description: data.Description}
+		if result := DB.Where("name = ?", data.Name).FirstOrCreate(&family); result.Error != nil {
+			log.Printf("Error seeding magazine family %s: %v", data.Name, result.Error)
+			continue
+		}
+
+		takes := []models.FirearmModel{}
+		for _, model := range firearmModels {
+			for _, keyword := range data.ModelKeywords {
+				if strings.Contains(model.Name, keyword) {
+					takes = append(takes, model)
+					break
+				}
+			}
+		}
+		if err := DB.Model(&family).Omit("FirearmModels.*").Association("FirearmModels").Replace(takes); err != nil {
+			log.Printf("Error linking firearm models to magazine family %s: %v", data.Name, err)
+		}
+
+		feeds := []models.Caliber{}
+		if len(data.Calibers) > 0 {
+			DB.Where("name IN ?", data.Calibers).Find(&feeds)
+		}
+		if err := DB.Model(&family).Omit("Calibers.*").Association("Calibers").Replace(feeds); err != nil {
+			log.Printf("Error linking calibers to magazine family %s: %v", data.Name, err)
+		}
+
+		for _, seed := range data.Variants {
+			variant := models.MagazineVariant{
+				MagazineFamilyID: family.ID,
+				Capacity:         seed.Capacity,
+				CaliberID:        caliberIDByName(seed.Caliber),
+				Notes:            seed.Notes,
+			}
+			if seed.Part != "" {
+				var part models.Part
+				if err := DB.Where("name = ?", seed.Part).First(&part).Error; err != nil {
+					log.Printf("Magazine part %s not found, seeding the variant without it", seed.Part)
+				} else {
+					variant.PartID = &part.ID
+				}
+			}
+
+			query := DB.Where("magazine_family_id = ? AND capacity = ? AND notes = ?", family.ID, seed.Capacity, seed.Notes)
+			if result := query.FirstOrCreate(&variant); result.Error != nil {
+				log.Printf("Error seeding %d-round %s magazine: %v", seed.Capacity, data.Name, result.Error)
+			}
+		}
+		log.Printf("Created magazine family %s for %d firearm models", data.Name, len(takes))
+	}
+}
+
+// seedJurisdictions creates the federal jurisdiction and the states under it
+func seedJurisdictions() {
+	federal := models.Jurisdiction{Code: FederalJurisdiction.Code, Name: FederalJurisdiction.Name, Level: models.JurisdictionLevelFederal}
+	if result := DB.Where("code = ?", federal.Code).FirstOrCreate(&federal); result.Error != nil {
+		log.Printf("Error seeding jurisdiction %s: %v", federal.Code, result.Error)
+		return
+	}
+
+	for _, data := range JurisdictionData {
+		state := models.Jurisdiction{
+			Code:                 data.Code,
+			Name:                 data.Name,
+			Level:                models.JurisdictionLevelState,
+			ParentJurisdictionID: &federal.ID,
+		}
+		if result := DB.Where("code = ?", data.Code).FirstOrCreate(&state); result.Error != nil {
+			log.Printf("Error seeding jurisdiction %s: %v", data.Code, result.Error)
+		}
+	}
+	log.Printf("Created %d state jurisdictions", len(JurisdictionData))
+}
+
+// seedLawRules creates the law rules of each jurisdiction
+func seedLawRules() {
+	var jurisdictions []models.Jurisdiction
+	DB.Find(&jurisdictions)
+	jurisdictionIDs := make(map[string]int, len(jurisdictions))
+	for _, jurisdiction := range jurisdictions {
+		jurisdictionIDs[jurisdiction.Code] = jurisdiction.ID
+	}
+
+	for _, data := range LawRuleData {
+		jurisdictionID, ok := jurisdictionIDs[data.Jurisdiction]
+		if !ok {
+			log.Printf("Jurisdiction %s not found, skipping law rule %s", data.Jurisdiction, data.Name)
+			continue
+		}
+		rule := models.LawRule{
+			JurisdictionID: jurisdictionID,
+			Name:           data.Name,
+			Description:    data.Description,
+			Type:           data.Type,
+			Value:          data.Value,
+			Feature:        data.Feature,
+			Features:       featuresJSON(data.Features),
+			FirearmClass:   data.FirearmClass,
+			Citation:       data.Citation,
+			CitationURL:    data.CitationURL,
+			Source:         data.Source,
+			Version:        1,
+		}
+		if data.EffectiveFrom != "" {
+			effectiveFrom, err := time.Parse("2006-01-02", data.EffectiveFrom)
+			if err != nil {
+				log.Printf("Invalid effective date %s for law rule %s: %v", data.EffectiveFrom, data.Name, err)
+				continue
+			}
+			rule.EffectiveFrom = &effectiveFrom
+		}
+
+		result := DB.Where("jurisdiction_id = ? AND name = ?", jurisdictionID, data.Name).FirstOrCreate(&rule)
+		if result.Error != nil {
+			log.Printf("Error seeding law rule %s: %v", data.Name, result.Error)
+			continue
+		}
+		if result.RowsAffected == 0 {
+			continue
+		}
+
+		// Start the rule's audit trail so every rule has a recorded origin
+		ruleJSON, _ := json.Marshal(rule)
+		fields := map[string]interface{}{}
+		_ = json.Unmarshal(ruleJSON, &fields)
+		setFields := []string{}
+		for name := range fields {
+			switch name {
+			case "id", "version", "created_at", "updated_at":
+			default:
+				setFields = append(setFields, name)
+			}
+		}
+		sort.Strings(setFields)
+		fieldsJSON, _ := json.Marshal(setFields)
+		change := models.LawRuleChange{
+			LawRuleID:      rule.ID,
+			JurisdictionID: rule.JurisdictionID,
+			Version:        rule.Version,
+			Action:         models.LawRuleChangeCreated,
+			ChangedBy:      "seed",
+			Reason:         "Initial seed data",
+			ChangedFields:  datatypes.JSON(fieldsJSON),
+			After:          datatypes.JSON(ruleJSON),
+		}
+		if err := DB.Create(&change).Error; err != nil {
+			log.Printf("Error recording the creation of law rule %s: %v", data.Name, err)
+		}
+	}
+	log.Printf("Created %d law rules", len(LawRuleData))
+}
+
+// featuresJSON encodes the features of an assault weapon feature rule, or nothing for other rules
+func featuresJSON(features []string) datatypes.JSON {
+	if len(features) == 0 {
+		return nil
+	}
+	content, _ := json.Marshal(features)
+	return datatypes.JSON(content)
+}
+
+// seedShippingRestrictions creates the destination restrictions of each seller
+func seedShippingRestrictions() {
+	for _, data := range ShippingRestrictionData {
+		var seller models.Seller
+		if err := DB.Where("name = ?", data.Seller).First(&seller).Error; err != nil {
+			log.Printf("Seller %s not found, skipping its shipping restrictions", data.Seller)
+			continue
+		}
+		states := data.States
+		if len(states) == 0 {
+			states = []string{""}
+		}
+		for _, state := range states {
+			restriction := models.ShippingRestriction{
+				SellerID:      &seller.ID,
+				State:         state,
+				Type:          data.Type,
+				ShippingClass: data.ShippingClass,
+				Reason:        data.Reason,
+			}
+			query := DB.Where("seller_id = ? AND state = ? AND type = ? AND shipping_class = ?", seller.ID, state, data.Type, data.ShippingClass)
+			if result := query.FirstOrCreate(&restriction); result.Error != nil {
+				log.Printf("Error seeding shipping restriction of %s to %s: %v", data.Seller, state, result.Error)
+			}
+		}
+	}
+}
+
 // Seed product listings
 func seedProductListings() {
 	var parts []models.Part
@@ -1251,8 +1758,8 @@ func seedPrebuiltFirearms() {
 	description := "A complete AR-15 rifle with all mil-spec components. Ready to fire out of the box."
 
 	// Create specs
+	caliberName := defaultCaliberForModel(arModel.Name)
 	specs := map[string]interface{}{
-		"caliber":       "5.56x45mm NATO",
 		"weight":        "6.36 lbs",
 		"barrel_length": "14.5 inches",
 		"finish":        "Matte Black",
@@ -1306,13 +1813,14 @@ func seedPrebuiltFirearms() {
 	// Create the prebuilt model using raw SQL to set both components and parts columns
 	query := `
 		INSERT INTO prebuilt_firearms 
-		(firearm_model_id, name, description, specifications, components, parts, compatible_parts, price, images, availability, created_at, updated_at) 
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+		(firearm_model_id, caliber_id, name, description, specifications, components, parts, compatible_parts, price, images, availability, created_at, updated_at) 
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
 	`
 
 	result := DB.Exec(
 		query,
 		arModel.ID,
+		caliberIDByName(caliberName),
 		prebuiltName,
 		description,
 		specsJSON,
@@ -1390,6 +1898,8 @@ func seedModelCategoryRelationships() {
 		{18, true},  // Magazines (required)
 		{19, false}, // Rails and Mounting (optional)
 		{17, false}, // Miscellaneous Accessories (optional)
+		{23, false}, // Stock (optional)
+		{24, false}, // Suppressor (optional)
 	}
 
 	// Check if relationships already exist
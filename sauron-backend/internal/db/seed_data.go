@@ -1,5 +1,7 @@
 package db
 
+import "sauron-backend/internal/models"
+
 // This file contains ALL data used for seeding the database
 // It is imported by seed.go which handles the actual insertion into the database
 
@@ -115,6 +117,10 @@ var AllParts = map[string]map[string][]string{
 			"Compensator (AR-15)",
 			"Flash Suppressor (AR-15)",
 		},
+		"Suppressor": {
+			"5.56 Direct-Thread Suppressor (AR-15)",
+			"5.56 Quick-Detach Suppressor (AR-15)",
+		},
 		"Upper Receiver": {
 			"Stripped Upper Receiver (AR-15)",
 			"Complete Upper Receiver (AR-15)",
@@ -163,6 +169,7 @@ var AllParts = map[string]map[string][]string{
 			"Standard Magazine Release (AR-15)",
 			"Extended Magazine Release (AR-15)",
 			"Ambidextrous Magazine Release (AR-15)",
+			"Fixed Magazine Kit (AR-15)",
 		},
 		"Bolt Catch": {
 			"Standard Bolt Catch (AR-15)",
@@ -188,6 +195,7 @@ var AllParts = map[string]map[string][]string{
 			"Magpul CTR Stock (AR-15)",
 			"B5 SOPMOD Stock (AR-15)",
 			"Fixed A2 Stock (AR-15)",
+			"SBA3 Pistol Brace (AR-15)",
 		},
 	},
 	"Accessories": {
@@ -258,3 +266,605 @@ var AssemblyParts = []struct {
 		},
 	},
 }
+
+// Calibers with their dimensions, parent cartridges, the calibers their chambers can safely
+// fire, and keywords of the firearm model names chambered in them by default
+var CaliberData = []struct {
+	Name           string
+	Aliases        []string
+	Type           string
+	BulletDiameter float64
+	CaseLength     float64
+	OverallLength  float64
+	Parent         string
+	Fires          []string
+	ModelKeywords  []string
+}{
+	{
+		Name:           ".223 Remington",
+		Aliases:        []string{".223 Rem", ".223"},
+		Type:           "rifle",
+		BulletDiameter: 0.224,
+		CaseLength:     44.70,
+		OverallLength:  57.40,
+	},
+	{
+		Name:           "5.56x45mm NATO",
+		Aliases:        []string{"5.56 NATO", "5.56mm", "5.56"},
+		Type:           "rifle",
+		BulletDiameter: 0.224,
+		CaseLength:     44.70,
+		OverallLength:  57.40,
+		Parent:         ".223 Remington",
+		Fires:          []string{".223 Remington"},
+		ModelKeywords:  []string{"AR-15", "M4", "M16", "SIG M400"},
+	},
+	{
+		Name:           ".223 Wylde",
+		Aliases:        []string{"223 Wylde"},
+		Type:           "rifle",
+		BulletDiameter: 0.224,
+		CaseLength:     44.70,
+		OverallLength:  57.40,
+		Parent:         "5.56x45mm NATO",
+		Fires:          []string{"5.56x45mm NATO", ".223 Remington"},
+	},
+	{
+		Name:           ".300 AAC Blackout",
+		Aliases:        []string{".300 BLK", "300 Blackout", "7.62x35mm"},
+		Type:           "rifle",
+		BulletDiameter: 0.308,
+		CaseLength:     34.70,
+		OverallLength:  57.00,
+		Parent:         ".223 Remington",
+	},
+	{
+		Name:           "7.62x39mm",
+		Aliases:        []string{"7.62x39", "7.62 Soviet"},
+		Type:           "rifle",
+		BulletDiameter: 0.311,
+		CaseLength:     38.70,
+		OverallLength:  56.00,
+		ModelKeywords:  []string{"AK-47", "AKM"},
+	},
+	{
+		Name:           "5.45x39mm",
+		Aliases:        []string{"5.45x39", "5.45 Soviet"},
+		Type:           "rifle",
+		BulletDiameter: 0.221,
+		CaseLength:     39.82,
+		OverallLength:  57.00,
+		ModelKeywords:  []string{"AK-74"},
+	},
+	{
+		Name:           ".308 Winchester",
+		Aliases:        []string{".308 Win", ".308"},
+		Type:           "rifle",
+		BulletDiameter: 0.308,
+		CaseLength:     51.18,
+		OverallLength:  71.12,
+	},
+	{
+		Name:           "7.62x51mm NATO",
+		Aliases:        []string{"7.62 NATO", "7.62x51"},
+		Type:           "rifle",
+		BulletDiameter: 0.308,
+		CaseLength:     51.18,
+		OverallLength:  71.12,
+		Parent:         ".308 Winchester",
+		ModelKeywords:  []string{"AR-10", "SR-25"},
+	},
+	{
+		Name:           "9x19mm Parabellum",
+		Aliases:        []string{"9mm", "9mm Luger", "9x19"},
+		Type:           "pistol",
+		BulletDiameter: 0.355,
+		CaseLength:     19.15,
+		OverallLength:  29.69,
+		ModelKeywords:  []string{"Glock", "SIG P320", "M&P", "CZ P-10"},
+	},
+	{
+		Name:           ".45 ACP",
+		Aliases:        []string{".45 Auto", "45 ACP"},
+		Type:           "pistol",
+		BulletDiameter: 0.452,
+		CaseLength:     22.81,
+		OverallLength:  32.39,
+		ModelKeywords:  []string{"1911", "P220"},
+	},
+	{
+		Name:           ".38 Special",
+		Aliases:        []string{".38 Spl", "38 Special"},
+		Type:           "pistol",
+		BulletDiameter: 0.357,
+		CaseLength:     29.34,
+		OverallLength:  39.37,
+	},
+	{
+		Name:           ".357 Magnum",
+		Aliases:        []string{".357 Mag", "357 Magnum"},
+		Type:           "pistol",
+		BulletDiameter: 0.357,
+		CaseLength:     32.77,
+		OverallLength:  40.01,
+		Parent:         ".38 Special",
+		Fires:          []string{".38 Special"},
+		ModelKeywords:  []string{"Model 686", "Python"},
+	},
+	{
+		Name:           "12 Gauge",
+		Aliases:        []string{"12 ga", "12ga"},
+		Type:           "shotgun",
+		BulletDiameter: 0.729,
+		CaseLength:     69.85,
+		ModelKeywords:  []string{"Mossberg", "Remington", "Benelli", "Beretta"},
+	},
+}
+
+// Accessory mounting interfaces with their slot pitch in inches; KeyMod has no standard pitch
+var MountingInterfaceData = []struct {
+	Name        string
+	Description string
+	SlotPitch   float64
+}{
+	{
+		Name:        "Picatinny",
+		Description: "MIL-STD-1913 rail with cross slots on 0.394 in centers",
+		SlotPitch:   0.394,
+	},
+	{
+		Name:        "M-LOK",
+		Description: "Magpul modular lock system with 0.3 x 1.260 in slots on 1.575 in centers",
+		SlotPitch:   1.575,
+	},
+	{
+		Name:        "KeyMod",
+		Description: "Keyhole mounting slots cut directly into the handguard",
+	},
+}
+
+// Rail sections provided and rail space consumed by seed parts, keyed by part name
+var PartMountData = map[string][]struct {
+	Interface string
+	Role      string
+	Position  string
+	Slots     int
+	Notes     string
+}{
+	"Complete Upper Receiver (AR-15)": {{"Picatinny", "provides", "top", 12, "Flat-top receiver rail"}},
+	"Stripped Upper Receiver (AR-15)": {{"Picatinny", "provides", "top", 12, "Flat-top receiver rail"}},
+	"Billet Upper Receiver (AR-15)":   {{"Picatinny", "provides", "top", 12, "Flat-top receiver rail"}},
+	"Forged Upper Receiver (AR-15)":   {{"Picatinny", "provides", "top", 12, "Flat-top receiver rail"}},
+	"Quad Rail Handguard (AR-15)": {
+		{"Picatinny", "provides", "top", 20, "Continuous top rail"},
+		{"Picatinny", "provides", "bottom", 17, ""},
+		{"Picatinny", "provides", "left", 17, ""},
+		{"Picatinny", "provides", "right", 17, ""},
+	},
+	"M-LOK Handguard (AR-15)": {
+		{"Picatinny", "provides", "top", 36, "Full-length top rail"},
+		{"M-LOK", "provides", "bottom", 8, ""},
+		{"M-LOK", "provides", "left", 6, ""},
+		{"M-LOK", "provides", "right", 6, ""},
+	},
+	"KeyMod Handguard (AR-15)": {
+		{"Picatinny", "provides", "top", 36, "Full-length top rail"},
+		{"KeyMod", "provides", "bottom", 7, ""},
+		{"KeyMod", "provides", "left", 7, ""},
+		{"KeyMod", "provides", "right", 7, ""},
+	},
+	"Free Float Handguard (AR-15)": {
+		{"Picatinny", "provides", "top", 30, "Top rail only"},
+		{"M-LOK", "provides", "bottom", 6, ""},
+	},
+	"MBUS Backup Sights (AR-15)": {{"Picatinny", "consumes", "top", 6, "Front and rear sight together"}},
+	"Fixed Front Sight (AR-15)":  {{"Picatinny", "consumes", "top", 3, ""}},
+	"Fixed Rear Sight (AR-15)":   {{"Picatinny", "consumes", "top", 3, ""}},
+	"Red Dot Mount (AR-15)":      {{"Picatinny", "consumes", "top", 5, "Absolute co-witness height"}},
+	"Sling Mount (AR-15)":        {{"M-LOK", "consumes", "any", 1, "QD cup on a single M-LOK slot"}},
+}
+
+// magazineVariantSeed is a capacity variant of a seed magazine family
+type magazineVariantSeed struct {
+	Capacity int
+	Caliber  string
+	Part     string
+	Notes    string
+}
+
+// Magazine families with keywords of the firearm model names that take them, the calibers
+// they feed and their capacity variants. A variant with a caliber only feeds that caliber,
+// and a variant with a part is sold as that seed part.
+var MagazineFamilyData = []struct {
+	Name          string
+	Description   string
+	ModelKeywords []string
+	Calibers      []string
+	Variants      []magazineVariantSeed
+}{
+	{
+		Name:          "STANAG",
+		Description:   "NATO STANAG 4179 pattern AR-15/M16 box magazine",
+		ModelKeywords: []string{"AR-15", "M4", "M16", "SIG M400"},
+		Calibers:      []string{"5.56x45mm NATO", ".223 Remington", ".223 Wylde", ".300 AAC Blackout"},
+		Variants: []magazineVariantSeed{
+			{Capacity: 10},
+			{Capacity: 20},
+			{Capacity: 30, Part: "USGI 30-Round (AR-15)", Notes: "Aluminium body with anti-tilt follower"},
+			{Capacity: 30, Part: "Lancer 30-Round (AR-15)", Notes: "Translucent polymer body with steel feed lips"},
+			{Capacity: 40},
+		},
+	},
+	{
+		Name:          "PMAG",
+		Description:   "Magpul PMAG AR/M4 polymer magazine, which also fits most STANAG magazine wells",
+		ModelKeywords: []string{"AR-15", "M4", "M16", "SIG M400"},
+		Calibers:      []string{"5.56x45mm NATO", ".223 Remington", ".223 Wylde", ".300 AAC Blackout"},
+		Variants: []magazineVariantSeed{
+			{Capacity: 10},
+			{Capacity: 20, Part: "PMAG 20-Round (AR-15)", Notes: "Gen M3"},
+			{Capacity: 30, Part: "PMAG 30-Round (AR-15)", Notes: "Gen M3"},
+			{Capacity: 30, Caliber: ".300 AAC Blackout", Notes: "300 BLK specific follower and ribbed body"},
+			{Capacity: 40},
+		},
+	},
+	{
+		Name:          "Glock",
+		Description:   "Glock double-stack 9mm magazine",
+		ModelKeywords: []string{"Glock"},
+		Calibers:      []string{"9x19mm Parabellum"},
+		Variants: []magazineVariantSeed{
+			{Capacity: 10},
+			{Capacity: 15, Notes: "Glock 19 flush fit"},
+			{Capacity: 17, Notes: "Glock 17 flush fit"},
+			{Capacity: 33, Notes: "Extended"},
+		},
+	},
+	{
+		Name:          "AK",
+		Description:   "AK-47/AKM pattern 7.62x39mm box magazine",
+		ModelKeywords: []string{"AK-47", "AKM"},
+		Calibers:      []string{"7.62x39mm"},
+		Variants: []magazineVariantSeed{
+			{Capacity: 10},
+			{Capacity: 20},
+			{Capacity: 30},
+			{Capacity: 40},
+		},
+	},
+}
+
+// Federal jurisdiction the states belong to
+var FederalJurisdiction = struct {
+	Code string
+	Name string
+}{Code: "US", Name: "United States (federal)"}
+
+// States and DC with their ISO 3166-2 codes; each belongs to the federal jurisdiction
+var JurisdictionData = []struct {
+	Code string
+	Name string
+}{
+	{"US-AL", "Alabama"}, {"US-AK", "Alaska"}, {"US-AZ", "Arizona"}, {"US-AR", "Arkansas"},
+	{"US-CA", "California"}, {"US-CO", "Colorado"}, {"US-CT", "Connecticut"}, {"US-DE", "Delaware"},
+	{"US-DC", "District of Columbia"}, {"US-FL", "Florida"}, {"US-GA", "Georgia"}, {"US-HI", "Hawaii"},
+	{"US-ID", "Idaho"}, {"US-IL", "Illinois"}, {"US-IN", "Indiana"}, {"US-IA", "Iowa"},
+	{"US-KS", "Kansas"}, {"US-KY", "Kentucky"}, {"US-LA", "Louisiana"}, {"US-ME", "Maine"},
+	{"US-MD", "Maryland"}, {"US-MA", "Massachusetts"}, {"US-MI", "Michigan"}, {"US-MN", "Minnesota"},
+	{"US-MS", "Mississippi"}, {"US-MO", "Missouri"}, {"US-MT", "Montana"}, {"US-NE", "Nebraska"},
+	{"US-NV", "Nevada"}, {"US-NH", "New Hampshire"}, {"US-NJ", "New Jersey"}, {"US-NM", "New Mexico"},
+	{"US-NY", "New York"}, {"US-NC", "North Carolina"}, {"US-ND", "North Dakota"}, {"US-OH", "Ohio"},
+	{"US-OK", "Oklahoma"}, {"US-OR", "Oregon"}, {"US-PA", "Pennsylvania"}, {"US-RI", "Rhode Island"},
+	{"US-SC", "South Carolina"}, {"US-SD", "South Dakota"}, {"US-TN", "Tennessee"}, {"US-TX", "Texas"},
+	{"US-UT", "Utah"}, {"US-VT", "Vermont"}, {"US-VA", "Virginia"}, {"US-WA", "Washington"},
+	{"US-WV", "West Virginia"}, {"US-WI", "Wisconsin"}, {"US-WY", "Wyoming"},
+}
+
+// Law rules keyed by jurisdiction code, with the law that enacted them and the date they took
+// effect (YYYY-MM-DD) where known. These are a simplified summary for build planning, not
+// legal advice: exemptions, registration and grandfathering are not modelled.
+var LawRuleData = []struct {
+	Jurisdiction  string
+	Name          string
+	Description   string
+	Type          string
+	Value         float64
+	Feature       string
+	Features      []string
+	FirearmClass  string
+	Citation      string
+	CitationURL   string
+	Source        string
+	EffectiveFrom string
+}{
+	{
+		Jurisdiction: "US",
+		Name:         "Short-barreled rifle",
+		Description:  "A rifle with a barrel under 16 inches is a short-barreled rifle, an NFA firearm that needs ATF approval and registration",
+		Type:         models.LawRuleMinBarrelLength,
+		Value:        16,
+		FirearmClass: models.FirearmClassRifle,
+		Citation:     "18 U.S.C. § 921(a)(8); 26 U.S.C. § 5845(a)(3)",
+		CitationURL:  "https://www.law.cornell.edu/uscode/text/26/5845",
+		Source:       "National Firearms Act (1934); Gun Control Act of 1968",
+	},
+	{
+		Jurisdiction: "US",
+		Name:         "Minimum rifle overall length",
+		Description:  "A weapon made from a rifle with an overall length under 26 inches is an NFA firearm",
+		Type:         models.LawRuleMinOverallLength,
+		Value:        26,
+		FirearmClass: models.FirearmClassRifle,
+		Citation:     "26 U.S.C. § 5845(a)(4)",
+		CitationURL:  "https://www.law.cornell.edu/uscode/text/26/5845",
+		Source:       "National Firearms Act (1934)",
+	},
+	{
+		Jurisdiction: "US",
+		Name:         "Short-barreled shotgun",
+		Description:  "A shotgun with a barrel under 18 inches is a short-barreled shotgun, an NFA firearm that needs ATF approval and registration",
+		Type:         models.LawRuleMinBarrelLength,
+		Value:        18,
+		FirearmClass: models.FirearmClassShotgun,
+		Citation:     "18 U.S.C. § 921(a)(6); 26 U.S.C. § 5845(a)(1)",
+		CitationURL:  "https://www.law.cornell.edu/uscode/text/26/5845",
+		Source:       "National Firearms Act (1934); Gun Control Act of 1968",
+	},
+	{
+		Jurisdiction:  "US-CA",
+		Name:          "Large-capacity magazine ban",
+		Description:   "Magazines holding more than 10 rounds may not be manufactured, imported, sold or possessed",
+		Type:          models.LawRuleMaxMagazineCapacity,
+		Value:         10,
+		Citation:      "Cal. Penal Code § 32310",
+		CitationURL:   "https://leginfo.legislature.ca.gov/faces/codes_displaySection.xhtml?lawCode=PEN&sectionNum=32310",
+		Source:        "SB 23 (1999); Proposition 63 (2016)",
+		EffectiveFrom: "2000-01-01",
+	},
+	{
+		Jurisdiction:  "US-CA",
+		Name:          "Assault weapon: featured rifle",
+		Description:   "A semi-automatic centerfire rifle with a detachable magazine may not have a pistol grip that protrudes conspicuously beneath the action, a folding or telescoping stock, a forward pistol grip or a flash suppressor",
+		Type:          models.LawRuleAssaultWeaponFeatures,
+		Features:      []string{models.LawFeaturePistolGrip, models.LawFeatureAdjustableStock, models.LawFeatureFoldingStock, models.LawFeatureForwardGrip, models.LawFeatureFlashHider},
+		FirearmClass:  models.FirearmClassRifle,
+		Citation:      "Cal. Penal Code § 30515(a)(1)",
+		CitationURL:   "https://leginfo.legislature.ca.gov/faces/codes_displaySection.xhtml?lawCode=PEN&sectionNum=30515",
+		Source:        "SB 23 (1999)",
+		EffectiveFrom: "2000-01-01",
+	},
+	{
+		Jurisdiction:  "US-CA",
+		Name:          "Assault weapon: featured pistol",
+		Description:   "A semi-automatic pistol with a detachable magazine may not have a threaded barrel or a second handgrip",
+		Type:          models.LawRuleAssaultWeaponFeatures,
+		Features:      []string{models.LawFeatureThreadedBarrel, models.LawFeatureForwardGrip, models.LawFeatureBarrelShroud},
+		FirearmClass:  models.FirearmClassPistol,
+		Citation:      "Cal. Penal Code § 30515(a)(4)",
+		CitationURL:   "https://leginfo.legislature.ca.gov/faces/codes_displaySection.xhtml?lawCode=PEN&sectionNum=30515",
+		Source:        "SB 23 (1999)",
+		EffectiveFrom: "2000-01-01",
+	},
+	{
+		Jurisdiction: "US-CA",
+		Name:         "Silencer prohibition",
+		Description:  "Possessing a silencer is a felony",
+		Type:         models.LawRuleSuppressorProhibited,
+		Citation:     "Cal. Penal Code § 33410",
+		CitationURL:  "https://leginfo.legislature.ca.gov/faces/codes_displaySection.xhtml?lawCode=PEN&sectionNum=33410",
+	},
+	{
+		Jurisdiction:  "US-NY",
+		Name:          "Large-capacity feeding device ban",
+		Description:   "Feeding devices holding more than 10 rounds may not be possessed",
+		Type:          models.LawRuleMaxMagazineCapacity,
+		Value:         10,
+		Citation:      "N.Y. Penal Law §§ 265.00(23), 265.02(8)",
+		Source:        "NY SAFE Act, L. 2013, ch. 1",
+		EffectiveFrom: "2013-01-15",
+	},
+	{
+		Jurisdiction:  "US-NY",
+		Name:          "Assault weapon: featured rifle",
+		Description:   "A semi-automatic rifle with a detachable magazine may not have a folding or telescoping stock, a pistol grip that protrudes conspicuously beneath the action, a second handgrip, or a flash suppressor or threaded barrel designed to accommodate one",
+		Type:          models.LawRuleAssaultWeaponFeatures,
+		Features:      []string{models.LawFeatureAdjustableStock, models.LawFeatureFoldingStock, models.LawFeaturePistolGrip, models.LawFeatureForwardGrip, models.LawFeatureFlashHider, models.LawFeatureThreadedBarrel},
+		FirearmClass:  models.FirearmClassRifle,
+		Citation:      "N.Y. Penal Law § 265.00(22)(a)",
+		Source:        "NY SAFE Act, L. 2013, ch. 1",
+		EffectiveFrom: "2013-01-15",
+	},
+	{
+		Jurisdiction: "US-NY",
+		Name:         "Silencer prohibition",
+		Description:  "Possessing a silencer is criminal possession of a weapon",
+		Type:         models.LawRuleSuppressorProhibited,
+		Citation:     "N.Y. Penal Law § 265.02(2)",
+	},
+	{
+		Jurisdiction:  "US-NJ",
+		Name:          "Large-capacity magazine ban",
+		Description:   "Magazines holding more than 10 rounds may not be possessed",
+		Type:          models.LawRuleMaxMagazineCapacity,
+		Value:         10,
+		Citation:      "N.J.S.A. 2C:39-1(y), 2C:39-3(j)",
+		Source:        "P.L. 2018, c. 39",
+		EffectiveFrom: "2018-12-10",
+	},
+	{
+		Jurisdiction: "US-NJ",
+		Name:         "Silencer prohibition",
+		Description:  "Possessing a firearm silencer is prohibited",
+		Type:         models.LawRuleSuppressorProhibited,
+		Citation:     "N.J.S.A. 2C:39-3(c)",
+	},
+	{
+		Jurisdiction: "US-MA",
+		Name:         "Large-capacity feeding device ban",
+		Description:  "Feeding devices holding more than 10 rounds may not be sold or possessed",
+		Type:         models.LawRuleMaxMagazineCapacity,
+		Value:        10,
+		Citation:     "M.G.L. c. 140 § 131M",
+	},
+	{
+		Jurisdiction: "US-MA",
+		Name:         "Silencer prohibition",
+		Description:  "Possessing a silencer is prohibited",
+		Type:         models.LawRuleSuppressorProhibited,
+		Citation:     "M.G.L. c. 269 § 10A",
+	},
+	{
+		Jurisdiction:  "US-CO",
+		Name:          "Large-capacity magazine ban",
+		Description:   "Magazines holding more than 15 rounds may not be sold, transferred or possessed",
+		Type:          models.LawRuleMaxMagazineCapacity,
+		Value:         15,
+		Citation:      "C.R.S. § 18-12-302",
+		Source:        "HB 13-1224",
+		EffectiveFrom: "2013-07-01",
+	},
+	{
+		Jurisdiction:  "US-WA",
+		Name:          "Large-capacity magazine ban",
+		Description:   "Magazines holding more than 10 rounds may not be manufactured, distributed or sold",
+		Type:          models.LawRuleMaxMagazineCapacity,
+		Value:         10,
+		Citation:      "RCW 9.41.370",
+		Source:        "SB 5078 (2022)",
+		EffectiveFrom: "2022-07-01",
+	},
+	{
+		Jurisdiction:  "US-IL",
+		Name:          "Large-capacity long gun magazine ban",
+		Description:   "Long gun magazines holding more than 10 rounds may not be sold or purchased",
+		Type:          models.LawRuleMaxMagazineCapacity,
+		Value:         10,
+		FirearmClass:  models.FirearmClassRifle,
+		Citation:      "720 ILCS 5/24-1.10",
+		Source:        "Protect Illinois Communities Act, P.A. 102-1116",
+		EffectiveFrom: "2023-01-10",
+	},
+	{
+		Jurisdiction:  "US-IL",
+		Name:          "Large-capacity handgun magazine ban",
+		Description:   "Handgun magazines holding more than 15 rounds may not be sold or purchased",
+		Type:          models.LawRuleMaxMagazineCapacity,
+		Value:         15,
+		FirearmClass:  models.FirearmClassPistol,
+		Citation:      "720 ILCS 5/24-1.10",
+		Source:        "Protect Illinois Communities Act, P.A. 102-1116",
+		EffectiveFrom: "2023-01-10",
+	},
+	{
+		Jurisdiction: "US-IL",
+		Name:         "Silencer prohibition",
+		Description:  "Possessing a device for silencing a firearm is unlawful use of weapons",
+		Type:         models.LawRuleSuppressorProhibited,
+		Citation:     "720 ILCS 5/24-1(a)(6)",
+	},
+	{
+		Jurisdiction: "US-HI",
+		Name:         "Large-capacity pistol magazine ban",
+		Description:  "Pistol magazines holding more than 10 rounds may not be manufactured, possessed or sold",
+		Type:         models.LawRuleMaxMagazineCapacity,
+		Value:        10,
+		FirearmClass: models.FirearmClassPistol,
+		Citation:     "HRS § 134-8(c)",
+	},
+	{
+		Jurisdiction: "US-HI",
+		Name:         "Silencer prohibition",
+		Description:  "Possessing a silencer is prohibited",
+		Type:         models.LawRuleSuppressorProhibited,
+		Citation:     "HRS § 134-8(a)",
+	},
+	{
+		Jurisdiction: "US-DC",
+		Name:         "Large-capacity ammunition feeding device ban",
+		Description:  "Feeding devices holding more than 10 rounds may not be possessed, sold or transferred",
+		Type:         models.LawRuleMaxMagazineCapacity,
+		Value:        10,
+		Citation:     "D.C. Code § 7-2506.01(b)",
+	},
+	{
+		Jurisdiction: "US-DC",
+		Name:         "Silencer prohibition",
+		Description:  "Possessing a firearm silencer is prohibited",
+		Type:         models.LawRuleSuppressorProhibited,
+		Citation:     "D.C. Code § 22-4514(a)",
+	},
+}
+
+// States with magazine capacity limits that sellers commonly refuse to ship magazines to
+var magazineRestrictedStates = []string{"CA", "CO", "CT", "DC", "HI", "IL", "MA", "MD", "NJ", "NY", "VT", "WA"}
+
+// Shipping restrictions keyed by seller name, one per listed state, or one covering every
+// state when none are listed. Sample data reflecting common retailer policies, not any
+// seller's actual terms.
+var ShippingRestrictionData = []struct {
+	Seller        string
+	States        []string
+	Type          string
+	ShippingClass string
+	Reason        string
+}{
+	{
+		Seller:        "Brownells",
+		States:        magazineRestrictedStates,
+		Type:          models.ShippingRestrictionNoShip,
+		ShippingClass: models.ShippingClassMagazine,
+		Reason:        "Brownells does not ship magazines to states with magazine capacity limits",
+	},
+	{
+		Seller:        "Brownells",
+		Type:          models.ShippingRestrictionFFLRequired,
+		ShippingClass: models.ShippingClassFirearm,
+		Reason:        "Firearms and receivers ship only to a licensed dealer (FFL)",
+	},
+	{
+		Seller:        "Midway USA",
+		States:        []string{"CA", "DC", "HI", "MA", "NJ", "NY"},
+		Type:          models.ShippingRestrictionNoShip,
+		ShippingClass: models.ShippingClassMagazine,
+		Reason:        "Midway USA does not ship magazines to this state",
+	},
+	{
+		Seller:        "Midway USA",
+		Type:          models.ShippingRestrictionFFLRequired,
+		ShippingClass: models.ShippingClassFirearm,
+		Reason:        "Firearms and receivers ship only to a licensed dealer (FFL)",
+	},
+	{
+		Seller:        "Primary Arms",
+		States:        []string{"CA", "NY"},
+		Type:          models.ShippingRestrictionNoShip,
+		ShippingClass: models.ShippingClassAmmunition,
+		Reason:        "Primary Arms does not ship ammunition to this state",
+	},
+	{
+		Seller:        "Primary Arms",
+		Type:          models.ShippingRestrictionFFLRequired,
+		ShippingClass: models.ShippingClassFirearm,
+		Reason:        "Firearms and receivers ship only to a licensed dealer (FFL)",
+	},
+	{
+		Seller:        "Palmetto State Armory",
+		States:        []string{"CA", "NJ", "NY"},
+		Type:          models.ShippingRestrictionNoShip,
+		ShippingClass: models.ShippingClassMagazine,
+		Reason:        "Palmetto State Armory does not ship magazines to this state",
+	},
+	{
+		Seller:        "Palmetto State Armory",
+		States:        []string{"CA", "NY"},
+		Type:          models.ShippingRestrictionNoShip,
+		ShippingClass: models.ShippingClassFirearm,
+		Reason:        "Palmetto State Armory does not ship firearms or receivers to this state",
+	},
+	{
+		Seller:        "Palmetto State Armory",
+		Type:          models.ShippingRestrictionFFLRequired,
+		ShippingClass: models.ShippingClassFirearm,
+		Reason:        "Firearms and receivers ship only to a licensed dealer (FFL)",
+	},
+}
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+)
+
+// Build represents a user-saved firearm configuration
+// @Description A firearm model together with the part chosen for each of its part category slots
+type Build struct {
+	// Unique identifier for the build
+	ID int `json:"id" gorm:"primaryKey" example:"1"`
+
+	// Name of the build
+	Name string `json:"name" gorm:"size:255;not null" example:"Lightweight 16in Carbine"`
+
+	// Description of the build
+	Description string `json:"description" gorm:"type:text" example:"A lightweight AR-15 build for general purpose use."`
+
+	// Reference to the base firearm model
+	FirearmModelID int          `json:"firearm_model_id" gorm:"index;not null" example:"1"`
+	FirearmModel   FirearmModel `json:"-" gorm:"foreignKey:FirearmModelID"`
+
+	// Parts selected for each slot of the build
+	Parts []BuildPart `json:"parts" gorm:"foreignKey:BuildID;constraint:OnDelete:CASCADE"`
+
+	// Creation timestamp
+	CreatedAt time.Time `json:"created_at" gorm:"default:current_timestamp"`
+
+	// Last update timestamp
+	UpdatedAt time.Time `json:"updated_at" gorm:"default:current_timestamp"`
+}
@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+)
+
+// BuildPart represents the part selected for a single slot of a build
+// @Description A slot in a build, identified by a part category of the build's firearm model, and the part chosen for it
+type BuildPart struct {
+	// Unique identifier for the build part
+	ID int `json:"id" gorm:"primaryKey" example:"1"`
+
+	// Reference to the build this slot belongs to
+	BuildID int `json:"build_id" gorm:"index;uniqueIndex:build_slot;not null" example:"1"`
+
+	// Part category that identifies the slot
+	PartCategoryID int          `json:"part_category_id" gorm:"uniqueIndex:build_slot;not null" example:"10"`
+	PartCategory   PartCategory `json:"-" gorm:"foreignKey:PartCategoryID"`
+
+	// Reference to the part chosen for the slot
+	PartID int  `json:"part_id" gorm:"index;not null" example:"5"`
+	Part   Part `json:"part" gorm:"foreignKey:PartID"`
+
+	// Creation timestamp
+	CreatedAt time.Time `json:"created_at" gorm:"default:current_timestamp"`
+}
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// BuildRevision is an immutable snapshot of a build taken every time it is saved
+// @Description Append-only history entry recording a build's details and slot selections at a point in time
+type BuildRevision struct {
+	// Unique identifier for the revision
+	ID int `json:"id" gorm:"primaryKey" example:"1"`
+
+	// Reference to the build this revision belongs to
+	BuildID int   `json:"build_id" gorm:"index;uniqueIndex:build_revision;not null" example:"1"`
+	Build   Build `json:"-" gorm:"foreignKey:BuildID;constraint:OnDelete:CASCADE"`
+
+	// Sequential revision number within the build, starting at 1
+	Revision int `json:"revision" gorm:"uniqueIndex:build_revision;not null" example:"3"`
+
+	// Name of the build at this revision
+	Name string `json:"name" gorm:"size:255;not null" example:"Lightweight 16in Carbine"`
+
+	// Description of the build at this revision
+	Description string `json:"description" gorm:"type:text" example:"A lightweight AR-15 build for general purpose use."`
+
+	// Firearm model of the build at this revision
+	FirearmModelID int `json:"firearm_model_id" gorm:"not null" example:"1"`
+
+	// Selected parts keyed by part category (slot) ID
+	Slots datatypes.JSON `json:"slots" gorm:"type:jsonb;not null" swaggertype:"object,integer" example:"10:5,13:2"`
+
+	// Creation timestamp
+	CreatedAt time.Time `json:"created_at" gorm:"default:current_timestamp"`
+}
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// Caliber represents a cartridge that firearms, barrels, bolts, magazines and ammunition are made for
+// @Description Cartridge information including alternate names, case dimensions and which cartridges a chamber can safely fire
+type Caliber struct {
+	// Unique identifier for the caliber
+	ID int `json:"id" gorm:"primaryKey" example:"1"`
+
+	// Canonical name of the caliber
+	Name string `json:"name" gorm:"size:100;uniqueIndex;not null" example:"5.56x45mm NATO"`
+
+	// Alternate names the caliber is sold under
+	Aliases datatypes.JSON `json:"aliases" gorm:"type:jsonb" swaggertype:"array,string" example:"[\"5.56 NATO\",\"5.56mm\"]"`
+
+	// Broad type of cartridge
+	Type string `json:"type" gorm:"size:50" example:"rifle" enums:"rifle,pistol,rimfire,shotgun"`
+
+	// Bullet diameter in inches
+	BulletDiameter float64 `json:"bullet_diameter" gorm:"type:decimal(6,3)" example:"0.224"`
+
+	// Case length in millimetres
+	CaseLength float64 `json:"case_length" gorm:"type:decimal(6,2)" example:"44.70"`
+
+	// Overall cartridge length in millimetres
+	OverallLength float64 `json:"overall_length" gorm:"type:decimal(6,2)" example:"57.40"`
+
+	// Cartridge this caliber was derived from, e.g. .223 Remington for .300 AAC Blackout
+	ParentCaliberID *int     `json:"parent_caliber_id,omitempty" gorm:"index" example:"2"`
+	ParentCaliber   *Caliber `json:"parent_caliber,omitempty" gorm:"foreignKey:ParentCaliberID"`
+
+	// Other calibers that can be safely fired in a chamber for this caliber
+	FiresCalibers []Caliber `json:"fires_calibers,omitempty" gorm:"many2many:caliber_compatibilities;joinForeignKey:ChamberCaliberID;joinReferences:AmmoCaliberID"`
+
+	// Creation timestamp
+	CreatedAt time.Time `json:"created_at" gorm:"default:current_timestamp"`
+
+	// Last update timestamp
+	UpdatedAt time.Time `json:"updated_at" gorm:"default:current_timestamp"`
+}
@@ -0,0 +1,92 @@
+package models
+
+import (
+	"time"
+)
+
+// Well-known part attribute keys used by compatibility rules
+const (
+	PartAttributeCaliber                 = "caliber"
+	PartAttributeThreadPitch             = "thread_pitch"
+	PartAttributeGasSystemLength         = "gas_system_length"
+	PartAttributeBufferTubeSpec          = "buffer_tube_spec"
+	PartAttributeGasBlockJournalDiameter = "gas_block_journal_diameter"
+	PartAttributeGasBlockBoreDiameter    = "gas_block_bore_diameter"
+)
+
+// Comparison operators supported by compatibility rules
+const (
+	RuleOperatorEqual          = "eq"
+	RuleOperatorNotEqual       = "ne"
+	RuleOperatorLessThan       = "lt"
+	RuleOperatorLessOrEqual    = "lte"
+	RuleOperatorGreaterThan    = "gt"
+	RuleOperatorGreaterOrEqual = "gte"
+)
+
+// CompatibilityRule is a declarative constraint between the attributes of parts in two categories
+// @Description Rule comparing an attribute of parts in one category with an attribute of parts in another, e.g. gas block bore must equal barrel journal diameter
+type CompatibilityRule struct {
+	// Unique identifier for the rule
+	ID int `json:"id" gorm:"primaryKey" example:"1"`
+
+	// Short name of the rule
+	Name string `json:"name" gorm:"size:255;not null" example:"Gas block fits barrel journal"`
+
+	// Explanation shown when the rule fails
+	Description string `json:"description" gorm:"type:text" example:"Gas block inner diameter must equal the barrel's gas block journal diameter"`
+
+	// Category (including its subcategories) whose parts supply the left-hand attribute
+	SourceCategoryID int          `json:"source_category_id" gorm:"index;not null" example:"11"`
+	SourceCategory   PartCategory `json:"-" gorm:"foreignKey:SourceCategoryID"`
+
+	// Attribute read from the source part
+	SourceAttribute string `json:"source_attribute" gorm:"size:100;not null" example:"gas_block_bore_diameter"`
+
+	// Category (including its subcategories) whose parts supply the right-hand attribute
+	TargetCategoryID int          `json:"target_category_id" gorm:"index;not null" example:"10"`
+	TargetCategory   PartCategory `json:"-" gorm:"foreignKey:TargetCategoryID"`
+
+	// Attribute read from the target part
+	TargetAttribute string `json:"target_attribute" gorm:"size:100;not null" example:"gas_block_journal_diameter"`
+
+	// Comparison applied as source <operator> target (eq, ne, lt, lte, gt, gte)
+	Operator string `json:"operator" gorm:"size:10;not null;default:'eq'" example:"eq"`
+
+	// Allowed difference when comparing numeric attributes for equality
+	Tolerance float64 `json:"tolerance" gorm:"default:0" example:"0.001"`
+
+	// Ruleset version that imported the rule, unset for rules managed through the API
+	RuleSetID *int `json:"rule_set_id,omitempty" gorm:"index" example:"3"`
+
+	// Part pairings the rule does not apply to
+	Exceptions []CompatibilityRuleException `json:"exceptions,omitempty" gorm:"foreignKey:RuleID;constraint:OnDelete:CASCADE"`
+
+	// Creation timestamp
+	CreatedAt time.Time `json:"created_at" gorm:"default:current_timestamp"`
+
+	// Last update timestamp
+	UpdatedAt time.Time `json:"updated_at" gorm:"default:current_timestamp"`
+}
+
+// CompatibilityRuleException exempts specific parts from a compatibility rule
+// @Description Known-good pairing that passes a rule regardless of attributes; an unset part matches any part
+type CompatibilityRuleException struct {
+	// Unique identifier for the exception
+	ID int `json:"id" gorm:"primaryKey" example:"1"`
+
+	// Rule the exception belongs to
+	RuleID int `json:"rule_id" gorm:"index;not null" example:"1"`
+
+	// Source part exempted, or any source part when unset
+	SourcePartID *int `json:"source_part_id,omitempty" example:"31"`
+
+	// Target part exempted, or any target part when unset
+	TargetPartID *int `json:"target_part_id,omitempty" example:"7"`
+
+	// Why the pairing is known to work
+	Reason string `json:"reason" gorm:"type:text" example:"Ships with a 0.750 adapter sleeve"`
+
+	// Creation timestamp
+	CreatedAt time.Time `json:"created_at" gorm:"default:current_timestamp"`
+}
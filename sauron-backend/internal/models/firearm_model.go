@@ -6,6 +6,22 @@ import (
 	"gorm.io/datatypes"
 )
 
+// Action types of a firearm model
+const (
+	ActionTypeSemiAuto = "semi_auto"
+	ActionTypeBolt     = "bolt"
+	ActionTypePump     = "pump"
+	ActionTypeLever    = "lever"
+	ActionTypeSingle   = "single_shot"
+)
+
+// Magazine types of a firearm model
+const (
+	MagazineTypeDetachable = "detachable"
+	MagazineTypeFixed      = "fixed"
+	MagazineTypeTube       = "tube"
+)
+
 // FirearmModel represents a firearm model in the database
 // @Description Firearm model information including hierarchical parts structure
 type FirearmModel struct {
@@ -31,8 +47,18 @@ type FirearmModel struct {
 	// Variant of the firearm model
 	Variant string `json:"variant" gorm:"size:50" example:""`
 
+	// How the firearm cycles, which assault weapon laws depend on; unknown when empty
+	ActionType string `json:"action_type,omitempty" gorm:"size:20" example:"semi_auto" enums:"semi_auto,bolt,pump,lever,single_shot"`
+
+	// How the firearm is fed, which assault weapon laws depend on; unknown when empty
+	MagazineType string `json:"magazine_type,omitempty" gorm:"size:20" example:"detachable" enums:"detachable,fixed,tube"`
+
+	// Caliber the firearm model is chambered in by default
+	CaliberID *int     `json:"caliber_id,omitempty" gorm:"index" example:"1"`
+	Caliber   *Caliber `json:"caliber,omitempty" gorm:"foreignKey:CaliberID"`
+
 	// Specifications of the firearm
-	Specifications datatypes.JSON `json:"specifications" gorm:"type:jsonb" swaggertype:"string" example:"{\"weight\": \"6.5 lbs\", \"caliber\": \"5.56x45mm NATO\"}"`
+	Specifications datatypes.JSON `json:"specifications" gorm:"type:jsonb" swaggertype:"string" example:"{\"weight\": \"6.5 lbs\"}"`
 
 	// Related part categories through FirearmModelPartCategory
 	PartCategories []PartCategory `json:"part_categories,omitempty" gorm:"many2many:firearm_model_part_categories"`
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"time"
+)
+
+// Levels of government a jurisdiction can belong to
+const (
+	JurisdictionLevelFederal = "federal"
+	JurisdictionLevelState   = "state"
+)
+
+// Jurisdiction represents a government whose firearm laws builds are checked against
+// @Description Federal or state jurisdiction; the laws of a jurisdiction include those of its parent
+type Jurisdiction struct {
+	// Unique identifier for the jurisdiction
+	ID int `json:"id" gorm:"primaryKey" example:"2"`
+
+	// Short code, e.g. US for federal law or US-CA for California
+	Code string `json:"code" gorm:"size:20;uniqueIndex;not null" example:"US-CA"`
+
+	// Name of the jurisdiction
+	Name string `json:"name" gorm:"size:100;not null" example:"California"`
+
+	// Level of government
+	Level string `json:"level" gorm:"size:20;not null" example:"state" enums:"federal,state"`
+
+	// Jurisdiction whose laws also apply, e.g. federal law for a state
+	ParentJurisdictionID *int          `json:"parent_jurisdiction_id,omitempty" gorm:"index" example:"1"`
+	ParentJurisdiction   *Jurisdiction `json:"parent_jurisdiction,omitempty" gorm:"foreignKey:ParentJurisdictionID"`
+
+	// Creation timestamp
+	CreatedAt time.Time `json:"created_at" gorm:"default:current_timestamp"`
+
+	// Last update timestamp
+	UpdatedAt time.Time `json:"updated_at" gorm:"default:current_timestamp"`
+}
@@ -0,0 +1,130 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// Well-known part attribute keys read by law rules
+const (
+	// Barrel length in inches
+	PartAttributeBarrelLength = "barrel_length"
+
+	// Overall length in inches of a complete firearm or assembly
+	PartAttributeOverallLength = "overall_length"
+
+	// Rounds a magazine holds, for magazines not recorded as a magazine variant
+	PartAttributeCapacity = "capacity"
+
+	// Action type a part gives the firearm, e.g. a bolt-action upper, overriding its model's
+	PartAttributeActionType = "action_type"
+
+	// Magazine type a part gives the firearm, e.g. fixed for a fixed-magazine kit
+	PartAttributeMagazineType = "magazine_type"
+)
+
+// Kinds of law rule
+const (
+	// Barrel must be at least Value inches long
+	LawRuleMinBarrelLength = "min_barrel_length"
+
+	// Firearm must be at least Value inches long overall
+	LawRuleMinOverallLength = "min_overall_length"
+
+	// Magazines may hold at most Value rounds
+	LawRuleMaxMagazineCapacity = "max_magazine_capacity"
+
+	// No part may have Feature
+	LawRuleBannedFeature = "banned_feature"
+
+	// Suppressors may not be possessed
+	LawRuleSuppressorProhibited = "suppressor_prohibited"
+
+	// A semi-automatic firearm with a detachable magazine may not have any of Features
+	LawRuleAssaultWeaponFeatures = "assault_weapon_features"
+)
+
+// Well-known features banned feature and assault weapon feature rules refer to. A part has a feature when its boolean
+// attribute of the same name is true.
+const (
+	LawFeatureThreadedBarrel  = "threaded_barrel"
+	LawFeatureAdjustableStock = "adjustable_stock"
+	LawFeatureFoldingStock    = "folding_stock"
+	LawFeatureFlashHider      = "flash_hider"
+	LawFeaturePistolGrip      = "pistol_grip"
+	LawFeatureForwardGrip     = "forward_grip"
+	LawFeatureBarrelShroud    = "barrel_shroud"
+	LawFeatureSuppressor      = "suppressor"
+)
+
+// Firearm classes law rules can be limited to
+const (
+	FirearmClassRifle   = "rifle"
+	FirearmClassPistol  = "pistol"
+	FirearmClassShotgun = "shotgun"
+
+	// Neither a rifle, pistol nor shotgun, e.g. a stockless long gun; no class-limited rule
+	// applies to it
+	FirearmClassOther = "firearm"
+)
+
+// LawRule is a firearm law of a jurisdiction that builds and prebuilt firearms are checked against
+// @Description Legal limit or prohibition of a jurisdiction, optionally limited to a firearm class or model, with its citation and the dates it is in effect
+type LawRule struct {
+	// Unique identifier for the law rule
+	ID int `json:"id" gorm:"primaryKey" example:"1"`
+
+	// Jurisdiction that enacted the rule
+	JurisdictionID int           `json:"jurisdiction_id" gorm:"index;not null" example:"2"`
+	Jurisdiction   *Jurisdiction `json:"jurisdiction,omitempty" gorm:"foreignKey:JurisdictionID"`
+
+	// Short name of the rule
+	Name string `json:"name" gorm:"size:255;not null" example:"Large-capacity magazine ban"`
+
+	// Explanation shown when the rule is violated
+	Description string `json:"description" gorm:"type:text" example:"Magazines holding more than 10 rounds may not be manufactured, imported, sold or possessed"`
+
+	// Kind of rule
+	Type string `json:"type" gorm:"size:50;not null" example:"max_magazine_capacity" enums:"min_barrel_length,min_overall_length,max_magazine_capacity,banned_feature,suppressor_prohibited,assault_weapon_features"`
+
+	// Length in inches or capacity in rounds for limit rules
+	Value float64 `json:"value" gorm:"type:decimal(8,2);default:0" example:"10"`
+
+	// Feature a banned feature rule prohibits
+	Feature string `json:"feature,omitempty" gorm:"size:50" example:"flash_hider"`
+
+	// Features an assault weapon feature rule prohibits, any one of which is enough
+	Features datatypes.JSON `json:"features,omitempty" gorm:"type:jsonb" swaggertype:"array,string" example:"[\"pistol_grip\",\"adjustable_stock\",\"flash_hider\"]"`
+
+	// Firearm class the rule is limited to, or every class when empty
+	FirearmClass string `json:"firearm_class,omitempty" gorm:"size:20" example:"rifle" enums:"rifle,pistol,shotgun"`
+
+	// Firearm model the rule is limited to, or every model when unset
+	FirearmModelID *int          `json:"firearm_model_id,omitempty" gorm:"index" example:"1"`
+	FirearmModel   *FirearmModel `json:"firearm_model,omitempty" gorm:"foreignKey:FirearmModelID"`
+
+	// Statute or regulation the rule comes from
+	Citation string `json:"citation" gorm:"size:255;not null" example:"Cal. Penal Code § 32310"`
+
+	// Link to the text of the statute, if any
+	CitationURL string `json:"citation_url,omitempty" gorm:"size:500" example:"https://leginfo.legislature.ca.gov/faces/codes_displaySection.xhtml?lawCode=PEN&sectionNum=32310"`
+
+	// Bill, session law or regulation that enacted or last amended the rule
+	Source string `json:"source,omitempty" gorm:"size:255" example:"SB 1446 (2016)"`
+
+	// Date the rule takes effect; it has always applied when unset
+	EffectiveFrom *time.Time `json:"effective_from,omitempty" example:"2017-07-01T00:00:00Z"`
+
+	// Date the rule stops applying, exclusive; it applies indefinitely when unset
+	EffectiveUntil *time.Time `json:"effective_until,omitempty" example:"2030-01-01T00:00:00Z"`
+
+	// Revision number, starting at 1 and increased by every change
+	Version int `json:"version" gorm:"not null;default:1" example:"2"`
+
+	// Creation timestamp
+	CreatedAt time.Time `json:"created_at" gorm:"default:current_timestamp"`
+
+	// Last update timestamp
+	UpdatedAt time.Time `json:"updated_at" gorm:"default:current_timestamp"`
+}
@@ -0,0 +1,51 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// Kinds of change recorded in the law rule audit trail
+const (
+	LawRuleChangeCreated = "created"
+	LawRuleChangeUpdated = "updated"
+	LawRuleChangeDeleted = "deleted"
+)
+
+// LawRuleChange records one change to a law rule
+// @Description Append-only audit entry recording who created, updated or deleted a law rule, when, and the rule before and after
+type LawRuleChange struct {
+	// Unique identifier for the change
+	ID int `json:"id" gorm:"primaryKey" example:"1"`
+
+	// Law rule that was changed; kept after the rule is deleted
+	LawRuleID int `json:"law_rule_id" gorm:"index;not null" example:"4"`
+
+	// Jurisdiction of the rule at the time of the change
+	JurisdictionID int `json:"jurisdiction_id" gorm:"index;not null" example:"2"`
+
+	// Version of the rule the change produced, or the last version for deletions
+	Version int `json:"version" gorm:"not null" example:"2"`
+
+	// Kind of change
+	Action string `json:"action" gorm:"size:20;not null" example:"updated" enums:"created,updated,deleted"`
+
+	// Person or system that made the change
+	ChangedBy string `json:"changed_by" gorm:"size:255;index;not null" example:"jdoe"`
+
+	// Why the change was made, e.g. the bill that prompted it
+	Reason string `json:"reason" gorm:"type:text" example:"AB 1234 raised the limit"`
+
+	// Names of the fields that changed
+	ChangedFields datatypes.JSON `json:"changed_fields" gorm:"type:jsonb" swaggertype:"array,string" example:"value,effective_from"`
+
+	// Rule before the change, empty for creations
+	Before datatypes.JSON `json:"before,omitempty" gorm:"type:jsonb" swaggertype:"object"`
+
+	// Rule after the change, empty for deletions
+	After datatypes.JSON `json:"after,omitempty" gorm:"type:jsonb" swaggertype:"object"`
+
+	// Time of the change
+	CreatedAt time.Time `json:"created_at" gorm:"index;default:current_timestamp"`
+}
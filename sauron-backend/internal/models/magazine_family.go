@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+)
+
+// MagazineFamily represents a magazine pattern shared by interchangeable magazines, e.g. STANAG or Glock
+// @Description Magazine pattern with the firearm models that take it and the calibers it feeds
+type MagazineFamily struct {
+	// Unique identifier for the magazine family
+	ID int `json:"id" gorm:"primaryKey" example:"1"`
+
+	// Name of the magazine family
+	Name string `json:"name" gorm:"size:100;uniqueIndex;not null" example:"STANAG"`
+
+	// Description of the magazine family
+	Description string `json:"description" gorm:"type:text" example:"NATO STANAG 4179 pattern AR-15/M16 box magazine"`
+
+	// Firearm models whose magazine well takes the family
+	FirearmModels []FirearmModel `json:"firearm_models,omitempty" gorm:"many2many:magazine_family_firearm_models"`
+
+	// Calibers the family feeds
+	Calibers []Caliber `json:"calibers,omitempty" gorm:"many2many:magazine_family_calibers"`
+
+	// Capacity variants of the family
+	Variants []MagazineVariant `json:"variants,omitempty" gorm:"foreignKey:MagazineFamilyID;constraint:OnDelete:CASCADE"`
+
+	// Creation timestamp
+	CreatedAt time.Time `json:"created_at" gorm:"default:current_timestamp"`
+
+	// Last update timestamp
+	UpdatedAt time.Time `json:"updated_at" gorm:"default:current_timestamp"`
+}
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+)
+
+// MagazineVariant represents a capacity variant of a magazine family, optionally tied to a catalog part
+// @Description Magazine of a family with a given capacity, optionally for a single caliber of the family
+type MagazineVariant struct {
+	// Unique identifier for the magazine variant
+	ID int `json:"id" gorm:"primaryKey" example:"1"`
+
+	// Magazine family the variant belongs to
+	MagazineFamilyID int             `json:"magazine_family_id" gorm:"index;not null" example:"1"`
+	MagazineFamily   *MagazineFamily `json:"magazine_family,omitempty" gorm:"foreignKey:MagazineFamilyID"`
+
+	// Rounds the magazine holds
+	Capacity int `json:"capacity" gorm:"not null" example:"30"`
+
+	// Caliber the variant is made for; when empty it feeds every caliber of its family
+	CaliberID *int     `json:"caliber_id,omitempty" gorm:"index" example:"2"`
+	Caliber   *Caliber `json:"caliber,omitempty" gorm:"foreignKey:CaliberID"`
+
+	// Catalog part sold as this variant, if any; a part belongs to at most one variant
+	PartID *int  `json:"part_id,omitempty" gorm:"uniqueIndex" example:"45"`
+	Part   *Part `json:"part,omitempty" gorm:"foreignKey:PartID;constraint:OnDelete:SET NULL"`
+
+	// Variant notes, e.g. generation or follower colour
+	Notes string `json:"notes" gorm:"type:text" example:"Gen M3 with window"`
+
+	// Creation timestamp
+	CreatedAt time.Time `json:"created_at" gorm:"default:current_timestamp"`
+
+	// Last update timestamp
+	UpdatedAt time.Time `json:"updated_at" gorm:"default:current_timestamp"`
+}
@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+)
+
+// MountingInterface represents an accessory mounting standard such as Picatinny, M-LOK or KeyMod
+// @Description Accessory mounting standard whose rail sections are measured in slots
+type MountingInterface struct {
+	// Unique identifier for the mounting interface
+	ID int `json:"id" gorm:"primaryKey" example:"1"`
+
+	// Name of the mounting interface
+	Name string `json:"name" gorm:"size:100;uniqueIndex;not null" example:"Picatinny"`
+
+	// Description of the mounting interface
+	Description string `json:"description" gorm:"type:text" example:"MIL-STD-1913 rail with cross slots on 0.394 in centers"`
+
+	// Distance between slot centres in inches, used to express slot counts as rail length; 0 when not recorded
+	SlotPitch float64 `json:"slot_pitch" gorm:"type:decimal(6,3)" example:"0.394"`
+
+	// Creation timestamp
+	CreatedAt time.Time `json:"created_at" gorm:"default:current_timestamp"`
+
+	// Last update timestamp
+	UpdatedAt time.Time `json:"updated_at" gorm:"default:current_timestamp"`
+}
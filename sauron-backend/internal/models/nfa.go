@@ -0,0 +1,23 @@
+package models
+
+// NFA-relevant roles a part category or part can be tagged with. A part without a tag of its
+// own takes the tag of its nearest tagged category.
+const (
+	// Silencer or suppressor, an NFA firearm in its own right
+	NFATagSuppressor = "suppressor"
+
+	// Barrel too short for a rifle or shotgun, for barrels without a recorded barrel_length
+	NFATagShortBarrel = "short_barrel"
+
+	// Stock designed to be fired from the shoulder
+	NFATagShoulderStock = "shoulder_stock"
+
+	// Pistol stabilizing brace, which is not a shoulder stock
+	NFATagPistolBrace = "pistol_brace"
+
+	// Vertical foregrip, which makes a pistol an any other weapon
+	NFATagVerticalForegrip = "vertical_foregrip"
+
+	// Part that makes a firearm fire automatically, e.g. an auto sear or M16 fire control group
+	NFATagFullAuto = "full_auto"
+)
@@ -26,6 +26,10 @@ type Part struct {
 	PartCategoryID *int          `json:"part_category_id,omitempty" gorm:"index" example:"18"`
 	PartCategory   *PartCategory `json:"part_category,omitempty" gorm:"foreignKey:PartCategoryID"`
 
+	// Caliber the part is made for, for caliber-sensitive parts such as barrels, bolts, magazines and ammunition
+	CaliberID *int     `json:"caliber_id,omitempty" gorm:"index" example:"1"`
+	Caliber   *Caliber `json:"caliber,omitempty" gorm:"foreignKey:CaliberID"`
+
 	// Whether this is a pre-built component
 	IsPrebuilt bool `json:"is_prebuilt" gorm:"default:false" example:"false"`
 
@@ -38,6 +42,13 @@ type Part struct {
 	// Dimensions of the part
 	Dimensions string `json:"dimensions" gorm:"size:50" example:"5 x 3 x 2 in"`
 
+	// Typed interface attributes evaluated by compatibility rules, e.g. thread pitch or gas system
+	// length; the caliber comes from caliber_id and cannot be set here
+	Attributes datatypes.JSON `json:"attributes" gorm:"type:jsonb" swaggertype:"object,string" example:"thread_pitch:1/2x28,gas_system_length:carbine"`
+
+	// NFA-relevant role of the part, overriding the one its category implies
+	NFATag string `json:"nfa_tag,omitempty" gorm:"size:30" example:"pistol_brace" enums:"suppressor,short_barrel,shoulder_stock,pistol_brace,vertical_foregrip,full_auto"`
+
 	// Creation timestamp
 	CreatedAt time.Time `json:"created_at" gorm:"default:current_timestamp"`
 
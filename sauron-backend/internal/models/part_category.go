@@ -2,8 +2,40 @@ package models
 
 import (
 	"time"
+
+	"gorm.io/datatypes"
+)
+
+// Value types an attribute definition can declare
+const (
+	AttributeTypeString  = "string"
+	AttributeTypeNumber  = "number"
+	AttributeTypeInteger = "integer"
+	AttributeTypeBoolean = "boolean"
 )
 
+// AttributeDefinition declares one attribute that parts in a category carry
+// @Description Name, type, unit and allowed values of a part attribute
+type AttributeDefinition struct {
+	// Attribute key in Part.Attributes
+	Name string `json:"name" example:"gas_block_journal_diameter"`
+
+	// Value type: string, number, integer or boolean
+	Type string `json:"type" example:"number" enums:"string,number,integer,boolean"`
+
+	// Unit the value is expressed in, if any
+	Unit string `json:"unit,omitempty" example:"in"`
+
+	// Allowed values for string attributes; empty allows any value
+	Enum []string `json:"enum,omitempty"`
+
+	// Whether every part in the category must set the attribute
+	Required bool `json:"required" example:"true"`
+
+	// What the attribute describes
+	Description string `json:"description,omitempty" example:"Outer diameter of the barrel where the gas block mounts"`
+}
+
 // PartCategory represents a category of firearm parts in the database
 // @Description Hierarchical structure of part categories
 type PartCategory struct {
@@ -25,6 +57,15 @@ type PartCategory struct {
 	// Description of the category
 	Description string `json:"description" gorm:"type:text" example:"Core upper receiver components"`
 
+	// Attributes parts in this category and its subcategories must satisfy, as a list of AttributeDefinition
+	AttributeSchema datatypes.JSON `json:"attribute_schema,omitempty" gorm:"type:jsonb" swaggertype:"array,object"`
+
+	// NFA-relevant role of parts in this category and its subcategories, if any
+	NFATag string `json:"nfa_tag,omitempty" gorm:"size:30" example:"suppressor" enums:"suppressor,short_barrel,shoulder_stock,pistol_brace,vertical_foregrip,full_auto"`
+
+	// Shipping class of parts in this category and its subcategories that sellers may restrict, if any
+	ShippingClass string `json:"shipping_class,omitempty" gorm:"size:20" example:"magazine" enums:"magazine,ammunition,firearm"`
+
 	// Creation timestamp
 	CreatedAt time.Time `json:"created_at" gorm:"default:current_timestamp"`
 
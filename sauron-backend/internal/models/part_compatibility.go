@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+)
+
+// PartCompatibility is an edge of the precomputed part compatibility graph. Every compatible
+// pair is stored once, from the lower part ID, so a part's neighbors are found through either column.
+// @Description Precomputed compatibility between two parts
+type PartCompatibility struct {
+	// Lower ID of the pair
+	PartID int   `json:"part_id" gorm:"primaryKey;autoIncrement:false" example:"12"`
+	Part   *Part `json:"part,omitempty" gorm:"foreignKey:PartID;constraint:OnDelete:CASCADE"`
+
+	// Higher ID of the pair
+	CompatiblePartID int   `json:"compatible_part_id" gorm:"primaryKey;autoIncrement:false;index" example:"31"`
+	CompatiblePart   *Part `json:"compatible_part,omitempty" gorm:"foreignKey:CompatiblePartID;constraint:OnDelete:CASCADE"`
+
+	// Whether a compatibility rule could not be evaluated for the pair because an attribute is missing
+	HasUnknown bool `json:"has_unknown" gorm:"default:false" example:"false"`
+
+	// When the edge was last computed
+	ComputedAt time.Time `json:"computed_at" gorm:"default:current_timestamp"`
+}
+
+// CompatibilityIndexState records whether the compatibility graph is current. Changes that can
+// affect any pair bump Version and leave the full rebuild to the background; until BuiltVersion
+// catches up, compatibility is evaluated at query time instead of read from the graph.
+type CompatibilityIndexState struct {
+	// Always 1, the table holds a single row
+	ID int `json:"id" gorm:"primaryKey" example:"1"`
+
+	// Incremented whenever a change requires a full rebuild
+	Version int `json:"version" gorm:"not null;default:0" example:"4"`
+
+	// Version the graph was last fully rebuilt for
+	BuiltVersion int `json:"built_version" gorm:"not null;default:0" example:"4"`
+
+	// Last update timestamp
+	UpdatedAt time.Time `json:"updated_at" gorm:"default:current_timestamp"`
+}
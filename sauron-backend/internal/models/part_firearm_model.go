@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+)
+
+// PartFirearmModel declares that a part fits a specific firearm model
+// @Description Explicit fitment of a part to a firearm model, with notes and whether fitting requires modification
+type PartFirearmModel struct {
+	// Unique identifier for the fitment
+	ID int `json:"id" gorm:"primaryKey" example:"1"`
+
+	// Part that fits the firearm model
+	PartID int   `json:"part_id" gorm:"index;uniqueIndex:part_firearm_model;not null" example:"12"`
+	Part   *Part `json:"part,omitempty" gorm:"foreignKey:PartID;constraint:OnDelete:CASCADE"`
+
+	// Firearm model the part fits
+	FirearmModelID int           `json:"firearm_model_id" gorm:"index;uniqueIndex:part_firearm_model;not null" example:"1"`
+	FirearmModel   *FirearmModel `json:"firearm_model,omitempty" gorm:"foreignKey:FirearmModelID;constraint:OnDelete:CASCADE"`
+
+	// Installation notes, e.g. generation or variant caveats
+	Notes string `json:"notes" gorm:"type:text" example:"Gen 3 and later only"`
+
+	// Whether the part needs gunsmithing or fitting to work with the model
+	RequiresModification bool `json:"requires_modification" gorm:"default:false" example:"false"`
+
+	// Creation timestamp
+	CreatedAt time.Time `json:"created_at" gorm:"default:current_timestamp"`
+
+	// Last update timestamp
+	UpdatedAt time.Time `json:"updated_at" gorm:"default:current_timestamp"`
+}
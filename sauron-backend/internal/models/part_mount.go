@@ -0,0 +1,58 @@
+package models
+
+import (
+	"time"
+)
+
+// Roles a part plays on a mounting interface
+const (
+	// The part has a rail section accessories can be mounted on, e.g. a handguard
+	MountRoleProvides = "provides"
+
+	// The part mounts onto a rail section, e.g. an optic or a light
+	MountRoleConsumes = "consumes"
+)
+
+// Positions of a rail section around the bore axis
+const (
+	MountPositionTop    = "top"
+	MountPositionBottom = "bottom"
+	MountPositionLeft   = "left"
+	MountPositionRight  = "right"
+
+	// Only for consumed mounts: the accessory can go on a section in any position
+	MountPositionAny = "any"
+)
+
+// PartMount records a rail section a part provides or the rail space a part consumes
+// @Description Rail section a part provides, or rail space an accessory consumes, on a mounting interface
+type PartMount struct {
+	// Unique identifier for the mount
+	ID int `json:"id" gorm:"primaryKey" example:"1"`
+
+	// Part providing or consuming rail space
+	PartID int   `json:"part_id" gorm:"index;uniqueIndex:part_mount;not null" example:"20"`
+	Part   *Part `json:"part,omitempty" gorm:"foreignKey:PartID;constraint:OnDelete:CASCADE"`
+
+	// Mounting interface of the rail section
+	MountingInterfaceID int                `json:"mounting_interface_id" gorm:"index;uniqueIndex:part_mount;not null" example:"1"`
+	MountingInterface   *MountingInterface `json:"mounting_interface,omitempty" gorm:"foreignKey:MountingInterfaceID"`
+
+	// Whether the part provides the rail section or consumes space on one
+	Role string `json:"role" gorm:"size:20;uniqueIndex:part_mount;not null" example:"provides" enums:"provides,consumes"`
+
+	// Where the section is, or for consumed mounts where the accessory must go
+	Position string `json:"position" gorm:"size:20;uniqueIndex:part_mount;not null" example:"top" enums:"top,bottom,left,right,any"`
+
+	// Slots the section offers, or the accessory occupies
+	Slots int `json:"slots" gorm:"not null" example:"12"`
+
+	// Mounting notes, e.g. sight height or clamp type
+	Notes string `json:"notes" gorm:"type:text" example:"Continuous top rail, flush with the upper receiver"`
+
+	// Creation timestamp
+	CreatedAt time.Time `json:"created_at" gorm:"default:current_timestamp"`
+
+	// Last update timestamp
+	UpdatedAt time.Time `json:"updated_at" gorm:"default:current_timestamp"`
+}
@@ -22,8 +22,12 @@ type PrebuiltFirearm struct {
 	// Description of the prebuilt firearm
 	Description string `json:"description" gorm:"type:text" example:"A prebuilt M4 variant with a 14.5-inch barrel."`
 
+	// Caliber the prebuilt firearm is chambered in
+	CaliberID *int     `json:"caliber_id,omitempty" gorm:"index" example:"1"`
+	Caliber   *Caliber `json:"caliber,omitempty" gorm:"foreignKey:CaliberID"`
+
 	// Specifications specific to this prebuilt configuration
-	Specifications datatypes.JSON `json:"specifications" gorm:"type:jsonb" swaggertype:"string" example:"{\"weight\": \"6.5 lbs\", \"caliber\": \"5.56x45mm NATO\"}"`
+	Specifications datatypes.JSON `json:"specifications" gorm:"type:jsonb" swaggertype:"string" example:"{\"weight\": \"6.5 lbs\"}"`
 
 	// Hierarchical structure of actual parts used in this prebuilt with IDs
 	Parts datatypes.JSON `json:"parts" gorm:"column:components;type:jsonb;not null" swaggertype:"string" example:"{\"Upper Assembly\": {\"id\": 1, \"sub_parts\": {\"Bolt Carrier Group\": {\"id\": 5}}}}"`
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+)
+
+// RuleSet records one imported version of the compatibility ruleset
+// @Description A versioned compatibility ruleset imported from YAML; importing swaps it in for all existing rules
+type RuleSet struct {
+	// Unique identifier for the ruleset
+	ID int `json:"id" gorm:"primaryKey" example:"1"`
+
+	// Version number, increasing with every import
+	Version int `json:"version" gorm:"uniqueIndex;not null" example:"3"`
+
+	// Name of the ruleset
+	Name string `json:"name" gorm:"size:255;not null" example:"AR-15 core rules"`
+
+	// Description of the ruleset
+	Description string `json:"description" gorm:"type:text" example:"Interface constraints between AR-15 upper assembly parts"`
+
+	// File or client the ruleset was imported from
+	Source string `json:"source" gorm:"size:255" example:"rulesets/ar15.yaml"`
+
+	// SHA-256 of the imported YAML
+	Checksum string `json:"checksum" gorm:"size:64" example:"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"`
+
+	// YAML document exactly as imported
+	Content string `json:"-" gorm:"type:text"`
+
+	// Number of rules in the ruleset
+	RuleCount int `json:"rule_count" example:"3"`
+
+	// Whether this is the most recently imported ruleset
+	Active bool `json:"active" gorm:"default:false" example:"true"`
+
+	// Import timestamp
+	CreatedAt time.Time `json:"created_at" gorm:"default:current_timestamp"`
+}
@@ -0,0 +1,54 @@
+package models
+
+import "time"
+
+// Shipping classes a part category can be given; prebuilt firearms are always firearms
+const (
+	ShippingClassMagazine   = "magazine"
+	ShippingClassAmmunition = "ammunition"
+
+	// Serialized firearm or receiver, which generally ships only to a licensed dealer
+	ShippingClassFirearm = "firearm"
+)
+
+// Kinds of shipping restriction
+const (
+	// The item is not shipped to the state at all
+	ShippingRestrictionNoShip = "no_ship"
+
+	// The item is only shipped to a federally licensed dealer (FFL) in the state
+	ShippingRestrictionFFLRequired = "ffl_required"
+)
+
+// ShippingRestriction limits where a seller, or one of its listings, ships
+// @Description Destination state a seller or a single listing will not ship to, or only ships to through an FFL, optionally limited to magazines, ammunition or firearms
+type ShippingRestriction struct {
+	// Unique identifier for the shipping restriction
+	ID int `json:"id" gorm:"primaryKey" example:"1"`
+
+	// Seller whose listings the restriction covers; exactly one of seller_id and product_listing_id is set
+	SellerID *int    `json:"seller_id,omitempty" gorm:"index" example:"1"`
+	Seller   *Seller `json:"seller,omitempty" gorm:"foreignKey:SellerID"`
+
+	// Single listing the restriction covers
+	ProductListingID *int            `json:"product_listing_id,omitempty" gorm:"index" example:"12"`
+	ProductListing   *ProductListing `json:"product_listing,omitempty" gorm:"foreignKey:ProductListingID"`
+
+	// Two-letter code of the destination state, or every state when empty
+	State string `json:"state,omitempty" gorm:"size:2;index" example:"CA"`
+
+	// Kind of restriction
+	Type string `json:"type" gorm:"size:20;not null" example:"no_ship" enums:"no_ship,ffl_required"`
+
+	// Shipping class of the items covered, or every item when empty
+	ShippingClass string `json:"shipping_class,omitempty" gorm:"size:20" example:"magazine" enums:"magazine,ammunition,firearm"`
+
+	// Explanation shown to buyers
+	Reason string `json:"reason" gorm:"size:500" example:"We do not ship magazines to California"`
+
+	// Creation timestamp
+	CreatedAt time.Time `json:"created_at" gorm:"default:current_timestamp"`
+
+	// Last update timestamp
+	UpdatedAt time.Time `json:"updated_at" gorm:"default:current_timestamp"`
+}